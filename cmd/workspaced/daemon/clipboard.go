@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/db"
+	"github.com/lucasew/workspaced/internal/types"
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/clipboard"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// clipboardHistoryConfig is workspaced.clipboard.history.
+type clipboardHistoryConfig struct {
+	Enable        bool `json:"enable"`
+	MaxEntries    int  `json:"max_entries"`
+	MaxBytes      int  `json:"max_bytes"`
+	MaxTotalBytes int  `json:"max_total_bytes"`
+}
+
+// loadClipboardHistoryConfig mirrors the schema defaults: history is off
+// unless enabled.
+func loadClipboardHistoryConfig(ctx context.Context) clipboardHistoryConfig {
+	cfg := clipboardHistoryConfig{MaxEntries: 500, MaxBytes: 8 << 20, MaxTotalBytes: 64 << 20}
+	c, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return cfg
+	}
+	if _, err := c.Lookup("clipboard.history"); err != nil {
+		return cfg
+	}
+	if err := c.Decode("clipboard.history", &cfg); err != nil {
+		logging.GetLogger(ctx).Warn("invalid clipboard.history, using defaults", "error", err)
+	}
+	return cfg
+}
+
+// watchClipboard records every new clipboard selection in the history
+// until ctx is done.
+func watchClipboard(ctx context.Context, database *db.DB) {
+	logger := logging.GetLogger(ctx)
+	cfg := loadClipboardHistoryConfig(ctx)
+	if !cfg.Enable {
+		return
+	}
+	err := clipboard.Watch(ctx, func(e *clipboard.Entry) {
+		if len(e.Data) > cfg.MaxBytes {
+			logger.Debug("clipboard entry too large, not recorded", "mime", e.MIME, "size", len(e.Data))
+			return
+		}
+		err := database.RecordClipboard(ctx, types.ClipboardEntry{
+			Hash:      e.Hash(),
+			MIME:      e.MIME,
+			Preview:   e.Preview(120),
+			Timestamp: time.Now().Unix(),
+			Data:      e.Data,
+		}, cfg.MaxEntries, cfg.MaxTotalBytes)
+		if err != nil {
+			logging.ReportError(ctx, err, "op", "record clipboard")
+		}
+	})
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	if errors.Is(err, driver.ErrNotFound) || errors.Is(err, dapi.ErrNotSupported) {
+		logger.Debug("clipboard history disabled", "error", err)
+		return
+	}
+	logger.Error("clipboard watcher failed", "error", err)
+}
@@ -24,8 +24,11 @@ import (
 	"github.com/lucasew/workspaced/internal/executil"
 	"github.com/lucasew/workspaced/internal/icons"
 	"github.com/lucasew/workspaced/internal/types"
+	"github.com/lucasew/workspaced/pkg/driver/battery"
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
 	"github.com/lucasew/workspaced/pkg/driver/media"
 	"github.com/lucasew/workspaced/pkg/driver/tray"
+	"github.com/lucasew/workspaced/pkg/driver/wm"
 	"github.com/lucasew/workspaced/pkg/logging"
 
 	"github.com/coreos/go-systemd/v22/activation"
@@ -133,6 +136,11 @@ func RunDaemon(ctx context.Context) error {
 	defer logging.Close(ctx, database)
 
 	go media.Watch(ctx)
+	go brightness.WatchNightLight(ctx)
+	go battery.WatchPolicy(ctx)
+	go wm.WatchLayouts(ctx)
+	go watchClipboard(ctx, database)
+	defer stopRecording(ctx)
 
 	go func() {
 		logger := logging.GetLogger(ctx)
@@ -308,6 +316,13 @@ func handleWS(w http.ResponseWriter, r *http.Request, database *db.DB) {
 				if err := database.RecordHistory(ctx, event); err != nil {
 					logger.Error("failed to record history", "error", err)
 				}
+			case "screenrecord":
+				var req types.ScreenrecordRequest
+				if err := json.Unmarshal(packet.Payload, &req); err != nil {
+					logger.Warn("ws unmarshal screenrecord request error", "error", err)
+					continue
+				}
+				handleScreenrecord(ctx, req, outCh)
 			}
 		}
 	}()
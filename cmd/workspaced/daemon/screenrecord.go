@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lucasew/workspaced/internal/executil"
+	"github.com/lucasew/workspaced/internal/types"
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+	"github.com/lucasew/workspaced/pkg/driver/screenshot"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// handleScreenrecord runs a screenrecord operation on the daemon's recorder
+// and replies with the resulting status as JSON.
+func handleScreenrecord(ctx context.Context, req types.ScreenrecordRequest, outCh chan types.StreamPacket) {
+	ctx = executil.WithEnv(ctx, append(req.Env, "WORKSPACED_DAEMON=1"))
+	st, err := runScreenrecord(ctx, req)
+	var resp types.Response
+	if err != nil {
+		resp.Error = err.Error()
+	} else if out, marshalErr := json.Marshal(st); marshalErr == nil {
+		resp.Output = string(out)
+	} else {
+		resp.Error = marshalErr.Error()
+	}
+	payload, marshalErr := json.Marshal(resp)
+	if marshalErr != nil {
+		payload = []byte(`{}`)
+		logging.ReportError(ctx, marshalErr)
+	}
+	outCh <- types.StreamPacket{Type: "result", Payload: payload}
+}
+
+func runScreenrecord(ctx context.Context, req types.ScreenrecordRequest) (screenrecord.Status, error) {
+	target, err := screenshot.ParseTarget(req.Target)
+	if err != nil {
+		return screenrecord.Status{}, err
+	}
+	so := screenrecord.StartOptions{Target: target, Audio: req.Audio, Format: req.Format}
+	switch req.Op {
+	case "start":
+		return screenrecord.Start(ctx, so)
+	case "stop":
+		return screenrecord.Stop(ctx)
+	case "toggle":
+		return screenrecord.Toggle(ctx, so)
+	case "status":
+		return screenrecord.CurrentStatus(), nil
+	}
+	return screenrecord.Status{}, fmt.Errorf("unknown screenrecord op %q", req.Op)
+}
+
+// stopRecording finalizes a recording still running when the daemon exits.
+func stopRecording(ctx context.Context) {
+	if _, err := screenrecord.Stop(context.WithoutCancel(ctx)); err != nil && !errors.Is(err, screenrecord.ErrNotRecording) {
+		logging.ReportError(ctx, err, "op", "stop recording")
+	}
+}
@@ -0,0 +1,130 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/driver/audio"
+	"github.com/lucasew/workspaced/pkg/driver/dialog"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		for _, kind := range []struct {
+			use, short string
+			list       func(context.Context) ([]audio.Device, error)
+		}{
+			{"sinks", "List output devices", audio.ListSinks},
+			{"sources", "List input devices", audio.ListSources},
+		} {
+			cmd := &cobra.Command{
+				Use:   kind.use,
+				Short: kind.short,
+				Long:  kind.short + ". The default device is marked with '*'.",
+				Args:  cobra.NoArgs,
+				RunE: func(c *cobra.Command, args []string) error {
+					devices, err := kind.list(c.Context())
+					if err != nil {
+						return err
+					}
+					return printDevices(c, devices)
+				},
+			}
+			cmd.Flags().Bool("json", false, "Output as JSON")
+			parent.AddCommand(cmd)
+		}
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "switch [sink]",
+			Short: "Switch the default output device",
+			Long: `Make a sink the default output and move playing streams to it. The sink
+is matched by index, name or part of its description; without an argument a
+dialog lists the sinks.
+
+Examples:
+  workspaced driver audio switch
+  workspaced driver audio switch headphones`,
+			Args: cobra.MaximumNArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				ctx := c.Context()
+				if len(args) == 1 {
+					return audio.SwitchSink(ctx, args[0])
+				}
+				sinks, err := audio.ListSinks(ctx)
+				if err != nil {
+					return err
+				}
+				items := make([]dialog.Item, len(sinks))
+				for i, s := range sinks {
+					label := s.Label()
+					if s.Default {
+						label += " (current)"
+					}
+					items[i] = dialog.Item{Label: label, Icon: "audio-card", Value: s.Name}
+				}
+				choice, err := dialog.Choose(ctx, dialog.ChooseOptions{Prompt: "Output", Items: items})
+				if err != nil {
+					return err
+				}
+				if choice == nil {
+					return nil
+				}
+				return audio.SwitchSink(ctx, choice.Value)
+			},
+		})
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "mic-toggle",
+			Short: "Toggle microphone mute",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				return audio.ToggleMicMute(c.Context())
+			},
+		})
+	})
+}
+
+func printDevices(c *cobra.Command, devices []audio.Device) error {
+	asJSON, err := c.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if devices == nil {
+			devices = []audio.Device{}
+		}
+		return json.NewEncoder(c.OutOrStdout()).Encode(devices)
+	}
+	for _, d := range devices {
+		mark := " "
+		if d.Default {
+			mark = "*"
+		}
+		if _, err := fmt.Fprintf(c.OutOrStdout(), "%s %d\t%s\t%s\t%s\n", mark, d.Index, levelString(d.Volume, d.Muted), d.Name, d.Label()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func levelString(volume float64, muted bool) string {
+	s := fmt.Sprintf("%d%%", int(volume*100+0.5))
+	if muted {
+		s += " (muted)"
+	}
+	return s
+}
+
+// parseLevel reads a percentage such as "40" or "40%".
+func parseLevel(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid volume %q", s)
+	}
+	return v / 100, nil
+}
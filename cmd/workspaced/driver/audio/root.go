@@ -11,7 +11,7 @@ var Registry cmdregistry.CommandRegistry
 func GetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "audio",
-		Short: "Control audio volume",
+		Short: "Control audio volume and devices",
 	}
 	return Registry.FillCommands(cmd)
 }
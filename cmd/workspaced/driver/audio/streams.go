@@ -0,0 +1,71 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lucasew/workspaced/pkg/driver/audio"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		streams := &cobra.Command{
+			Use:   "streams",
+			Short: "List application playback streams",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				streams, err := audio.ListStreams(c.Context())
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					if streams == nil {
+						streams = []audio.Stream{}
+					}
+					return json.NewEncoder(c.OutOrStdout()).Encode(streams)
+				}
+				for _, s := range streams {
+					if _, err := fmt.Fprintf(c.OutOrStdout(), "%d\t%s\t%s\t%s\t%s\n", s.ID, levelString(s.Volume, s.Muted), s.App, s.Media, s.Sink); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+		streams.Flags().Bool("json", false, "Output as JSON")
+		parent.AddCommand(streams)
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "stream-volume <id|app> <percent>",
+			Short: "Set the volume of application streams",
+			Long: `Set the volume of the streams with the given id or application name.
+
+Examples:
+  workspaced driver audio stream-volume firefox 40
+  workspaced driver audio stream-volume 81 100%`,
+			Args: cobra.ExactArgs(2),
+			RunE: func(c *cobra.Command, args []string) error {
+				level, err := parseLevel(args[1])
+				if err != nil {
+					return err
+				}
+				return audio.SetStreamVolume(c.Context(), args[0], level)
+			},
+		})
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "stream-mute <id|app>",
+			Short: "Toggle mute of application streams",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				return audio.ToggleStreamMute(c.Context(), args[0])
+			},
+		})
+	})
+}
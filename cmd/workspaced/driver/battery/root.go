@@ -0,0 +1,17 @@
+package battery
+
+import (
+	"github.com/lucasew/workspaced/internal/cmdregistry"
+
+	"github.com/spf13/cobra"
+)
+
+var Registry cmdregistry.CommandRegistry
+
+func GetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "battery",
+		Short: "Battery charge, health and charge thresholds",
+	}
+	return Registry.FillCommands(cmd)
+}
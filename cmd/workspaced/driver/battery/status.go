@@ -0,0 +1,70 @@
+package battery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/lucasew/workspaced/pkg/driver/battery"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		status := &cobra.Command{
+			Use:   "status",
+			Short: "Show charge, rate, time remaining and health of every battery",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				info, err := battery.GetInfo(c.Context())
+				if err != nil {
+					return err
+				}
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					return json.NewEncoder(c.OutOrStdout()).Encode(info)
+				}
+				return printInfo(c.OutOrStdout(), info)
+			},
+		}
+		status.Flags().Bool("json", false, "Output as JSON")
+		parent.AddCommand(status)
+	})
+}
+
+func printInfo(w io.Writer, info *battery.Info) error {
+	power := "battery"
+	if info.OnAC {
+		power = "AC"
+	}
+	line := fmt.Sprintf("%s, %d%%, %s", info.Status(), info.Capacity(), power)
+	if left := info.TimeRemaining(); left > 0 {
+		line += fmt.Sprintf(", %s remaining", left)
+	}
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return err
+	}
+	for _, b := range info.Batteries {
+		line := fmt.Sprintf("%s\t%s\t%d%%", b.Name, b.Status, b.Capacity)
+		if b.Power > 0 {
+			line += fmt.Sprintf("\t%.1fW", b.Power)
+		}
+		if h := b.Health(); h > 0 {
+			line += fmt.Sprintf("\thealth %d%%", int(h*100+0.5))
+		}
+		if b.CycleCount > 0 {
+			line += fmt.Sprintf("\t%d cycles", b.CycleCount)
+		}
+		if t := b.Thresholds; t != nil {
+			line += fmt.Sprintf("\tcharges %d-%d%%", t.Start, t.End)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package battery
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lucasew/workspaced/pkg/driver/battery"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		cmd := &cobra.Command{
+			Use:   "thresholds <start> <end>",
+			Short: "Limit charging to between start and end percent",
+			Long: `Limit charging to between start and end percent, where the battery
+supports it. Writing the limits usually needs root or a udev rule.
+
+Examples:
+  workspaced driver battery thresholds 75 80
+  workspaced driver battery thresholds 0 100 --battery BAT1`,
+			Args: cobra.ExactArgs(2),
+			RunE: func(c *cobra.Command, args []string) error {
+				start, err := strconv.Atoi(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid start %q: %w", args[0], err)
+				}
+				end, err := strconv.Atoi(args[1])
+				if err != nil {
+					return fmt.Errorf("invalid end %q: %w", args[1], err)
+				}
+				name, err := c.Flags().GetString("battery")
+				if err != nil {
+					return err
+				}
+				return battery.SetThresholds(c.Context(), name, start, end)
+			},
+		}
+		cmd.Flags().StringP("battery", "b", "", "Battery name from 'status' (default: every battery with thresholds)")
+		parent.AddCommand(cmd)
+	})
+}
@@ -0,0 +1,95 @@
+package brightness
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		list := &cobra.Command{
+			Use:   "list",
+			Short: "List backlights and external monitors",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				devices, err := brightness.ListDevices(c.Context())
+				if err != nil {
+					return err
+				}
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					if devices == nil {
+						devices = []brightness.Device{}
+					}
+					return json.NewEncoder(c.OutOrStdout()).Encode(devices)
+				}
+				for _, d := range devices {
+					if _, err := fmt.Fprintf(c.OutOrStdout(), "%s\t%s\t%d%%\t%s\n", d.ID, d.Kind, int(d.Brightness*100+0.5), d.Name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+		list.Flags().Bool("json", false, "Output as JSON")
+		parent.AddCommand(list)
+
+		get := &cobra.Command{
+			Use:   "get",
+			Short: "Print the brightness of a device in percent",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				id, err := c.Flags().GetString("device")
+				if err != nil {
+					return err
+				}
+				d, err := brightness.GetDevice(c.Context(), id)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(c.OutOrStdout(), int(d.Brightness*100+0.5))
+				return err
+			},
+		}
+		get.Flags().StringP("device", "d", "", "Device id from 'list' (default: the backlight)")
+		parent.AddCommand(get)
+
+		set := &cobra.Command{
+			Use:   "set <percent>",
+			Short: "Set the brightness of a device",
+			Long: `Set the brightness of a device, optionally fading over a duration.
+
+Examples:
+  workspaced driver brightness set 40
+  workspaced driver brightness set 80% --device ddc:DP-1 --duration 500ms`,
+			Args: cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				id, err := c.Flags().GetString("device")
+				if err != nil {
+					return err
+				}
+				duration, err := c.Flags().GetDuration("duration")
+				if err != nil {
+					return err
+				}
+				percent, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "%"), 64)
+				if err != nil {
+					return fmt.Errorf("invalid brightness %q", args[0])
+				}
+				return brightness.SetDevice(c.Context(), id, percent/100, duration)
+			},
+		}
+		set.Flags().StringP("device", "d", "", "Device id from 'list' (default: the backlight)")
+		set.Flags().Duration("duration", 0, "Fade to the new level over this long")
+		parent.AddCommand(set)
+	})
+}
@@ -0,0 +1,80 @@
+package brightness
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		nightLight := &cobra.Command{
+			Use:   "night-light",
+			Short: "Display color temperature",
+			Long: `Inspect or override the display color temperature. The daemon follows
+the brightness.night_light schedule from the config on its own.`,
+		}
+
+		status := &cobra.Command{
+			Use:   "status",
+			Short: "Print the scheduled color temperature",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				cfg, err := brightness.LoadNightLightConfig(c.Context())
+				if err != nil {
+					return err
+				}
+				schedule, err := cfg.Schedule()
+				if err != nil {
+					return err
+				}
+				at, err := c.Flags().GetString("at")
+				if err != nil {
+					return err
+				}
+				when := time.Now()
+				if at != "" {
+					t, err := time.ParseInLocation("15:04", at, time.Local)
+					if err != nil {
+						return fmt.Errorf("invalid --at %q, expected HH:MM", at)
+					}
+					when = t
+				}
+				state := "disabled"
+				if cfg.Enable {
+					state = "enabled"
+				}
+				_, err = fmt.Fprintf(c.OutOrStdout(), "%dK (%s, night %s-%s)\n", schedule.TemperatureAt(when), state, cfg.Sunset, cfg.Sunrise)
+				return err
+			},
+		}
+		status.Flags().String("at", "", "Time of day to evaluate (HH:MM, default now)")
+		nightLight.AddCommand(status)
+
+		nightLight.AddCommand(&cobra.Command{
+			Use:   "set <kelvin>",
+			Short: "Tint the display until interrupted",
+			Long: `Tint the display to a color temperature. On Wayland the tint lasts while
+the command runs; stop the daemon's schedule first or it will override it.`,
+			Args: cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				kelvin, err := strconv.Atoi(args[0])
+				if err != nil || kelvin < 1000 || kelvin > 25000 {
+					return fmt.Errorf("invalid temperature %q, expected 1000-25000", args[0])
+				}
+				ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return brightness.SetTemperature(ctx, kelvin)
+			},
+		})
+
+		parent.AddCommand(nightLight)
+	})
+}
@@ -11,7 +11,7 @@ var Registry cmdregistry.CommandRegistry
 func GetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "brightness",
-		Short: "Control screen brightness",
+		Short: "Control screen brightness and color temperature",
 	}
 	return Registry.FillCommands(cmd)
 }
@@ -38,16 +38,52 @@ func init() {
 				if err != nil {
 					return err
 				}
-				return capture(cmd, id, outPath)
+				opts, err := captureOptions(cmd)
+				if err != nil {
+					return err
+				}
+				return capture(cmd, id, outPath, opts)
 			},
 		}
 		cmd.Flags().StringP("id", "i", "", "camera ID or name (defaults to the first camera)")
 		cmd.Flags().StringP("output", "o", "", "output path (use - for stdout; defaults to cache directory)")
+		addCaptureFlags(cmd, 0)
 		parent.AddCommand(cmd)
 	})
 }
 
-func capture(cmd *cobra.Command, id, outPath string) error {
+// addCaptureFlags adds the flags read by captureOptions.
+func addCaptureFlags(cmd *cobra.Command, warmup int) {
+	cmd.Flags().String("size", "", "resolution as WIDTHxHEIGHT, e.g. 1280x720 (defaults to the device's)")
+	cmd.Flags().String("format", "", "input pixel format, e.g. mjpeg or yuyv422")
+	cmd.Flags().Int("fps", 0, "frame rate to request")
+	cmd.Flags().Int("warmup", warmup, "frames to drop while exposure settles")
+}
+
+func captureOptions(cmd *cobra.Command) (cameraapi.CaptureOptions, error) {
+	var opts cameraapi.CaptureOptions
+	size, err := cmd.Flags().GetString("size")
+	if err != nil {
+		return opts, err
+	}
+	if size != "" {
+		if _, err := fmt.Sscanf(size, "%dx%d", &opts.Width, &opts.Height); err != nil || opts.Width <= 0 || opts.Height <= 0 {
+			return opts, fmt.Errorf("invalid size %q: want WIDTHxHEIGHT", size)
+		}
+	}
+	if opts.PixelFormat, err = cmd.Flags().GetString("format"); err != nil {
+		return opts, err
+	}
+	if opts.FrameRate, err = cmd.Flags().GetInt("fps"); err != nil {
+		return opts, err
+	}
+	if opts.Warmup, err = cmd.Flags().GetInt("warmup"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+func capture(cmd *cobra.Command, id, outPath string, opts cameraapi.CaptureOptions) error {
 	drv, err := driver.Get[cameraapi.Driver](cmd.Context())
 	if err != nil {
 		return err
@@ -61,7 +97,7 @@ func capture(cmd *cobra.Command, id, outPath string) error {
 		return err
 	}
 
-	usedCam, img, err := captureFromCamera(cmd, cams, cam, id)
+	usedCam, img, err := captureFromCamera(cmd, cams, cam, id, opts)
 	if err != nil {
 		return err
 	}
@@ -110,9 +146,9 @@ func selectCamera(cams []cameraapi.Camera, id string) (cameraapi.Camera, error)
 	return nil, fmt.Errorf("%w: %q", ErrCameraNotFound, id)
 }
 
-func captureFromCamera(cmd *cobra.Command, cams []cameraapi.Camera, preferred cameraapi.Camera, id string) (cameraapi.Camera, image.Image, error) {
+func captureFromCamera(cmd *cobra.Command, cams []cameraapi.Camera, preferred cameraapi.Camera, id string, opts cameraapi.CaptureOptions) (cameraapi.Camera, image.Image, error) {
 	if id != "" {
-		img, err := preferred.Capture(cmd.Context())
+		img, err := cameraapi.Capture(cmd.Context(), preferred, opts)
 		return preferred, img, err
 	}
 
@@ -123,7 +159,7 @@ func captureFromCamera(cmd *cobra.Command, cams []cameraapi.Camera, preferred ca
 
 	var errs []string
 	for _, cam := range ordered {
-		img, err := cam.Capture(cmd.Context())
+		img, err := cameraapi.Capture(cmd.Context(), cam, opts)
 		if err == nil {
 			return cam, img, nil
 		}
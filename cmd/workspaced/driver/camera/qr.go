@@ -0,0 +1,225 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	cameraapi "github.com/lucasew/workspaced/pkg/driver/camera"
+	"github.com/lucasew/workspaced/pkg/driver/clipboard"
+	"github.com/lucasew/workspaced/pkg/driver/opener"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/qrcode"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		cmd := &cobra.Command{
+			Use:   "qr",
+			Short: "Scan a QR code with the camera",
+			Long: `Scan a QR code with the camera and print its text.
+
+Frames are decoded as they arrive until a code is found or the timeout
+passes. Wi-Fi and one-time password setup codes get a summary on stderr.
+Use --image to decode a picture instead of the camera.`,
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				id, err := cmd.Flags().GetString("id")
+				if err != nil {
+					return err
+				}
+				file, err := cmd.Flags().GetString("image")
+				if err != nil {
+					return err
+				}
+				timeout, err := cmd.Flags().GetDuration("timeout")
+				if err != nil {
+					return err
+				}
+				copyText, err := cmd.Flags().GetBool("copy")
+				if err != nil {
+					return err
+				}
+				open, err := cmd.Flags().GetBool("open")
+				if err != nil {
+					return err
+				}
+				opts, err := captureOptions(cmd)
+				if err != nil {
+					return err
+				}
+
+				var code *qrcode.Code
+				if file != "" {
+					code, err = scanFile(ctx, file)
+				} else {
+					code, err = scanCamera(ctx, id, opts, timeout)
+				}
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintln(cmd.OutOrStdout(), code.Text)
+				if summary := describeQR(code.Text); summary != "" {
+					cmd.PrintErrln(summary)
+				}
+				if copyText {
+					if err := clipboard.WriteText(ctx, code.Text); err != nil {
+						return err
+					}
+				}
+				if open {
+					if u, err := url.Parse(code.Text); err != nil || u.Scheme == "" {
+						return fmt.Errorf("cannot open %q: not a URI", code.Text)
+					}
+					return opener.Open(ctx, code.Text)
+				}
+				return nil
+			},
+		}
+		cmd.Flags().StringP("id", "i", "", "camera ID or name (defaults to the first camera)")
+		cmd.Flags().String("image", "", "decode this PNG or JPEG file instead of using the camera")
+		cmd.Flags().Duration("timeout", time.Minute, "give up after this long without a code")
+		cmd.Flags().BoolP("copy", "c", false, "copy the text to the clipboard")
+		cmd.Flags().Bool("open", false, "open the text as a URI")
+		addCaptureFlags(cmd, 0)
+		parent.AddCommand(cmd)
+	})
+}
+
+func scanFile(ctx context.Context, path string) (*qrcode.Code, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer logging.Close(ctx, f)
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return qrcode.Decode(img)
+}
+
+func scanCamera(ctx context.Context, id string, opts cameraapi.CaptureOptions, timeout time.Duration) (*qrcode.Code, error) {
+	drv, err := driver.Get[cameraapi.Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	cams, err := drv.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cam, err := selectCamera(cams, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	logger := logging.GetLogger(ctx)
+	logger.Info("scanning for a QR code", "camera", cam.Name())
+
+	var code *qrcode.Code
+	err = cameraapi.Stream(ctx, cam, opts, func(img image.Image) error {
+		found, err := qrcode.Decode(img)
+		if err != nil {
+			logger.Debug("no code in frame", "error", err)
+			return nil
+		}
+		code = found
+		return cameraapi.ErrStop
+	})
+	if code != nil {
+		return code, nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w within %s", qrcode.ErrNotFound, timeout)
+	}
+	return nil, err
+}
+
+// describeQR summarizes setup payloads worth a second look, or returns ""
+// for anything else.
+func describeQR(text string) string {
+	switch {
+	case strings.HasPrefix(text, "WIFI:"):
+		fields := parseWifi(text)
+		security := fields["T"]
+		if security == "" || security == "nopass" {
+			security = "open"
+		}
+		summary := fmt.Sprintf("Wi-Fi network %q (%s)", fields["S"], security)
+		if fields["H"] == "true" {
+			summary += ", hidden"
+		}
+		if pass := fields["P"]; pass != "" {
+			summary += fmt.Sprintf(", password %q", pass)
+		}
+		return summary
+	case strings.HasPrefix(text, "otpauth://"):
+		u, err := url.Parse(text)
+		if err != nil {
+			return ""
+		}
+		label := strings.TrimPrefix(u.Path, "/")
+		issuer, account, found := strings.Cut(label, ":")
+		if !found {
+			issuer, account = "", label
+		}
+		if q := u.Query().Get("issuer"); q != "" {
+			issuer = q
+		}
+		summary := fmt.Sprintf("%s one-time password for %q", strings.ToUpper(u.Host), strings.TrimSpace(account))
+		if issuer != "" {
+			summary += fmt.Sprintf(" from %s", issuer)
+		}
+		return summary
+	}
+	return ""
+}
+
+// parseWifi splits a WIFI: payload into its fields. Backslash escapes the
+// next character, so passwords may contain ; and :.
+func parseWifi(text string) map[string]string {
+	fields := map[string]string{}
+	var key, cur strings.Builder
+	inValue := false
+	escaped := false
+	for _, r := range strings.TrimPrefix(text, "WIFI:") {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':' && !inValue:
+			key.WriteString(cur.String())
+			cur.Reset()
+			inValue = true
+		case r == ';':
+			if inValue {
+				fields[key.String()] = cur.String()
+			}
+			key.Reset()
+			cur.Reset()
+			inValue = false
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	return fields
+}
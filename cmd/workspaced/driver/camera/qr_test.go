@@ -0,0 +1,21 @@
+package camera
+
+import "testing"
+
+func TestDescribeQR(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`WIFI:T:WPA;S:home-net;P:pa\;ss\:word;H:true;;`, `Wi-Fi network "home-net" (WPA), hidden, password "pa;ss:word"`},
+		{`WIFI:S:cafe;T:nopass;;`, `Wi-Fi network "cafe" (open)`},
+		{"otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example", `TOTP one-time password for "alice@example.com" from Example`},
+		{"otpauth://hotp/bob?secret=JBSWY3DPEHPK3PXP&counter=1", `HOTP one-time password for "bob"`},
+		{"https://example.com", ""},
+	}
+	for _, tt := range tests {
+		if got := describeQR(tt.text); got != tt.want {
+			t.Errorf("describeQR(%q)\n got %s\nwant %s", tt.text, got, tt.want)
+		}
+	}
+}
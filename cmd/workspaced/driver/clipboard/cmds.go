@@ -0,0 +1,98 @@
+package clipboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/lucasew/workspaced/pkg/driver/clipboard"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		read := &cobra.Command{
+			Use:   "read",
+			Short: "Write the clipboard contents to stdout",
+			Long: `Write the clipboard contents to stdout, as text by default.
+
+Examples:
+  workspaced driver clipboard read
+  workspaced driver clipboard read --type image/png > shot.png`,
+			Args: cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				mime, err := c.Flags().GetString("type")
+				if err != nil {
+					return err
+				}
+				var data []byte
+				if mime == "" {
+					text, err := clipboard.ReadText(c.Context())
+					if err != nil {
+						return err
+					}
+					data = []byte(text)
+				} else if data, err = clipboard.Read(c.Context(), mime); err != nil {
+					return err
+				}
+				_, err = c.OutOrStdout().Write(data)
+				return err
+			},
+		}
+		read.Flags().StringP("type", "t", "", "MIME type to read (default: text)")
+		parent.AddCommand(read)
+
+		write := &cobra.Command{
+			Use:   "write",
+			Short: "Copy stdin to the clipboard",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				mime, err := c.Flags().GetString("type")
+				if err != nil {
+					return err
+				}
+				data, err := io.ReadAll(c.InOrStdin())
+				if err != nil {
+					return err
+				}
+				if mime == "" {
+					return clipboard.WriteText(c.Context(), string(data))
+				}
+				return clipboard.Write(c.Context(), mime, data)
+			},
+		}
+		write.Flags().StringP("type", "t", "", "MIME type to offer (default: text)")
+		parent.AddCommand(write)
+
+		types := &cobra.Command{
+			Use:   "types",
+			Short: "List the types the clipboard is offered as",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				list, err := clipboard.ListTypes(c.Context())
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					if list == nil {
+						list = []string{}
+					}
+					return json.NewEncoder(c.OutOrStdout()).Encode(list)
+				}
+				for _, t := range list {
+					if _, err := fmt.Fprintln(c.OutOrStdout(), t); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+		types.Flags().Bool("json", false, "Output as JSON")
+		parent.AddCommand(types)
+	})
+}
@@ -0,0 +1,138 @@
+package clipboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/db"
+	"github.com/lucasew/workspaced/pkg/driver/clipboard"
+	"github.com/lucasew/workspaced/pkg/driver/dialog"
+	"github.com/lucasew/workspaced/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		history := &cobra.Command{
+			Use:   "history",
+			Short: "List the clipboard history recorded by the daemon",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				limit, err := c.Flags().GetInt("limit")
+				if err != nil {
+					return err
+				}
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				clearAll, err := c.Flags().GetBool("clear")
+				if err != nil {
+					return err
+				}
+				return withDB(c.Context(), func(database *db.DB) error {
+					if clearAll {
+						return database.Queries.ClearClipboard(c.Context())
+					}
+					entries, err := database.ListClipboard(c.Context(), limit)
+					if err != nil {
+						return err
+					}
+					if asJSON {
+						return json.NewEncoder(c.OutOrStdout()).Encode(entries)
+					}
+					for _, e := range entries {
+						t := time.Unix(e.Timestamp, 0).Format("2006-01-02 15:04:05")
+						if _, err := fmt.Fprintf(c.OutOrStdout(), "%d\t%s\t%s\n", e.ID, t, e.Preview); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			},
+		}
+		history.Flags().Int("limit", 100, "Limit number of entries")
+		history.Flags().Bool("json", false, "Output as JSON")
+		history.Flags().Bool("clear", false, "Delete the whole history")
+		parent.AddCommand(history)
+
+		pick := &cobra.Command{
+			Use:   "pick [id]",
+			Short: "Choose a history entry and copy it back to the clipboard",
+			Long: `Choose a clipboard history entry with the dialog chooser (or pass its id)
+and copy it back to the clipboard with its original type.
+
+Examples:
+  workspaced driver clipboard pick
+  workspaced driver clipboard pick --print`,
+			Args: cobra.MaximumNArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				limit, err := c.Flags().GetInt("limit")
+				if err != nil {
+					return err
+				}
+				printOnly, err := c.Flags().GetBool("print")
+				if err != nil {
+					return err
+				}
+				ctx := c.Context()
+				return withDB(ctx, func(database *db.DB) error {
+					var id int64
+					if len(args) == 1 {
+						if id, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+							return fmt.Errorf("invalid id %q: %w", args[0], err)
+						}
+					} else {
+						entries, err := database.ListClipboard(ctx, limit)
+						if err != nil {
+							return err
+						}
+						items := make([]dialog.Item, len(entries))
+						for i, e := range entries {
+							items[i] = dialog.Item{Label: e.Preview, Value: strconv.FormatInt(e.ID, 10)}
+						}
+						choice, err := dialog.Choose(ctx, dialog.ChooseOptions{Prompt: "Clipboard", Items: items})
+						if err != nil {
+							return err
+						}
+						if choice == nil {
+							return nil
+						}
+						if id, err = strconv.ParseInt(choice.Value, 10, 64); err != nil {
+							return err
+						}
+					}
+					entry, err := database.GetClipboard(ctx, id)
+					if err != nil {
+						return fmt.Errorf("clipboard entry %d: %w", id, err)
+					}
+					if printOnly {
+						_, err = c.OutOrStdout().Write(entry.Data)
+						return err
+					}
+					return clipboard.Write(ctx, entry.MIME, entry.Data)
+				})
+			},
+		}
+		pick.Flags().Int("limit", 100, "Number of entries to choose from")
+		pick.Flags().Bool("print", false, "Print the entry instead of copying it")
+		parent.AddCommand(pick)
+	})
+}
+
+func withDB(ctx context.Context, fn func(*db.DB) error) error {
+	database, ok := db.FromContext(ctx)
+	if !ok {
+		var err error
+		database, err = db.Open(ctx)
+		if err != nil {
+			return err
+		}
+		defer logging.Close(ctx, database)
+	}
+	return fn(database)
+}
@@ -0,0 +1,17 @@
+package clipboard
+
+import (
+	"github.com/lucasew/workspaced/internal/cmdregistry"
+
+	"github.com/spf13/cobra"
+)
+
+var Registry cmdregistry.CommandRegistry
+
+func GetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clipboard",
+		Short: "Clipboard access and history commands",
+	}
+	return Registry.FillCommands(cmd)
+}
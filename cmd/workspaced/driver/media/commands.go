@@ -1,20 +1,223 @@
 package media
 
 import (
-	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lucasew/workspaced/pkg/driver/media"
+
+	"github.com/spf13/cobra"
 )
 
 func init() {
-	addAction := func(use, short, action string) {
-		Registry.Add(use, short, func(ctx context.Context) error {
-			return media.RunAction(ctx, action)
+	Registry.Register(func(parent *cobra.Command) {
+		for _, a := range []struct{ use, short string }{
+			{"next", "Next media"},
+			{"previous", "Previous media"},
+			{"play-pause", "Play or pause media"},
+			{"stop", "Stop media"},
+			{"show", "Show media metadata"},
+		} {
+			parent.AddCommand(&cobra.Command{
+				Use:   a.use,
+				Short: a.short,
+				Args:  cobra.NoArgs,
+				RunE: func(c *cobra.Command, args []string) error {
+					target, err := player(c)
+					if err != nil {
+						return err
+					}
+					return media.RunAction(c.Context(), target, a.use)
+				},
+			})
+		}
+
+		players := &cobra.Command{
+			Use:   "players",
+			Short: "List media players",
+			Long:  "List the MPRIS players. The one commands target by default is marked with '*'.",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				players, err := media.ListPlayers(c.Context())
+				if err != nil {
+					return err
+				}
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				if asJSON {
+					if players == nil {
+						players = []media.Player{}
+					}
+					return json.NewEncoder(c.OutOrStdout()).Encode(players)
+				}
+				for _, p := range players {
+					mark := " "
+					if p.Selected {
+						mark = "*"
+					}
+					if _, err := fmt.Fprintf(c.OutOrStdout(), "%s %s\t%s\t%s\n", mark, p.Name, p.Status, p.Identity); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+		players.Flags().Bool("json", false, "Output as JSON")
+		parent.AddCommand(players)
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "seek <offset>",
+			Short: "Seek relative to the current position",
+			Long: `Seek by an offset in seconds or as a duration; negative values go back.
+
+Examples:
+  workspaced driver media seek 10
+  workspaced driver media seek -- -1m30s`,
+			Args: cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				offset, err := parseDuration(args[0])
+				if err != nil {
+					return err
+				}
+				target, err := player(c)
+				if err != nil {
+					return err
+				}
+				return media.Seek(c.Context(), target, offset)
+			},
+		})
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "position <time>",
+			Short: "Jump to a position in the current track",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				position, err := parseDuration(args[0])
+				if err != nil {
+					return err
+				}
+				target, err := player(c)
+				if err != nil {
+					return err
+				}
+				return media.SetPosition(c.Context(), target, position)
+			},
+		})
+
+		parent.AddCommand(&cobra.Command{
+			Use:   "volume <percent>",
+			Short: "Set the player volume",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				v, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "%"), 64)
+				if err != nil {
+					return fmt.Errorf("invalid volume %q", args[0])
+				}
+				target, err := player(c)
+				if err != nil {
+					return err
+				}
+				return media.SetVolume(c.Context(), target, v/100)
+			},
+		})
+
+		parent.AddCommand(&cobra.Command{
+			Use:       "shuffle [on|off|toggle]",
+			Short:     "Set or toggle shuffle",
+			Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+			ValidArgs: []string{"on", "off", "toggle"},
+			RunE: func(c *cobra.Command, args []string) error {
+				mode := "toggle"
+				if len(args) == 1 {
+					mode = args[0]
+				}
+				target, err := player(c)
+				if err != nil {
+					return err
+				}
+				if mode == "toggle" {
+					_, err := media.ToggleShuffle(c.Context(), target)
+					return err
+				}
+				return media.SetShuffle(c.Context(), target, mode == "on")
+			},
 		})
+
+		parent.AddCommand(&cobra.Command{
+			Use:       "loop <none|track|playlist>",
+			Short:     "Set the loop mode",
+			Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+			ValidArgs: []string{"none", "track", "playlist"},
+			RunE: func(c *cobra.Command, args []string) error {
+				loop := map[string]media.LoopStatus{"none": media.LoopNone, "track": media.LoopTrack, "playlist": media.LoopPlaylist}[args[0]]
+				target, err := player(c)
+				if err != nil {
+					return err
+				}
+				return media.SetLoop(c.Context(), target, loop)
+			},
+		})
+
+		watch := &cobra.Command{
+			Use:   "watch",
+			Short: "Print the now-playing state on every change",
+			Long: `Print the target player's state whenever the track, playback status,
+volume, shuffle or loop changes, until interrupted. With --json every change is
+one JSON object per line (null when no player is left), ready for status bars.
+
+Examples:
+  workspaced driver media watch --json
+  workspaced driver media watch --player spotify`,
+			Args: cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				asJSON, err := c.Flags().GetBool("json")
+				if err != nil {
+					return err
+				}
+				target, err := player(c)
+				if err != nil {
+					return err
+				}
+				enc := json.NewEncoder(c.OutOrStdout())
+				err = media.WatchMetadata(c.Context(), target, func(meta *media.Metadata) {
+					if asJSON {
+						_ = enc.Encode(meta)
+						return
+					}
+					if meta == nil {
+						fmt.Fprintln(c.OutOrStdout(), "no player")
+						return
+					}
+					fmt.Fprintf(c.OutOrStdout(), "[%s] %s - %s (%s)\n", meta.Status, meta.Artist, meta.Title, meta.Player)
+				})
+				if c.Context().Err() != nil {
+					return nil
+				}
+				return err
+			},
+		}
+		watch.Flags().Bool("json", false, "Output one JSON object per change")
+		parent.AddCommand(watch)
+	})
+}
+
+func player(c *cobra.Command) (string, error) {
+	return c.Flags().GetString("player")
+}
+
+// parseDuration reads plain seconds ("10", "-2.5") or a Go duration ("1m30s").
+func parseDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
 	}
-	addAction("next", "Next media", "next")
-	addAction("previous", "Previous media", "previous")
-	addAction("play-pause", "Play or pause media", "play-pause")
-	addAction("stop", "Stop media", "stop")
-	addAction("show", "Show media metadata", "show")
+	return d, nil
 }
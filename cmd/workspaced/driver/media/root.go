@@ -13,5 +13,6 @@ func GetCommand() *cobra.Command {
 		Use:   "media",
 		Short: "Control media playback",
 	}
+	cmd.PersistentFlags().StringP("player", "p", "", "Target player (name or its part before the first dot; default: most recently active)")
 	return Registry.FillCommands(cmd)
 }
@@ -1,6 +1,10 @@
 package notification
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/lucasew/workspaced/pkg/driver/notification"
 
 	"github.com/spf13/cobra"
@@ -12,20 +16,67 @@ func GetCommand() *cobra.Command {
 	var icon string
 	var urgency string
 	var progress float64
+	var actions []string
+	var timeout time.Duration
+	var persistent bool
+	var category string
+	var hints []string
+	var wait bool
 
 	cmd := &cobra.Command{
 		Use:   "notification",
 		Short: "Send a desktop notification",
+		Long: `Send a desktop notification.
+
+With --wait the command blocks until the notification closes and prints the
+key of the action the user picked, if any.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			n := &notification.Notification{
-				Title:    title,
-				Message:  message,
-				Icon:     icon,
-				Urgency:  urgency,
-				Progress: progress,
+				Title:       title,
+				Message:     message,
+				Icon:        icon,
+				Urgency:     urgency,
+				Progress:    progress,
+				HasProgress: cmd.Flags().Changed("progress"),
+				Timeout:     timeout,
+				Category:    category,
+			}
+			if persistent {
+				n.Timeout = notification.NeverExpire
+			}
+			for _, a := range actions {
+				key, label, ok := strings.Cut(a, "=")
+				if !ok {
+					label = key
+				}
+				n.Actions = append(n.Actions, notification.Action{Key: key, Label: label})
+			}
+			for _, h := range hints {
+				name, value, ok := strings.Cut(h, "=")
+				if !ok {
+					return fmt.Errorf("invalid hint %q: expected name=value", h)
+				}
+				if n.Hints == nil {
+					n.Hints = map[string]any{}
+				}
+				n.Hints[name] = value
+			}
+			if !wait {
+				return notification.Notify(ctx, n)
+			}
+
+			events, err := notification.NotifyEvents(ctx, n)
+			if err != nil {
+				return err
+			}
+			for ev := range events {
+				if ev.Kind == notification.EventAction {
+					fmt.Fprintln(cmd.OutOrStdout(), ev.Action)
+					return nil
+				}
 			}
-			return notification.Notify(ctx, n)
+			return nil
 		},
 	}
 
@@ -34,6 +85,12 @@ func GetCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&icon, "icon", "i", "", "Notification icon")
 	cmd.Flags().StringVarP(&urgency, "urgency", "u", "normal", "Notification urgency (low, normal, critical)")
 	cmd.Flags().Float64VarP(&progress, "progress", "p", 0, "Notification progress (0.0-1.0)")
+	cmd.Flags().StringArrayVarP(&actions, "action", "a", nil, "Action button as key=label (repeatable, key \"default\" is a click)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Expire after this long (0 uses the server default)")
+	cmd.Flags().BoolVar(&persistent, "persistent", false, "Never expire the notification")
+	cmd.Flags().StringVarP(&category, "category", "c", "", "Notification category (e.g. device, transfer.complete)")
+	cmd.Flags().StringArrayVar(&hints, "hint", nil, "Extra string hint as name=value (repeatable)")
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait for the notification to close and print the chosen action")
 
 	return cmd
 }
@@ -11,6 +11,7 @@ func init() {
 	Registry.Add("reboot", "Reboot the system", power.Reboot)
 	Registry.Add("shutdown", "Power off the system", power.Shutdown)
 	Registry.Add("suspend", "Suspend the system", power.Suspend)
+	Registry.Add("hibernate", "Hibernate the system", power.Hibernate)
 	Registry.Register(func(parent *cobra.Command) {
 		parent.AddCommand(&cobra.Command{
 			Use:   "wake <host>",
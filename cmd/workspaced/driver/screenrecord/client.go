@@ -0,0 +1,66 @@
+package screenrecord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/types"
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+	"github.com/lucasew/workspaced/pkg/logging"
+
+	"github.com/gorilla/websocket"
+)
+
+// callDaemon runs req on the daemon's recorder. ok is false when the
+// daemon is not reachable.
+func callDaemon(ctx context.Context, req types.ScreenrecordRequest) (st screenrecord.Status, ok bool, err error) {
+	socketPath := types.DaemonSocketPath()
+	dialer := websocket.Dialer{
+		NetDial: func(network, addr string) (net.Conn, error) {
+			return net.DialTimeout("unix", socketPath, 200*time.Millisecond)
+		},
+	}
+	conn, resp, err := dialer.DialContext(ctx, "ws://localhost/ws", nil)
+	if err != nil {
+		logging.GetLogger(ctx).Debug("daemon not reachable", "error", err)
+		return st, false, nil
+	}
+	if resp != nil && resp.Body != nil {
+		logging.Close(ctx, resp.Body)
+	}
+	defer logging.Close(ctx, conn, "socket", socketPath)
+
+	req.Env = os.Environ()
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return st, true, err
+	}
+	if err := conn.WriteJSON(types.StreamPacket{Type: "screenrecord", Payload: payload}); err != nil {
+		return st, true, fmt.Errorf("send request: %w", err)
+	}
+	for {
+		var packet types.StreamPacket
+		if err := conn.ReadJSON(&packet); err != nil {
+			return st, true, fmt.Errorf("read response: %w", err)
+		}
+		if packet.Type != "result" {
+			continue
+		}
+		var result types.Response
+		if err := json.Unmarshal(packet.Payload, &result); err != nil {
+			return st, true, fmt.Errorf("parse result: %w", err)
+		}
+		if result.Error != "" {
+			return st, true, errors.New(result.Error)
+		}
+		if err := json.Unmarshal([]byte(result.Output), &st); err != nil {
+			return st, true, fmt.Errorf("parse status: %w", err)
+		}
+		return st, true, nil
+	}
+}
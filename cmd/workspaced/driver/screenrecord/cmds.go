@@ -0,0 +1,99 @@
+package screenrecord
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lucasew/workspaced/internal/types"
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+	"github.com/lucasew/workspaced/pkg/driver/screenshot"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		for _, op := range []struct{ use, short string }{
+			{"start", "Start recording"},
+			{"stop", "Stop recording and save the file"},
+			{"toggle", "Start recording, or stop the current recording"},
+		} {
+			parent.AddCommand(&cobra.Command{
+				Use:   op.use,
+				Short: op.short,
+				Args:  cobra.NoArgs,
+				RunE: func(c *cobra.Command, args []string) error {
+					st, err := run(c, op.use)
+					if errors.Is(err, dapi.ErrCanceled) {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					c.Println(st.Path)
+					return nil
+				},
+			})
+		}
+		parent.AddCommand(&cobra.Command{
+			Use:   "status",
+			Short: "Print the recorder status as JSON",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				st, err := run(c, "status")
+				if err != nil {
+					return err
+				}
+				return json.NewEncoder(c.OutOrStdout()).Encode(st)
+			},
+		})
+	})
+}
+
+func run(c *cobra.Command, op string) (screenrecord.Status, error) {
+	req, err := requestFromFlags(c, op)
+	if err != nil {
+		return screenrecord.Status{}, err
+	}
+	st, ok, err := callDaemon(c.Context(), req)
+	if ok {
+		return st, err
+	}
+	switch op {
+	case "start", "toggle":
+		target, err := screenshot.ParseTarget(req.Target)
+		if err != nil {
+			return st, err
+		}
+		ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		c.PrintErrln("no daemon running, recording in the foreground; press Ctrl-C to stop")
+		return screenrecord.Run(ctx, screenrecord.StartOptions{Target: target, Audio: req.Audio, Format: req.Format})
+	case "status":
+		return screenrecord.Status{}, nil
+	}
+	return st, screenrecord.ErrNoDaemon
+}
+
+func requestFromFlags(c *cobra.Command, op string) (types.ScreenrecordRequest, error) {
+	req := types.ScreenrecordRequest{Op: op}
+	var err error
+	if req.Target, err = c.Flags().GetString("target"); err != nil {
+		return req, err
+	}
+	if req.Format, err = c.Flags().GetString("format"); err != nil {
+		return req, err
+	}
+	if c.Flags().Changed("audio") {
+		audio, err := c.Flags().GetBool("audio")
+		if err != nil {
+			return req, err
+		}
+		req.Audio = &audio
+	}
+	return req, nil
+}
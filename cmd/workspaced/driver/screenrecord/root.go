@@ -0,0 +1,25 @@
+package screenrecord
+
+import (
+	"github.com/lucasew/workspaced/internal/cmdregistry"
+
+	"github.com/spf13/cobra"
+)
+
+var Registry cmdregistry.CommandRegistry
+
+func GetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "screenrecord",
+		Short: "Screen recording management",
+		Long: `Record the screen with wf-recorder (Wayland) or ffmpeg (X11).
+
+The recorder runs inside the daemon, so start, stop and toggle can be bound
+to keys. Without a daemon, start and toggle record in the foreground until
+interrupted.`,
+	}
+	cmd.PersistentFlags().String("target", "all", "What to record: all, output, window or select")
+	cmd.PersistentFlags().Bool("audio", false, "Record the default audio source (default from config)")
+	cmd.PersistentFlags().String("format", "", "Container: mp4, mkv or webm (default from config)")
+	return Registry.FillCommands(cmd)
+}
@@ -27,12 +27,15 @@ func init() {
 				Use:   cc.use,
 				Short: cc.short,
 				RunE: func(c *cobra.Command, args []string) error {
-					path, err := screenshot.Capture(c.Context(), cc.target)
+					then, err := thenFromFlags(c)
 					if err != nil {
 						return err
 					}
-					c.Println(path)
-					return nil
+					path, err := screenshot.CaptureThen(c.Context(), cc.target, then)
+					if path != "" {
+						c.Println(path) // saved even when a post-capture action failed
+					}
+					return err
 				},
 			})
 		}
@@ -40,18 +43,32 @@ func init() {
 			Use:   "select",
 			Short: "Capture selected area",
 			RunE: func(c *cobra.Command, args []string) error {
-				path, err := screenshot.Capture(c.Context(), screenshot.TargetSelection)
+				then, err := thenFromFlags(c)
 				if err != nil {
-					if errors.Is(err, dapi.ErrCanceled) {
-						return nil
-					}
 					return err
 				}
+				path, err := screenshot.CaptureThen(c.Context(), screenshot.TargetSelection, then)
 				if path != "" {
 					c.Println(path)
 				}
-				return nil
+				if errors.Is(err, dapi.ErrCanceled) {
+					return nil
+				}
+				return err
 			},
 		})
 	})
 }
+
+// thenFromFlags returns the --then actions, nil when the flag is unset and
+// empty (run nothing) when it was given as "".
+func thenFromFlags(c *cobra.Command) ([]string, error) {
+	if !c.Flags().Changed("then") {
+		return nil, nil
+	}
+	then, err := c.Flags().GetStringSlice("then")
+	if then == nil {
+		then = []string{}
+	}
+	return then, err
+}
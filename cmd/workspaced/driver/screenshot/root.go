@@ -13,5 +13,6 @@ func GetCommand() *cobra.Command {
 		Use:   "screenshot",
 		Short: "Screen capture management",
 	}
+	cmd.PersistentFlags().StringSlice("then", nil, "Post-capture actions to run, in order (e.g. ocr,copy; default from screenshot.then)")
 	return Registry.FillCommands(cmd)
 }
@@ -12,7 +12,16 @@ func init() {
 			Use:   "apod",
 			Short: "Fetch NASA Astronomy Picture of the Day and set as wallpaper",
 			RunE: func(c *cobra.Command, args []string) error {
-				return wallpaper.SetAPOD(c.Context())
+				ctx := c.Context()
+				path, err := wallpaper.FetchAPOD(ctx)
+				if err != nil {
+					return err
+				}
+				if err := wallpaper.SetStatic(ctx, path); err != nil {
+					return err
+				}
+				retheme(ctx, path)
+				return nil
 			},
 		})
 	})
@@ -12,11 +12,21 @@ func init() {
 			Use:   "change [path]",
 			Short: "Change wallpaper to a random image or specific path",
 			RunE: func(c *cobra.Command, args []string) error {
-				path := ""
+				ctx := c.Context()
+				var path string
 				if len(args) > 0 {
 					path = args[0]
+				} else {
+					var err error
+					if path, err = wallpaper.Random(ctx); err != nil {
+						return err
+					}
 				}
-				return wallpaper.SetStatic(c.Context(), path)
+				if err := wallpaper.SetStatic(ctx, path); err != nil {
+					return err
+				}
+				retheme(ctx, path)
+				return nil
 			},
 		})
 	})
@@ -0,0 +1,18 @@
+package wallpaper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lucasew/workspaced/internal/autotheme"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// retheme follows the new wallpaper with the desktop palette when
+// desktop.wallpaper.theme is enabled. The wallpaper is already set by then,
+// so failures are reported rather than failing the command.
+func retheme(ctx context.Context, path string) {
+	if err := autotheme.OnWallpaper(ctx, path); err != nil {
+		logging.ReportError(ctx, fmt.Errorf("theme from wallpaper: %w", err), "path", path)
+	}
+}
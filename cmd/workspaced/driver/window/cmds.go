@@ -0,0 +1,125 @@
+package window
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lucasew/workspaced/pkg/driver/wm"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		parent.AddCommand(&cobra.Command{
+			Use:   "list",
+			Short: "List windows as JSON",
+			Long: `List the windows matching the criteria flags as a JSON array.
+
+Examples:
+  workspaced driver window list
+  workspaced driver window list --app-id '^firefox$'`,
+			Args: cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				criteria, err := criteriaFromFlags(c)
+				if err != nil {
+					return err
+				}
+				windows, err := wm.ListWindows(c.Context(), criteria)
+				if err != nil {
+					return err
+				}
+				if windows == nil {
+					windows = []wm.Window{}
+				}
+				return printJSON(c, windows)
+			},
+		})
+		parent.AddCommand(&cobra.Command{
+			Use:   "focus",
+			Short: "Focus a matching window",
+			Long: `Focus the first window matching the criteria flags. When the focused
+window already matches, the next match is focused, so binding this to a key
+cycles through e.g. all terminals.
+
+Examples:
+  workspaced driver window focus --app-id kitty
+  workspaced driver window focus --title 'YouTube'`,
+			Args: cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				criteria, err := criteriaFromFlags(c)
+				if err != nil {
+					return err
+				}
+				w, err := wm.FocusWindow(c.Context(), criteria)
+				if err != nil {
+					return err
+				}
+				return printJSON(c, w)
+			},
+		})
+		parent.AddCommand(&cobra.Command{
+			Use:   "move <workspace>",
+			Short: "Move matching windows to a workspace",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(c *cobra.Command, args []string) error {
+				criteria, err := criteriaFromFlags(c)
+				if err != nil {
+					return err
+				}
+				windows, err := wm.MoveWindows(c.Context(), criteria, args[0])
+				if err != nil {
+					return err
+				}
+				return printJSON(c, windows)
+			},
+		})
+		parent.AddCommand(&cobra.Command{
+			Use:   "close",
+			Short: "Close matching windows",
+			Args:  cobra.NoArgs,
+			RunE: func(c *cobra.Command, args []string) error {
+				criteria, err := criteriaFromFlags(c)
+				if err != nil {
+					return err
+				}
+				if criteria == (wm.Criteria{}) {
+					return fmt.Errorf("refusing to close every window: pass at least one criteria flag")
+				}
+				windows, err := wm.CloseWindows(c.Context(), criteria)
+				if err != nil {
+					return err
+				}
+				return printJSON(c, windows)
+			},
+		})
+	})
+}
+
+func criteriaFromFlags(c *cobra.Command) (wm.Criteria, error) {
+	var cr wm.Criteria
+	var err error
+	flags := c.Flags()
+	if cr.AppID, err = flags.GetString("app-id"); err != nil {
+		return cr, err
+	}
+	if cr.Title, err = flags.GetString("title"); err != nil {
+		return cr, err
+	}
+	if cr.Workspace, err = flags.GetString("workspace"); err != nil {
+		return cr, err
+	}
+	if cr.ID, err = flags.GetInt64("id"); err != nil {
+		return cr, err
+	}
+	if cr.Focused, err = flags.GetBool("focused"); err != nil {
+		return cr, err
+	}
+	return cr, nil
+}
+
+func printJSON(c *cobra.Command, v any) error {
+	enc := json.NewEncoder(c.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
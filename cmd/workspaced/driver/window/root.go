@@ -0,0 +1,22 @@
+package window
+
+import (
+	"github.com/lucasew/workspaced/internal/cmdregistry"
+
+	"github.com/spf13/cobra"
+)
+
+var Registry cmdregistry.CommandRegistry
+
+func GetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "window",
+		Short: "Window listing and manipulation commands",
+	}
+	cmd.PersistentFlags().String("app-id", "", "Regex matched against the app id or X11 class")
+	cmd.PersistentFlags().String("title", "", "Regex matched against the window title")
+	cmd.PersistentFlags().String("workspace", "", "Regex matched against the workspace name")
+	cmd.PersistentFlags().Int64("id", 0, "Select a single window by id")
+	cmd.PersistentFlags().Bool("focused", false, "Only match the focused window")
+	return Registry.FillCommands(cmd)
+}
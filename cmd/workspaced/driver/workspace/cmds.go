@@ -1,6 +1,10 @@
 package workspace
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+
 	"github.com/lucasew/workspaced/pkg/driver/wm"
 
 	"github.com/spf13/cobra"
@@ -33,5 +37,40 @@ func init() {
 				return wm.NextWorkspace(c.Context(), move)
 			},
 		})
+		parent.AddCommand(watchCommand())
 	})
 }
+
+func watchCommand() *cobra.Command {
+	var kinds []string
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print window manager events as JSON lines",
+		Long: `Subscribe to window manager events and print one JSON object per line
+until interrupted.
+
+Examples:
+  workspaced driver workspace watch
+  workspaced driver workspace watch --kind output --kind workspace`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			var filter []wm.EventKind
+			for _, k := range kinds {
+				filter = append(filter, wm.EventKind(k))
+			}
+			ctx, cancel := context.WithCancel(c.Context())
+			defer cancel()
+			enc := json.NewEncoder(c.OutOrStdout())
+			var encErr error
+			err := wm.Watch(ctx, func(ev wm.Event) {
+				ev.Payload = nil
+				if encErr = enc.Encode(ev); encErr != nil {
+					cancel() // stdout is gone (closed pipe)
+				}
+			}, filter...)
+			return errors.Join(err, encErr)
+		},
+	}
+	cmd.Flags().StringArrayVar(&kinds, "kind", nil, "Event kind to watch: workspace, window or output (repeatable; default all)")
+	return cmd
+}
@@ -0,0 +1,97 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/lucasew/workspaced/pkg/driver/wm"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		layout := &cobra.Command{
+			Use:   "layout",
+			Short: "Workspace-to-output layouts (workspaced.desktop.layouts)",
+		}
+		layout.AddCommand(layoutApplyCommand())
+		parent.AddCommand(layout)
+	})
+}
+
+func layoutApplyCommand() *cobra.Command {
+	var (
+		name   string
+		dryRun bool
+		asJSON bool
+	)
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the layout matching the connected outputs",
+		Long: `Pick the workspaced.desktop.layouts entry that fits the connected outputs,
+position and scale its outputs, and move its workspaces onto them. The
+daemon does the same on every output hotplug.
+
+Examples:
+  workspaced driver workspace layout apply
+  workspaced driver workspace layout apply --dry-run --json
+  workspaced driver workspace layout apply --layout docked`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := c.Context()
+			lc, ok, err := wm.LoadLayoutConfig(ctx)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("no layouts configured in workspaced.desktop.layouts")
+			}
+			var plan *wm.LayoutPlan
+			if dryRun {
+				plan, err = wm.PlanLayout(ctx, lc, name)
+			} else {
+				plan, err = wm.ApplyLayout(ctx, lc, name)
+			}
+			if plan != nil {
+				if printErr := printPlan(c, plan, asJSON); printErr != nil {
+					return errors.Join(err, printErr)
+				}
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&name, "layout", "", "Apply this layout instead of the best match")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the plan without changing anything")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the plan as JSON")
+	return cmd
+}
+
+func printPlan(c *cobra.Command, plan *wm.LayoutPlan, asJSON bool) error {
+	out := c.OutOrStdout()
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	if _, err := fmt.Fprintf(out, "layout %s\n", plan.Layout); err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(plan.Outputs)) {
+		output := plan.Outputs[key]
+		var workspaces []string
+		for ws, o := range plan.Workspaces {
+			if o == output {
+				workspaces = append(workspaces, ws)
+			}
+		}
+		slices.Sort(workspaces)
+		if _, err := fmt.Fprintf(out, "  %s -> %s %v\n", key, output, workspaces); err != nil {
+			return err
+		}
+	}
+	return nil
+}
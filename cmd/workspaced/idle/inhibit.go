@@ -0,0 +1,66 @@
+package idle
+
+import (
+	"os"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/idle"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/taskgroup"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		var why string
+		cmd := &cobra.Command{
+			Use:   "inhibit [-- command [args...]]",
+			Short: "Hold off the idle policy while a command runs",
+			Long: `Hold off dimming, locking, DPMS and suspend of ` + "`workspaced svc idle`" + `
+while a command runs, or until interrupted when no command is given.
+
+Examples:
+  workspaced idle inhibit -- mpv movie.mkv
+  workspaced idle inhibit --why "presenting"`,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				reason := why
+				if reason == "" && len(args) > 0 {
+					reason = strings.Join(args, " ")
+				}
+				if reason == "" {
+					reason = "workspaced idle inhibit"
+				}
+				release, err := idle.Inhibit(idle.InhibitDir(), reason)
+				if err != nil {
+					return err
+				}
+
+				if len(args) == 0 {
+					defer func() { _ = release() }()
+					<-ctx.Done()
+					return nil
+				}
+				command, err := execdriver.Run(ctx, args[0], args[1:]...)
+				if err != nil {
+					_ = release()
+					return err
+				}
+				// Run after the session tears down its UI so the child gets
+				// the real stdio; the inhibitor lives until it exits.
+				taskgroup.MustSessionFrom(ctx).AfterWait(func() error {
+					defer func() { _ = release() }()
+					command.Stdin = os.Stdin
+					command.Stdout = os.Stdout
+					command.Stderr = os.Stderr
+					return command.Run()
+				})
+				return nil
+			},
+		}
+		cmd.Flags().StringVar(&why, "why", "", "Reason shown by `workspaced idle status`")
+		cmd.Flags().SetInterspersed(false)
+		parent.AddCommand(cmd)
+	})
+}
@@ -0,0 +1,17 @@
+package idle
+
+import (
+	"github.com/lucasew/workspaced/internal/cmdregistry"
+
+	"github.com/spf13/cobra"
+)
+
+var Registry cmdregistry.CommandRegistry
+
+func GetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "idle",
+		Short: "Idle policy inhibitors and status",
+	}
+	return Registry.FillCommands(cmd)
+}
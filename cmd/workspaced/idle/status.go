@@ -0,0 +1,58 @@
+package idle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/idle"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		parent.AddCommand(&cobra.Command{
+			Use:   "status",
+			Short: "Show the idle policy and the held inhibitors",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				cfg, err := idle.LoadConfig(ctx)
+				if err != nil {
+					return err
+				}
+				policy, err := cfg.Policy()
+				if err != nil {
+					return err
+				}
+				out := cmd.OutOrStdout()
+				for _, s := range []struct {
+					name  string
+					after time.Duration
+				}{
+					{"dim", policy.Dim},
+					{"lock", policy.Lock},
+					{"dpms", policy.DPMS},
+					{"suspend", policy.Suspend},
+				} {
+					if s.after == 0 {
+						fmt.Fprintf(out, "%-8s off\n", s.name)
+					} else {
+						fmt.Fprintf(out, "%-8s %s\n", s.name, s.after)
+					}
+				}
+
+				inhibitors, err := idle.Inhibitors(idle.InhibitDir())
+				if err != nil {
+					return err
+				}
+				if len(inhibitors) == 0 {
+					fmt.Fprintln(out, "no inhibitors")
+				}
+				for _, i := range inhibitors {
+					fmt.Fprintf(out, "inhibited by %d: %s\n", i.PID, i.Reason)
+				}
+				return nil
+			},
+		})
+	})
+}
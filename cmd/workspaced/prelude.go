@@ -6,6 +6,7 @@ import (
 	pkg_driver "github.com/lucasew/workspaced/cmd/workspaced/driver"
 	pkg_experiments "github.com/lucasew/workspaced/cmd/workspaced/experiments"
 	pkg_home "github.com/lucasew/workspaced/cmd/workspaced/home"
+	pkg_idle "github.com/lucasew/workspaced/cmd/workspaced/idle"
 	pkg_init "github.com/lucasew/workspaced/cmd/workspaced/init"
 	pkg_is "github.com/lucasew/workspaced/cmd/workspaced/is"
 	pkg_mod "github.com/lucasew/workspaced/cmd/workspaced/mod"
@@ -23,6 +24,7 @@ func init() {
 	Registry.FromGetter(pkg_driver.GetCommand)
 	Registry.FromGetter(pkg_experiments.GetCommand)
 	Registry.FromGetter(pkg_home.GetCommand)
+	Registry.FromGetter(pkg_idle.GetCommand)
 	Registry.FromGetter(pkg_init.GetCommand)
 	Registry.FromGetter(pkg_is.GetCommand)
 	Registry.FromGetter(pkg_mod.GetCommand)
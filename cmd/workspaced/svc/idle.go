@@ -0,0 +1,60 @@
+package svc
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/internal/idle"
+	idledriver "github.com/lucasew/workspaced/pkg/driver/idle"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func init() {
+	Registry.Register(func(parent *cobra.Command) {
+		parent.AddCommand(&cobra.Command{
+			Use:   "idle",
+			Short: "Dim, lock, power off the screen and suspend after inactivity",
+			Long: `Apply workspaced.idle: dim the backlight, lock the session, turn the
+displays off with DPMS and, on battery, suspend after each configured
+stretch without input. A playing media player or ` + "`workspaced idle inhibit`" + `
+holds every step off.`,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runIdle(cmd.Context())
+			},
+		})
+	})
+}
+
+func runIdle(ctx context.Context) error {
+	cfg, err := idle.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	policy, err := cfg.Policy()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan idledriver.Event)
+	watchErr := make(chan error, 1)
+	go func() {
+		defer close(events)
+		watchErr <- idledriver.Watch(ctx, func(ev idledriver.Event) {
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	logging.GetLogger(ctx).Info("idle policy", "dim", policy.Dim, "lock", policy.Lock, "dpms", policy.DPMS, "suspend", policy.Suspend)
+	if err := idle.Run(ctx, idle.NewMachine(policy, time.Now()), idle.SystemClock{}, idle.NewDriverEffects(policy), events); err != nil {
+		return err
+	}
+	cancel()
+	return <-watchErr
+}
@@ -0,0 +1,137 @@
+package tool
+
+import (
+	"fmt"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/modfile"
+	"github.com/lucasew/workspaced/internal/tool"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(c *cobra.Command) {
+		bundle := &cobra.Command{
+			Use:   "bundle",
+			Short: "Export and import offline tool bundles",
+			Long: `Move pinned tools to hosts without network access.
+
+"bundle export" installs every tool pinned in a workspace lockfile for the
+requested platforms and writes them, plus the lock metadata, to a tarball.
+"bundle import" unpacks a bundle into the local tool store after checking
+each tree against the importing workspace's lockfile and its recorded
+digest, so lazy tools resolve without HTTP.`,
+		}
+
+		var platforms []string
+		var home bool
+		export := &cobra.Command{
+			Use:   "export <out.tar>",
+			Short: "Write the workspace's pinned tools to a bundle",
+			Long: `Install every tool pinned in the workspace lockfile into a staging
+directory, once per --platform (default: this host), and write it to
+<out.tar> with a manifest. Foreign platforms need a backend that exposes
+release artifacts; other tools are listed as skipped.`,
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				var pairs []tool.BundlePlatform
+				for _, raw := range platforms {
+					p, err := tool.ParseBundlePlatform(raw)
+					if err != nil {
+						return err
+					}
+					pairs = append(pairs, p)
+				}
+
+				var ws *modfile.Workspace
+				var cfg *configcue.Config
+				if home {
+					root, err := envdriver.GetDotfilesRoot(ctx)
+					if err != nil {
+						return err
+					}
+					ws = modfile.NewWorkspace(root)
+					if cfg, err = configcue.LoadHome(ctx); err != nil {
+						return err
+					}
+				} else {
+					var err error
+					if ws, err = modfile.DetectWorkspace(ctx, ""); err != nil {
+						return err
+					}
+					if cfg, err = configcue.LoadForWorkspace(ctx, ws.Root); err != nil {
+						return err
+					}
+				}
+
+				manifest, err := tool.ExportBundle(ctx, ws, cfg, args[0], pairs)
+				if err != nil {
+					return err
+				}
+				out := cmd.OutOrStdout()
+				for _, e := range manifest.Tools {
+					fmt.Fprintf(out, "%s@%s %s\n", e.Ref, e.Version, e.BundlePlatform)
+				}
+				for _, s := range manifest.Skipped {
+					fmt.Fprintf(out, "skipped %s@%s %s: %s\n", s.Ref, s.Version, s.BundlePlatform, s.Reason)
+				}
+				return nil
+			},
+		}
+		export.Flags().StringSliceVar(&platforms, "platform", nil, "Target os/arch (repeatable; default: this host)")
+		export.Flags().BoolVar(&home, "home", false, "Bundle the home/dotfiles lockfile instead of the current workspace")
+
+		var platform string
+		var importHome bool
+		importCmd := &cobra.Command{
+			Use:   "import <bundle.tar>",
+			Short: "Populate the tool store from a bundle",
+			Long: `Unpack <bundle.tar> into the local tool store. Only trees whose ref,
+version and upstream digest match the workspace lockfile are accepted;
+a bundle tool the lockfile does not pin aborts the import.`,
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				target := tool.HostPlatform()
+				if platform != "" {
+					var err error
+					if target, err = tool.ParseBundlePlatform(platform); err != nil {
+						return err
+					}
+				}
+				var ws *modfile.Workspace
+				if importHome {
+					root, err := envdriver.GetDotfilesRoot(ctx)
+					if err != nil {
+						return err
+					}
+					ws = modfile.NewWorkspace(root)
+				} else {
+					var err error
+					if ws, err = modfile.DetectWorkspace(ctx, ""); err != nil {
+						return err
+					}
+				}
+				entries, err := tool.ImportBundle(ctx, ws, args[0], target)
+				if err != nil {
+					return err
+				}
+				if len(entries) == 0 {
+					return fmt.Errorf("bundle has no tools for %s", target)
+				}
+				for _, e := range entries {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s@%s\n", e.Ref, e.Version)
+				}
+				return nil
+			},
+		}
+		importCmd.Flags().StringVar(&platform, "platform", "", "Import trees built for this os/arch (default: this host)")
+		importCmd.Flags().BoolVar(&importHome, "home", false, "Check against the home/dotfiles lockfile instead of the current workspace")
+
+		bundle.AddCommand(export, importCmd)
+		c.AddCommand(bundle)
+	})
+}
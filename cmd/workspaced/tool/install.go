@@ -23,11 +23,14 @@ Tool spec format:
   package                  (uses registry backend + latest; for curated short names e.g. ripgrep, uv)
 
 Bare names (no backend:) default to the registry backend (curated github tools).
-For mise-managed tools (e.g. go, node) or direct github use 'mise:' or 'github:'.`,
+For mise-managed tools (e.g. go, node) or direct github use 'mise:' or 'github:'.
+Go programs shipped only as 'go install' targets use 'go:module/path/cmd/foo'
+(built from GOPROXY with the golang toolchain).`,
 			Example: `  workspaced tool install github:denoland/deno@1.40.0
   workspaced tool install ripgrep@14.0.0
   workspaced tool install uv
-  workspaced tool install mise:go@latest`,
+  workspaced tool install mise:go@latest
+  workspaced tool install go:golang.org/x/tools/cmd/goimports@v0.30.0`,
 			Args: cobra.ExactArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				manager, err := tool.NewManager()
@@ -0,0 +1,55 @@
+package tool
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/lucasew/workspaced/internal/tool"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(c *cobra.Command) {
+		var all bool
+		cmd := &cobra.Command{
+			Use:   "outdated",
+			Short: "Compare locked lazy tools with upstream versions",
+			Long: `Compare every locked lazy tool in the home lockfile and in the current
+codebase lockfile with the versions its backend reports upstream.
+
+WANTED is the newest version allowed by the tool's "constraint" in CUE (what
+"tool upgrade" would lock); LATEST ignores the constraint. Tools already at
+LATEST are hidden unless --all is given. No lockfile is modified.`,
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				scopes, err := tool.LazyToolScopes(ctx)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "SCOPE\tTOOL\tCURRENT\tWANTED\tLATEST\tCONSTRAINT")
+				for _, scope := range scopes {
+					updates, err := tool.CheckLazyTools(ctx, scope.Workspace, scope.Config)
+					if err != nil {
+						return fmt.Errorf("%s: %w", scope.Name, err)
+					}
+					for _, u := range updates {
+						if !all && !u.Outdated() && u.Current == u.Latest {
+							continue
+						}
+						constraint := u.Constraint
+						if constraint == "" {
+							constraint = "-"
+						}
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", scope.Name, u.Name, u.Current, u.Wanted, u.Latest, constraint)
+					}
+				}
+				return w.Flush()
+			},
+		}
+		cmd.Flags().BoolVar(&all, "all", false, "Also list tools that are already up to date")
+		c.AddCommand(cmd)
+	})
+}
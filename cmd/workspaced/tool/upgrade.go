@@ -0,0 +1,56 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lucasew/workspaced/internal/cmdctx"
+	"github.com/lucasew/workspaced/internal/tool"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Registry.Register(func(c *cobra.Command) {
+		c.AddCommand(&cobra.Command{
+			Use:   "upgrade [name]",
+			Short: "Bump locked lazy tools within their CUE constraints",
+			Long: `Move lazy tool locks in the home and current codebase lockfiles to the
+newest upstream version allowed by each tool's "constraint" (e.g. "^1.2",
+"~1.2.3", ">=1 <2"). Tools without a constraint move to latest; tools pinned
+with "version" and no constraint are left alone.
+
+Each lockfile is rewritten once, atomically. With a name, only that tool is
+upgraded. Use --dry-run to print the plan without writing.`,
+			Args: cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				scopes, err := tool.LazyToolScopes(ctx)
+				if err != nil {
+					return err
+				}
+				found := len(args) == 0
+				for _, scope := range scopes {
+					updates, err := tool.UpgradeLazyTools(ctx, scope.Workspace, scope.Config, args...)
+					if errors.Is(err, tool.ErrLazyToolNotFound) {
+						continue
+					}
+					if err != nil {
+						return fmt.Errorf("%s: %w", scope.Name, err)
+					}
+					found = true
+					for _, u := range updates {
+						fmt.Fprintf(cmd.OutOrStdout(), "%s: %s %s -> %s\n", scope.Name, u.Name, u.Current, u.Wanted)
+					}
+				}
+				if !found {
+					return fmt.Errorf("%w: %s", tool.ErrLazyToolNotFound, args[0])
+				}
+				if cmdctx.IsDryRun(ctx) {
+					fmt.Fprintln(cmd.OutOrStdout(), "dry-run: lockfiles not modified")
+				}
+				return nil
+			},
+		})
+	})
+}
@@ -35,6 +35,8 @@ Tool spec format:
 
 Bare names (no backend:) default to the registry backend (curated github tools).
 For mise-managed tools (e.g. go, node) or direct github use 'mise:' or 'github:'.
+Go programs shipped only as 'go install' targets use 'go:module/path/cmd/foo'
+(built from GOPROXY with the golang toolchain).
 
 Examples:
   workspaced tool with github:denoland/deno@1.40.0 -- deno run app.ts
@@ -0,0 +1,81 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/internal/cmdctx"
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/scheme"
+)
+
+func GetCommand() *cobra.Command {
+	var (
+		dirs  []string
+		clear bool
+	)
+	cmd := &cobra.Command{
+		Use:   "apply <scheme>",
+		Short: "Make a scheme the active desktop palette",
+		Long: `Record a scheme as the active desktop palette. It overrides
+workspaced.desktop.palette until 'palette apply --clear'.
+
+Templates pick it up as .palette on the next 'workspaced home apply'.`,
+		Args: func(c *cobra.Command, args []string) error {
+			if clear {
+				return cobra.NoArgs(c, args)
+			}
+			return cobra.ExactArgs(1)(c, args)
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := c.Context()
+			logger := logging.GetLogger(ctx)
+			dryRun := cmdctx.IsDryRun(ctx)
+
+			if clear {
+				if dryRun {
+					logger.Info("dry-run: would clear the applied palette")
+					return nil
+				}
+				return palette.ClearAppliedSource(ctx)
+			}
+
+			cfg, err := configcue.LoadHome(ctx)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			configured, err := palette.SchemeDirs(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			if len(dirs) == 0 {
+				dirs = configured
+			}
+			s, err := scheme.Find(ctx, args[0], dirs...)
+			if err != nil {
+				return err
+			}
+			// Templates resolve against the configured dirs; store the file
+			// path when the slug would not find this same scheme there.
+			name := s.Slug
+			if found, err := scheme.Find(ctx, name, configured...); err != nil || found.Path != s.Path {
+				name = s.Path
+			}
+			if dryRun {
+				logger.Info("dry-run: would apply palette", "scheme", name, "name", s.Name)
+				return nil
+			}
+			if err := palette.SetAppliedSource(ctx, palette.Source{Scheme: name}); err != nil {
+				return err
+			}
+			logger.Info("applied palette; run 'workspaced home apply' to re-render templates", "scheme", name, "name", s.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&dirs, "dir", nil, "Scheme directory (repeatable; replaces the configured ones)")
+	cmd.Flags().BoolVar(&clear, "clear", false, "Drop the applied scheme and fall back to workspaced.desktop.palette")
+	return cmd
+}
@@ -0,0 +1,154 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/contrast"
+)
+
+func GetCommand() *cobra.Command {
+	var (
+		levelName  string
+		fix        bool
+		dirs       []string
+		driverName string
+		polarity   string
+		colorCount int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check [palette.json|image|scheme|-]",
+		Short: "Check palette contrast and show the adjusted ratios",
+		Long: `Measure WCAG 2.x contrast ratio and APCA Lc for the base16 pairs
+(base05 on base00..02, accents base08..0F on base00/01, muted base03/04 on
+base00) and show each pair before and after lightness adjustment.
+
+Adjustment keeps each foreground's hue and chroma (HCT) and picks the passing
+tone closest to the original (CIE76 DeltaE); backgrounds never move.
+
+Without an argument the active desktop palette is checked; "-" reads palette
+JSON from stdin.
+
+Examples:
+  workspaced utils palette check
+  workspaced utils palette check gruvbox-dark --level aaa
+  workspaced utils palette generate wall.jpg | workspaced utils palette check - --fix > palette.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := c.Context()
+			level, err := contrast.ParseLevel(levelName)
+			if err != nil {
+				return err
+			}
+
+			pol, err := palette.ParsePolarity(polarity)
+			if err != nil {
+				return err
+			}
+			var arg string
+			if len(args) == 1 {
+				arg = args[0]
+			}
+			// Check what the source yields before its own adjustment.
+			resolved, err := palette.LoadInput(ctx, palette.Input{
+				Arg:     arg,
+				Stdin:   c.InOrStdin(),
+				Dirs:    dirs,
+				Driver:  driverName,
+				Options: api.Options{Polarity: pol, ColorCount: colorCount, MaxSamples: 10000},
+				Raw:     true,
+			})
+			if err != nil {
+				return err
+			}
+			pal := resolved.Palette
+
+			before, err := contrast.Check(pal, level)
+			if err != nil {
+				return err
+			}
+			fixed, changes, err := contrast.Fix(pal, level)
+			if err != nil {
+				return err
+			}
+			if fix {
+				encoder := json.NewEncoder(c.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(fixed)
+			}
+			after, err := contrast.Check(fixed, level)
+			if err != nil {
+				return err
+			}
+			return printReport(c, level, before, after, changes)
+		},
+	}
+
+	cmd.Flags().StringVar(&levelName, "level", "aa", "Target level: aa or aaa")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Print the adjusted palette as JSON instead of the report")
+	cmd.Flags().StringArrayVar(&dirs, "dir", nil, "Scheme directory (repeatable; replaces the configured ones)")
+	cmd.Flags().StringVar(&driverName, "driver", "genetic", "Extraction algorithm when the input is an image")
+	cmd.Flags().StringVar(&polarity, "polarity", "any", "Theme preference when the input is an image: dark, light, or any")
+	cmd.Flags().IntVar(&colorCount, "colors", 16, "Number of colors when the input is an image (16 or 24)")
+	if regErr := cmd.RegisterFlagCompletionFunc("level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"aa", "aaa"}, cobra.ShellCompDirectiveNoFileComp
+	}); regErr != nil {
+		// completion registration is best-effort
+	}
+	return cmd
+}
+
+func printReport(c *cobra.Command, level contrast.Level, before, after []contrast.Result, changes []contrast.Change) error {
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "PAIR\tROLE\tWCAG\tAPCA Lc\tTARGET\tSTATUS"); err != nil {
+		return err
+	}
+	for i, b := range before {
+		a := after[i]
+		status := "ok"
+		switch {
+		case b.Pass():
+		case a.Pass():
+			status = "fixed"
+		default:
+			status = "FAIL"
+		}
+		wcag := fmt.Sprintf("%.2f", b.WCAG)
+		apca := fmt.Sprintf("%.1f", b.APCA)
+		if a.FGHex != b.FGHex {
+			wcag += fmt.Sprintf(" -> %.2f", a.WCAG)
+			apca += fmt.Sprintf(" -> %.1f", a.APCA)
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f / %.0f\t%s\n", b.Pair, b.Pair.Role, wcag, apca, b.Target.WCAG, b.Target.APCA, status); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	out := c.OutOrStdout()
+	if len(changes) == 0 {
+		_, err := fmt.Fprintf(out, "\nall pairs meet %s\n", level)
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "\nadjusted for %s:\n", level); err != nil {
+		return err
+	}
+	for _, ch := range changes {
+		note := ""
+		if !ch.Met {
+			note = " (target not reachable at this hue)"
+		}
+		if _, err := fmt.Fprintf(out, "  %s  #%s -> #%s  DeltaE %.1f%s\n", ch.Slot, ch.From, ch.To, ch.DeltaE, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/export"
+)
+
+func GetCommand() *cobra.Command {
+	var (
+		format     string
+		name       string
+		driverName string
+		polarity   string
+		colorCount int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [palette.json|image]",
+		Short: "Export a palette as an application color config",
+		Long:  exportLongHelp(),
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := c.Context()
+			if _, err := export.Get(format); err != nil {
+				return err
+			}
+
+			var pal *api.Palette
+			switch {
+			case len(args) == 0 || args[0] == "-":
+				p, err := palette.DecodeJSON(c.InOrStdin())
+				if err != nil {
+					return err
+				}
+				pal = p
+			default:
+				pol, err := palette.ParsePolarity(polarity)
+				if err != nil {
+					return err
+				}
+				p, err := palette.LoadFile(ctx, args[0], driverName, api.Options{
+					Polarity:   pol,
+					ColorCount: colorCount,
+					MaxSamples: 10000,
+				})
+				if err != nil {
+					return err
+				}
+				pal = p
+			}
+
+			return export.Encode(c.OutOrStdout(), format, pal, export.Options{Name: name})
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "kitty", "Output format (see --help for the list)")
+	cmd.Flags().StringVar(&name, "name", export.DefaultName, "Scheme name embedded in formats that carry one")
+	cmd.Flags().StringVar(&driverName, "driver", "genetic", "Extraction algorithm when the input is an image")
+	cmd.Flags().StringVar(&polarity, "polarity", "any", "Theme preference when the input is an image: dark, light, or any")
+	cmd.Flags().IntVar(&colorCount, "colors", 16, "Number of colors when the input is an image (16 or 24)")
+	if regErr := cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return export.Names(), cobra.ShellCompDirectiveNoFileComp
+	}); regErr != nil {
+		// completion registration is best-effort
+	}
+	if regErr := cmd.RegisterFlagCompletionFunc("driver", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return palette.DriverNames(), cobra.ShellCompDirectiveNoFileComp
+	}); regErr != nil {
+		// completion registration is best-effort
+	}
+	return cmd
+}
+
+func exportLongHelp() string {
+	var b strings.Builder
+	b.WriteString(`Export a base16/base24 palette as an application color config.
+
+The input is palette JSON (as printed by 'palette generate'), read from a
+.json file or stdin, or an image to extract a palette from first.
+
+Formats:
+`)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, f := range export.List() {
+		fmt.Fprintf(w, "  %s\t%s\n", f.Name, f.Description)
+	}
+	if flushErr := w.Flush(); flushErr != nil {
+		// best-effort for help text formatting
+	}
+	b.WriteString(`
+Templates can use the same encoders: {{ paletteExport "kitty" .base16 }}
+
+Examples:
+  # kitty colors straight from a wallpaper
+  workspaced utils palette export ~/wallpaper.jpg --polarity dark > ~/.config/kitty/colors.conf
+
+  # Reuse a generated palette for several apps
+  workspaced utils palette generate ~/wallpaper.jpg > palette.json
+  workspaced utils palette export palette.json --format foot
+  workspaced utils palette export palette.json --format vim --name wallpaper > ~/.config/nvim/colors/wallpaper.vim`)
+	return b.String()
+}
@@ -32,7 +32,7 @@ func GetCommand() *cobra.Command {
 				return err
 			}
 
-			pol, err := parsePolarityFlag(polarity)
+			pol, err := palette.ParsePolarity(polarity)
 			if err != nil {
 				return err
 			}
@@ -89,20 +89,9 @@ Examples:
   workspaced utils palette generate ~/wallpaper.jpg --polarity dark
 
   # Material You scheme as base24
-  workspaced utils palette generate image.png --driver materialyou --colors 24 --polarity light`)
-	return b.String()
-}
+  workspaced utils palette generate image.png --driver materialyou --colors 24 --polarity light
 
-// parsePolarityFlag converts string flag to Polarity enum
-func parsePolarityFlag(s string) (api.Polarity, error) {
-	switch strings.ToLower(s) {
-	case "any":
-		return api.PolarityAny, nil
-	case "dark":
-		return api.PolarityDark, nil
-	case "light":
-		return api.PolarityLight, nil
-	default:
-		return api.PolarityAny, fmt.Errorf("invalid polarity: %s (must be 'dark', 'light', or 'any')", s)
-	}
+  # Fast deterministic clustering
+  workspaced utils palette generate ~/wallpaper.jpg --driver kmeans`)
+	return b.String()
 }
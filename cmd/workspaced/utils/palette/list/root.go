@@ -0,0 +1,75 @@
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/scheme"
+)
+
+func GetCommand() *cobra.Command {
+	var (
+		dirs     []string
+		jsonFlag bool
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List base16/base24 schemes in the scheme directories",
+		Long: `List tinted-theming scheme files (*.yaml) found in the scheme directories.
+
+Directories come from workspaced.desktop.palette_dirs, defaulting to
+<dotfiles>/palettes and ~/.local/share/workspaced/palettes. The active
+scheme (palette apply, else desktop.palette) is marked with '*'.`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			ctx := c.Context()
+			cfg, err := configcue.LoadHome(ctx)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if len(dirs) == 0 {
+				if dirs, err = palette.SchemeDirs(ctx, cfg); err != nil {
+					return err
+				}
+			}
+			schemes, err := scheme.List(ctx, dirs...)
+			if err != nil {
+				return err
+			}
+			if jsonFlag {
+				encoder := json.NewEncoder(c.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(schemes)
+			}
+
+			active := ""
+			if src, ok, err := palette.ConfigSource(ctx, cfg); err == nil && ok && src.Scheme != "" {
+				if s, err := scheme.Find(ctx, src.Scheme, dirs...); err == nil {
+					active = s.Slug
+				}
+			}
+			w := tabwriter.NewWriter(c.OutOrStdout(), 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(w, "\tSCHEME\tSYSTEM\tVARIANT\tNAME"); err != nil {
+				return err
+			}
+			for _, s := range schemes {
+				mark := ""
+				if s.Slug == active {
+					mark = "*"
+				}
+				if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", mark, s.Slug, s.System, s.Variant, s.Name); err != nil {
+					return err
+				}
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringArrayVar(&dirs, "dir", nil, "Scheme directory (repeatable; replaces the configured ones)")
+	cmd.Flags().BoolVar(&jsonFlag, "json", false, "Print schemes as JSON")
+	return cmd
+}
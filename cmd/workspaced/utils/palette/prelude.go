@@ -2,11 +2,23 @@ package palette
 
 // this file is generated by internal/devtools/autoregistry, do not edit manually
 import (
+	pkg_apply "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/apply"
+	pkg_check "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/check"
 	pkg_drivers "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/drivers"
+	pkg_export "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/export"
 	pkg_generate "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/generate"
+	pkg_list "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/list"
+	pkg_preview "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/preview"
+	pkg_show "github.com/lucasew/workspaced/cmd/workspaced/utils/palette/show"
 )
 
 func init() {
+	Registry.FromGetter(pkg_apply.GetCommand)
+	Registry.FromGetter(pkg_check.GetCommand)
 	Registry.FromGetter(pkg_drivers.GetCommand)
+	Registry.FromGetter(pkg_export.GetCommand)
 	Registry.FromGetter(pkg_generate.GetCommand)
+	Registry.FromGetter(pkg_list.GetCommand)
+	Registry.FromGetter(pkg_preview.GetCommand)
+	Registry.FromGetter(pkg_show.GetCommand)
 }
@@ -0,0 +1,110 @@
+package preview
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/internal/atomicfile"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/preview"
+)
+
+func GetCommand() *cobra.Command {
+	var (
+		output     string
+		compare    bool
+		dirs       []string
+		driverName string
+		polarity   string
+		colorCount int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "preview [palette.json|image|scheme|-]",
+		Short: "Render a palette as a swatch sheet or terminal color blocks",
+		Long: `Render a palette as truecolor blocks in the terminal, or as a PNG swatch
+sheet with hex labels when --output is given.
+
+With --compare the argument must be an image: every registered driver runs
+on it and the results are lined up slot by slot, one row per driver.
+
+Without an argument the active desktop palette is shown; "-" reads palette
+JSON from stdin.
+
+Examples:
+  workspaced utils palette preview
+  workspaced utils palette preview gruvbox-dark -o /tmp/gruvbox.png
+  workspaced utils palette preview ~/wallpaper.jpg --compare --polarity dark
+  workspaced utils palette preview ~/wallpaper.jpg --compare -o /tmp/drivers.png`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := c.Context()
+			pol, err := palette.ParsePolarity(polarity)
+			if err != nil {
+				return err
+			}
+			opts := api.Options{Polarity: pol, ColorCount: colorCount, MaxSamples: 10000}
+
+			if compare {
+				if len(args) != 1 || args[0] == "-" {
+					return errors.New("--compare needs an image argument")
+				}
+				img, err := palette.LoadImage(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				logger := logging.GetLogger(ctx)
+				var entries []preview.Entry
+				for _, d := range palette.ListDrivers() {
+					logger.Info("extracting palette", "driver", d.Name())
+					p, err := d.Extract(ctx, img, opts)
+					if err != nil {
+						return fmt.Errorf("%s: %w", d.Name(), err)
+					}
+					entries = append(entries, preview.Entry{Name: d.Name(), Palette: p})
+				}
+				if output != "" {
+					return atomicfile.WritePNG(output, preview.Compare(entries))
+				}
+				return preview.CompareANSI(c.OutOrStdout(), entries)
+			}
+
+			var arg string
+			if len(args) == 1 {
+				arg = args[0]
+			}
+			resolved, err := palette.LoadInput(ctx, palette.Input{
+				Arg:     arg,
+				Stdin:   c.InOrStdin(),
+				Dirs:    dirs,
+				Driver:  driverName,
+				Options: opts,
+			})
+			if err != nil {
+				return err
+			}
+			entry := preview.Entry{Name: resolved.Name, Palette: resolved.Palette}
+			if output != "" {
+				return atomicfile.WritePNG(output, preview.Swatches(entry))
+			}
+			return preview.ANSI(c.OutOrStdout(), entry)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write a PNG swatch sheet to this path instead of printing")
+	cmd.Flags().BoolVar(&compare, "compare", false, "Run every driver on the image and show the results side by side")
+	cmd.Flags().StringArrayVar(&dirs, "dir", nil, "Scheme directory (repeatable; replaces the configured ones)")
+	cmd.Flags().StringVar(&driverName, "driver", "genetic", "Extraction algorithm when the input is an image")
+	cmd.Flags().StringVar(&polarity, "polarity", "any", "Theme preference when the input is an image: dark, light, or any")
+	cmd.Flags().IntVar(&colorCount, "colors", 16, "Number of colors when the input is an image (16 or 24)")
+	if regErr := cmd.RegisterFlagCompletionFunc("driver", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return palette.DriverNames(), cobra.ShellCompDirectiveNoFileComp
+	}); regErr != nil {
+		// completion registration is best-effort
+	}
+	return cmd
+}
@@ -0,0 +1,65 @@
+package show
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/scheme"
+)
+
+func GetCommand() *cobra.Command {
+	var dirs []string
+	cmd := &cobra.Command{
+		Use:   "show [scheme]",
+		Short: "Show a scheme, or the active desktop palette",
+		Long: `Print a scheme (by slug, bare name or file path) as JSON.
+
+Without an argument, print the active desktop palette: the scheme recorded by
+'palette apply', else workspaced.desktop.palette, resolved the same way
+templates see it as .palette.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := c.Context()
+			cfg, err := configcue.LoadHome(ctx)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if len(dirs) == 0 {
+				if dirs, err = palette.SchemeDirs(ctx, cfg); err != nil {
+					return err
+				}
+			}
+
+			var out any
+			if len(args) == 1 {
+				s, err := scheme.Find(ctx, args[0], dirs...)
+				if err != nil {
+					return err
+				}
+				out = s
+			} else {
+				src, ok, err := palette.ConfigSource(ctx, cfg)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("no active palette: set workspaced.desktop.palette or run 'palette apply <scheme>'")
+				}
+				resolved, err := palette.Resolve(ctx, src, dirs)
+				if err != nil {
+					return err
+				}
+				out = resolved
+			}
+			encoder := json.NewEncoder(c.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(out)
+		},
+	}
+	cmd.Flags().StringArrayVar(&dirs, "dir", nil, "Scheme directory (repeatable; replaces the configured ones)")
+	return cmd
+}
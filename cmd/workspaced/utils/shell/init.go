@@ -31,7 +31,9 @@ func getInitCommand() *cobra.Command {
 		Use:   "init [shell]",
 		Short: "Generate shell initialization script",
 		Long: `Generates shell initialization by concatenating prelude scripts.
-Uses caching for performance - regenerates only when source files change.`,
+Uses caching for performance - regenerates only when source files change.
+The output is POSIX shell (bash, zsh); fish is rejected, use
+"utils shell hook fish" there.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -46,6 +48,10 @@ Uses caching for performance - regenerates only when source files change.`,
 			if len(args) > 0 {
 				shell = args[0]
 			}
+			if shell == "fish" {
+				// The prelude and the other generators are POSIX shell.
+				return fmt.Errorf("%w: init emits POSIX shell for %s; load the tool hook with \"workspaced utils shell hook fish | source\"", shellgen.ErrUnsupportedShell, shell)
+			}
 
 			dotfilesRoot, err := findDotfilesRoot(ctx)
 			if err != nil {
@@ -70,7 +76,8 @@ Uses caching for performance - regenerates only when source files change.`,
 			}
 
 			buildID := version.GetBuildID()
-			cacheFile := filepath.Join(cacheDir, fmt.Sprintf("shell-init-%s-%s-%s.bash", shell, buildID, preludeFingerprint))
+			// Keyed by shell: generators such as the tool env hook emit shell-specific code.
+			cacheFile := filepath.Join(cacheDir, fmt.Sprintf("shell-init-%s-%s.%s", buildID, preludeFingerprint, shell))
 
 			// Local --force is shell-cache only; global --no-cache is the full cascade.
 			if !force && !cmdctx.IsNoCache(ctx) {
@@ -155,7 +162,7 @@ Uses caching for performance - regenerates only when source files change.`,
 
 			// Generate all inline shell initialization code in parallel
 			t3 := time.Now()
-			inlineCode, err := shellgen.Generate(ctx)
+			inlineCode, err := shellgen.Generate(shellgen.WithShell(ctx, shell))
 			if err != nil {
 				return fmt.Errorf("generate inline shell initialization: %w", err)
 			}
@@ -11,6 +11,8 @@ func GetCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(getInitCommand())
+	cmd.AddCommand(getHookCommand())
+	cmd.AddCommand(getToolPathCommand())
 
 	return cmd
 }
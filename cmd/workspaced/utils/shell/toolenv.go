@@ -0,0 +1,65 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lucasew/workspaced/internal/shellgen"
+	"github.com/lucasew/workspaced/internal/tool"
+)
+
+func getHookCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "hook <bash|zsh|fish>",
+		Short: "Print the per-directory tool activation hook",
+		Long: `Print the hook that activates a codebase's pinned lazy tools on cd.
+
+"utils shell init <shell>" already includes it for bash and zsh; fish
+(which init does not support) and setups that only want the hook load it
+directly, e.g.
+
+    workspaced utils shell hook fish | source`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			code, err := shellgen.GenerateToolEnv(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), code)
+			return nil
+		},
+	}
+}
+
+func getToolPathCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tool-path [dir]",
+		Short: "Print PATH entries for a workspace's locked lazy tools",
+		Long: `Print the colon-separated bin directories of the installed, locked
+lazy tools for the workspace whose workspaced.cue lives in dir (default: the
+current directory). Used by the shell hook; cached between calls.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := ""
+			if len(args) > 0 {
+				dir = args[0]
+			} else {
+				var err error
+				if dir, err = os.Getwd(); err != nil {
+					return err
+				}
+			}
+			dirs, err := tool.LazyToolBinDirs(cmd.Context(), dir)
+			if err != nil {
+				return err
+			}
+			if len(dirs) > 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), strings.Join(dirs, ":"))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,122 @@
+// Package autotheme re-themes the desktop when the wallpaper changes.
+//
+// With workspaced.desktop.wallpaper.theme.enable set, a new wallpaper is run
+// through a palette driver (cached by image hash), recorded as the applied
+// palette that templates read as .palette, and the palette-aware modules
+// and templates are re-applied with the usual home apply hooks (dconf, GTK
+// reload). Polarity follows desktop.dark_mode.
+package autotheme
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/lucasew/workspaced/internal/apply"
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/dotfiles"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/taskgroup"
+)
+
+// Settings is workspaced.desktop.wallpaper.theme.
+type Settings struct {
+	Enable   bool   `json:"enable"`
+	Driver   string `json:"driver,omitempty"`
+	Colors   int    `json:"colors,omitempty"`
+	Contrast string `json:"contrast,omitempty"`
+}
+
+// FromConfig reads the wallpaper theme settings; ok is false when the
+// block is missing or disabled.
+func FromConfig(cfg *configcue.Config) (s Settings, ok bool, err error) {
+	if cfg == nil {
+		return Settings{}, false, nil
+	}
+	if _, lookupErr := cfg.Lookup("desktop.wallpaper.theme"); lookupErr != nil {
+		return Settings{}, false, nil
+	}
+	if err := cfg.Decode("desktop.wallpaper.theme", &s); err != nil {
+		return Settings{}, false, fmt.Errorf("decode desktop.wallpaper.theme: %w", err)
+	}
+	return s, s.Enable, nil
+}
+
+// Source is the palette source for image under s. Polarity is taken from
+// desktop.dark_mode ("any" when unset).
+func (s Settings) Source(cfg *configcue.Config, image string) palette.Source {
+	src := palette.Source{
+		Image:    image,
+		Driver:   s.Driver,
+		Polarity: "any",
+		Colors:   s.Colors,
+		Contrast: s.Contrast,
+	}
+	var dark bool
+	if cfg != nil {
+		if err := cfg.Decode("desktop.dark_mode", &dark); err == nil {
+			src.Polarity = "light"
+			if dark {
+				src.Polarity = "dark"
+			}
+		}
+	}
+	return src
+}
+
+// OnWallpaper themes the desktop from image when enabled in the home
+// config; it is a no-op otherwise.
+func OnWallpaper(ctx context.Context, image string) error {
+	cfg, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	settings, ok, err := FromConfig(cfg)
+	if err != nil || !ok {
+		return err
+	}
+	_, err = Apply(ctx, cfg, settings, image)
+	return err
+}
+
+// Apply extracts the palette of image, records it as the applied palette
+// and re-applies everything that reads it.
+func Apply(ctx context.Context, cfg *configcue.Config, settings Settings, image string) (*dotfiles.ApplyResult, error) {
+	logger := logging.GetLogger(ctx)
+	abs, err := filepath.Abs(image)
+	if err != nil {
+		return nil, err
+	}
+	src := settings.Source(cfg, abs)
+
+	dirs, err := palette.SchemeDirs(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve now so a bad image fails before the applied palette moves;
+	// the template run below hits the extraction cache.
+	resolved, err := palette.Resolve(ctx, src, dirs)
+	if err != nil {
+		return nil, err
+	}
+	if err := palette.SetAppliedSource(ctx, src); err != nil {
+		return nil, err
+	}
+	logger.Info("palette from wallpaper", "image", abs, "driver", src.Driver, "variant", resolved.Variant)
+
+	var result *dotfiles.ApplyResult
+	err = taskgroup.GoIsolated(ctx, "theme:apply", taskgroup.Control, func(ctx context.Context, s *taskgroup.Status) error {
+		s.Update("re-applying themed modules")
+		r, err := apply.Home(ctx, apply.HomeOptions{PaletteOnly: true})
+		result = r
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("apply themed modules: %w", err)
+	}
+	if result != nil {
+		logger.Info("themed modules applied", "created", result.FilesCreated, "updated", result.FilesUpdated)
+	}
+	return result, nil
+}
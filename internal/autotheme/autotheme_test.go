@@ -0,0 +1,61 @@
+package autotheme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	_ "github.com/lucasew/workspaced/pkg/driver/env/native"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func loadConfig(t *testing.T, body string) *configcue.Config {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "workspaced.cue"), []byte("package workspaced\n\nworkspaced: "+body+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configcue.LoadForWorkspace(logging.NewWriterContext(t.Output()), root)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	return cfg
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Parallel()
+
+	if _, ok, err := FromConfig(loadConfig(t, `{}`)); err != nil || ok {
+		t.Fatalf("unset theme: ok=%v err=%v", ok, err)
+	}
+	if _, ok, err := FromConfig(loadConfig(t, `desktop: wallpaper: theme: enable: false`)); err != nil || ok {
+		t.Fatalf("disabled theme: ok=%v err=%v", ok, err)
+	}
+
+	cfg := loadConfig(t, `desktop: {
+	dark_mode: true
+	wallpaper: theme: {enable: true, colors: 24, contrast: "aa"}
+}`)
+	s, ok, err := FromConfig(cfg)
+	if err != nil || !ok {
+		t.Fatalf("enabled theme: ok=%v err=%v", ok, err)
+	}
+	src := s.Source(cfg, "/walls/a.jpg")
+	if src.Image != "/walls/a.jpg" || src.Driver != "kmeans" || src.Colors != 24 || src.Contrast != "aa" || src.Polarity != "dark" {
+		t.Fatalf("source = %+v", src)
+	}
+}
+
+func TestSourcePolarityFollowsDarkMode(t *testing.T) {
+	t.Parallel()
+	for body, want := range map[string]string{
+		`desktop: dark_mode: false`: "light",
+		`desktop: dark_mode: true`:  "dark",
+		`{}`:                        "any",
+	} {
+		if got := (Settings{}).Source(loadConfig(t, body), "a.jpg").Polarity; got != want {
+			t.Errorf("%s: polarity = %q, want %q", body, got, want)
+		}
+	}
+}
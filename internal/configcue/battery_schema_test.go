@@ -0,0 +1,45 @@
+package configcue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestBatterySchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+
+	bat := func(user string) (map[string]any, error) {
+		u := cueCtx.CompileString("package workspaced\nworkspaced: battery: "+user, cue.Filename("user.cue"))
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.battery"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	got, err := bat(`{critical_action: "hibernate", charge_thresholds: {start: 75, end: 80}}`)
+	if err != nil {
+		t.Fatalf("unify: %v", err)
+	}
+	if got["low"] != 15.0 || got["critical"] != 5.0 || got["critical_action"] != "hibernate" {
+		t.Fatalf("defaults = %v", got)
+	}
+	if _, err := bat(`{critical_action: "explode"}`); err == nil {
+		t.Fatal("expected schema error for unknown critical_action")
+	}
+	if _, err := bat(`{charge_thresholds: {start: 80, end: 75}}`); err == nil {
+		t.Fatal("expected schema error for start above end")
+	}
+}
@@ -0,0 +1,45 @@
+package configcue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestNightLightSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+
+	nightLight := func(user string) (map[string]any, error) {
+		u := cueCtx.CompileString("package workspaced\nworkspaced: brightness: night_light: "+user, cue.Filename("user.cue"))
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.brightness.night_light"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	got, err := nightLight(`{night: 3400}`)
+	if err != nil {
+		t.Fatalf("unify: %v", err)
+	}
+	if got["enable"] != true || got["day"] != 6500.0 || got["night"] != 3400.0 || got["sunset"] != "19:00" || got["transition"] != "30m" {
+		t.Fatalf("defaults = %v", got)
+	}
+	if _, err := nightLight(`{sunset: "7pm"}`); err == nil {
+		t.Fatal("expected schema error for sunset not in HH:MM")
+	}
+	if _, err := nightLight(`{night: 100}`); err == nil {
+		t.Fatal("expected schema error for out of range temperature")
+	}
+}
@@ -0,0 +1,42 @@
+package configcue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestIdleSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+
+	idle := func(user string) (map[string]any, error) {
+		u := cueCtx.CompileString("package workspaced\nworkspaced: idle: "+user, cue.Filename("user.cue"))
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.idle"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	got, err := idle(`{lock: "10m"}`)
+	if err != nil {
+		t.Fatalf("unify: %v", err)
+	}
+	if got["dim"] != "2m" || got["lock"] != "10m" || got["suspend"] != "15m" || got["dim_level"] != 0.3 || got["inhibit_on_media"] != true || got["suspend_on_ac"] != false {
+		t.Fatalf("defaults = %v", got)
+	}
+	if _, err := idle(`{dim_level: 2}`); err == nil {
+		t.Fatal("expected schema error for dim_level above 1")
+	}
+}
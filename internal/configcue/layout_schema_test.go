@@ -0,0 +1,48 @@
+package configcue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestOutputLayoutSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+
+	layout := func(user string) (map[string]any, error) {
+		u := cueCtx.CompileString("package workspaced\nworkspaced: desktop: layouts: docked: "+user, cue.Filename("user.cue"))
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.desktop.layouts.docked"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	got, err := layout(`{outputs: {
+		laptop: {match: name: "eDP-1", position: {x: 0, y: 0}, scale: 1.5}
+		dell: {match: {make: "Dell.*", model: "U2720Q"}, workspaces: ["www", "3"]}
+	}}`)
+	if err != nil {
+		t.Fatalf("unify: %v", err)
+	}
+	if got["exact"] != true {
+		t.Fatalf("exact default = %v", got["exact"])
+	}
+	if _, err := layout(`{outputs: laptop: {match: name: "eDP-1", scale: 0}}`); err == nil {
+		t.Fatal("expected schema error for scale 0")
+	}
+	if _, err := layout(`{outputs: laptop: {match: connector: "eDP-1"}}`); err == nil {
+		t.Fatal("expected schema error for unknown match field")
+	}
+}
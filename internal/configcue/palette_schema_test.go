@@ -0,0 +1,107 @@
+package configcue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestDesktopPaletteSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+	if err := schema.Err(); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+
+	palette := func(t *testing.T, user string) (map[string]any, error) {
+		t.Helper()
+		u := cueCtx.CompileString("package workspaced\nworkspaced: desktop: palette: "+user, cue.Filename("user.cue"))
+		if err := u.Err(); err != nil {
+			return nil, err
+		}
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.desktop.palette"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	t.Run("scheme", func(t *testing.T) {
+		t.Parallel()
+		got, err := palette(t, `{scheme: "gruvbox-dark"}`)
+		if err != nil {
+			t.Fatalf("unify: %v", err)
+		}
+		if got["scheme"] != "gruvbox-dark" || len(got) != 1 {
+			t.Fatalf("palette = %v", got)
+		}
+	})
+
+	t.Run("image defaults driver", func(t *testing.T) {
+		t.Parallel()
+		got, err := palette(t, `{image: "~/wall.jpg", colors: 24}`)
+		if err != nil {
+			t.Fatalf("unify: %v", err)
+		}
+		if got["driver"] != "genetic" || got["colors"] != float64(24) {
+			t.Fatalf("palette = %v", got)
+		}
+	})
+
+	t.Run("rejects scheme and image together", func(t *testing.T) {
+		t.Parallel()
+		if _, err := palette(t, `{scheme: "x", image: "y"}`); err == nil {
+			t.Fatal("expected schema error")
+		}
+	})
+
+	t.Run("rejects bad polarity", func(t *testing.T) {
+		t.Parallel()
+		if _, err := palette(t, `{image: "y", polarity: "dim"}`); err == nil {
+			t.Fatal("expected schema error")
+		}
+	})
+}
+
+func TestWallpaperThemeSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+
+	theme := func(user string) (map[string]any, error) {
+		u := cueCtx.CompileString("package workspaced\nworkspaced: desktop: wallpaper: theme: "+user, cue.Filename("user.cue"))
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.desktop.wallpaper.theme"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	got, err := theme(`{enable: true}`)
+	if err != nil {
+		t.Fatalf("unify: %v", err)
+	}
+	if got["enable"] != true || got["driver"] != "kmeans" {
+		t.Fatalf("theme = %v", got)
+	}
+	if _, err := theme(`{enable: true, polarity: "dark"}`); err == nil {
+		t.Fatal("polarity must come from desktop.dark_mode, expected schema error")
+	}
+}
@@ -14,11 +14,13 @@ package workspaced
 
 #LazyTool: {
 	version?: string
-	ref?:     string
-	pkg?:     string
-	global?:  bool
-	alias?:   string
-	bins?:    [...string]
+	// Range `tool upgrade` may move the lock within: "^1.2", "~1.2.3", ">=1 <2".
+	constraint?: string
+	ref?:        string
+	pkg?:        string
+	global?:     bool
+	alias?:      string
+	bins?:       [...string]
 }
 
 // core:place step: exact-path move on the origin virtual FS (file or dir prefix).
@@ -83,6 +85,56 @@ package workspaced
 	internet?: int
 }
 
+// Desktop palette from a tinted-theming scheme file (see desktop.palette_dirs).
+#PaletteScheme: close({
+	scheme:    string
+	contrast?: #PaletteContrast
+})
+
+// Desktop palette extracted from an image by a `palette drivers` driver.
+#PaletteImage: close({
+	image:     string
+	driver:    *"genetic" | string
+	// Unset follows desktop.dark_mode.
+	polarity?: "any" | "dark" | "light"
+	colors?:   16 | 24
+	contrast?: #PaletteContrast
+})
+
+// Wallpaper change -> palette extraction (cached by image hash) -> re-apply
+// of the modules and templates that read .palette.
+#WallpaperTheme: close({
+	enable:    bool | *false
+	driver:    *"kmeans" | string
+	colors?:   16 | 24
+	contrast?: #PaletteContrast
+})
+
+// Workspace/output layout for one set of connected outputs. The layout
+// whose outputs all match (and, with exact, nothing else is connected) is
+// applied by `driver workspace layout apply` and on hotplug by the daemon.
+#OutputLayout: close({
+	exact: bool | *true
+	outputs: [string]: #LayoutOutput
+})
+
+#LayoutOutput: close({
+	// Regular expressions matched against the whole output field.
+	match: close({
+		name?:   string
+		make?:   string
+		model?:  string
+		serial?: string
+	})
+	position?: close({x: int, y: int})
+	scale?:    number & >0
+	// WM workspace names, or keys of workspaced.workspaces.
+	workspaces?: [...string]
+})
+
+// Nudge foreground tones until base16 text/accent pairs meet WCAG+APCA targets.
+#PaletteContrast: "aa" | "aaa"
+
 #BackupActionGitRepoSync: close({
 	name?: string
 	kind: "git_repo_sync"
@@ -108,6 +160,36 @@ package workspaced
 })
 
 
+// Screenshot post-capture actions. Actions share the shot: ocr and upload
+// leave text behind that a following copy puts on the clipboard.
+#ScreenshotActionCopy: close({
+	kind: "copy"
+})
+
+#ScreenshotActionAnnotate: close({
+	kind: "annotate"
+	// Editor argv; {path} is the screenshot. Default: satty, then swappy.
+	cmd?: [...string] & [_, ...]
+})
+
+#ScreenshotActionOCR: close({
+	kind: "ocr"
+	// tesseract language, e.g. "eng+por".
+	lang?: string
+})
+
+#ScreenshotActionUpload: close({
+	kind: "upload"
+	// Uploader argv; {path} is the screenshot, the last stdout line the URL.
+	cmd: [...string] & [_, ...]
+})
+
+// Open so kinds registered outside workspaced still validate.
+#ScreenshotAction: #ScreenshotActionCopy | #ScreenshotActionAnnotate | #ScreenshotActionOCR | #ScreenshotActionUpload | {
+	kind: !="copy" & !="annotate" & !="ocr" & !="upload"
+	...
+}
+
 #BackupAction: #BackupActionGitRepoSync | #BackupActionRsync | #BackupActionArchive 
 
 workspaced: {
@@ -130,10 +212,82 @@ workspaced: {
 		wallpaper?: {
 			dir?:     string
 			default?: string
+			// Opt-in: derive desktop.palette from each new wallpaper.
+			theme?: #WallpaperTheme
 		}
+		// Output layouts by name; the best match for the connected outputs wins.
+		layouts?: [string]: #OutputLayout
+		// Resolved into template data as .palette (base00..base17, name, variant).
+		palette?: #PaletteScheme | #PaletteImage
+		// Scheme search path; default <dotfiles>/palettes, ~/.local/share/workspaced/palettes.
+		palette_dirs?: [...string]
 	}
 	screenshot?: {
 		dir?: string
+		// Post-capture action names run in order; `--then` overrides.
+		then: [...string] | *["copy"]
+		// Named actions; the kinds are also usable by name unconfigured.
+		actions?: [string]: #ScreenshotAction
+	}
+	screenrecord?: {
+		// Defaults to screenshot.dir.
+		dir?:   string
+		format: *"mp4" | "mkv" | "webm"
+		audio:  bool | *false
+	}
+	brightness?: {
+		// Color temperature by time of day, applied by the daemon.
+		night_light?: {
+			enable: bool | *true
+			// Kelvin; 6500 leaves the display untouched.
+			day:   int & >=1000 & <=25000 | *6500
+			night: int & >=1000 & <=25000 | *4000
+			// Local "HH:MM"; night runs from sunset to sunrise.
+			sunset:  =~"^([01][0-9]|2[0-3]):[0-5][0-9]$" | *"19:00"
+			sunrise: =~"^([01][0-9]|2[0-3]):[0-5][0-9]$" | *"07:00"
+			// Fade after sunset and after sunrise, as a Go duration.
+			transition: string | *"30m"
+		}
+	}
+	// Checked by the daemon while discharging; percentages of the combined
+	// charge of every battery.
+	battery?: {
+		low:      int & >=0 & <=100 | *15
+		critical: int & >=0 & <=100 | *5
+		// Runs after the battery stays critical for a minute.
+		critical_action: *"none" | "suspend" | "hibernate" | "shutdown"
+		// Charge limits set when the daemon starts, where the battery
+		// supports them; writing them usually needs a udev rule.
+		charge_thresholds?: {
+			start: int & >=0 & <100
+			end:   int & >start & <=100
+		}
+	}
+	// Read by `workspaced svc idle`. Each step is a Go duration of
+	// inactivity, or "0" to skip it.
+	idle?: {
+		dim:     string | *"2m"
+		lock:    string | *"5m"
+		dpms:    string | *"6m"
+		suspend: string | *"15m"
+		// Fraction of the current brightness kept while dimmed.
+		dim_level: number & >=0 & <=1 | *0.3
+		// Suspend only applies on battery unless this is set.
+		suspend_on_ac: bool | *false
+		// A playing media player holds everything off.
+		inhibit_on_media: bool | *true
+	}
+	clipboard?: {
+		// Recorded by the daemon when enabled; password-manager selections
+		// are never stored.
+		history?: {
+			enable:      bool | *false
+			max_entries: int & >0 | *500
+			// Larger selections (big images) are not recorded.
+			max_bytes: int & >0 | *8388608
+			// Oldest entries are dropped once the history outgrows this.
+			max_total_bytes: int & >0 | *67108864
+		}
 	}
 	hosts?: [string]: #Host
 	backup?: {
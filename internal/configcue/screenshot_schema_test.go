@@ -0,0 +1,49 @@
+package configcue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+)
+
+func TestScreenshotActionSchema(t *testing.T) {
+	t.Parallel()
+
+	schemaBytes, err := schemaFS.ReadFile("schema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cueCtx := cuecontext.New()
+	schema := cueCtx.CompileBytes(schemaBytes, cue.Filename("schema.cue"))
+
+	screenshot := func(user string) (map[string]any, error) {
+		u := cueCtx.CompileString("package workspaced\nworkspaced: screenshot: "+user, cue.Filename("user.cue"))
+		v := schema.Unify(u).LookupPath(cue.ParsePath("workspaced.screenshot"))
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		return out, json.Unmarshal(data, &out)
+	}
+
+	got, err := screenshot(`{dir: "/tmp", actions: {
+		imgur: {kind: "upload", cmd: ["imgur-upload", "{path}"]}
+		pt: {kind: "ocr", lang: "por"}
+		s3: {kind: "team-s3", bucket: "shots"}
+	}}`)
+	if err != nil {
+		t.Fatalf("unify: %v", err)
+	}
+	if then, _ := got["then"].([]any); len(then) != 1 || then[0] != "copy" {
+		t.Fatalf("then default = %v", got["then"])
+	}
+	if _, err := screenshot(`{actions: up: {kind: "upload"}}`); err == nil {
+		t.Fatal("expected schema error for upload without cmd")
+	}
+	if _, err := screenshot(`{actions: o: {kind: "ocr", language: "eng"}}`); err == nil {
+		t.Fatal("expected schema error for unknown ocr field")
+	}
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasew/workspaced/internal/types"
+	_ "github.com/lucasew/workspaced/pkg/driver/env/native"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func TestClipboardHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := logging.NewWriterContext(t.Output())
+	database, err := Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logging.Close(ctx, database)
+
+	record := func(hash, text string, ts int64) {
+		t.Helper()
+		err := database.RecordClipboard(ctx, types.ClipboardEntry{
+			Hash: hash, MIME: "text/plain", Preview: text, Timestamp: ts, Data: []byte(text),
+		}, 3, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	record("a", "first", 1)
+	record("b", "second", 2)
+	record("a", "first", 3) // re-copy bumps, does not duplicate
+	record("c", "third", 4)
+
+	entries, err := database.ListClipboard(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Preview)
+	}
+	if want := []string{"third", "first", "second"}; len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("history = %q, want %q", got, want)
+	}
+	if entries[0].Size != 5 || entries[0].Data != nil {
+		t.Fatalf("listed entry = %+v, want size 5 and no data", entries[0])
+	}
+
+	record("d", "fourth", 5) // prunes "second", the oldest
+	entries, err = database.ListClipboard(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[2].Preview != "first" {
+		t.Fatalf("after prune = %+v", entries)
+	}
+
+	full, err := database.GetClipboard(ctx, entries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(full.Data) != "fourth" || full.MIME != "text/plain" {
+		t.Fatalf("GetClipboard = %+v", full)
+	}
+}
+
+func TestClipboardHistorySizeCap(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ctx := logging.NewWriterContext(t.Output())
+	database, err := Open(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logging.Close(ctx, database)
+
+	for i, text := range []string{"aaaa", "bbbb", "cccc"} {
+		err := database.RecordClipboard(ctx, types.ClipboardEntry{
+			Hash: text, MIME: "text/plain", Preview: text, Timestamp: int64(i), Data: []byte(text),
+		}, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := database.ListClipboard(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Preview != "cccc" || entries[1].Preview != "bbbb" {
+		t.Fatalf("after size prune = %+v, want the two newest", entries)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(home, "*", "*", "workspaced", "workspaced.db"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("database file: %v %v", matches, err)
+	}
+	info, err := os.Stat(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("database mode = %o, want 600", perm)
+	}
+}
@@ -45,8 +45,19 @@ func Open(ctx context.Context) (*DB, error) {
 		return nil, err
 	}
 
+	// The database holds shell and clipboard history: keep it private.
 	dbPath := filepath.Join(dataDir, "workspaced.db")
-	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(dbPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dbPath, 0o600); err != nil {
 		return nil, err
 	}
 
@@ -157,3 +168,77 @@ func (db *DB) SearchHistory(ctx context.Context, query string, limit int) ([]typ
 	}
 	return events, nil
 }
+
+// RecordClipboard stores entry (bumping it to the top if its hash is
+// already known) and keeps only the newest keep entries whose data adds up
+// to at most keepBytes. Zero limits are not enforced.
+func (db *DB) RecordClipboard(ctx context.Context, entry types.ClipboardEntry, keep, keepBytes int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.ReportError(ctx, err)
+		}
+	}()
+
+	q := db.Queries.WithTx(tx)
+	err = q.RecordClipboard(ctx, sqlc.RecordClipboardParams{
+		Hash:      entry.Hash,
+		Mime:      entry.MIME,
+		Content:   entry.Data,
+		Preview:   entry.Preview,
+		Timestamp: entry.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	if keep > 0 {
+		if err := q.PruneClipboard(ctx, int64(keep)); err != nil {
+			return err
+		}
+	}
+	if keepBytes > 0 {
+		if err := q.PruneClipboardSize(ctx, int64(keepBytes)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListClipboard returns the newest clipboard entries without their data.
+func (db *DB) ListClipboard(ctx context.Context, limit int) ([]types.ClipboardEntry, error) {
+	rows, err := db.Queries.ListClipboard(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]types.ClipboardEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = types.ClipboardEntry{
+			ID:        row.ID,
+			MIME:      row.Mime,
+			Preview:   row.Preview,
+			Size:      row.Size,
+			Timestamp: row.Timestamp,
+		}
+	}
+	return entries, nil
+}
+
+// GetClipboard returns one clipboard entry with its data.
+func (db *DB) GetClipboard(ctx context.Context, id int64) (types.ClipboardEntry, error) {
+	row, err := db.Queries.GetClipboard(ctx, id)
+	if err != nil {
+		return types.ClipboardEntry{}, err
+	}
+	return types.ClipboardEntry{
+		ID:        row.ID,
+		Hash:      row.Hash,
+		MIME:      row.Mime,
+		Preview:   row.Preview,
+		Size:      int64(len(row.Content)),
+		Timestamp: row.Timestamp,
+		Data:      row.Content,
+	}, nil
+}
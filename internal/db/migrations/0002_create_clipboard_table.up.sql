@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS clipboard (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hash TEXT NOT NULL UNIQUE,
+    mime TEXT NOT NULL,
+    content BLOB NOT NULL,
+    preview TEXT NOT NULL,
+    timestamp INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_clipboard_timestamp ON clipboard(timestamp);
@@ -12,3 +12,38 @@ SELECT * FROM history
 WHERE command LIKE ?
 ORDER BY timestamp DESC
 LIMIT ?;
+
+-- name: RecordClipboard :exec
+INSERT INTO clipboard (hash, mime, content, preview, timestamp)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (hash) DO UPDATE SET timestamp = excluded.timestamp;
+
+-- name: ListClipboard :many
+SELECT id, mime, preview, length(content) AS size, timestamp FROM clipboard
+ORDER BY timestamp DESC, id DESC
+LIMIT ?;
+
+-- name: GetClipboard :one
+SELECT * FROM clipboard
+WHERE id = ?;
+
+-- name: PruneClipboard :exec
+DELETE FROM clipboard
+WHERE id NOT IN (
+    SELECT id FROM clipboard
+    ORDER BY timestamp DESC, id DESC
+    LIMIT ?
+);
+
+-- name: PruneClipboardSize :exec
+DELETE FROM clipboard
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, SUM(length(content)) OVER (ORDER BY timestamp DESC, id DESC) AS total
+        FROM clipboard
+    )
+    WHERE total > sqlc.arg(max_bytes)
+);
+
+-- name: ClearClipboard :exec
+DELETE FROM clipboard;
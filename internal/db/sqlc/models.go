@@ -4,6 +4,15 @@
 
 package sqlc
 
+type Clipboard struct {
+	ID        int64
+	Hash      string
+	Mime      string
+	Content   []byte
+	Preview   string
+	Timestamp int64
+}
+
 type History struct {
 	ID         int64
 	Command    string
@@ -9,6 +9,34 @@ import (
 	"context"
 )
 
+const clearClipboard = `-- name: ClearClipboard :exec
+DELETE FROM clipboard
+`
+
+func (q *Queries) ClearClipboard(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, clearClipboard)
+	return err
+}
+
+const getClipboard = `-- name: GetClipboard :one
+SELECT id, hash, mime, content, preview, timestamp FROM clipboard
+WHERE id = ?
+`
+
+func (q *Queries) GetClipboard(ctx context.Context, id int64) (Clipboard, error) {
+	row := q.db.QueryRowContext(ctx, getClipboard, id)
+	var i Clipboard
+	err := row.Scan(
+		&i.ID,
+		&i.Hash,
+		&i.Mime,
+		&i.Content,
+		&i.Preview,
+		&i.Timestamp,
+	)
+	return i, err
+}
+
 const getHistory = `-- name: GetHistory :many
 SELECT id, command, cwd, timestamp, exit_code, duration_ms FROM history
 ORDER BY timestamp DESC
@@ -45,6 +73,104 @@ func (q *Queries) GetHistory(ctx context.Context, limit int64) ([]History, error
 	return items, nil
 }
 
+const listClipboard = `-- name: ListClipboard :many
+SELECT id, mime, preview, length(content) AS size, timestamp FROM clipboard
+ORDER BY timestamp DESC, id DESC
+LIMIT ?
+`
+
+type ListClipboardRow struct {
+	ID        int64
+	Mime      string
+	Preview   string
+	Size      int64
+	Timestamp int64
+}
+
+func (q *Queries) ListClipboard(ctx context.Context, limit int64) ([]ListClipboardRow, error) {
+	rows, err := q.db.QueryContext(ctx, listClipboard, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListClipboardRow
+	for rows.Next() {
+		var i ListClipboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Mime,
+			&i.Preview,
+			&i.Size,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pruneClipboard = `-- name: PruneClipboard :exec
+DELETE FROM clipboard
+WHERE id NOT IN (
+    SELECT id FROM clipboard
+    ORDER BY timestamp DESC, id DESC
+    LIMIT ?
+)
+`
+
+func (q *Queries) PruneClipboard(ctx context.Context, limit int64) error {
+	_, err := q.db.ExecContext(ctx, pruneClipboard, limit)
+	return err
+}
+
+const pruneClipboardSize = `-- name: PruneClipboardSize :exec
+DELETE FROM clipboard
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, SUM(length(content)) OVER (ORDER BY timestamp DESC, id DESC) AS total
+        FROM clipboard
+    )
+    WHERE total > ?1
+)
+`
+
+func (q *Queries) PruneClipboardSize(ctx context.Context, maxBytes int64) error {
+	_, err := q.db.ExecContext(ctx, pruneClipboardSize, maxBytes)
+	return err
+}
+
+const recordClipboard = `-- name: RecordClipboard :exec
+INSERT INTO clipboard (hash, mime, content, preview, timestamp)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (hash) DO UPDATE SET timestamp = excluded.timestamp
+`
+
+type RecordClipboardParams struct {
+	Hash      string
+	Mime      string
+	Content   []byte
+	Preview   string
+	Timestamp int64
+}
+
+func (q *Queries) RecordClipboard(ctx context.Context, arg RecordClipboardParams) error {
+	_, err := q.db.ExecContext(ctx, recordClipboard,
+		arg.Hash,
+		arg.Mime,
+		arg.Content,
+		arg.Preview,
+		arg.Timestamp,
+	)
+	return err
+}
+
 const recordHistory = `-- name: RecordHistory :exec
 INSERT INTO history (command, cwd, timestamp, exit_code, duration_ms)
 VALUES (?, ?, ?, ?, ?)
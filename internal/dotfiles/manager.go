@@ -71,6 +71,10 @@ func NewManager(cfg Config) (*Manager, error) {
 type ApplyOptions struct {
 	DryRun   bool // If true, only shows what would be done.
 	ShowDiff bool // If true, shows a detailed diff.
+	// Scoped plans only the targets the pipeline produced: managed files
+	// outside them are left alone instead of pruned. Use it with pipelines
+	// that deliberately emit a subset (e.g. StandardDotfilesOptions.PaletteOnly).
+	Scoped bool
 }
 
 // ApplyResult contains the result of Apply.
@@ -133,7 +137,11 @@ func (m *Manager) Apply(ctx context.Context, opts ApplyOptions) (*ApplyResult, e
 	// 4. Plan actions
 	logger.Info("planning actions")
 	planStart := time.Now()
-	actions, err := m.planner.Plan(ctx, desired, state)
+	planState := state
+	if opts.Scoped {
+		planState = scopeState(state, desired)
+	}
+	actions, err := m.planner.Plan(ctx, desired, planState)
 	if err != nil {
 		result.Error = err
 		return result, fmt.Errorf("plan: %w", err)
@@ -217,6 +225,17 @@ func (m *Manager) Apply(ctx context.Context, opts ApplyOptions) (*ApplyResult, e
 	return result, nil
 }
 
+// scopeState returns the part of state that desired covers.
+func scopeState(state *deployer.State, desired []deployer.DesiredState) *deployer.State {
+	scoped := &deployer.State{Files: map[string]deployer.ManagedInfo{}}
+	for _, d := range desired {
+		if info, ok := state.Files[d.Target()]; ok {
+			scoped.Files[d.Target()] = info
+		}
+	}
+	return scoped
+}
+
 // GetPipeline returns the configured pipeline.
 func (m *Manager) GetPipeline() *source.Pipeline {
 	return m.pipeline
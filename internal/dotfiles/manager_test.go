@@ -118,3 +118,66 @@ func TestApplyPersistsDropOfGitignoredStateOnIdle(t *testing.T) {
 		t.Fatalf("ignored file should stay on disk: %v", err)
 	}
 }
+
+func TestApplyScopedKeepsUnplannedState(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	otherAbs := filepath.Join(root, "other.conf")
+	themedAbs := filepath.Join(root, "themed.conf")
+	if err := os.WriteFile(otherAbs, []byte("other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(themedAbs, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pipe := source.NewPipeline(fileListPlugin{files: []source.File{
+		&source.BufferFile{
+			BasicFile: source.BasicFile{
+				RelPathStr:    "themed.conf",
+				TargetBaseDir: root,
+				FileMode:      0o644,
+				Info:          "config/themed.conf.tmpl",
+				FileType:      source.TypeStatic,
+			},
+			Content: []byte("new\n"),
+		},
+	}})
+	store, err := deployer.NewFileStateStore(filepath.Join(root, ".workspaced", "state.json"), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(&deployer.State{Files: map[string]deployer.ManagedInfo{
+		otherAbs:  {SourceInfo: "config/other.conf"},
+		themedAbs: {SourceInfo: "config/themed.conf.tmpl"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	mgr, err := NewManager(Config{Pipeline: pipe, StateStore: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ctx := taskgroup.New(logging.NewWriterContext(t.Output()), taskgroup.DefaultLimits())
+	result, err := mgr.Apply(ctx, ApplyOptions{Scoped: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FilesUpdated != 1 || result.FilesDeleted != 0 {
+		t.Fatalf("want one update and no delete, got update=%d delete=%d", result.FilesUpdated, result.FilesDeleted)
+	}
+	if got, err := os.ReadFile(themedAbs); err != nil || string(got) != "new\n" {
+		t.Fatalf("themed.conf = %q, %v", got, err)
+	}
+	if _, err := os.Stat(otherAbs); err != nil {
+		t.Fatalf("unplanned file removed: %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loaded.Files[otherAbs]; !ok {
+		t.Fatal("unplanned file dropped from state")
+	}
+}
@@ -0,0 +1,78 @@
+// Package gtktheme generates a GTK3/GTK4 theme directory from a palette.
+//
+// The theme layers a palette-colored stylesheet over GTK's built-in
+// Adwaita (GTK3) or Default (GTK4) theme, so widgets it does not restyle
+// keep working. libadwaita apps ignore themes but read the named colors in
+// gtk-4.0/gtk.css when it is linked into ~/.config/gtk-4.0.
+package gtktheme
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/lucasew/workspaced/pkg/palette/export"
+)
+
+// Version is bumped whenever the generated output changes, so cached
+// themes are regenerated.
+const Version = 1
+
+//go:embed gtk.css.tmpl
+var stylesheet string
+
+var tmpl = template.Must(template.New("gtk.css").Parse(stylesheet))
+
+type data struct {
+	*export.Scheme
+	GTK     int
+	Dark    bool
+	Defines string
+}
+
+// Files renders the theme as relative path -> content: index.theme plus
+// gtk-3.0/gtk.css, gtk-3.0/gtk-dark.css and gtk-4.0/gtk.css. The -dark
+// sheet is identical; GTK3 apps that prefer dark still get the palette.
+func Files(s *export.Scheme) (map[string][]byte, error) {
+	var defines bytes.Buffer
+	if err := export.Encode(&defines, "gtk", s.Palette, export.Options{Name: s.Name, Author: s.Author}); err != nil {
+		return nil, err
+	}
+	d := data{Scheme: s, Dark: s.Variant == "dark", Defines: defines.String()}
+
+	out := map[string][]byte{
+		"index.theme": fmt.Appendf(nil, "[Desktop Entry]\nType=X-GNOME-Metatheme\nName=%s\nComment=%s (%s), generated by workspaced\nEncoding=UTF-8\n\n[X-GNOME-Metatheme]\nGtkTheme=%s\n", s.Name, s.Name, s.Variant, s.Name),
+	}
+	for _, gtk := range []int{3, 4} {
+		d.GTK = gtk
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, d); err != nil {
+			return nil, fmt.Errorf("render gtk-%d.0: %w", gtk, err)
+		}
+		out[fmt.Sprintf("gtk-%d.0/gtk.css", gtk)] = buf.Bytes()
+	}
+	out["gtk-3.0/gtk-dark.css"] = out["gtk-3.0/gtk.css"]
+	return out, nil
+}
+
+// Generate writes the theme for s into dir (the theme root, e.g.
+// ~/.local/share/themes/<name>).
+func Generate(dir string, s *export.Scheme) error {
+	files, err := Files(s)
+	if err != nil {
+		return err
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
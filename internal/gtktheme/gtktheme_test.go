@@ -0,0 +1,82 @@
+package gtktheme
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/export"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func loadScheme(t *testing.T, name string) *export.Scheme {
+	t.Helper()
+	b, err := os.ReadFile(palettetest.Path(t, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatal(err)
+	}
+	s, err := export.NewScheme(&p, export.Options{Name: "bliss"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		palette    string
+		gtk3, gtk4 string
+	}{
+		{"materialyou_dark16_bliss.json", "Adwaita/gtk-contained-dark.css", "Default/Default-dark.css"},
+		{"materialyou_light24_bliss.json", "Adwaita/gtk-contained.css", "Default/Default-light.css"},
+	}
+	for _, tc := range cases {
+		s := loadScheme(t, tc.palette)
+		files, err := Files(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gtk3, gtk4 := string(files["gtk-3.0/gtk.css"]), string(files["gtk-4.0/gtk.css"])
+		for _, check := range []struct {
+			name, css, want string
+		}{
+			{"gtk3 import", gtk3, tc.gtk3 + `");`},
+			{"gtk4 import", gtk4, tc.gtk4 + `");`},
+			{"gtk3 define", gtk3, "@define-color base0D #" + s.Base0D + ";"},
+			{"gtk4 libadwaita", gtk4, "@define-color accent_bg_color #" + s.Base0D + ";"},
+			{"gtk3 menus", gtk3, "menu, .menu, .context-menu"},
+			{"gtk4 popovers", gtk4, "popover > contents"},
+		} {
+			if !strings.Contains(check.css, check.want) {
+				t.Errorf("%s %s: missing %q", tc.palette, check.name, check.want)
+			}
+		}
+		if strings.Contains(gtk4, "menu, .menu") || strings.Contains(gtk3, "popover > contents") {
+			t.Errorf("%s: version-specific rules leaked", tc.palette)
+		}
+		if !strings.Contains(string(files["index.theme"]), "GtkTheme=bliss\n") {
+			t.Errorf("index.theme = %s", files["index.theme"])
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := Generate(dir, loadScheme(t, "materialyou_dark16_bliss.json")); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"index.theme", "gtk-3.0/gtk.css", "gtk-3.0/gtk-dark.css", "gtk-4.0/gtk.css"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Error(err)
+		}
+	}
+}
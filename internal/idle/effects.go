@@ -0,0 +1,93 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver/battery"
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+	"github.com/lucasew/workspaced/pkg/driver/media"
+	"github.com/lucasew/workspaced/pkg/driver/power"
+	"github.com/lucasew/workspaced/pkg/driver/screen"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+const (
+	// dimFade is slow enough to notice and move the mouse before the lock.
+	dimFade   = 2 * time.Second
+	undimFade = 200 * time.Millisecond
+)
+
+// DriverEffects applies the policy through the brightness, power, screen,
+// battery and media drivers.
+type DriverEffects struct {
+	Policy Policy
+	// InhibitDir is where `workspaced idle inhibit` holds its files.
+	InhibitDir string
+	// restore is the brightness before dimming, negative when not dimmed.
+	restore float64
+}
+
+// NewDriverEffects returns effects for p with the default inhibit dir.
+func NewDriverEffects(p Policy) *DriverEffects {
+	return &DriverEffects{Policy: p, InhibitDir: InhibitDir(), restore: -1}
+}
+
+func (e *DriverEffects) Apply(ctx context.Context, action Action) error {
+	switch action {
+	case ActionDim:
+		dev, err := brightness.GetDevice(ctx, "")
+		if err != nil {
+			return err
+		}
+		e.restore = dev.Brightness
+		return brightness.SetDevice(ctx, "", dev.Brightness*e.Policy.DimLevel, dimFade)
+	case ActionUndim:
+		if e.restore < 0 {
+			return nil
+		}
+		level := e.restore
+		e.restore = -1
+		return brightness.SetDevice(ctx, "", level, undimFade)
+	case ActionLock:
+		return power.Lock(ctx)
+	case ActionDPMSOff:
+		return screen.SetDPMS(ctx, false)
+	case ActionDPMSOn:
+		return screen.SetDPMS(ctx, true)
+	case ActionSuspend:
+		return power.Suspend(ctx)
+	}
+	return fmt.Errorf("unknown idle action %q", action)
+}
+
+func (e *DriverEffects) Inhibitor(ctx context.Context) string {
+	inhibitors, err := Inhibitors(e.InhibitDir)
+	if err != nil {
+		logging.ReportError(ctx, err)
+	}
+	if len(inhibitors) > 0 {
+		if inhibitors[0].Reason == "" {
+			return fmt.Sprintf("pid %d", inhibitors[0].PID)
+		}
+		return inhibitors[0].Reason
+	}
+	if e.Policy.InhibitOnMedia {
+		meta, err := media.GetMetadata(ctx, "")
+		if err == nil && meta.Status == media.StatusPlaying {
+			return "media: " + meta.Title
+		}
+	}
+	return ""
+}
+
+// OnBattery is false without a battery, or when it cannot be read.
+func (e *DriverEffects) OnBattery(ctx context.Context) bool {
+	info, err := battery.GetInfo(ctx)
+	if err != nil {
+		logging.GetLogger(ctx).Debug("battery status unavailable", "error", err)
+		return false
+	}
+	return !info.OnAC && info.Status() == battery.Discharging
+}
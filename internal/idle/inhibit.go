@@ -0,0 +1,76 @@
+package idle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/types"
+)
+
+// Inhibitor is a held `workspaced idle inhibit`.
+type Inhibitor struct {
+	PID    int
+	Reason string
+}
+
+// InhibitDir holds one file per inhibitor, named <pid>-<random>, with the
+// reason as its content. Files of dead processes are ignored and removed,
+// so a crashed holder does not keep the session awake.
+func InhibitDir() string {
+	return filepath.Join(types.RuntimeDir(), "workspaced", "idle-inhibit")
+}
+
+// Inhibit holds idling off in dir until release is called.
+func Inhibit(dir, reason string) (release func() error, err error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(dir, fmt.Sprintf("%d-*", os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.WriteString(reason)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return func() error { return os.Remove(f.Name()) }, nil
+}
+
+// Inhibitors lists the live inhibitors in dir.
+func Inhibitors(dir string) ([]Inhibitor, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ret []Inhibitor
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		pidPart, _, _ := strings.Cut(e.Name(), "-")
+		pid, err := strconv.Atoi(pidPart)
+		if err != nil {
+			continue
+		}
+		if !alive(pid) {
+			_ = os.Remove(path)
+			continue
+		}
+		reason, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, Inhibitor{PID: pid, Reason: string(reason)})
+	}
+	return ret, nil
+}
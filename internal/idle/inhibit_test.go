@@ -0,0 +1,55 @@
+package idle
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestInhibit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "inhibit")
+
+	if got, err := Inhibitors(dir); err != nil || len(got) != 0 {
+		t.Fatalf("Inhibitors on missing dir = %v, %v", got, err)
+	}
+
+	release, err := Inhibit(dir, "watching a movie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Inhibitors(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].PID != os.Getpid() || got[0].Reason != "watching a movie" {
+		t.Fatalf("Inhibitors = %+v", got)
+	}
+
+	if err := release(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Inhibitors(dir); err != nil || len(got) != 0 {
+		t.Fatalf("Inhibitors after release = %v, %v", got, err)
+	}
+}
+
+func TestInhibitorsDropsDeadProcesses(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip("true not available:", err)
+	}
+	dir := t.TempDir()
+	stale := filepath.Join(dir, strconv.Itoa(cmd.Process.Pid)+"-1")
+	if err := os.WriteFile(stale, []byte("crashed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := Inhibitors(dir); err != nil || len(got) != 0 {
+		t.Fatalf("Inhibitors = %v, %v", got, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("stale inhibitor not removed: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package idle
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Action is a side effect the machine asks for.
+type Action string
+
+const (
+	ActionDim     Action = "dim"
+	ActionUndim   Action = "undim"
+	ActionLock    Action = "lock"
+	ActionDPMSOff Action = "dpms-off"
+	ActionDPMSOn  Action = "dpms-on"
+	ActionSuspend Action = "suspend"
+)
+
+// Input is what the machine knows about the session at one instant.
+type Input struct {
+	// IdleSince is when input stopped; zero while the user is active.
+	IdleSince time.Time
+	Inhibited bool
+	OnBattery bool
+}
+
+type step struct {
+	after  time.Duration
+	action Action
+}
+
+// Machine is the timing state machine of the policy. It has no clock of
+// its own: callers pass the time to Update, so tests drive it directly.
+type Machine struct {
+	policy Policy
+	steps  []step
+	// activeAt is the last moment the user was active or an inhibitor was
+	// held; steps count from it or from IdleSince, whichever is later.
+	activeAt  time.Time
+	since     time.Time
+	idle      bool
+	onBattery bool
+	done      map[Action]bool
+}
+
+// NewMachine starts the machine at now with the session active.
+func NewMachine(p Policy, now time.Time) *Machine {
+	m := &Machine{policy: p, activeAt: now, done: map[Action]bool{}}
+	for _, s := range []step{
+		{p.Dim, ActionDim},
+		{p.Lock, ActionLock},
+		{p.DPMS, ActionDPMSOff},
+		{p.Suspend, ActionSuspend},
+	} {
+		if s.after > 0 {
+			m.steps = append(m.steps, s)
+		}
+	}
+	slices.SortStableFunc(m.steps, func(a, b step) int { return cmp.Compare(a.after, b.after) })
+	return m
+}
+
+// Update feeds the state at now and returns the actions due, in order.
+func (m *Machine) Update(now time.Time, in Input) []Action {
+	m.onBattery = in.OnBattery
+	if in.IdleSince.IsZero() || in.Inhibited {
+		m.activeAt = now
+		m.idle = false
+		return m.wake()
+	}
+
+	m.idle = true
+	m.since = in.IdleSince
+	if m.activeAt.After(m.since) {
+		m.since = m.activeAt
+	}
+	var ret []Action
+	for _, s := range m.steps {
+		if m.done[s.action] || !m.allowed(s.action) || now.Before(m.since.Add(s.after)) {
+			continue
+		}
+		m.done[s.action] = true
+		ret = append(ret, s.action)
+	}
+	return ret
+}
+
+// Next is when the next step is due, or zero when none is pending.
+func (m *Machine) Next() time.Time {
+	if !m.idle {
+		return time.Time{}
+	}
+	for _, s := range m.steps {
+		if !m.done[s.action] && m.allowed(s.action) {
+			return m.since.Add(s.after)
+		}
+	}
+	return time.Time{}
+}
+
+// Reset undoes the reversible steps, as if the user came back.
+func (m *Machine) Reset(now time.Time) []Action {
+	return m.Update(now, Input{OnBattery: m.onBattery})
+}
+
+func (m *Machine) allowed(a Action) bool {
+	return a != ActionSuspend || m.onBattery || m.policy.SuspendOnAC
+}
+
+func (m *Machine) wake() []Action {
+	var ret []Action
+	if m.done[ActionDPMSOff] {
+		ret = append(ret, ActionDPMSOn)
+	}
+	if m.done[ActionDim] {
+		ret = append(ret, ActionUndim)
+	}
+	clear(m.done)
+	return ret
+}
@@ -0,0 +1,110 @@
+package idle
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func testPolicy() Policy {
+	return Policy{Dim: 2 * time.Minute, Lock: 5 * time.Minute, DPMS: 6 * time.Minute, Suspend: 15 * time.Minute, DimLevel: 0.3}
+}
+
+func after(d time.Duration) time.Time { return t0.Add(d) }
+
+func expect(t *testing.T, got []Action, want ...Action) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestMachineSteps(t *testing.T) {
+	m := NewMachine(testPolicy(), t0)
+	idle := Input{IdleSince: t0, OnBattery: true}
+
+	expect(t, m.Update(after(time.Minute), idle))
+	if next := m.Next(); !next.Equal(after(2 * time.Minute)) {
+		t.Fatalf("Next = %v", next)
+	}
+	expect(t, m.Update(after(2*time.Minute), idle), ActionDim)
+	expect(t, m.Update(after(3*time.Minute), idle))
+	expect(t, m.Update(after(6*time.Minute), idle), ActionLock, ActionDPMSOff)
+	expect(t, m.Update(after(15*time.Minute), idle), ActionSuspend)
+	if next := m.Next(); !next.IsZero() {
+		t.Fatalf("Next after every step = %v", next)
+	}
+
+	expect(t, m.Update(after(20*time.Minute), Input{OnBattery: true}), ActionDPMSOn, ActionUndim)
+	expect(t, m.Update(after(21*time.Minute), Input{OnBattery: true}))
+}
+
+func TestMachineActivityCancels(t *testing.T) {
+	m := NewMachine(testPolicy(), t0)
+	expect(t, m.Update(after(2*time.Minute), Input{IdleSince: t0}), ActionDim)
+	expect(t, m.Update(after(3*time.Minute), Input{}), ActionUndim)
+
+	// A new idle stretch counts from its own start.
+	since := after(4 * time.Minute)
+	expect(t, m.Update(after(5*time.Minute), Input{IdleSince: since}))
+	expect(t, m.Update(since.Add(2*time.Minute), Input{IdleSince: since}), ActionDim)
+}
+
+func TestMachineSuspendOnlyOnBattery(t *testing.T) {
+	m := NewMachine(testPolicy(), t0)
+	expect(t, m.Update(after(20*time.Minute), Input{IdleSince: t0}), ActionDim, ActionLock, ActionDPMSOff)
+	if next := m.Next(); !next.IsZero() {
+		t.Fatalf("Next on AC = %v, want none", next)
+	}
+	expect(t, m.Update(after(21*time.Minute), Input{IdleSince: t0, OnBattery: true}), ActionSuspend)
+
+	p := testPolicy()
+	p.SuspendOnAC = true
+	m = NewMachine(p, t0)
+	expect(t, m.Update(after(20*time.Minute), Input{IdleSince: t0}), ActionDim, ActionLock, ActionDPMSOff, ActionSuspend)
+}
+
+func TestMachineInhibit(t *testing.T) {
+	m := NewMachine(testPolicy(), t0)
+	expect(t, m.Update(after(2*time.Minute), Input{IdleSince: t0}), ActionDim)
+	// The inhibitor shows up while dimmed: undo, and hold everything off.
+	expect(t, m.Update(after(3*time.Minute), Input{IdleSince: t0, Inhibited: true}), ActionUndim)
+	expect(t, m.Update(after(30*time.Minute), Input{IdleSince: t0, Inhibited: true}))
+	// Once released the countdown starts over from the last inhibited check.
+	expect(t, m.Update(after(31*time.Minute), Input{IdleSince: t0}))
+	if next := m.Next(); !next.Equal(after(32 * time.Minute)) {
+		t.Fatalf("Next = %v", next)
+	}
+	expect(t, m.Update(after(32*time.Minute), Input{IdleSince: t0}), ActionDim)
+}
+
+func TestMachineDisabledSteps(t *testing.T) {
+	m := NewMachine(Policy{Lock: time.Minute, DPMS: 30 * time.Second}, t0)
+	expect(t, m.Update(after(time.Hour), Input{IdleSince: t0, OnBattery: true}), ActionDPMSOff, ActionLock)
+	expect(t, m.Update(after(time.Hour), Input{}), ActionDPMSOn)
+}
+
+func TestConfigPolicy(t *testing.T) {
+	p, err := DefaultConfig.Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Dim != 2*time.Minute || p.Suspend != 15*time.Minute || !p.InhibitOnMedia {
+		t.Fatalf("default policy = %+v", p)
+	}
+	cfg := DefaultConfig
+	cfg.Suspend = "0"
+	if p, err := cfg.Policy(); err != nil || p.Suspend != 0 {
+		t.Fatalf("suspend 0 = %+v, %v", p, err)
+	}
+	cfg.Lock = "soon"
+	if _, err := cfg.Policy(); err == nil {
+		t.Fatal("expected error for invalid duration")
+	}
+	cfg.Lock = "-1m"
+	if _, err := cfg.Policy(); err == nil {
+		t.Fatal("expected error for negative duration")
+	}
+}
@@ -0,0 +1,89 @@
+// Package idle runs the idle policy of `workspaced svc idle`: after a
+// stretch without input the screen dims, then the session locks, the
+// displays power off and, on battery, the machine suspends. Input undoes
+// the dim and DPMS steps; the lock stays.
+//
+// Idleness comes from the idle driver. A playing media player or a held
+// inhibitor (`workspaced idle inhibit`) keeps every step off and restarts
+// the countdown when it goes away.
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+)
+
+// Config is workspaced.idle.
+type Config struct {
+	Dim            string  `json:"dim"`
+	Lock           string  `json:"lock"`
+	DPMS           string  `json:"dpms"`
+	Suspend        string  `json:"suspend"`
+	DimLevel       float64 `json:"dim_level"`
+	SuspendOnAC    bool    `json:"suspend_on_ac"`
+	InhibitOnMedia bool    `json:"inhibit_on_media"`
+}
+
+// DefaultConfig mirrors the schema defaults.
+var DefaultConfig = Config{
+	Dim:            "2m",
+	Lock:           "5m",
+	DPMS:           "6m",
+	Suspend:        "15m",
+	DimLevel:       0.3,
+	InhibitOnMedia: true,
+}
+
+// LoadConfig reads workspaced.idle, falling back to the defaults.
+func LoadConfig(ctx context.Context) (Config, error) {
+	cfg := DefaultConfig
+	c, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return cfg, err
+	}
+	if _, err := c.Lookup("idle"); err == nil {
+		if err := c.Decode("idle", &cfg); err != nil {
+			return cfg, fmt.Errorf("decode idle: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// Policy is a parsed Config. A zero step duration disables the step.
+type Policy struct {
+	Dim, Lock, DPMS, Suspend time.Duration
+	DimLevel                 float64
+	SuspendOnAC              bool
+	InhibitOnMedia           bool
+}
+
+// Policy parses the step durations of c.
+func (c Config) Policy() (Policy, error) {
+	p := Policy{DimLevel: c.DimLevel, SuspendOnAC: c.SuspendOnAC, InhibitOnMedia: c.InhibitOnMedia}
+	for _, f := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"dim", c.Dim, &p.Dim},
+		{"lock", c.Lock, &p.Lock},
+		{"dpms", c.DPMS, &p.DPMS},
+		{"suspend", c.Suspend, &p.Suspend},
+	} {
+		if f.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(f.value)
+		if err != nil {
+			return p, fmt.Errorf("%s: %w", f.name, err)
+		}
+		if d < 0 {
+			return p, fmt.Errorf("%s: negative duration %s", f.name, f.value)
+		}
+		*f.dest = d
+	}
+	return p, nil
+}
@@ -0,0 +1,89 @@
+package idle
+
+import (
+	"context"
+	"time"
+
+	idledriver "github.com/lucasew/workspaced/pkg/driver/idle"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// PollInterval bounds how long an idle session goes without rechecking
+// inhibitors and the power source.
+const PollInterval = 30 * time.Second
+
+// Clock is the time source of Run.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time                         { return time.Now() }
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Effects is how Run observes and changes the session.
+type Effects interface {
+	Apply(ctx context.Context, action Action) error
+	// Inhibitor returns why idling is held off, or "" when it is not.
+	Inhibitor(ctx context.Context) string
+	OnBattery(ctx context.Context) bool
+}
+
+// Run drives m from the idle events until ctx is done, then undoes the
+// dim and DPMS steps so the session is not left dark.
+func Run(ctx context.Context, m *Machine, clock Clock, fx Effects, events <-chan idledriver.Event) error {
+	logger := logging.GetLogger(ctx)
+	apply := func(ctx context.Context, actions []Action) {
+		for _, a := range actions {
+			logger.Info("idle action", "action", a)
+			if err := fx.Apply(ctx, a); err != nil {
+				logging.ReportError(ctx, err, "action", a)
+			}
+		}
+	}
+
+	var idleSince time.Time
+	var inhibitor string
+	for {
+		in := Input{IdleSince: idleSince, OnBattery: fx.OnBattery(ctx)}
+		if !idleSince.IsZero() {
+			reason := fx.Inhibitor(ctx)
+			if reason != inhibitor {
+				logger.Info("idle inhibitor changed", "reason", reason)
+				inhibitor = reason
+			}
+			in.Inhibited = reason != ""
+		}
+		now := clock.Now()
+		apply(ctx, m.Update(now, in))
+
+		// While active only an event can change anything.
+		var timer <-chan time.Time
+		if !idleSince.IsZero() {
+			wait := PollInterval
+			if next := m.Next(); !next.IsZero() {
+				wait = min(wait, max(0, next.Sub(now)))
+			}
+			timer = clock.After(wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			apply(context.WithoutCancel(ctx), m.Reset(clock.Now()))
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				apply(context.WithoutCancel(ctx), m.Reset(clock.Now()))
+				return nil
+			}
+			idleSince = time.Time{}
+			if ev.Idle {
+				idleSince = ev.Since
+			}
+		case <-timer:
+		}
+	}
+}
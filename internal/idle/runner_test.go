@@ -0,0 +1,140 @@
+package idle
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	idledriver "github.com/lucasew/workspaced/pkg/driver/idle"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// fakeClock only moves on Advance. Every After call is announced on
+// waiting, which tells the test the runner is parked again.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []fakeTimer
+	waiting chan struct{}
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, fakeTimer{c.now.Add(d), ch})
+	c.mu.Unlock()
+	c.waiting <- struct{}{}
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.timers = slices.DeleteFunc(c.timers, func(t fakeTimer) bool {
+		if t.at.After(c.now) {
+			return false
+		}
+		t.ch <- c.now
+		return true
+	})
+}
+
+type fakeEffects struct {
+	mu        sync.Mutex
+	actions   []Action
+	inhibitor string
+}
+
+func (f *fakeEffects) Apply(ctx context.Context, action Action) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.actions = append(f.actions, action)
+	return nil
+}
+
+func (f *fakeEffects) Inhibitor(ctx context.Context) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.inhibitor
+}
+
+func (f *fakeEffects) OnBattery(ctx context.Context) bool { return true }
+
+func (f *fakeEffects) inhibit(reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inhibitor = reason
+}
+
+func (f *fakeEffects) take() []Action {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ret := f.actions
+	f.actions = nil
+	return ret
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(logging.NewWriterContext(t.Output()))
+	defer cancel()
+
+	clock := &fakeClock{now: t0, waiting: make(chan struct{})}
+	fx := &fakeEffects{}
+	events := make(chan idledriver.Event)
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, NewMachine(testPolicy(), t0), clock, fx, events) }()
+
+	// tick moves the clock and waits until the runner handled it.
+	tick := func(d time.Duration) {
+		t.Helper()
+		clock.Advance(d)
+		select {
+		case <-clock.waiting:
+		case <-time.After(5 * time.Second):
+			t.Fatal("runner did not wait again")
+		}
+	}
+
+	events <- idledriver.Event{Idle: true, Since: t0}
+	<-clock.waiting
+	expect(t, fx.take())
+
+	tick(2 * time.Minute)
+	expect(t, fx.take(), ActionDim)
+
+	fx.inhibit("test")
+	tick(PollInterval)
+	expect(t, fx.take(), ActionUndim)
+	tick(10 * time.Minute)
+	expect(t, fx.take())
+
+	fx.inhibit("")
+	tick(PollInterval)
+	expect(t, fx.take())
+	tick(2 * time.Minute)
+	expect(t, fx.take(), ActionDim)
+	tick(3 * time.Minute)
+	tick(time.Minute)
+	expect(t, fx.take(), ActionLock, ActionDPMSOff)
+
+	events <- idledriver.Event{}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	expect(t, fx.take(), ActionDPMSOn, ActionUndim)
+}
@@ -39,12 +39,13 @@ func BuildRenovateDependenciesFromLocks(sources map[string]LockedSource, tools m
 			continue
 		}
 		dep := RenovateDependency{
-			Kind:        "tool",
-			Ref:         ref,
-			DepName:     strings.TrimSpace(tool.DepName),
-			Datasource:  strings.TrimSpace(tool.Datasource),
-			PackageName: strings.TrimSpace(tool.PackageName),
-			Versioning:  strings.TrimSpace(tool.Versioning),
+			Kind:          "tool",
+			Ref:           ref,
+			DepName:       strings.TrimSpace(tool.DepName),
+			Datasource:    strings.TrimSpace(tool.Datasource),
+			PackageName:   strings.TrimSpace(tool.PackageName),
+			Versioning:    strings.TrimSpace(tool.Versioning),
+			CurrentDigest: strings.TrimSpace(tool.Digest),
 		}
 		if dep.CurrentValue == "" {
 			dep.CurrentValue = version
@@ -29,6 +29,11 @@ type LockedTool struct {
 	Datasource  string `json:"datasource,omitempty"`
 	PackageName string `json:"packageName,omitempty"`
 	Versioning  string `json:"versioning,omitempty"`
+
+	// Digest is the upstream content hash for Version when the backend
+	// defines one (backend.DigestTool, e.g. a go.sum h1: hash). Stored as
+	// the dependency's currentDigest.
+	Digest string `json:"digest,omitempty"`
 }
 type RenovateDependency struct {
 	// === Workspace-specific / custom fields ===
@@ -203,6 +208,7 @@ func rebuildToolLocksFromDependencies(sum *SumFile) map[string]LockedTool {
 			Datasource:  strings.TrimSpace(dep.Datasource),
 			PackageName: strings.TrimSpace(dep.PackageName),
 			Versioning:  strings.TrimSpace(dep.Versioning),
+			Digest:      strings.TrimSpace(dep.CurrentDigest),
 		}
 	}
 	return out
@@ -275,6 +281,7 @@ func (s *SumFile) FindTool(name string) (LockedTool, bool) {
 				Datasource:  dep.Datasource,
 				PackageName: dep.PackageName,
 				Versioning:  dep.Versioning,
+				Digest:      dep.CurrentDigest,
 			}, true
 		}
 	}
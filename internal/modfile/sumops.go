@@ -59,6 +59,7 @@ func (s *SumFile) UpsertTool(name string, lock LockedTool) bool {
 	lock.Datasource = strings.TrimSpace(lock.Datasource)
 	lock.PackageName = strings.TrimSpace(lock.PackageName)
 	lock.Versioning = strings.TrimSpace(lock.Versioning)
+	lock.Digest = strings.TrimSpace(lock.Digest)
 	if lock.Ref == "" || lock.Version == "" {
 		return false
 	}
@@ -82,6 +83,12 @@ func (s *SumFile) UpsertTool(name string, lock LockedTool) bool {
 		found = true
 		if d.CurrentValue != lock.Version {
 			d.CurrentValue = lock.Version
+			// A digest pinned for the old version no longer applies.
+			d.CurrentDigest = ""
+			changed = true
+		}
+		if lock.Digest != "" && d.CurrentDigest != lock.Digest {
+			d.CurrentDigest = lock.Digest
 			changed = true
 		}
 		if lock.DepName != "" && d.DepName != lock.DepName {
@@ -104,13 +111,14 @@ func (s *SumFile) UpsertTool(name string, lock LockedTool) bool {
 	}
 	if !found {
 		s.Dependencies = append(s.Dependencies, RenovateDependency{
-			Kind:         "tool",
-			Ref:          lock.Ref,
-			CurrentValue: lock.Version,
-			DepName:      lock.DepName,
-			Datasource:   lock.Datasource,
-			PackageName:  lock.PackageName,
-			Versioning:   lock.Versioning,
+			Kind:          "tool",
+			Ref:           lock.Ref,
+			CurrentValue:  lock.Version,
+			CurrentDigest: lock.Digest,
+			DepName:       lock.DepName,
+			Datasource:    lock.Datasource,
+			PackageName:   lock.PackageName,
+			Versioning:    lock.Versioning,
 		})
 		changed = true
 	}
@@ -61,3 +61,27 @@ func TestUpsertToolRefreshesStaleCurrentValue(t *testing.T) {
 		t.Fatalf("Versioning = %q, want semver", dep.Versioning)
 	}
 }
+
+func TestUpsertToolPinsDigestPerVersion(t *testing.T) {
+	t.Parallel()
+
+	sum := &SumFile{}
+	sum.EnsureTool("goimports", LockedTool{
+		Ref:        "go:golang.org/x/tools/cmd/goimports",
+		Version:    "v0.30.0",
+		Datasource: "go",
+		Digest:     "h1:abc=",
+	})
+	if lock, _ := sum.Tool("go:golang.org/x/tools/cmd/goimports"); lock.Digest != "h1:abc=" {
+		t.Fatalf("Digest = %q, want h1:abc=", lock.Digest)
+	}
+
+	if changed := sum.EnsureTool("goimports", LockedTool{Ref: "go:golang.org/x/tools/cmd/goimports", Version: "v0.30.0"}); changed {
+		t.Fatal("omitting the digest for the same version must keep the pin")
+	}
+
+	sum.EnsureTool("goimports", LockedTool{Ref: "go:golang.org/x/tools/cmd/goimports", Version: "v0.31.0"})
+	if d := sum.Dependencies[0].CurrentDigest; d != "" {
+		t.Fatalf("digest of the previous version should be dropped, got %q", d)
+	}
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/atomicfile"
+	"github.com/lucasew/workspaced/internal/cmdctx"
+	"github.com/lucasew/workspaced/internal/module"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/export"
+)
+
+// ErrNoThemePalette is returned by palette-generated modules when neither a
+// desktop palette nor modules.base16 is configured.
+var ErrNoThemePalette = errors.New("no palette to generate the theme from")
+
+// cachedBundle returns ~/.cache/workspaced/modules/<engine>/<fp>, running
+// generate into a scratch dir and swapping it in when the bundle is cold
+// (marker missing) or --no-cache is set. Generated modules key fp on their
+// inputs plus a generator version, so a warm bundle is always current.
+func cachedBundle(ctx context.Context, engine, fp, marker string, generate func(workDir string) error) (string, error) {
+	cacheRoot := envdriver.ExpandPath(filepath.Join("~/.cache/workspaced/modules", engine))
+	cacheDir := filepath.Join(cacheRoot, fp)
+	st, markerErr := os.Stat(filepath.Join(cacheDir, marker))
+	warm := markerErr == nil && st != nil
+	noCache := cmdctx.IsNoCache(ctx)
+	if warm && !noCache {
+		return cacheDir, nil
+	}
+	if noCache && cmdctx.IsDryRun(ctx) && warm {
+		logging.GetLogger(ctx).Debug("no-cache: would regenerate bundle (dry-run)", "engine", engine, "cache_dir", cacheDir)
+		return cacheDir, nil
+	}
+	if noCache {
+		logging.GetLogger(ctx).Debug("no-cache: regenerating bundle", "engine", engine, "cache_dir", cacheDir)
+	}
+	workDir := cacheDir + ".tmp"
+	if rmErr := os.RemoveAll(workDir); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		logging.ReportError(ctx, rmErr, "path", workDir)
+	}
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return "", err
+	}
+	err := generate(workDir)
+	if err == nil {
+		err = atomicfile.ReplaceDir(cacheDir, workDir)
+	}
+	if err != nil {
+		if rmErr := os.RemoveAll(workDir); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			logging.ReportError(ctx, rmErr, "path", workDir)
+		}
+		return "", err
+	}
+	return cacheDir, nil
+}
+
+// bundleFiles lists the regular files of a generated bundle, to be placed
+// under targetBase. Dotfiles are skipped.
+func bundleFiles(cacheDir, targetBase, moduleName, fp string) ([]module.ResolvedFile, error) {
+	var out []module.ResolvedFile
+	err := filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(filepath.Base(path), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(cacheDir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, module.ResolvedFile{
+			RelPath:    rel,
+			TargetBase: targetBase,
+			Mode:       info.Mode(),
+			Info:       fmt.Sprintf("module:%s bundle:%s (%s)", moduleName, fp, rel),
+			AbsPath:    path,
+			Symlink:    false,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RelPath < out[j].RelPath })
+	return out, nil
+}
+
+// themePalette is the palette generated themes are built from: the active
+// desktop palette (workspaced.desktop.palette or `palette apply`), else
+// the colors in modules.base16.
+func themePalette(ctx context.Context, req module.ResolveRequest) (*api.Palette, error) {
+	resolved, err := palette.FromConfig(ctx, req.Config)
+	if err != nil {
+		return nil, fmt.Errorf("desktop palette: %w", err)
+	}
+	if resolved != nil {
+		return resolved.Palette, nil
+	}
+	entry, err := req.Config.ModuleEntry("base16")
+	if err != nil || entry.Config == nil {
+		return nil, fmt.Errorf("%w: module %q from core:%s needs desktop.palette or modules.base16", ErrNoThemePalette, req.ModuleName, req.Ref)
+	}
+	raw := map[string]any{}
+	for k, v := range entry.Config {
+		if s, ok := v.(string); ok {
+			raw[k] = strings.TrimPrefix(s, "#")
+		}
+	}
+	p, err := module.DecodeConfig[api.Palette](raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBase16Config, err)
+	}
+	return &p, nil
+}
+
+// themeScheme resolves the module palette into an export scheme named
+// themeName, plus the bundle fingerprint over engine and palette.
+func themeScheme(ctx context.Context, req module.ResolveRequest, themeName, engine string) (*export.Scheme, string, error) {
+	p, err := themePalette(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	scheme, err := export.NewScheme(p, export.Options{Name: themeName})
+	if err != nil {
+		return nil, "", fmt.Errorf("module %s: %w", req.ModuleName, err)
+	}
+	b, err := json.Marshal(map[string]any{
+		"engine":     engine,
+		"theme_name": themeName,
+		"palette":    export.Slots(scheme.Palette),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	h := sha256.Sum256(b)
+	return scheme, hex.EncodeToString(h[:]), nil
+}
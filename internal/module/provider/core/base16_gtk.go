@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/lucasew/workspaced/internal/gtktheme"
+	"github.com/lucasew/workspaced/internal/module"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+)
+
+func init() {
+	module.RegisterCoreModule(base16GTKModule{})
+}
+
+// base16GTKModule generates a GTK3/GTK4 theme from the desktop palette.
+//
+//	modules: gtk: {
+//	  from: "core:base16-gtk"
+//	  config: theme_name: "workspaced-base16" // default
+//	}
+//
+// The theme lands in ~/.local/share/themes/<theme_name>; select it with
+// gtk-theme-name (settings.ini) or org.gnome.desktop.interface gtk-theme.
+type base16GTKModule struct{}
+
+func (base16GTKModule) Ref() string { return "base16-gtk" }
+
+func (base16GTKModule) Prepare(context.Context, map[string]any, module.SourceRefResolver, string) error {
+	return nil
+}
+
+type base16ThemeConfig struct {
+	ThemeName string `json:"theme_name"`
+	OutputDir string `json:"output_dir"`
+}
+
+func (base16GTKModule) Resolve(ctx context.Context, req module.ResolveRequest) (module.ResolveResult, error) {
+	cfg, err := module.DecodeConfig[base16ThemeConfig](req.ModuleConfig)
+	if err != nil {
+		return module.ResolveResult{}, fmt.Errorf("module %s: %w", req.ModuleName, err)
+	}
+	if cfg.ThemeName == "" {
+		cfg.ThemeName = "workspaced-base16"
+	}
+	if cfg.OutputDir == "" {
+		cfg.OutputDir = filepath.Join("~/.local/share/themes", cfg.ThemeName)
+	}
+	cfg.OutputDir = envdriver.ExpandPath(cfg.OutputDir)
+
+	scheme, fp, err := themeScheme(ctx, req, cfg.ThemeName, fmt.Sprintf("core-base16-gtk-v%d", gtktheme.Version))
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
+	cacheDir, err := cachedBundle(ctx, "core-base16-gtk", fp, "index.theme", func(workDir string) error {
+		return gtktheme.Generate(workDir, scheme)
+	})
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
+	out, err := bundleFiles(cacheDir, cfg.OutputDir, req.ModuleName, fp)
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
+	return module.ResolveResult{Files: out, Themed: true}, nil
+}
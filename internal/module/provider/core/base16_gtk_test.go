@@ -0,0 +1,105 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/module"
+	_ "github.com/lucasew/workspaced/pkg/driver/env/native"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func TestBase16ThemeModules(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ctx := logging.NewWriterContext(t.Output())
+
+	root := t.TempDir()
+	cue := `package workspaced
+
+workspaced: desktop: {
+	palette: scheme: "gruvbox-dark"
+	palette_dirs: [` + `"` + palettetest.Path(t, "schemes") + `"` + `]
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "workspaced.cue"), []byte(cue), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configcue.LoadForWorkspace(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolve := func(cm module.CoreModule) module.ResolveResult {
+		t.Helper()
+		res, err := cm.Resolve(ctx, module.ResolveRequest{
+			ModuleName:   "theme",
+			Ref:          cm.Ref(),
+			ModuleConfig: map[string]any{"theme_name": "test"},
+			Config:       cfg,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", cm.Ref(), err)
+		}
+		if !res.Themed {
+			t.Errorf("%s: result not marked themed", cm.Ref())
+		}
+		return res
+	}
+	paths := func(res module.ResolveResult) string {
+		var out []string
+		for _, f := range res.Files {
+			out = append(out, filepath.ToSlash(f.RelPath))
+		}
+		return strings.Join(out, ",")
+	}
+
+	gtk := resolve(base16GTKModule{})
+	if got, want := paths(gtk), "gtk-3.0/gtk-dark.css,gtk-3.0/gtk.css,gtk-4.0/gtk.css,index.theme"; got != want {
+		t.Fatalf("gtk files = %s, want %s", got, want)
+	}
+	if got, want := gtk.Files[0].TargetBase, filepath.Join(home, ".local/share/themes/test"); got != want {
+		t.Fatalf("gtk target = %s, want %s", got, want)
+	}
+	css, err := os.ReadFile(gtk.Files[1].AbsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(css), "@define-color base0D #83a598;") {
+		t.Fatalf("gtk.css does not carry the desktop palette:\n%s", css)
+	}
+
+	// A second resolve is served from the same bundle.
+	if again := resolve(base16GTKModule{}); again.Files[1].AbsPath != gtk.Files[1].AbsPath {
+		t.Fatalf("bundle moved: %s -> %s", gtk.Files[1].AbsPath, again.Files[1].AbsPath)
+	}
+
+	qt := resolve(base16QtModule{})
+	if got, want := paths(qt), "Kvantum/test/test.kvconfig,qt5ct/colors/test.conf,qt6ct/colors/test.conf"; got != want {
+		t.Fatalf("qt files = %s, want %s", got, want)
+	}
+	if got, want := qt.Files[0].TargetBase, filepath.Join(home, ".config"); got != want {
+		t.Fatalf("qt target = %s, want %s", got, want)
+	}
+}
+
+func TestBase16ThemeNeedsPalette(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := logging.NewWriterContext(t.Output())
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "workspaced.cue"), []byte("package workspaced\n\nworkspaced: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configcue.LoadForWorkspace(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = base16GTKModule{}.Resolve(ctx, module.ResolveRequest{ModuleName: "gtk", Ref: "base16-gtk", Config: cfg})
+	if err == nil || !strings.Contains(err.Error(), ErrNoThemePalette.Error()) {
+		t.Fatalf("err = %v, want ErrNoThemePalette", err)
+	}
+}
@@ -10,18 +10,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
-	"github.com/lucasew/workspaced/internal/atomicfile"
-	"github.com/lucasew/workspaced/internal/cmdctx"
 	"github.com/lucasew/workspaced/internal/icons"
 	"github.com/lucasew/workspaced/internal/module"
 	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
-	"github.com/lucasew/workspaced/pkg/logging"
 )
 
 func init() {
@@ -112,28 +107,8 @@ func (base16IconsLinuxModule) Resolve(ctx context.Context, req module.ResolveReq
 		return module.ResolveResult{}, err
 	}
 
-	cacheRoot := envdriver.ExpandPath("~/.cache/workspaced/modules/core-base16-icons-linux")
-	cacheDir := filepath.Join(cacheRoot, fp)
-	indexPath := filepath.Join(cacheDir, "index.theme")
-	st, indexErr := os.Stat(indexPath)
-	warm := indexErr == nil && st != nil
-	noCache := cmdctx.IsNoCache(ctx)
-	if warm && !noCache {
-		// cache hit
-	} else if noCache && cmdctx.IsDryRun(ctx) && warm {
-		logging.GetLogger(ctx).Debug("no-cache: would regenerate icons (dry-run)", "cache_dir", cacheDir)
-	} else {
-		if noCache {
-			logging.GetLogger(ctx).Debug("no-cache: regenerating icons", "cache_dir", cacheDir)
-		}
-		workDir := cacheDir + ".tmp"
-		if rmErr := os.RemoveAll(workDir); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
-			logging.ReportError(ctx, rmErr, "path", workDir)
-		}
-		if err := os.MkdirAll(workDir, 0755); err != nil {
-			return module.ResolveResult{}, err
-		}
-		err := icons.RunThemeGenerate(ctx, icons.ThemeGenerateOptions{
+	cacheDir, err := cachedBundle(ctx, "core-base16-icons-linux", fp, "index.theme", func(workDir string) error {
+		return icons.RunThemeGenerate(ctx, icons.ThemeGenerateOptions{
 			InputDir:       cfg.InputDir,
 			OutputDir:      workDir,
 			ThemeName:      cfg.ThemeName,
@@ -149,53 +124,14 @@ func (base16IconsLinuxModule) Resolve(ctx context.Context, req module.ResolveReq
 			DefaultContext: cfg.DefaultContext,
 			UseCache:       false,
 		})
-		if err != nil {
-			if rmErr := os.RemoveAll(workDir); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
-				logging.ReportError(ctx, rmErr, "path", workDir)
-			}
-			return module.ResolveResult{}, err
-		}
-		if err := atomicReplaceDir(cacheDir, workDir); err != nil {
-			if rmErr := os.RemoveAll(workDir); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
-				logging.ReportError(ctx, rmErr, "path", workDir)
-			}
-			return module.ResolveResult{}, err
-		}
-	}
-
-	var out []module.ResolvedFile
-	err = filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
-		if d.IsDir() {
-			return nil
-		}
-		if strings.HasPrefix(filepath.Base(path), ".") {
-			return nil
-		}
-		rel, err := filepath.Rel(cacheDir, path)
-		if err != nil {
-			return err
-		}
-		info, err := d.Info()
-		if err != nil {
-			return err
-		}
-		out = append(out, module.ResolvedFile{
-			RelPath:    rel,
-			TargetBase: cfg.OutputDir,
-			Mode:       info.Mode(),
-			Info:       fmt.Sprintf("module:%s bundle:%s (%s)", req.ModuleName, fp, rel),
-			AbsPath:    path,
-			Symlink:    false,
-		})
-		return nil
 	})
 	if err != nil {
 		return module.ResolveResult{}, err
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].RelPath < out[j].RelPath })
+	out, err := bundleFiles(cacheDir, cfg.OutputDir, req.ModuleName, fp)
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
 	return module.ResolveResult{Files: out}, nil
 }
 
@@ -236,10 +172,6 @@ func moduleFingerprint(cfg base16IconsConfig, palette map[string]any) (string, e
 	return hex.EncodeToString(h[:]), nil
 }
 
-func atomicReplaceDir(dest, tmpDir string) error {
-	return atomicfile.ReplaceDir(dest, tmpDir)
-}
-
 func sourceStats(dir string) (map[string]any, error) {
 	s, err := icons.CollectSVGSourceStats(dir)
 	if err != nil {
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lucasew/workspaced/internal/module"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+	"github.com/lucasew/workspaced/pkg/palette/export"
+)
+
+func init() {
+	module.RegisterCoreModule(base16QtModule{})
+}
+
+// qtEngineVersion is bumped whenever the generated Qt files change.
+const qtEngineVersion = 1
+
+// base16QtModule generates Qt color schemes from the desktop palette:
+// qt5ct/colors/<theme_name>.conf, qt6ct/colors/<theme_name>.conf and a
+// colors-only Kvantum theme in Kvantum/<theme_name>, all under
+// ~/.config (output_dir). Pick them in qt5ct/qt6ct or kvantummanager.
+type base16QtModule struct{}
+
+func (base16QtModule) Ref() string { return "base16-qt" }
+
+func (base16QtModule) Prepare(context.Context, map[string]any, module.SourceRefResolver, string) error {
+	return nil
+}
+
+func (base16QtModule) Resolve(ctx context.Context, req module.ResolveRequest) (module.ResolveResult, error) {
+	cfg, err := module.DecodeConfig[base16ThemeConfig](req.ModuleConfig)
+	if err != nil {
+		return module.ResolveResult{}, fmt.Errorf("module %s: %w", req.ModuleName, err)
+	}
+	if cfg.ThemeName == "" {
+		cfg.ThemeName = "workspaced-base16"
+	}
+	if cfg.OutputDir == "" {
+		cfg.OutputDir = "~/.config"
+	}
+	cfg.OutputDir = envdriver.ExpandPath(cfg.OutputDir)
+
+	scheme, fp, err := themeScheme(ctx, req, cfg.ThemeName, fmt.Sprintf("core-base16-qt-v%d", qtEngineVersion))
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
+	cacheDir, err := cachedBundle(ctx, "core-base16-qt", fp, filepath.Join("qt5ct", "colors", cfg.ThemeName+".conf"), func(workDir string) error {
+		return writeQtThemes(workDir, cfg.ThemeName, scheme)
+	})
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
+	out, err := bundleFiles(cacheDir, cfg.OutputDir, req.ModuleName, fp)
+	if err != nil {
+		return module.ResolveResult{}, err
+	}
+	return module.ResolveResult{Files: out, Themed: true}, nil
+}
+
+func writeQtThemes(dir, name string, scheme *export.Scheme) error {
+	files := map[string]string{
+		filepath.Join("qt5ct", "colors", name+".conf"):   "qtct",
+		filepath.Join("qt6ct", "colors", name+".conf"):   "qtct",
+		filepath.Join("Kvantum", name, name+".kvconfig"): "kvantum",
+	}
+	for rel, format := range files {
+		content, err := export.String(format, scheme.Palette, export.Options{Name: scheme.Name, Author: scheme.Author})
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
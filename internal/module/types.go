@@ -26,9 +26,12 @@ type ResolvedFile struct {
 
 // ResolveResult is the output of a module Resolve.
 // Warnings are soft diagnostics (logged and shown after the plan file diff).
+// Themed marks files generated from the desktop palette, so palette-only
+// applies (wallpaper re-theming) re-resolve the module.
 type ResolveResult struct {
 	Files    []ResolvedFile
 	Warnings []string
+	Themed   bool
 }
 
 type Provider interface {
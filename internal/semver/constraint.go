@@ -0,0 +1,248 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidConstraint is returned by ParseConstraint for malformed ranges.
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// Constraint is a version range in the npm/cargo dialect:
+//
+//	^1.2       >=1.2.0 <2.0.0 (^0.2 stays within 0.2.x)
+//	~1.2.3     >=1.2.3 <1.3.0
+//	1.2 / 1.x  >=1.2.0 <1.3.0
+//	>=1 <2     space- or comma-separated comparators are ANDed
+//	^1 || ^2   "||" separates alternatives
+//	1.2 - 2.3  >=1.2.0 <2.4.0 (inclusive hyphen range)
+//
+// "", "*" and "latest" match every release. As in npm, a prerelease only
+// matches when a comparator in the same alternative names a prerelease of
+// the same major.minor.patch: ">=1.2.3-rc.1" admits 1.2.3-rc.2 but not
+// 1.2.4-rc.1.
+type Constraint struct {
+	Original string
+	sets     []comparatorSet
+}
+
+type comparator struct {
+	op string
+	v  SemVer
+}
+
+// ParseConstraint parses a constraint string.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{Original: s}
+	for alt := range strings.SplitSeq(s, "||") {
+		set, err := parseComparatorSet(alt)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("%w %q: %v", ErrInvalidConstraint, s, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+// String returns the original constraint string.
+func (c Constraint) String() string {
+	return c.Original
+}
+
+// Check reports whether v satisfies the constraint.
+func (c Constraint) Check(v SemVer) bool {
+	if v.IsLatest() {
+		return false
+	}
+	for _, set := range c.sets {
+		if set.matches(v) && (!v.IsPrerelease() || set.admitsPrerelease(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Best returns the highest of versions that satisfies the constraint.
+func (c Constraint) Best(versions []string) (string, bool) {
+	var best SemVer
+	found := false
+	for _, raw := range versions {
+		v := Parse(raw)
+		if !c.Check(v) {
+			continue
+		}
+		if !found || v.Greater(best) {
+			best, found = v, true
+		}
+	}
+	return best.Original, found
+}
+
+type comparatorSet []comparator
+
+func (set comparatorSet) matches(v SemVer) bool {
+	for _, cmp := range set {
+		r := v.Compare(cmp.v)
+		ok := false
+		switch cmp.op {
+		case "=":
+			ok = r == 0
+		case ">":
+			ok = r > 0
+		case ">=":
+			ok = r >= 0
+		case "<":
+			ok = r < 0
+		case "<=":
+			ok = r <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// admitsPrerelease reports whether a comparator names a prerelease with
+// the same major.minor.patch as v.
+func (set comparatorSet) admitsPrerelease(v SemVer) bool {
+	for _, cmp := range set {
+		if cmp.v.IsPrerelease() && slices.Equal(cmp.v.coreParts(), v.coreParts()) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseComparatorSet(s string) (comparatorSet, error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	set := comparatorSet{}
+	for i := 0; i < len(fields); i++ {
+		tok := fields[i]
+		// "1.2 - 2.3" is an inclusive range.
+		if i+2 < len(fields) && fields[i+1] == "-" {
+			lower, err := parseComparator(">=" + tok)
+			if err != nil {
+				return nil, err
+			}
+			upper, err := parseComparator("<=" + fields[i+2])
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, lower...)
+			set = append(set, upper...)
+			i += 2
+			continue
+		}
+		// Allow ">= 1.2" by gluing a bare operator to the next field.
+		if strings.Trim(tok, "^~<>=") == "" && i+1 < len(fields) {
+			i++
+			tok += fields[i]
+		}
+		cmps, err := parseComparator(tok)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, cmps...)
+	}
+	return set, nil
+}
+
+func parseComparator(tok string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(tok, candidate) {
+			op = candidate
+			break
+		}
+	}
+	raw := strings.TrimSpace(strings.TrimPrefix(tok, op))
+	if raw == "*" || raw == "latest" {
+		if op != "" && op != "=" {
+			return nil, fmt.Errorf("operator %q needs a version", op)
+		}
+		return nil, nil
+	}
+	parts, n, err := parsePartial(raw)
+	if err != nil {
+		return nil, err
+	}
+	lower := SemVer{Original: raw, Parts: parts}
+	if n == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "^", "~":
+			return nil, nil
+		}
+		// ">*" and "<*" match nothing.
+		return []comparator{{op: "<", v: SemVer{Original: "0", Parts: []int{0}}}}, nil
+	}
+
+	switch op {
+	case "^":
+		i := 0
+		for i < n-1 && parts[i] == 0 {
+			i++
+		}
+		return []comparator{{op: ">=", v: lower}, {op: "<", v: bump(parts, i)}}, nil
+	case "~":
+		return []comparator{{op: ">=", v: lower}, {op: "<", v: bump(parts, min(n-1, 1))}}, nil
+	case "", "=":
+		if n >= 3 {
+			return []comparator{{op: "=", v: lower}}, nil
+		}
+		return []comparator{{op: ">=", v: lower}, {op: "<", v: bump(parts, n-1)}}, nil
+	case ">":
+		if n < 3 {
+			return []comparator{{op: ">=", v: bump(parts, n-1)}}, nil
+		}
+	case "<=":
+		if n < 3 {
+			return []comparator{{op: "<", v: bump(parts, n-1)}}, nil
+		}
+	}
+	return []comparator{{op: op, v: lower}}, nil
+}
+
+// parsePartial parses "1", "1.2", "1.2.x" or "v1.2.3-rc.1" into numeric
+// parts plus the count of explicitly given (non-wildcard) components.
+func parsePartial(raw string) ([]int, int, error) {
+	raw = strings.TrimPrefix(raw, "v")
+	if raw == "" {
+		return nil, 0, errors.New("empty version")
+	}
+	core := raw
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	var parts []int
+	wildcard := false
+	for field := range strings.SplitSeq(core, ".") {
+		if field == "x" || field == "X" || field == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return nil, 0, fmt.Errorf("%q: number after wildcard", raw)
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("%q: bad component %q", raw, field)
+		}
+		parts = append(parts, n)
+	}
+	return parts, len(parts), nil
+}
+
+// bump increments parts[i] and drops everything after it.
+func bump(parts []int, i int) SemVer {
+	out := append([]int(nil), parts[:i+1]...)
+	out[i]++
+	strs := make([]string, len(out))
+	for j, p := range out {
+		strs[j] = strconv.Itoa(p)
+	}
+	return SemVer{Original: strings.Join(strs, "."), Parts: out}
+}
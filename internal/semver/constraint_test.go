@@ -0,0 +1,90 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		reject     []string
+	}{
+		{constraint: "^1.2", match: []string{"1.2.0", "v1.9.9", "1.2"}, reject: []string{"1.1.9", "2.0.0"}},
+		{constraint: "^0.2.3", match: []string{"0.2.3", "0.2.9"}, reject: []string{"0.3.0", "0.2.2"}},
+		{constraint: "^0.0.3", match: []string{"0.0.3"}, reject: []string{"0.0.4"}},
+		{constraint: "~1.2.3", match: []string{"1.2.3", "1.2.10"}, reject: []string{"1.3.0", "1.2.2"}},
+		{constraint: "~1", match: []string{"1.0.0", "1.99.0"}, reject: []string{"2.0.0"}},
+		{constraint: ">=1 <2", match: []string{"1.0.0", "1.5.2"}, reject: []string{"0.9.9", "2.0.0"}},
+		{constraint: ">= 1.2, < 1.4", match: []string{"1.3.9"}, reject: []string{"1.4.0"}},
+		{constraint: ">1.2", match: []string{"1.3.0"}, reject: []string{"1.2.9"}},
+		{constraint: "<=1.2", match: []string{"1.2.9"}, reject: []string{"1.3.0"}},
+		{constraint: "1.2.x", match: []string{"1.2.0", "1.2.7"}, reject: []string{"1.3.0"}},
+		{constraint: "1.2.3", match: []string{"v1.2.3"}, reject: []string{"1.2.4"}},
+		{constraint: "^1 || ^3", match: []string{"1.4.0", "3.0.1"}, reject: []string{"2.0.0"}},
+		{constraint: "*", match: []string{"0.0.1", "99.0.0"}, reject: []string{"1.0.0-rc.1", "latest"}},
+		{constraint: "", match: []string{"1.0.0"}},
+		{constraint: ">=2.0.0-rc.1", match: []string{"2.0.0-rc.2", "2.1.0"}, reject: []string{"1.9.0"}},
+		{constraint: "^1", reject: []string{"1.5.0-beta"}},
+		{constraint: ">=2.0.0-rc.2", match: []string{"2.0.0-rc.10", "2.0.0"}, reject: []string{"2.0.0-rc.1", "2.1.0-rc.1"}},
+		{constraint: "^1.2.3-beta.2", match: []string{"1.2.3-beta.3", "1.4.0"}, reject: []string{"1.2.3-beta.1", "1.2.4-beta.5", "2.0.0-beta"}},
+		{constraint: "1.0.0 - 2.0.0", match: []string{"1.0.0", "2.0.0"}, reject: []string{"1.5.0-rc.1", "2.0.1", "0.9.9"}},
+		{constraint: "1.2 - 2.3", match: []string{"1.2.0", "2.3.9"}, reject: []string{"2.4.0", "1.1.9"}},
+		{constraint: "^1.0.0-rc.1 || ^3", match: []string{"1.0.0-rc.2", "3.1.0"}, reject: []string{"3.1.0-rc.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q): %v", tt.constraint, err)
+			}
+			for _, v := range tt.match {
+				if !c.Check(Parse(v)) {
+					t.Errorf("%q should match %q", tt.constraint, v)
+				}
+			}
+			for _, v := range tt.reject {
+				if c.Check(Parse(v)) {
+					t.Errorf("%q should not match %q", tt.constraint, v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, input := range []string{"^abc", "1.x.2", ">=", "~*"} {
+		if _, err := ParseConstraint(input); !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("ParseConstraint(%q) error = %v, want ErrInvalidConstraint", input, err)
+		}
+	}
+}
+
+func TestConstraintBest(t *testing.T) {
+	c, err := ParseConstraint("~1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := c.Best([]string{"v1.3.0", "v1.2.10", "v1.2.9", "v1.2.11-rc.1"})
+	if !ok || got != "v1.2.10" {
+		t.Fatalf("Best = %q, %v; want v1.2.10", got, ok)
+	}
+	if _, ok := c.Best([]string{"2.0.0"}); ok {
+		t.Fatal("Best should report no match")
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	for input, want := range map[string]bool{
+		"1.0.0":        false,
+		"v1.0.0-rc.1":  true,
+		"1.0.0+build5": false,
+		"1.0.0-beta+x": true,
+		"latest":       false,
+	} {
+		if got := Parse(input).IsPrerelease(); got != want {
+			t.Errorf("IsPrerelease(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package semver
 
 import (
+	"cmp"
 	"strconv"
 	"strings"
 )
@@ -67,6 +68,19 @@ func (v SemVer) IsLatest() bool {
 	return v.Original == "latest"
 }
 
+// IsPrerelease reports whether the version carries a "-suffix" after its
+// numeric components (e.g. "1.0.0-rc.1").
+func (v SemVer) IsPrerelease() bool {
+	core := v.Normalized()
+	if i := strings.Index(core, "+"); i >= 0 {
+		core = core[:i]
+	}
+	i := strings.IndexFunc(core, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	return i >= 0 && core[i] == '-'
+}
+
 // Compare compares two versions
 // Returns: -1 if v < other, 0 if equal, 1 if v > other
 func (v SemVer) Compare(other SemVer) int {
@@ -81,17 +95,18 @@ func (v SemVer) Compare(other SemVer) int {
 		return 0
 	}
 
-	maxLen := max(len(other.Parts), len(v.Parts))
+	vParts, otherParts := v.coreParts(), other.coreParts()
+	maxLen := max(len(otherParts), len(vParts))
 
 	for i := 0; i < maxLen; i++ {
 		vPart := 0
 		otherPart := 0
 
-		if i < len(v.Parts) {
-			vPart = v.Parts[i]
+		if i < len(vParts) {
+			vPart = vParts[i]
 		}
-		if i < len(other.Parts) {
-			otherPart = other.Parts[i]
+		if i < len(otherParts) {
+			otherPart = otherParts[i]
 		}
 
 		if vPart < otherPart {
@@ -102,7 +117,67 @@ func (v SemVer) Compare(other SemVer) int {
 		}
 	}
 
-	return 0
+	// A prerelease sorts before the release it precedes.
+	vPre, otherPre := v.prerelease(), other.prerelease()
+	switch {
+	case vPre == "" && otherPre == "":
+		return 0
+	case vPre == "":
+		return 1
+	case otherPre == "":
+		return -1
+	}
+	return comparePrerelease(vPre, otherPre)
+}
+
+// prerelease returns the identifiers after "-" (without build metadata),
+// or "" for a release.
+func (v SemVer) prerelease() string {
+	if !v.IsPrerelease() {
+		return ""
+	}
+	core := v.Normalized()
+	if i := strings.Index(core, "+"); i >= 0 {
+		core = core[:i]
+	}
+	_, pre, _ := strings.Cut(core, "-")
+	return pre
+}
+
+// coreParts returns the numeric parts that precede the prerelease suffix;
+// Parse also picks up numbers from inside it ("1.0.0-rc.1" -> [1 0 0 1]).
+func (v SemVer) coreParts() []int {
+	if !v.IsPrerelease() {
+		return v.Parts
+	}
+	core, _, _ := strings.Cut(v.Normalized(), "-")
+	return v.Parts[:min(len(v.Parts), strings.Count(core, ".")+1)]
+}
+
+// comparePrerelease orders dot-separated prerelease identifiers as semver
+// does: numeric identifiers compare numerically and sort before
+// alphanumeric ones, and a shorter list sorts first when it is a prefix.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < min(len(as), len(bs)); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if r := cmp.Compare(an, bn); r != 0 {
+				return r
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if r := strings.Compare(as[i], bs[i]); r != 0 {
+				return r
+			}
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
 }
 
 // Less returns true if v < other (for sorting)
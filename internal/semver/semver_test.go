@@ -43,6 +43,14 @@ func TestCompare(t *testing.T) {
 		{name: "both latest", a: "latest", b: "latest", want: 0},
 		{name: "different lengths", a: "1.0", b: "1.0.0", want: 0},
 		{name: "different lengths unequal", a: "1.0", b: "1.0.1", want: -1},
+		{name: "prerelease before release", a: "1.0.0-rc.1", b: "1.0.0", want: -1},
+		{name: "prerelease after older release", a: "1.0.1-rc.1", b: "1.0.0", want: 1},
+		{name: "prerelease numeric identifiers", a: "1.0.0-rc.2", b: "1.0.0-rc.1", want: 1},
+		{name: "prerelease numeric not lexical", a: "1.0.0-beta.2", b: "1.0.0-beta.11", want: -1},
+		{name: "prerelease alphanumeric", a: "1.0.0-alpha", b: "1.0.0-beta", want: -1},
+		{name: "prerelease numeric before alphanumeric", a: "1.0.0-1", b: "1.0.0-alpha", want: -1},
+		{name: "prerelease shorter first", a: "1.0.0-alpha", b: "1.0.0-alpha.1", want: -1},
+		{name: "prerelease ignores build", a: "1.0.0-rc.1+b2", b: "v1.0.0-rc.1", want: 0},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -32,6 +32,32 @@ var generators = map[string]Generator{
 	"06-daemon":     func(ctx context.Context) (string, error) { return GenerateDaemon() },
 	"10-completion": func(ctx context.Context) (string, error) { return GenerateCompletion() },
 	"20-history":    func(ctx context.Context) (string, error) { return GenerateHistory() },
+	"30-toolenv":    generateToolEnvFor,
+}
+
+// generateToolEnvFor emits the tool env hook for the shell on ctx, or
+// nothing for shells without one.
+func generateToolEnvFor(ctx context.Context) (string, error) {
+	code, err := GenerateToolEnv(shellFrom(ctx))
+	if errors.Is(err, ErrUnsupportedShell) {
+		return "", nil
+	}
+	return code, err
+}
+
+type shellKey struct{}
+
+// WithShell records the target shell ("bash", "zsh") for generators that
+// emit shell-specific code. Generate defaults to bash.
+func WithShell(ctx context.Context, shell string) context.Context {
+	return context.WithValue(ctx, shellKey{}, shell)
+}
+
+func shellFrom(ctx context.Context) string {
+	if shell, ok := ctx.Value(shellKey{}).(string); ok && shell != "" {
+		return shell
+	}
+	return "bash"
 }
 
 // Generate executes all generators in parallel and returns ordered output.
@@ -0,0 +1,127 @@
+package shellgen
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedShell is returned for shells without a generated hook.
+var ErrUnsupportedShell = errors.New("unsupported shell")
+
+// GenerateToolEnv generates the per-directory tool activation hook. On cd it
+// walks up (stopping below $HOME) to the nearest workspaced.cue and, only
+// when that directory changed, asks `workspaced utils shell tool-path` for
+// the bin dirs of the workspace's locked lazy tools and prepends them to
+// PATH, dropping the previous workspace's dirs. tool-path is cached on disk,
+// and cd within one workspace never leaves the shell.
+func GenerateToolEnv(shell string) (string, error) {
+	switch shell {
+	case "", "bash":
+		return toolEnvBash, nil
+	case "zsh":
+		return toolEnvZsh, nil
+	case "fish":
+		return toolEnvFish, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedShell, shell)
+}
+
+const toolEnvBash = `_workspaced_toolenv() {
+	local dir="$PWD" root="" p d
+	while [[ -n "$dir" && "$dir" != "$HOME" && "$dir" != "/" ]]; do
+		if [[ -f "$dir/workspaced.cue" ]]; then
+			root="$dir"
+			break
+		fi
+		dir="${dir%/*}"
+	done
+	[[ "$root" == "${_WORKSPACED_TOOLENV_ROOT-}" ]] && return
+
+	if [[ -n "${_WORKSPACED_TOOLENV_DIRS-}" ]]; then
+		p=":$PATH:"
+		while read -r -d : d; do
+			p="${p//:"$d":/:}"
+		done <<< "$_WORKSPACED_TOOLENV_DIRS:"
+		p="${p#:}"
+		PATH="${p%:}"
+	fi
+	_WORKSPACED_TOOLENV_ROOT="$root"
+	_WORKSPACED_TOOLENV_DIRS=""
+	if [[ -n "$root" ]]; then
+		_WORKSPACED_TOOLENV_DIRS="$(workspaced utils shell tool-path "$root" 2>/dev/null)"
+		if [[ -n "$_WORKSPACED_TOOLENV_DIRS" ]]; then
+			PATH="$_WORKSPACED_TOOLENV_DIRS:$PATH"
+		fi
+	fi
+	export PATH
+}
+
+if [[ -n "$BASH_VERSION" && $- == *i* ]]; then
+	if [[ "$PROMPT_COMMAND" != *"_workspaced_toolenv"* ]]; then
+		PROMPT_COMMAND="_workspaced_toolenv${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+	fi
+fi
+`
+
+const toolEnvZsh = `_workspaced_toolenv() {
+	local dir=$PWD root=
+	while [[ -n $dir && $dir != "$HOME" && $dir != / ]]; do
+		if [[ -f $dir/workspaced.cue ]]; then
+			root=$dir
+			break
+		fi
+		dir=${dir%/*}
+	done
+	[[ $root == "${_WORKSPACED_TOOLENV_ROOT-}" ]] && return
+
+	local -a old
+	old=(${(s.:.)_WORKSPACED_TOOLENV_DIRS-})
+	path=(${path:|old})
+	typeset -g _WORKSPACED_TOOLENV_ROOT=$root
+	typeset -g _WORKSPACED_TOOLENV_DIRS=
+	if [[ -n $root ]]; then
+		_WORKSPACED_TOOLENV_DIRS=$(workspaced utils shell tool-path $root 2>/dev/null)
+		if [[ -n $_WORKSPACED_TOOLENV_DIRS ]]; then
+			path=(${(s.:.)_WORKSPACED_TOOLENV_DIRS} $path)
+		fi
+	fi
+}
+
+if [[ -o interactive ]]; then
+	autoload -Uz add-zsh-hook
+	add-zsh-hook chpwd _workspaced_toolenv
+	_workspaced_toolenv
+fi
+`
+
+const toolEnvFish = `function _workspaced_toolenv --on-variable PWD
+	set -l dir $PWD
+	set -l root
+	while test -n "$dir" -a "$dir" != "$HOME" -a "$dir" != /
+		if test -f "$dir/workspaced.cue"
+			set root $dir
+			break
+		end
+		set dir (string replace -r '/[^/]*$' '' -- $dir)
+	end
+	if test "$root" = "$_WORKSPACED_TOOLENV_ROOT"
+		return
+	end
+
+	for d in $_WORKSPACED_TOOLENV_DIRS
+		while set -l i (contains -i -- $d $PATH)
+			set -e PATH[$i]
+		end
+	end
+	set -g _WORKSPACED_TOOLENV_ROOT $root
+	set -g _WORKSPACED_TOOLENV_DIRS
+	if test -n "$root"
+		set -g _WORKSPACED_TOOLENV_DIRS (workspaced utils shell tool-path $root 2>/dev/null | string split :)
+		set -gx PATH $_WORKSPACED_TOOLENV_DIRS $PATH
+	end
+end
+
+if status is-interactive
+	_workspaced_toolenv
+end
+`
@@ -0,0 +1,45 @@
+package shellgen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateToolEnvParses(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			code, err := GenerateToolEnv(shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bin, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skipf("%s not available", shell)
+			}
+			script := filepath.Join(t.TempDir(), "toolenv."+shell)
+			if err := os.WriteFile(script, []byte(code), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(bin, "-n", script).CombinedOutput(); err != nil {
+				t.Fatalf("%s -n: %v\n%s", shell, err, out)
+			}
+		})
+	}
+}
+
+func TestGenerateToolEnvUnsupported(t *testing.T) {
+	if _, err := GenerateToolEnv("tcsh"); !errors.Is(err, ErrUnsupportedShell) {
+		t.Fatalf("err = %v, want ErrUnsupportedShell", err)
+	}
+}
+
+func TestToolEnvGeneratorSkipsUnsupportedShell(t *testing.T) {
+	code, err := generators["30-toolenv"](WithShell(context.Background(), "tcsh"))
+	if err != nil || code != "" {
+		t.Fatalf("generator = %q, %v; want empty output for an unsupported shell", code, err)
+	}
+}
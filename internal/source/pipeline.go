@@ -78,6 +78,10 @@ type StandardDotfilesOptions struct {
 	// ModulesCfg is the config passed to the module scanner.
 	ModulesCfg *configcue.Config
 
+	// PaletteOnly adds a PaletteScopePlugin right after the scanners so only
+	// palette-aware modules and templates are planned (re-theming).
+	PaletteOnly bool
+
 	// RelocateTo, if non-empty, adds a RelocatePlugin early (right after
 	// scanners). This forces *all* files (config tree + modules) to use this
 	// physical root, interpreting their RelPaths relative to it.
@@ -89,6 +93,7 @@ type StandardDotfilesOptions struct {
 //
 //   - (optional) direct config tree (the "config/" directory with .tmpl rules)
 //   - (optional) module scanner
+//   - (optional) palette scope plugin
 //   - (optional) relocate plugin
 //   - template expander
 //   - dotd processor
@@ -127,6 +132,10 @@ func NewStandardDotfilesPipeline(
 		p.AddPlugin(NewModuleScannerPlugin(opts.ModulesDir, opts.ModulesCfg, 100))
 	}
 
+	if opts.PaletteOnly {
+		p.AddPlugin(NewPaletteScopePlugin())
+	}
+
 	if opts.RelocateTo != "" {
 		p.AddPlugin(NewRelocatePlugin(opts.RelocateTo))
 	}
@@ -164,7 +164,8 @@ func (p *ModuleScannerPlugin) resolveModule(
 				FileType:      fileType,
 				Module:        m.name,
 			},
-			AbsPath: rf.AbsPath,
+			AbsPath:     rf.AbsPath,
+			FromPalette: resolved.Themed,
 		})
 	}
 	return out, nil
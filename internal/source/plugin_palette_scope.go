@@ -0,0 +1,99 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// paletteRef matches template source that reads the desktop palette.
+var paletteRef = regexp.MustCompile(`\.palette\b|\bpaletteExport\b`)
+
+// PaletteScopePlugin narrows a run to what depends on the desktop palette,
+// so a palette change re-renders only themed files. A module is kept whole
+// when any of its templates references .palette (or paletteExport) or it
+// generates files from the palette (core:base16-gtk and friends); from
+// the config tree only such templates are kept, together with the rest of
+// their .d.tmpl group so concatenations stay complete.
+type PaletteScopePlugin struct{}
+
+// NewPaletteScopePlugin creates a palette scope plugin.
+func NewPaletteScopePlugin() *PaletteScopePlugin {
+	return &PaletteScopePlugin{}
+}
+
+func (p *PaletteScopePlugin) Name() string {
+	return "palette-scope"
+}
+
+func (p *PaletteScopePlugin) Process(ctx context.Context, files []File) ([]File, error) {
+	keep := map[string]bool{}
+	for _, f := range files {
+		if pf, ok := f.(PaletteFile); ok && pf.UsesPalette() {
+			keep[paletteScopeKey(f)] = true
+			continue
+		}
+		if !isTemplateFile(f.RelPath()) {
+			continue
+		}
+		refs, err := referencesPalette(f)
+		if err != nil {
+			return nil, err
+		}
+		if refs {
+			keep[paletteScopeKey(f)] = true
+		}
+	}
+
+	var out []File
+	for _, f := range files {
+		if keep[paletteScopeKey(f)] {
+			out = append(out, f)
+		}
+	}
+	logging.GetLogger(ctx).Debug("palette scope", "groups", len(keep), "files", len(out), "of", len(files))
+	return out, nil
+}
+
+// paletteScopeKey groups files that must be planned together.
+func paletteScopeKey(f File) string {
+	if m := moduleNameOf(f); m != "" {
+		return "module:" + m
+	}
+	rel := f.RelPath()
+	if i := strings.Index(rel, ".d.tmpl"+string(filepath.Separator)); i >= 0 {
+		return "dotd:" + filepath.Join(f.TargetBase(), rel[:i])
+	}
+	return "file:" + filepath.Join(f.TargetBase(), rel)
+}
+
+// isTemplateFile reports whether rel is rendered by the template expander
+// (file.tmpl, file.tmpl.ext) or the .d.tmpl processor.
+func isTemplateFile(rel string) bool {
+	if strings.Contains(rel, ".d.tmpl"+string(filepath.Separator)) {
+		return true
+	}
+	parts := strings.Split(filepath.Base(rel), ".")
+	return (len(parts) >= 2 && parts[len(parts)-1] == "tmpl") ||
+		(len(parts) >= 3 && parts[len(parts)-2] == "tmpl")
+}
+
+func referencesPalette(f File) (bool, error) {
+	r, err := f.Reader()
+	if err != nil {
+		return false, fmt.Errorf("read template source %s: %w", f.SourceInfo(), err)
+	}
+	content, err := io.ReadAll(r)
+	if closeErr := r.Close(); closeErr != nil {
+		return false, fmt.Errorf("close template source %s: %w", f.SourceInfo(), closeErr)
+	}
+	if err != nil {
+		return false, err
+	}
+	return paletteRef.Match(content), nil
+}
@@ -0,0 +1,54 @@
+package source
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func TestPaletteScopePlugin(t *testing.T) {
+	t.Parallel()
+	buf := func(rel, module, content string) File {
+		return &BufferFile{
+			BasicFile: BasicFile{RelPathStr: rel, TargetBaseDir: "/home/u", Module: module, Info: rel},
+			Content:   []byte(content),
+		}
+	}
+	files := []File{
+		// Module "kitty": one themed template pulls the whole module in.
+		buf(".config/kitty/kitty.conf", "kitty", "include colors.conf"),
+		buf(".config/kitty/colors.conf.tmpl", "kitty", `{{ paletteExport "kitty" .palette }}`),
+		// Module "git": templates without palette references stay out.
+		buf(".gitconfig.tmpl", "git", "name = {{ .root.user.name }}"),
+		// Module "gtk": generated from the palette by a core module.
+		&StaticFile{
+			BasicFile:   BasicFile{RelPathStr: "gtk-3.0/gtk.css", TargetBaseDir: "/home/u/.local/share/themes/t", Module: "gtk"},
+			FromPalette: true,
+		},
+		// Config tree: a themed fragment keeps its whole .d.tmpl group.
+		buf(filepath.Join(".bashrc.d.tmpl", "10-colors.sh"), "", "export BG={{ .palette.base00 }}"),
+		buf(filepath.Join(".bashrc.d.tmpl", "00-base.sh"), "", "set -o vi"),
+		buf(".config/foot/foot.ini.tmpl", "", "background={{ .palette.base00 }}"),
+		buf(".config/foot/other.ini", "", "font=monospace"),
+		buf(".profile.tmpl", "", "export PALETTE=none"),
+	}
+
+	out, err := NewPaletteScopePlugin().Process(logging.NewWriterContext(t.Output()), files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := relPaths(out)
+	want := []string{
+		".config/kitty/kitty.conf",
+		".config/kitty/colors.conf.tmpl",
+		"gtk-3.0/gtk.css",
+		filepath.Join(".bashrc.d.tmpl", "10-colors.sh"),
+		filepath.Join(".bashrc.d.tmpl", "00-base.sh"),
+		".config/foot/foot.ini.tmpl",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("kept %v\nwant %v", got, want)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"github.com/lucasew/workspaced/internal/configcue"
 	"github.com/lucasew/workspaced/internal/template"
+	"github.com/lucasew/workspaced/pkg/palette"
 	"io"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // TemplateExpanderPlugin renders templates and expands multi-file output.
@@ -32,6 +34,15 @@ func (p *TemplateExpanderPlugin) Name() string {
 func (p *TemplateExpanderPlugin) Process(ctx context.Context, files []File) ([]File, error) {
 	result := []File{}
 	globalCfg, _ := p.data.(*configcue.Config)
+	// Resolved once per run and only when a template is found: an image
+	// source runs palette extraction.
+	paletteData := sync.OnceValues(func() (map[string]any, error) {
+		resolved, err := palette.FromConfig(ctx, globalCfg)
+		if err != nil || resolved == nil {
+			return nil, err
+		}
+		return resolved.TemplateData(), nil
+	})
 
 	for _, f := range files {
 		// Detect if the file is a template
@@ -68,7 +79,11 @@ func (p *TemplateExpanderPlugin) Process(ctx context.Context, files []File) ([]F
 			return nil, err
 		}
 
-		templateData, err := buildTemplateData(ctx, globalCfg, f)
+		pal, err := paletteData()
+		if err != nil {
+			return nil, fmt.Errorf("resolve desktop palette: %w", err)
+		}
+		templateData, err := buildTemplateData(ctx, globalCfg, pal, f)
 		if err != nil {
 			return nil, err
 		}
@@ -11,7 +11,7 @@ import (
 	"github.com/pbnjay/memory"
 )
 
-func buildTemplateData(ctx context.Context, cfg *configcue.Config, f File) (map[string]any, error) {
+func buildTemplateData(ctx context.Context, cfg *configcue.Config, palette map[string]any, f File) (map[string]any, error) {
 	root := map[string]any{}
 	if cfg != nil {
 		root = cfg.Raw()
@@ -69,6 +69,9 @@ func buildTemplateData(ctx context.Context, cfg *configcue.Config, f File) (map[
 		"module":  module,
 		"runtime": runtimeData,
 	}
+	if palette != nil {
+		out["palette"] = palette
+	}
 
 	return out, nil
 }
@@ -71,6 +71,13 @@ type ScopedFile interface {
 	ModuleName() string
 }
 
+// PaletteFile is implemented by files whose content derives from the
+// desktop palette without being a template (generated theme bundles).
+type PaletteFile interface {
+	File
+	UsesPalette() bool
+}
+
 // BasicFile implements common File fields.
 type BasicFile struct {
 	RelPathStr    string
@@ -95,8 +102,13 @@ func (f *BasicFile) LinkTarget() (string, error) {
 type StaticFile struct {
 	BasicFile
 	AbsPath string
+	// FromPalette marks files a module generated from the desktop palette.
+	FromPalette bool
 }
 
+// UsesPalette reports whether the file was generated from the palette.
+func (f *StaticFile) UsesPalette() bool { return f.FromPalette }
+
 func (f *StaticFile) Reader() (io.ReadCloser, error) {
 	return os.Open(f.AbsPath)
 }
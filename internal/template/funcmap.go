@@ -2,6 +2,7 @@ package template
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/lucasew/workspaced/internal/icons"
@@ -10,6 +11,8 @@ import (
 	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
 	shimdriver "github.com/lucasew/workspaced/pkg/driver/shim"
 	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	paletteexport "github.com/lucasew/workspaced/pkg/palette/export"
 	"os"
 	"path/filepath"
 	"strings"
@@ -116,7 +119,35 @@ func makeFuncMap(ctx context.Context) template.FuncMap {
 		},
 		"lockTool":   lockTool,
 		"lockSource": lockSource,
+		// Palette helpers
+		"paletteExport": func(format string, palette any) (string, error) {
+			p, err := toPalette(palette)
+			if err != nil {
+				return "", err
+			}
+			return paletteexport.String(format, p, paletteexport.Options{})
+		},
+	}
+}
+
+// toPalette accepts an *api.Palette or anything with base00..base17 keys
+// (a module config map, decoded palette JSON).
+func toPalette(v any) (*api.Palette, error) {
+	switch p := v.(type) {
+	case *api.Palette:
+		return p, nil
+	case api.Palette:
+		return &p, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("paletteExport: %w", err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("paletteExport: palette must have base00..base0F keys: %w", err)
 	}
+	return &p, nil
 }
 
 func makeLockLookups(ctx context.Context) (func(string) map[string]any, func(string) map[string]any) {
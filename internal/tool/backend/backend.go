@@ -8,10 +8,10 @@
 //   - Install(version, destDir)
 //   - EnrichLockfile (mutates the RenovateDependency entry in the lockfile)
 //
-// Optional richer interfaces: ArtifactTool, BinaryTool, InstallFixer.
+// Optional richer interfaces: ArtifactTool, BinaryTool, InstallFixer, DigestTool.
 // Install-directory validation lives in internal/tool/checks (InstallChecker).
 //
-// Concrete backends live in sibling directories: github, mise, catalog.
+// Concrete backends live in sibling directories: github, mise, gomod, catalog.
 package backend
 
 import (
@@ -85,6 +85,32 @@ type InstallFixer interface {
 	Fix(ctx context.Context, destDir string) error
 }
 
+// DigestTool is an optional extension for Tools whose upstream defines a
+// content hash for a version (e.g. the go.sum h1: hash of a Go module).
+// The digest is pinned next to the version in the lockfile (currentDigest).
+type DigestTool interface {
+	Tool
+	Digest(ctx context.Context, version string) (string, error)
+}
+
+type lockedDigestKey struct{}
+
+// WithLockedDigest records the digest pinned in the lockfile for the tool
+// about to be installed. DigestTool backends check what they fetch against
+// it before installing.
+func WithLockedDigest(ctx context.Context, digest string) context.Context {
+	if digest == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, lockedDigestKey{}, digest)
+}
+
+// LockedDigest returns the digest set by WithLockedDigest, or "".
+func LockedDigest(ctx context.Context) string {
+	digest, _ := ctx.Value(lockedDigestKey{}).(string)
+	return digest
+}
+
 // --- Transitional / compatibility surface ---
 // These are kept while we migrate internal call sites to the Tool-based path.
 // New code should prefer Backend.Tool(ref) + the Tool interface and its extensions.
@@ -192,7 +218,8 @@ func ScoreArtifact(a Artifact, osName, arch, binaryHint string) int {
 	}
 
 	// Mild preference for common CLI archive formats.
-	if strings.HasSuffix(base, ".tar.gz") || strings.HasSuffix(base, ".tgz") || strings.HasSuffix(base, ".zip") || strings.HasSuffix(base, ".tar.xz") {
+	if strings.HasSuffix(base, ".tar.gz") || strings.HasSuffix(base, ".tgz") || strings.HasSuffix(base, ".zip") || strings.HasSuffix(base, ".tar.xz") ||
+		strings.HasSuffix(base, ".tar.zst") || strings.HasSuffix(base, ".tar.bz2") {
 		score += 10
 	}
 
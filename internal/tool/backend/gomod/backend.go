@@ -0,0 +1,305 @@
+// Package gomod is the tool backend for Go programs that are only published
+// as `go install` targets ("go:golang.org/x/tools/cmd/goimports@v0.30.0").
+//
+// Versions come from the module proxy protocol (GOPROXY, including file://
+// proxies), builds use the go toolchain from the "golang" catalog entry and
+// the lock pins the module version plus its go.sum h1: hash.
+package gomod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lucasew/workspaced/internal/modfile"
+	"github.com/lucasew/workspaced/internal/tool"
+	"github.com/lucasew/workspaced/internal/tool/backend"
+	"github.com/lucasew/workspaced/internal/tool/checks"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+var (
+	// ErrEmptyGoRef is returned when a go ref is empty.
+	ErrEmptyGoRef = errors.New("go ref cannot be empty (expected a package path)")
+	// ErrInvalidModulePath is returned when the ref is not a valid Go import path.
+	ErrInvalidModulePath = errors.New("invalid go package path")
+	// ErrInvalidVersion is returned when a version cannot be used as a module version.
+	ErrInvalidVersion = errors.New("invalid go module version")
+	// ErrNoProxy is returned when GOPROXY only lists "direct" or "off".
+	ErrNoProxy = errors.New("no usable module proxy in GOPROXY")
+	// ErrNotFound is returned when no proxy knows the requested module or version.
+	ErrNotFound = errors.New("not found on module proxy")
+	// ErrNoVersions is returned when a module has no usable versions.
+	ErrNoVersions = errors.New("no versions found")
+	// ErrModuleNotFound is returned when no prefix of the package path is a module.
+	ErrModuleNotFound = errors.New("no module provides package")
+	// ErrDigestMismatch is returned when the proxy serves a module zip whose
+	// h1: hash differs from the locked one.
+	ErrDigestMismatch = errors.New("module digest mismatch")
+)
+
+// toolchainTool and toolchainFallback select the go toolchain used for
+// builds: the "golang" lazy tool when configured (so the lock pins it),
+// else the latest "golang" catalog entry.
+const (
+	toolchainTool     = "golang"
+	toolchainFallback = "registry:golang@latest"
+)
+
+// goCommand builds the *exec.Cmd for the go toolchain. Tests replace it to
+// use the host go binary instead of downloading one.
+var goCommand = func(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	return tool.EnsureAndRunLazyWithFallback(ctx, toolchainTool, "go", toolchainFallback, args...)
+}
+
+// modulePaths caches package path -> module path. EnrichLockfile has no
+// context to query the proxy with, so it reads what ListVersions/Digest found.
+var modulePaths sync.Map
+
+func init() {
+	tool.Register("go", &Backend{})
+}
+
+type Backend struct{}
+
+func (p *Backend) Name() string { return "Go modules (go install)" }
+
+// Tool returns a first-class Tool for the given package path.
+func (p *Backend) Tool(ref string) (backend.Tool, error) {
+	return NewTool(ref)
+}
+
+// GoTool builds one main package from a Go module with `go install`.
+type GoTool struct {
+	pkg     string
+	proxies []proxyEntry
+}
+
+// NewTool constructs a GoTool for the given package path
+// (e.g. "golang.org/x/tools/cmd/goimports"). Proxies come from GOPROXY.
+func NewTool(ref string) (backend.Tool, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, ErrEmptyGoRef
+	}
+	if err := module.CheckImportPath(ref); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidModulePath, err)
+	}
+	return &GoTool{pkg: ref, proxies: proxyFromEnv()}, nil
+}
+
+// ListVersions returns module versions newest-first. Prereleases are only
+// listed when the module has no release versions.
+func (t *GoTool) ListVersions(ctx context.Context) ([]string, error) {
+	modPath, err := t.modulePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := listVersions(ctx, t.proxies, modPath)
+	if err != nil {
+		return nil, err
+	}
+	return sortVersions(raw)
+}
+
+func sortVersions(raw []string) ([]string, error) {
+	var releases, prereleases []string
+	for _, v := range raw {
+		if !semver.IsValid(v) {
+			continue
+		}
+		if semver.Prerelease(v) != "" {
+			prereleases = append(prereleases, v)
+		} else {
+			releases = append(releases, v)
+		}
+	}
+	out := releases
+	if len(out) == 0 {
+		out = prereleases
+	}
+	if len(out) == 0 {
+		return nil, ErrNoVersions
+	}
+	slices.SortFunc(out, func(a, b string) int { return semver.Compare(b, a) })
+	return out, nil
+}
+
+// Install runs `go install pkg@version` with GOBIN pointing at destDir/bin.
+// When the lockfile pins a digest (backend.WithLockedDigest) the build runs
+// inside a scratch module whose go.sum holds that digest, so the go command
+// itself verifies the exact zip it builds from, cached or downloaded.
+func (t *GoTool) Install(ctx context.Context, version string, destDir string) error {
+	version, err := t.resolveVersion(ctx, version)
+	if err != nil {
+		return err
+	}
+	binDir := filepath.Join(destDir, "bin")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		return err
+	}
+
+	target := t.pkg + "@" + version
+	args := []string{"install", target}
+	dir := destDir
+	locked := backend.LockedDigest(ctx)
+	if locked != "" {
+		modPath, err := t.modulePath(ctx)
+		if err != nil {
+			return err
+		}
+		dir, err = pinnedModule(modPath, version, locked)
+		if err != nil {
+			return err
+		}
+		defer logging.RunCleanup(ctx, "remove", func() error { return os.RemoveAll(dir) })
+		args = []string{"install", "-mod=mod", t.pkg}
+	}
+	cmd, err := goCommand(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("go toolchain: %w", err)
+	}
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GOBIN="+binDir,
+		"GOPROXY="+proxyValue(t.proxies),
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+	)
+	if offline(t.proxies) {
+		// The checksum database is online; with only local proxies the
+		// locked digest is what vouches for the module.
+		cmd.Env = append(cmd.Env, "GOSUMDB=off")
+	}
+	// Tee stderr: the go command reports a go.sum mismatch only there.
+	var stderr strings.Builder
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, &stderr)
+	} else {
+		cmd.Stderr = &stderr
+	}
+	logging.GetLogger(ctx).Info("building go tool", "target", target, "dest", destDir)
+	if err := cmd.Run(); err != nil {
+		if locked != "" && strings.Contains(stderr.String(), "checksum mismatch") {
+			return fmt.Errorf("%w: %s: locked %s", ErrDigestMismatch, target, locked)
+		}
+		return fmt.Errorf("go install %s: %w", target, err)
+	}
+	return nil
+}
+
+// pinnedModule writes a scratch module requiring modPath@version with the
+// locked h1: hash in its go.sum and returns its directory.
+func pinnedModule(modPath, version, digest string) (string, error) {
+	dir, err := os.MkdirTemp("", "workspaced-gomod-*")
+	if err != nil {
+		return "", err
+	}
+	gomod := fmt.Sprintf("module workspaced.local/install\n\nrequire %s %s\n", modPath, version)
+	gosum := fmt.Sprintf("%s %s %s\n", modPath, version, digest)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		return "", errors.Join(err, os.RemoveAll(dir))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(gosum), 0o644); err != nil {
+		return "", errors.Join(err, os.RemoveAll(dir))
+	}
+	return dir, nil
+}
+
+// Digest returns the go.sum h1: hash of the module zip for version.
+func (t *GoTool) Digest(ctx context.Context, version string) (string, error) {
+	version, err := t.resolveVersion(ctx, version)
+	if err != nil {
+		return "", err
+	}
+	modPath, err := t.modulePath(ctx)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp("", "workspaced-gomod-*.zip")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+	logging.Close(ctx, tmp)
+	defer logging.RunCleanup(ctx, "remove", func() error { return os.Remove(tmpPath) })
+
+	if err := downloadZip(ctx, t.proxies, modPath, version, tmpPath); err != nil {
+		return "", err
+	}
+	return dirhash.HashZip(tmpPath, dirhash.Hash1)
+}
+
+// EnrichLockfile sets Renovate's go datasource. The module path is only
+// known once the proxy has been queried; until then existing fields stay.
+func (t *GoTool) EnrichLockfile(entry *modfile.RenovateDependency) {
+	entry.Datasource = "go"
+	if modPath, ok := modulePaths.Load(t.pkg); ok {
+		entry.DepName = modPath.(string)
+	}
+}
+
+func (t *GoTool) InstallChecks() []checks.Check {
+	return checks.Checks(checks.Binary(binaryName(t.pkg)))
+}
+
+func (t *GoTool) resolveVersion(ctx context.Context, version string) (string, error) {
+	version = strings.TrimSpace(version)
+	if version == "" || version == "latest" {
+		versions, err := t.ListVersions(ctx)
+		if err != nil {
+			return "", err
+		}
+		return versions[0], nil
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidVersion, version)
+	}
+	return version, nil
+}
+
+// modulePath finds the module that provides t.pkg by probing the proxy
+// from the longest path prefix down, like the go command does.
+func (t *GoTool) modulePath(ctx context.Context) (string, error) {
+	if cached, ok := modulePaths.Load(t.pkg); ok {
+		return cached.(string), nil
+	}
+	for candidate := t.pkg; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+		if _, _, ok := module.SplitPathVersion(candidate); !ok {
+			continue
+		}
+		_, err := fetch(ctx, t.proxies, candidate, "@v/list")
+		if err == nil {
+			modulePaths.Store(t.pkg, candidate)
+			return candidate, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrModuleNotFound, t.pkg)
+}
+
+// binaryName mirrors the go command: the last path element, skipping a
+// major version suffix ("example.com/foo/v2" installs "foo").
+func binaryName(pkg string) string {
+	base := path.Base(pkg)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		return path.Base(path.Dir(pkg))
+	}
+	return base
+}
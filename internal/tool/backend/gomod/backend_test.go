@@ -0,0 +1,189 @@
+package gomod
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/internal/tool/backend"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	_ "github.com/lucasew/workspaced/pkg/driver/exec/native"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// writeFileProxy lays out a GOPROXY directory serving example.com/hello
+// with a cmd/hello main package for each version.
+func writeFileProxy(t *testing.T, versions ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	modDir := filepath.Join(root, "example.com", "hello", "@v")
+	if err := os.MkdirAll(modDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gomod := "module example.com/hello\n\ngo 1.21\n"
+	for _, v := range versions {
+		if err := os.WriteFile(filepath.Join(modDir, v+".mod"), []byte(gomod), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(modDir, v+".info"), []byte(`{"Version":"`+v+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(filepath.Join(modDir, v+".zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := zip.NewWriter(f)
+		prefix := "example.com/hello@" + v + "/"
+		for name, body := range map[string]string{
+			"go.mod":            gomod,
+			"cmd/hello/main.go": "package main\n\nfunc main() { println(\"" + v + "\") }\n",
+		} {
+			w, err := zw.Create(prefix + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte(body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(modDir, "list"), []byte(strings.Join(versions, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestProxyList(t *testing.T) {
+	t.Parallel()
+
+	got := proxyList("https://a.example, direct|file:///srv/proxy/,off")
+	want := []proxyEntry{
+		{url: "https://a.example"},
+		{url: "file:///srv/proxy", fallthroughAny: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("proxyList = %#v, want %#v", got, want)
+	}
+	if got := proxyValue(proxyList("https://a|https://b,https://c")); got != "https://a|https://b,https://c" {
+		t.Fatalf("proxyValue round trip = %q", got)
+	}
+	if got := proxyList("direct"); len(got) != 0 {
+		t.Fatalf("direct-only GOPROXY should yield no proxies, got %#v", got)
+	}
+}
+
+func TestBinaryName(t *testing.T) {
+	t.Parallel()
+
+	for pkg, want := range map[string]string{
+		"golang.org/x/tools/cmd/goimports": "goimports",
+		"example.com/foo/v2":               "foo",
+		"example.com/vendor":               "vendor",
+	} {
+		if got := binaryName(pkg); got != want {
+			t.Errorf("binaryName(%q) = %q, want %q", pkg, got, want)
+		}
+	}
+}
+
+func TestListVersionsFromFileProxy(t *testing.T) {
+	proxy := writeFileProxy(t, "v1.0.0", "v1.2.0-rc.1", "v1.1.0")
+	t.Setenv("GOPROXY", "file://"+proxy)
+	ctx := logging.NewWriterContext(t.Output())
+
+	tt, err := NewTool("example.com/hello/cmd/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := tt.ListVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v1.1.0", "v1.0.0"}; !reflect.DeepEqual(versions, want) {
+		t.Fatalf("ListVersions = %v, want %v", versions, want)
+	}
+	if modPath, _ := tt.(*GoTool).modulePath(ctx); modPath != "example.com/hello" {
+		t.Fatalf("module path = %q, want example.com/hello", modPath)
+	}
+
+	missing, err := NewTool("example.com/missing/cmd/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := missing.ListVersions(ctx); err == nil {
+		t.Fatal("expected error for unknown module")
+	}
+}
+
+func TestInstallAndDigestFromFileProxy(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not on PATH")
+	}
+	proxy := writeFileProxy(t, "v1.0.0")
+	t.Setenv("GOPROXY", "file://"+proxy)
+	// Install must turn the checksum database off itself for file:// proxies.
+	t.Setenv("GOSUMDB", "sum.golang.org")
+	t.Setenv("GOFLAGS", "-modcacherw")
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("GOCACHE", t.TempDir())
+
+	orig := goCommand
+	goCommand = func(ctx context.Context, args ...string) (*exec.Cmd, error) {
+		return execdriver.Run(ctx, goBin, args...)
+	}
+	t.Cleanup(func() { goCommand = orig })
+
+	ctx := logging.NewWriterContext(t.Output())
+	tt, err := NewTool("example.com/hello/cmd/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := dirhash.HashZip(filepath.Join(proxy, "example.com", "hello", "@v", "v1.0.0.zip"), dirhash.Hash1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := tt.(*GoTool).Digest(ctx, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != want || !strings.HasPrefix(digest, "h1:") {
+		t.Fatalf("Digest = %q, want %q", digest, want)
+	}
+
+	bad := t.TempDir()
+	err = tt.Install(backend.WithLockedDigest(ctx, "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="), "1.0.0", bad)
+	if !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("Install with a wrong locked digest: err = %v, want ErrDigestMismatch", err)
+	}
+	if _, err := os.Stat(filepath.Join(bad, "bin", "hello")); err == nil {
+		t.Fatal("built despite the digest mismatch")
+	}
+
+	dest := t.TempDir()
+	if err := tt.Install(backend.WithLockedDigest(ctx, want), "1.0.0", dest); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "bin", "hello")); err != nil {
+		t.Fatalf("expected bin/hello: %v", err)
+	}
+
+	// The module is cached now; the cached copy must be checked too.
+	err = tt.Install(backend.WithLockedDigest(ctx, "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="), "1.0.0", t.TempDir())
+	if !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("Install from cache with a wrong locked digest: err = %v, want ErrDigestMismatch", err)
+	}
+}
@@ -0,0 +1,196 @@
+package gomod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/httpclient"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"golang.org/x/mod/module"
+)
+
+// defaultProxy is used when GOPROXY is unset, mirroring the go command.
+const defaultProxy = "https://proxy.golang.org,direct"
+
+// proxyEntry is one element of a GOPROXY list. fallthroughAny is true when
+// the entry was followed by '|' (try the next proxy on any error) instead
+// of ',' (only on not found).
+type proxyEntry struct {
+	url            string
+	fallthroughAny bool
+}
+
+// proxyList parses a GOPROXY value. "direct" and "off" are dropped: this
+// backend only talks the module proxy protocol.
+func proxyList(value string) []proxyEntry {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = defaultProxy
+	}
+	var out []proxyEntry
+	for value != "" {
+		end := strings.IndexAny(value, ",|")
+		item, sep := value, byte(0)
+		if end >= 0 {
+			item, sep = value[:end], value[end]
+			value = value[end+1:]
+		} else {
+			value = ""
+		}
+		item = strings.TrimRight(strings.TrimSpace(item), "/")
+		if item == "" || item == "direct" || item == "off" {
+			continue
+		}
+		out = append(out, proxyEntry{url: item, fallthroughAny: sep == '|'})
+	}
+	return out
+}
+
+// proxyFromEnv returns the configured module proxies (GOPROXY).
+func proxyFromEnv() []proxyEntry {
+	return proxyList(os.Getenv("GOPROXY"))
+}
+
+// proxyValue renders the entries back into a GOPROXY string so the go
+// command used for builds sees the same proxies we resolved against.
+func proxyValue(entries []proxyEntry) string {
+	var b strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			if entries[i-1].fallthroughAny {
+				b.WriteByte('|')
+			} else {
+				b.WriteByte(',')
+			}
+		}
+		b.WriteString(e.url)
+	}
+	return b.String()
+}
+
+// offline reports whether every proxy is a file:// directory.
+func offline(entries []proxyEntry) bool {
+	for _, e := range entries {
+		if !strings.HasPrefix(e.url, "file://") {
+			return false
+		}
+	}
+	return len(entries) > 0
+}
+
+// fetch walks the proxy list for the escaped module path and request suffix
+// (e.g. "@v/list"). It returns ErrNotFound when every proxy reports a miss.
+func fetch(ctx context.Context, proxies []proxyEntry, modPath, suffix string) ([]byte, error) {
+	if len(proxies) == 0 {
+		return nil, ErrNoProxy
+	}
+	escaped, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidModulePath, err)
+	}
+	rel := escaped + "/" + suffix
+	var lastErr error
+	for _, p := range proxies {
+		data, err := fetchOne(ctx, p.url, rel)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+		if !p.fallthroughAny && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+func fetchOne(ctx context.Context, base, rel string) ([]byte, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("parse GOPROXY entry %q: %w", base, err)
+	}
+	if u.Scheme == "file" {
+		data, err := os.ReadFile(filepath.Join(filepath.FromSlash(u.Path), filepath.FromSlash(rel)))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, base, rel)
+		}
+		return data, err
+	}
+
+	target := base + "/" + rel
+	logging.GetLogger(ctx).Debug("fetching from module proxy", "url", target)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	hc, err := driver.Get[httpclient.Driver](ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get http client: %w", err)
+	}
+	resp, err := hc.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer logging.Close(ctx, resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, target)
+	default:
+		return nil, fmt.Errorf("module proxy %s: %s", target, resp.Status)
+	}
+}
+
+// listVersions returns the raw "@v/list" entries for modPath. When the list
+// is empty (modules with only pseudo-versions) it falls back to "@latest".
+func listVersions(ctx context.Context, proxies []proxyEntry, modPath string) ([]string, error) {
+	data, err := fetch(ctx, proxies, modPath, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if v := strings.TrimSpace(line); v != "" {
+			out = append(out, v)
+		}
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	data, err = fetch(ctx, proxies, modPath, "@latest")
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parse %s@latest: %w", modPath, err)
+	}
+	if info.Version == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoVersions, modPath)
+	}
+	return []string{info.Version}, nil
+}
+
+// downloadZip stores the module zip for modPath@version at dest.
+func downloadZip(ctx context.Context, proxies []proxyEntry, modPath, version, dest string) error {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidVersion, err)
+	}
+	data, err := fetch(ctx, proxies, modPath, "@v/"+escapedVersion+".zip")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0o644)
+}
@@ -0,0 +1,116 @@
+package install
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// arHeaderLen is the fixed size of a System V / GNU ar member header.
+const arHeaderLen = 60
+
+// extractDeb unpacks the data.tar.* member of a Debian package into dest.
+// A top-level usr/ is hoisted so usr/bin/foo lands at bin/foo, the layout
+// FindBinary and the install checks expect.
+func extractDeb(ctx context.Context, src, dest string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer logging.Close(ctx, file)
+
+	r := bufio.NewReader(file)
+	magic := make([]byte, 8)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return fmt.Errorf("read ar magic: %w", err)
+	}
+	if string(magic) != "!<arch>\n" {
+		return fmt.Errorf("%s: not an ar archive", filepath.Base(src))
+	}
+
+	for {
+		name, size, err := nextArMember(r)
+		if errors.Is(err, io.EOF) {
+			return ErrUnsupportedDeb
+		}
+		if err != nil {
+			return err
+		}
+		member := io.LimitReader(r, size)
+		if strings.HasPrefix(name, "data.tar") {
+			br := bufio.NewReaderSize(member, sniffLen)
+			head, err := br.Peek(sniffLen)
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			kind, ok := sniffFormat(head)
+			if !ok {
+				kind = formatFromName(name)
+			}
+			if err := extractStreamReader(ctx, kind, br, name, dest); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return hoistUsr(dest)
+		}
+		// Members are 2-byte aligned.
+		if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+			return err
+		}
+	}
+}
+
+// nextArMember reads one ar header and returns the member name and size.
+func nextArMember(r io.Reader) (string, int64, error) {
+	header := make([]byte, arHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return "", 0, fmt.Errorf("truncated ar header: %w", err)
+		}
+		return "", 0, err
+	}
+	if string(header[58:60]) != "`\n" {
+		return "", 0, errors.New("malformed ar header")
+	}
+	name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+	size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+	if err != nil || size < 0 {
+		return "", 0, fmt.Errorf("malformed ar member size for %q", name)
+	}
+	return name, size, nil
+}
+
+func hoistUsr(dest string) error {
+	usr := filepath.Join(dest, "usr")
+	info, err := os.Lstat(usr)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return nil
+	}
+	entries, err := os.ReadDir(usr)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		target := filepath.Join(dest, entry.Name())
+		if _, err := os.Lstat(target); err == nil {
+			continue
+		}
+		if err := os.Rename(filepath.Join(usr, entry.Name()), target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package install
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/lucasew/workspaced/internal/archive"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/ulikunitz/xz"
+)
+
+// ErrUnsupportedDeb is returned when a .deb has no data.tar member.
+var ErrUnsupportedDeb = errors.New("deb package has no data.tar member")
+
+// format is what Extract decided a downloaded file is.
+type format int
+
+const (
+	formatBinary format = iota
+	formatZip
+	formatTar
+	formatGzip
+	formatXz
+	formatZstd
+	formatBzip2
+	formatDeb
+	formatAppImage
+)
+
+// sniffLen covers the tar "ustar" magic at offset 257.
+const sniffLen = 512
+
+// detectFormat looks at the file's magic bytes first so a mislabelled asset
+// (".zip" that is really a tarball) still extracts; the name is only used
+// when the content is not recognised.
+func detectFormat(ctx context.Context, path string) (format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return formatBinary, err
+	}
+	defer logging.Close(ctx, f)
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return formatBinary, err
+	}
+	if kind, ok := sniffFormat(head[:n]); ok {
+		return kind, nil
+	}
+	return formatFromName(path), nil
+}
+
+func sniffFormat(head []byte) (format, bool) {
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return formatZip, true
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return formatGzip, true
+	case bytes.HasPrefix(head, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return formatXz, true
+	case bytes.HasPrefix(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return formatZstd, true
+	case bytes.HasPrefix(head, []byte("BZh")):
+		return formatBzip2, true
+	case bytes.HasPrefix(head, []byte("!<arch>\n")):
+		return formatDeb, true
+	case bytes.HasPrefix(head, []byte("\x7fELF")) && len(head) > 10 && head[8] == 'A' && head[9] == 'I' && (head[10] == 1 || head[10] == 2):
+		// AppImage type 1/2 magic lives in the ELF e_ident padding.
+		return formatAppImage, true
+	case isTarHeader(head):
+		return formatTar, true
+	}
+	return formatBinary, false
+}
+
+func isTarHeader(head []byte) bool {
+	return len(head) >= 262 && string(head[257:262]) == "ustar"
+}
+
+func formatFromName(path string) format {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return formatZip
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return formatGzip
+	case strings.HasSuffix(name, ".tar.xz"), strings.HasSuffix(name, ".txz"):
+		return formatXz
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		return formatZstd
+	case strings.HasSuffix(name, ".tar.bz2"), strings.HasSuffix(name, ".tbz2"), strings.HasSuffix(name, ".tbz"):
+		return formatBzip2
+	case strings.HasSuffix(name, ".tar"):
+		return formatTar
+	case strings.HasSuffix(name, ".deb"):
+		return formatDeb
+	case strings.HasSuffix(name, ".appimage"):
+		return formatAppImage
+	}
+	return formatBinary
+}
+
+// decompressor wraps r for a compressed format; formatTar passes through.
+func decompressor(kind format, r io.Reader) (io.ReadCloser, error) {
+	switch kind {
+	case formatGzip:
+		return gzip.NewReader(r)
+	case formatXz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xr), nil
+	case formatZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	case formatBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	case formatTar:
+		return io.NopCloser(r), nil
+	}
+	return nil, fmt.Errorf("not a stream format: %d", kind)
+}
+
+// extractStream unpacks a (possibly compressed) tarball. A compressed
+// stream that is not a tarball ("tool-linux-amd64.zst") is installed as a
+// single executable named after the file without its compression suffix.
+func extractStream(ctx context.Context, kind format, src, dest string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer logging.Close(ctx, file)
+	return extractStreamReader(ctx, kind, file, filepath.Base(src), dest)
+}
+
+func extractStreamReader(ctx context.Context, kind format, r io.Reader, name, dest string) error {
+	dr, err := decompressor(kind, r)
+	if err != nil {
+		return err
+	}
+	defer logging.Close(ctx, dr)
+
+	br := bufio.NewReaderSize(dr, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if kind == formatTar || isTarHeader(head) || looksLikeTarName(name) {
+		return untar(ctx, tar.NewReader(br), dest)
+	}
+
+	target, err := archive.JoinWithin(dest, NormalizeBinaryName(trimCompressionSuffix(name)))
+	if err != nil {
+		return err
+	}
+	return archive.WriteMember(target, 0o755, br)
+}
+
+// looksLikeTarName covers pre-POSIX tarballs that lack the "ustar" magic.
+func looksLikeTarName(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, ".tar") || hasAnySuffix(lower, ".tgz", ".txz", ".tzst", ".tbz", ".tbz2")
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func trimCompressionSuffix(name string) string {
+	lower := strings.ToLower(name)
+	for _, suffix := range []string{".gz", ".xz", ".zst", ".bz2"} {
+		if strings.HasSuffix(lower, suffix) {
+			return name[:len(name)-len(suffix)]
+		}
+	}
+	return name
+}
+
+// installAppImage installs the AppImage itself as an executable. Running
+// it needs FUSE (or APPIMAGE_EXTRACT_AND_RUN=1), same as a manual download.
+func installAppImage(ctx context.Context, src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer logging.Close(ctx, in)
+
+	name := filepath.Base(src)
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".appimage") {
+		name = strings.TrimSuffix(name, ext)
+	}
+	target, err := archive.JoinWithin(dest, NormalizeBinaryName(name))
+	if err != nil {
+		return err
+	}
+	return archive.WriteMember(target, 0o755, in)
+}
@@ -0,0 +1,206 @@
+package install
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// toolTarBz2 is tool-1.0/bin/tool (mode 0755) as a ustar .tar.bz2; the
+// standard library has no bzip2 writer.
+const toolTarBz2 = "QlpoOTFBWSZTWfXU0JcAAH/9gMqQQABoA/uAAAD6ZZ4QCAggAHUNKeoBoADJtQG0gkqA00DQAAAfbUj6EDIUIRXzbIWzNYgQ8Hu8B1Z87G5vZe+CawwQQyFLpsWv1Q56iXkXfIsIBwUPMMC2RrNYyuSQfi7kinChIeupoS4="
+
+func tarBytes(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Format: tar.FormatUSTAR}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func compress(t *testing.T, kind string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch kind {
+	case "gz":
+		w = gzip.NewWriter(&buf)
+	case "xz":
+		w, err = xz.NewWriter(&buf)
+	case "zst":
+		w, err = zstd.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown compression %q", kind)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func arBytes(members map[string][]byte, order ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("!<arch>\n")
+	for _, name := range order {
+		data := members[name]
+		fmt.Fprintf(&buf, "%-16s%-12s%-6s%-6s%-8s%-10d`\n", name, "0", "0", "0", "100644", len(data))
+		buf.Write(data)
+		if len(data)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeArchive(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func assertExecutable(t *testing.T, path string) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected %s: %v", path, err)
+	}
+	if info.Mode()&0o111 == 0 {
+		t.Fatalf("%s is not executable: %v", path, info.Mode())
+	}
+}
+
+func TestExtractCompressedTarballs(t *testing.T) {
+	t.Parallel()
+
+	plain := tarBytes(t, map[string]string{"tool-1.0/bin/tool": "#!/bin/sh\n"})
+	bz2, err := base64.StdEncoding.DecodeString(toolTarBz2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string][]byte{
+		"tool.tar.gz":  compress(t, "gz", plain),
+		"tool.tar.xz":  compress(t, "xz", plain),
+		"tool.tar.zst": compress(t, "zst", plain),
+		"tool.tar.bz2": bz2,
+		"tool.tar":     plain,
+		// Extension lies: content sniffing must win.
+		"tool.zip": compress(t, "zst", plain),
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dest := t.TempDir()
+			if err := Extract(t.Context(), writeArchive(t, name, data), dest); err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			assertExecutable(t, filepath.Join(dest, "tool-1.0", "bin", "tool"))
+		})
+	}
+}
+
+func TestExtractCompressedSingleBinary(t *testing.T) {
+	t.Parallel()
+
+	dest := t.TempDir()
+	src := writeArchive(t, "tool-linux-amd64.zst", compress(t, "zst", []byte("\x7fELFnot-really")))
+	if err := Extract(t.Context(), src, dest); err != nil {
+		t.Fatal(err)
+	}
+	assertExecutable(t, filepath.Join(dest, "tool"))
+}
+
+func TestExtractZstdRejectsPathTraversal(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dest := filepath.Join(root, "dest")
+	src := writeArchive(t, "bad.tar.zst", compress(t, "zst", tarBytes(t, map[string]string{"../outside": "bad"})))
+	if err := Extract(t.Context(), src, dest); err == nil {
+		t.Fatal("expected path traversal error")
+	}
+	if _, err := os.Stat(filepath.Join(root, "outside")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("path traversal wrote outside destination: %v", err)
+	}
+}
+
+func TestExtractDeb(t *testing.T) {
+	t.Parallel()
+
+	data := compress(t, "xz", tarBytes(t, map[string]string{
+		"./usr/bin/tool":              "#!/bin/sh\n",
+		"./usr/share/doc/tool/README": "hi",
+	}))
+	deb := arBytes(map[string][]byte{
+		"debian-binary":  []byte("2.0\n"),
+		"control.tar.gz": compress(t, "gz", tarBytes(t, map[string]string{"./control": "Package: tool\n"})),
+		"data.tar.xz":    data,
+	}, "debian-binary", "control.tar.gz", "data.tar.xz")
+
+	dest := t.TempDir()
+	if err := Extract(t.Context(), writeArchive(t, "tool_1.0_amd64.deb", deb), dest); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	assertExecutable(t, filepath.Join(dest, "bin", "tool"))
+	if _, err := os.Stat(filepath.Join(dest, "control")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("control.tar must not be extracted: %v", err)
+	}
+}
+
+func TestExtractDebRejectsPathTraversal(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dest := filepath.Join(root, "dest")
+	deb := arBytes(map[string][]byte{
+		"debian-binary": []byte("2.0\n"),
+		"data.tar.gz":   compress(t, "gz", tarBytes(t, map[string]string{"../../outside": "bad"})),
+	}, "debian-binary", "data.tar.gz")
+	if err := Extract(t.Context(), writeArchive(t, "bad.deb", deb), dest); err == nil {
+		t.Fatal("expected path traversal error")
+	}
+	if _, err := os.Stat(filepath.Join(root, "outside")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("path traversal wrote outside destination: %v", err)
+	}
+}
+
+func TestExtractAppImage(t *testing.T) {
+	t.Parallel()
+
+	elf := append([]byte("\x7fELF\x02\x01\x01\x00AI\x02"), bytes.Repeat([]byte{0}, 64)...)
+	dest := t.TempDir()
+	if err := Extract(t.Context(), writeArchive(t, "Tool-1.2.3-x86_64.AppImage", elf), dest); err != nil {
+		t.Fatal(err)
+	}
+	assertExecutable(t, filepath.Join(dest, "Tool"))
+}
@@ -3,7 +3,6 @@ package install
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -20,7 +19,6 @@ import (
 	"github.com/lucasew/workspaced/internal/constants"
 	"github.com/lucasew/workspaced/internal/tool/backend"
 	"github.com/lucasew/workspaced/pkg/driver"
-	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
 	"github.com/lucasew/workspaced/pkg/driver/fetchurl"
 	"github.com/lucasew/workspaced/pkg/driver/httpclient"
 	"github.com/lucasew/workspaced/pkg/logging"
@@ -124,14 +122,28 @@ func DownloadFirst(ctx context.Context, urls []string, dest string, opts Downloa
 	return fmt.Errorf("all downloads failed: %s", strings.Join(errs, "; "))
 }
 
+// Extract unpacks src into dest. The format is sniffed from the content
+// (zip, tar, tar.gz/xz/zst/bz2, .deb, AppImage) and only falls back to the
+// file name when the magic bytes are not recognised; anything else is
+// installed as a single executable. Every member path goes through
+// archive.JoinWithin.
 func Extract(ctx context.Context, src, dest string) error {
-	switch {
-	case strings.HasSuffix(src, ".zip"):
+	kind, err := detectFormat(ctx, src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	switch kind {
+	case formatZip:
 		return unzip(ctx, src, dest)
-	case strings.HasSuffix(src, ".tar.gz"), strings.HasSuffix(src, ".tgz"):
-		return untargz(ctx, src, dest)
-	case strings.HasSuffix(src, ".tar.xz"), strings.HasSuffix(src, ".txz"):
-		return untarxz(ctx, src, dest)
+	case formatTar, formatGzip, formatXz, formatZstd, formatBzip2:
+		return extractStream(ctx, kind, src, dest)
+	case formatDeb:
+		return extractDeb(ctx, src, dest)
+	case formatAppImage:
+		return installAppImage(ctx, src, dest)
 	default:
 		return installBinary(ctx, src, dest)
 	}
@@ -398,22 +410,6 @@ func unzip(ctx context.Context, src, dest string) error {
 	return nil
 }
 
-func untargz(ctx context.Context, src, dest string) error {
-	file, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer logging.Close(ctx, file)
-
-	gzipReader, err := gzip.NewReader(file)
-	if err != nil {
-		return err
-	}
-	defer logging.Close(ctx, gzipReader)
-
-	return untar(ctx, tar.NewReader(gzipReader), dest)
-}
-
 func untar(ctx context.Context, reader *tar.Reader, dest string) error {
 	for {
 		header, err := reader.Next()
@@ -451,15 +447,3 @@ func untar(ctx context.Context, reader *tar.Reader, dest string) error {
 		}
 	}
 }
-
-func untarxz(ctx context.Context, src, dest string) error {
-	if err := os.MkdirAll(dest, 0o755); err != nil {
-		return err
-	}
-
-	cmd := execdriver.MustRun(ctx, "tar", "-xf", src, "-C", dest)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("tar xf failed: %w", err)
-	}
-	return nil
-}
@@ -0,0 +1,477 @@
+package tool
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/archive"
+	"github.com/lucasew/workspaced/internal/atomicfile"
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/modfile"
+	parsespec "github.com/lucasew/workspaced/internal/parse/spec"
+	"github.com/lucasew/workspaced/internal/tool/backend"
+	"github.com/lucasew/workspaced/internal/tool/backend/install"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/taskgroup"
+)
+
+// bundleManifestName is the manifest member at the root of a tool bundle.
+const bundleManifestName = "manifest.json"
+
+// bundleFormat is bumped when the bundle layout changes incompatibly.
+const bundleFormat = 1
+
+var (
+	// ErrInvalidPlatform is returned for platform strings that are not "os/arch".
+	ErrInvalidPlatform = errors.New("platform must be os/arch")
+	// ErrBundleFormat is returned when a bundle has no manifest or an unknown format.
+	ErrBundleFormat = errors.New("unsupported tool bundle")
+	// ErrBundleDigestMismatch is returned when an unpacked tool tree does not
+	// hash to the digest recorded at export time.
+	ErrBundleDigestMismatch = errors.New("tool bundle digest mismatch")
+	// ErrBundleNotPinned is returned when a bundle carries a tool version
+	// the importing workspace's lockfile does not pin.
+	ErrBundleNotPinned = errors.New("tool not pinned by the workspace lock")
+)
+
+// BundlePlatform is one OS/arch pair a bundle carries tools for.
+type BundlePlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// HostPlatform is the platform of the running binary.
+func HostPlatform() BundlePlatform {
+	return BundlePlatform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// ParseBundlePlatform parses "linux/amd64".
+func ParseBundlePlatform(s string) (BundlePlatform, error) {
+	osName, arch, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok || osName == "" || arch == "" || strings.Contains(arch, "/") {
+		return BundlePlatform{}, fmt.Errorf("%w: %q", ErrInvalidPlatform, s)
+	}
+	return BundlePlatform{OS: osName, Arch: arch}, nil
+}
+
+func (p BundlePlatform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// BundleEntry is one installed tool tree inside a bundle.
+type BundleEntry struct {
+	Ref     string `json:"ref"`
+	Version string `json:"version"`
+	BundlePlatform
+	// Path is the tree's directory inside the bundle.
+	Path string `json:"path"`
+	// Digest is treeDigest of Path, checked again on import.
+	Digest string `json:"digest"`
+	// Artifact is the upstream URL the tree was installed from, when the
+	// backend exposes artifacts.
+	Artifact string `json:"artifact,omitempty"`
+}
+
+// BundleSkip records a tool/platform pair the backend cannot produce
+// offline (e.g. a source-built tool for a foreign platform).
+type BundleSkip struct {
+	Ref     string `json:"ref"`
+	Version string `json:"version"`
+	BundlePlatform
+	Reason string `json:"reason"`
+}
+
+// BundleManifest is the manifest.json of a tool bundle. Lock carries the
+// exporting workspace's tool pins; import checks them against its own.
+type BundleManifest struct {
+	Format  int                  `json:"format"`
+	Tools   []BundleEntry        `json:"tools"`
+	Skipped []BundleSkip         `json:"skipped,omitempty"`
+	Lock    []modfile.LockedTool `json:"lock"`
+}
+
+// ExportBundle installs every tool pinned in ws's lockfile for each of
+// platforms into a staging directory and writes it, with a manifest, as an
+// uncompressed tarball at out. The host platform uses the regular
+// Tool.Install path; other platforms go through ArtifactTool, with the
+// first lazy_tools bin of cfg (optional) as the artifact hint.
+func ExportBundle(ctx context.Context, ws *modfile.Workspace, cfg *configcue.Config, out string, platforms []BundlePlatform) (*BundleManifest, error) {
+	if ws == nil {
+		return nil, ErrNilWorkspace
+	}
+	if len(platforms) == 0 {
+		platforms = []BundlePlatform{HostPlatform()}
+	}
+	sum, err := ws.LoadSumFile()
+	if err != nil {
+		return nil, err
+	}
+	locks := sum.ToolLocks()
+	refs := make([]string, 0, len(locks))
+	for ref := range locks {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	hints := map[string]string{}
+	for name, toolCfg := range loadLazyTools(cfg) {
+		if _, lockRef, err := lazyToolSpec(name, toolCfg); err == nil && len(toolCfg.Bins) > 0 {
+			hints[lockRef] = toolCfg.Bins[0]
+		}
+	}
+
+	staging, err := os.MkdirTemp("", "workspaced-bundle-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(staging); err != nil {
+			logging.ReportError(ctx, err, "path", staging)
+		}
+	}()
+
+	type job struct {
+		lock     modfile.LockedTool
+		platform BundlePlatform
+	}
+	type result struct {
+		entry *BundleEntry
+		skip  *BundleSkip
+	}
+	manifest := &BundleManifest{Format: bundleFormat}
+	var jobs []job
+	for _, ref := range refs {
+		manifest.Lock = append(manifest.Lock, locks[ref])
+		for _, platform := range platforms {
+			jobs = append(jobs, job{lock: locks[ref], platform: platform})
+		}
+	}
+
+	results, err := taskgroup.Map[job, result]{
+		Name:     "tool-bundle",
+		Items:    jobs,
+		PoolKind: taskgroup.Control,
+		TaskName: func(_ int, j job) string { return "bundle:" + j.lock.Ref + ":" + j.platform.String() },
+		Fn: func(ctx context.Context, s *taskgroup.Status, j job) (result, error) {
+			s.Update(j.lock.Ref + "@" + j.lock.Version)
+			entry, reason, err := stageBundleEntry(ctx, staging, j.lock, j.platform, hints[j.lock.Ref])
+			if err != nil {
+				return result{}, fmt.Errorf("%s@%s (%s): %w", j.lock.Ref, j.lock.Version, j.platform, err)
+			}
+			if reason != "" {
+				logging.GetLogger(ctx).Warn("tool not bundled", "ref", j.lock.Ref, "platform", j.platform.String(), "reason", reason)
+				return result{skip: &BundleSkip{Ref: j.lock.Ref, Version: j.lock.Version, BundlePlatform: j.platform, Reason: reason}}, nil
+			}
+			return result{entry: entry}, nil
+		},
+	}.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if r.entry != nil {
+			manifest.Tools = append(manifest.Tools, *r.entry)
+		}
+		if r.skip != nil {
+			manifest.Skipped = append(manifest.Skipped, *r.skip)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(staging, bundleManifestName), append(data, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+	if err := writeTarball(ctx, staging, out); err != nil {
+		return nil, fmt.Errorf("write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// stageBundleEntry installs one tool for one platform under staging. A
+// non-empty reason means the pair was skipped rather than failed.
+func stageBundleEntry(ctx context.Context, staging string, lock modfile.LockedTool, platform BundlePlatform, hint string) (*BundleEntry, string, error) {
+	spec, err := parsespec.Parse(lock.Ref + "@" + lock.Version)
+	if err != nil {
+		return nil, "", err
+	}
+	p, err := Get(spec.Provider)
+	if err != nil {
+		return nil, "", err
+	}
+	t, err := p.Tool(spec.Package)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rel := filepath.ToSlash(filepath.Join("tools", platform.OS+"-"+platform.Arch, spec.Dir(), normalizeVersion(lock.Version)))
+	dir := filepath.Join(staging, filepath.FromSlash(rel))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, "", err
+	}
+	entry := &BundleEntry{Ref: lock.Ref, Version: lock.Version, BundlePlatform: platform, Path: rel}
+
+	switch at, isArtifact := t.(backend.ArtifactTool); {
+	case platform == HostPlatform():
+		if err := t.Install(backend.WithLockedDigest(ctx, lock.Digest), lock.Version, dir); err != nil {
+			return nil, "", err
+		}
+		if err := fixAndCheck(ctx, t, dir); err != nil {
+			return nil, "", err
+		}
+	case isArtifact:
+		artifacts, err := at.ListArtifacts(ctx, lock.Version)
+		if err != nil {
+			return nil, "", err
+		}
+		chosen := backend.SelectArtifact(artifacts, platform.OS, platform.Arch, hint)
+		if chosen == nil {
+			return nil, "no artifact for platform", nil
+		}
+		if err := at.InstallArtifact(ctx, *chosen, dir); err != nil {
+			return nil, "", err
+		}
+		entry.Artifact = chosen.URL
+	default:
+		return nil, "backend can only install for the host platform", nil
+	}
+
+	if entry.Digest, err = treeDigest(dir); err != nil {
+		return nil, "", err
+	}
+	return entry, "", nil
+}
+
+// ImportBundle unpacks a bundle written by ExportBundle and moves every
+// tree built for platform into the tool store. Each tree must be pinned by
+// ws's lockfile (same ref, version and upstream digest as the bundle's lock
+// metadata), hash to its recorded digest and pass the tool's install
+// checks. Returns the imported entries.
+func ImportBundle(ctx context.Context, ws *modfile.Workspace, src string, platform BundlePlatform) ([]BundleEntry, error) {
+	if ws == nil {
+		return nil, ErrNilWorkspace
+	}
+	sum, err := ws.LoadSumFile()
+	if err != nil {
+		return nil, err
+	}
+	locks := sum.ToolLocks()
+	mgr, err := NewManager()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(mgr.toolsDir, 0o755); err != nil {
+		return nil, err
+	}
+	// Stage inside the store so the final swap is a same-filesystem rename.
+	staging, err := os.MkdirTemp(mgr.toolsDir, ".bundle-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(staging); err != nil {
+			logging.ReportError(ctx, err, "path", staging)
+		}
+	}()
+	if err := install.Extract(ctx, src, staging); err != nil {
+		return nil, fmt.Errorf("extract bundle: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(staging, bundleManifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: missing %s", ErrBundleFormat, bundleManifestName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBundleFormat, err)
+	}
+	if manifest.Format != bundleFormat {
+		return nil, fmt.Errorf("%w: format %d", ErrBundleFormat, manifest.Format)
+	}
+
+	bundleLocks := map[string]modfile.LockedTool{}
+	for _, lock := range manifest.Lock {
+		bundleLocks[lock.Ref] = lock
+	}
+
+	logger := logging.GetLogger(ctx)
+	var imported []BundleEntry
+	for _, entry := range manifest.Tools {
+		if entry.BundlePlatform != platform {
+			continue
+		}
+		lock, ok := locks[entry.Ref]
+		if !ok || normalizeVersion(lock.Version) != normalizeVersion(entry.Version) {
+			return imported, fmt.Errorf("%w: %s@%s", ErrBundleNotPinned, entry.Ref, entry.Version)
+		}
+		if lock.Digest != "" && bundleLocks[entry.Ref].Digest != lock.Digest {
+			return imported, fmt.Errorf("%w: %s@%s: bundle locked %q, workspace locks %s", ErrBundleDigestMismatch, entry.Ref, entry.Version, bundleLocks[entry.Ref].Digest, lock.Digest)
+		}
+		dir, err := archive.JoinWithin(staging, filepath.FromSlash(entry.Path))
+		if err != nil {
+			return imported, err
+		}
+		digest, err := treeDigest(dir)
+		if err != nil {
+			return imported, err
+		}
+		if digest != entry.Digest {
+			return imported, fmt.Errorf("%w: %s@%s: got %s, want %s", ErrBundleDigestMismatch, entry.Ref, entry.Version, digest, entry.Digest)
+		}
+		spec, err := parsespec.Parse(entry.Ref + "@" + entry.Version)
+		if err != nil {
+			return imported, err
+		}
+		if p, err := Get(spec.Provider); err == nil {
+			if t, err := p.Tool(spec.Package); err == nil {
+				if err := fixAndCheck(ctx, t, dir); err != nil {
+					return imported, fmt.Errorf("%s@%s: %w", entry.Ref, entry.Version, err)
+				}
+			}
+		}
+		dest := filepath.Join(mgr.toolsDir, spec.Dir(), normalizeVersion(entry.Version))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return imported, err
+		}
+		if err := atomicReplaceDir(dest, dir); err != nil {
+			return imported, fmt.Errorf("install %s: %w", dest, err)
+		}
+		logger.Info("imported tool from bundle", "ref", entry.Ref, "version", entry.Version, "path", dest)
+		imported = append(imported, entry)
+	}
+	return imported, nil
+}
+
+// treeDigest hashes a directory tree: relative path, type, executable bit,
+// file contents and symlink targets. Other mode bits are ignored so the
+// digest survives umask differences between hosts.
+func treeDigest(root string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "l %s %s\n", rel, target)
+		case d.IsDir():
+			fmt.Fprintf(h, "d %s\n", rel)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "f %s %t %d\n", rel, info.Mode()&0o111 != 0, info.Size())
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(h, f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeTarball writes the tree at root to out atomically.
+func writeTarball(ctx context.Context, root, out string) error {
+	f, err := atomicfile.Create(out, 0o644)
+	if err != nil {
+		return err
+	}
+	// Abort is a no-op after Commit.
+	defer func() {
+		if err := f.Abort(); err != nil {
+			logging.ReportError(ctx, err, "path", out)
+		}
+	}()
+
+	tw := tar.NewWriter(f)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if d.Type()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+		header.Format = tar.FormatPAX
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, src)
+		if cerr := src.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return f.Commit()
+}
@@ -0,0 +1,222 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/internal/modfile"
+	parsespec "github.com/lucasew/workspaced/internal/parse/spec"
+	"github.com/lucasew/workspaced/internal/tool/backend"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/taskgroup"
+)
+
+type bundleBackend struct{ artifacts bool }
+
+func (b bundleBackend) Name() string { return "fakebundle" }
+func (b bundleBackend) Tool(ref string) (backend.Tool, error) {
+	if b.artifacts {
+		return bundleArtifactTool{bundleTool(ref)}, nil
+	}
+	return bundleTool(ref), nil
+}
+
+type bundleTool string
+
+func (t bundleTool) ListVersions(context.Context) ([]string, error) { return []string{"1.0.0"}, nil }
+func (t bundleTool) Install(_ context.Context, version, dest string) error {
+	return writeBin(dest, string(t), "host "+version)
+}
+func (t bundleTool) EnrichLockfile(*modfile.RenovateDependency) {}
+
+type bundleArtifactTool struct{ bundleTool }
+
+func (t bundleArtifactTool) ListArtifacts(_ context.Context, version string) ([]backend.Artifact, error) {
+	return []backend.Artifact{
+		{OS: "linux", Arch: "arm64", URL: "https://example.invalid/" + string(t.bundleTool) + "-linux-arm64"},
+		{OS: runtime.GOOS, Arch: runtime.GOARCH, URL: "https://example.invalid/" + string(t.bundleTool) + "-host"},
+	}, nil
+}
+func (t bundleArtifactTool) InstallArtifact(_ context.Context, a backend.Artifact, dest string) error {
+	return writeBin(dest, string(t.bundleTool), a.URL)
+}
+
+func writeBin(dest, name, body string) error {
+	if err := os.MkdirAll(filepath.Join(dest, "bin"), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dest, "bin", name), []byte("#!/bin/sh\n# "+body+"\n"), 0o755)
+}
+
+func TestBundleExportImportRoundTrip(t *testing.T) {
+	Register("fakebundle", bundleBackend{artifacts: true})
+	Register("fakebundlesrc", bundleBackend{})
+
+	workspaceRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(workspaceRoot, "workspaced.lock.json"), `{
+  "dependencies": [
+    {"kind": "tool", "ref": "fakebundle:rel", "currentValue": "1.0.0"},
+    {"kind": "tool", "ref": "fakebundlesrc:src", "currentValue": "v2.0.0"}
+  ]
+}
+`)
+	g, ctx := taskgroup.New(logging.NewWriterContext(t.Output()), taskgroup.DefaultLimits())
+	t.Cleanup(func() {
+		if err := g.Wait(); err != nil && !t.Failed() {
+			t.Errorf("group wait: %v", err)
+		}
+	})
+
+	foreign := BundlePlatform{OS: "linux", Arch: "arm64"}
+	if foreign == HostPlatform() {
+		foreign = BundlePlatform{OS: "linux", Arch: "amd64"}
+	}
+	out := filepath.Join(t.TempDir(), "tools.tar")
+	manifest, err := ExportBundle(ctx, modfile.NewWorkspace(workspaceRoot), nil, out, []BundlePlatform{HostPlatform(), foreign})
+	if err != nil {
+		t.Fatalf("ExportBundle: %v", err)
+	}
+	if len(manifest.Lock) != 2 {
+		t.Fatalf("lock metadata = %+v", manifest.Lock)
+	}
+	// rel: host + foreign (artifacts); src: host only, foreign skipped.
+	if len(manifest.Tools) != 3 || len(manifest.Skipped) != 1 {
+		t.Fatalf("tools=%+v skipped=%+v", manifest.Tools, manifest.Skipped)
+	}
+	if manifest.Skipped[0].Ref != "fakebundlesrc:src" {
+		t.Fatalf("unexpected skip: %+v", manifest.Skipped[0])
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	imported, err := ImportBundle(ctx, modfile.NewWorkspace(workspaceRoot), out, HostPlatform())
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("imported %d entries, want 2", len(imported))
+	}
+	toolsDir := filepath.Join(home, ".local", "share", "workspaced", "tools")
+	for spec, bin := range map[string]string{"fakebundle:rel@1.0.0": "rel", "fakebundlesrc:src@v2.0.0": "src"} {
+		parsed, err := parsespec.Parse(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(toolsDir, parsed.Dir(), normalizeVersion(parsed.Version), "bin", bin)
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s not in store: %v", spec, err)
+		}
+	}
+	entries, err := os.ReadDir(toolsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".bundle-") {
+			t.Errorf("staging dir left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestListInstalledSkipsStagingDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	toolsDir := filepath.Join(home, ".local", "share", "workspaced", "tools")
+	for _, dir := range []string{".bundle-123/tools", "fakebundle-rel/1.0.0"} {
+		if err := os.MkdirAll(filepath.Join(toolsDir, filepath.FromSlash(dir)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mgr, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed, err := mgr.ListInstalled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed) != 1 || installed[0].Name != "fakebundle-rel" {
+		t.Fatalf("ListInstalled = %+v, want only fakebundle-rel", installed)
+	}
+}
+
+// writeBadBundle writes a bundle with one host tree for fakebundle:x@1.0.0
+// whose manifest records lockDigest as the exporter's upstream digest and
+// treeDigest as the tree's digest.
+func writeBadBundle(t *testing.T, ctx context.Context, lockDigest, treeDigest string) string {
+	t.Helper()
+	root := t.TempDir()
+	rel := "tools/" + runtime.GOOS + "-" + runtime.GOARCH + "/x/1.0.0"
+	if err := writeBin(filepath.Join(root, filepath.FromSlash(rel)), "x", "tampered"); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, bundleManifestName), `{"format": 1, "tools": [
+  {"ref": "fakebundle:x", "version": "1.0.0", "os": "`+runtime.GOOS+`", "arch": "`+runtime.GOARCH+`", "path": "`+rel+`", "digest": "`+treeDigest+`"}
+], "lock": [{"ref": "fakebundle:x", "version": "1.0.0", "digest": "`+lockDigest+`"}]}`)
+	out := filepath.Join(t.TempDir(), "bad.tar")
+	if err := writeTarball(ctx, root, out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// lockWorkspace returns a workspace whose lockfile pins fakebundle:x at
+// version with digest.
+func lockWorkspace(t *testing.T, version, digest string) *modfile.Workspace {
+	t.Helper()
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "workspaced.lock.json"), `{
+  "dependencies": [
+    {"kind": "tool", "ref": "fakebundle:x", "currentValue": "`+version+`", "currentDigest": "`+digest+`"}
+  ]
+}
+`)
+	return modfile.NewWorkspace(root)
+}
+
+func TestImportBundleRejectsDigestMismatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := logging.NewWriterContext(t.Output())
+
+	out := writeBadBundle(t, ctx, "sha256:11", "sha256:00")
+	if _, err := ImportBundle(ctx, lockWorkspace(t, "1.0.0", "sha256:11"), out, HostPlatform()); !errors.Is(err, ErrBundleDigestMismatch) {
+		t.Fatalf("ImportBundle error = %v, want ErrBundleDigestMismatch", err)
+	}
+}
+
+func TestImportBundleChecksWorkspaceLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := logging.NewWriterContext(t.Output())
+
+	dir := t.TempDir()
+	if err := writeBin(dir, "x", "tampered"); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := treeDigest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := writeBadBundle(t, ctx, "sha256:11", tree)
+
+	for _, tc := range []struct {
+		name string
+		ws   *modfile.Workspace
+		want error
+	}{
+		{"unpinned", modfile.NewWorkspace(t.TempDir()), ErrBundleNotPinned},
+		{"other version", lockWorkspace(t, "2.0.0", "sha256:11"), ErrBundleNotPinned},
+		{"other digest", lockWorkspace(t, "1.0.0", "sha256:22"), ErrBundleDigestMismatch},
+	} {
+		if _, err := ImportBundle(ctx, tc.ws, out, HostPlatform()); !errors.Is(err, tc.want) {
+			t.Errorf("%s: ImportBundle error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if imported, err := ImportBundle(ctx, lockWorkspace(t, "1.0.0", "sha256:11"), out, HostPlatform()); err != nil || len(imported) != 1 {
+		t.Fatalf("pinned import = %+v, %v", imported, err)
+	}
+}
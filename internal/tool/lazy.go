@@ -29,12 +29,13 @@ var (
 )
 
 type lazyToolConfig struct {
-	Version string   `json:"version"`
-	Ref     string   `json:"ref"`
-	Pkg     string   `json:"pkg"`
-	Global  bool     `json:"global"`
-	Alias   string   `json:"alias"`
-	Bins    []string `json:"bins"`
+	Version    string   `json:"version"`
+	Constraint string   `json:"constraint"`
+	Ref        string   `json:"ref"`
+	Pkg        string   `json:"pkg"`
+	Global     bool     `json:"global"`
+	Alias      string   `json:"alias"`
+	Bins       []string `json:"bins"`
 }
 
 // ResolveLazyTool maps an abstract tool alias (e.g., "fmt") to a localized binary path,
@@ -156,7 +157,7 @@ func RefreshLazyToolLocks(ctx context.Context, ws *modfile.Workspace, cfg *confi
 				s.Update("resolving latest for " + name)
 				l := logging.GetLogger(ctx)
 				l.Info("resolving lazy tool version", "tool", name, "ref", lockRef)
-				v, err := mgr.ResolveLatestVersion(ctx, spec)
+				v, err := resolveLazyToolVersion(ctx, mgr, spec, toolCfg)
 				if err != nil {
 					return update{}, fmt.Errorf("resolve latest for %q: %w", name, err)
 				}
@@ -165,7 +166,13 @@ func RefreshLazyToolLocks(ctx context.Context, ws *modfile.Workspace, cfg *confi
 				s.Update("preparing lock entry for " + name)
 			}
 			s.Update(name + "@" + version)
-			return update{name: name, lt: lockedToolWithRenovate(lockRef, version, spec)}, nil
+			digest, err := lockDigest(ctx, spec, version)
+			if err != nil {
+				return update{}, fmt.Errorf("digest for %q: %w", name, err)
+			}
+			lt := lockedToolWithRenovate(lockRef, version, spec)
+			lt.Digest = digest
+			return update{name: name, lt: lt}, nil
 		},
 	}.Run(ctx)
 	if err != nil {
@@ -318,8 +325,10 @@ func resolveLazyToolInWorkspace(ctx context.Context, ws *modfile.Workspace, tool
 	}
 	logger.Debug("resolving lazy tool", "tool", toolName, "workspace", ws.Root, "lockfile", ws.SumPath())
 
+	lockedDigest := ""
 	if locked, ok := sum.Tool(lockRef); ok && strings.TrimSpace(locked.Ref) == lockRef && strings.TrimSpace(locked.Version) != "" {
 		spec.Version = strings.TrimSpace(locked.Version)
+		lockedDigest = strings.TrimSpace(locked.Digest)
 	}
 
 	mgr, err := NewManager()
@@ -331,14 +340,20 @@ func resolveLazyToolInWorkspace(ctx context.Context, ws *modfile.Workspace, tool
 		// Home/lazy tools: resolved from lockfile if present.
 		// If not in lockfile, fill with latest from upstream (not from installed
 		// versions, to keep home tools reproducible via the lockfile).
-		version, err := mgr.ResolveLatestVersion(ctx, spec)
+		version, err := resolveLazyToolVersion(ctx, mgr, spec, toolCfg)
 		if err != nil {
 			return "", fmt.Errorf("resolve version for %q: %w", toolName, err)
 		}
 		spec.Version = version
 	}
 
+	if lockedDigest == "" {
+		if lockedDigest, err = lockDigest(ctx, spec, spec.Version); err != nil {
+			return "", fmt.Errorf("digest for %q: %w", toolName, err)
+		}
+	}
 	lt := lockedToolWithRenovate(lockRef, spec.Version, spec)
+	lt.Digest = lockedDigest
 
 	// Obtain the live Tool once so we can Enrich the real structure.
 	var liveTool backend.Tool
@@ -364,7 +379,7 @@ func resolveLazyToolInWorkspace(ctx context.Context, ws *modfile.Workspace, tool
 		logger.Debug("lazy tool lock already up to date", "tool", toolName, "workspace", ws.Root, "ref", lockRef, "version", spec.Version)
 	}
 
-	return mgr.EnsureInstalled(ctx, spec.String(), binName)
+	return mgr.EnsureInstalled(backend.WithLockedDigest(ctx, lockedDigest), spec.String(), binName)
 }
 
 func findLazyTool(cfg *configcue.Config, query string) (string, lazyToolConfig, bool) {
@@ -483,6 +498,26 @@ func renovateDependencyEqual(a, b modfile.RenovateDependency) bool {
 	return true
 }
 
+// lockDigest returns the upstream content hash for version when the tool
+// implements backend.DigestTool, and "" otherwise. Callers run it before
+// lockedToolWithRenovate so backends that learn metadata while hashing
+// (e.g. the go module path) can report it from EnrichLockfile.
+func lockDigest(ctx context.Context, spec parsespec.Spec, version string) (string, error) {
+	p, err := Get(spec.Provider)
+	if err != nil {
+		return "", err
+	}
+	t, err := p.Tool(spec.Package)
+	if err != nil {
+		return "", err
+	}
+	dt, ok := t.(backend.DigestTool)
+	if !ok {
+		return "", nil
+	}
+	return dt.Digest(ctx, version)
+}
+
 // lockedToolWithRenovate builds the lock entry. It obtains the live Tool
 // and calls EnrichLockfile on a temporary RenovateDependency (the structure
 // passed by reference). This gives the Tool full control to set/upgrade any
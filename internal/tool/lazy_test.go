@@ -91,6 +91,15 @@ workspaced: {
 	}
 }
 
+func TestLockDigestUnknownProvider(t *testing.T) {
+	t.Parallel()
+
+	spec := parsespec.Spec{Provider: "nonexistent", Package: "pkg"}
+	if _, err := lockDigest(context.Background(), spec, "1.0.0"); err == nil {
+		t.Fatal("expected an error for an unregistered provider")
+	}
+}
+
 func TestApplyLiveToolEnrichmentIdempotent(t *testing.T) {
 	t.Parallel()
 
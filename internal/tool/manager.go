@@ -234,7 +234,8 @@ func (m *Manager) ListInstalled() ([]InstalledTool, error) {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// Dot directories are scratch space (e.g. bundle import staging).
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
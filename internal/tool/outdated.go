@@ -0,0 +1,256 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/cmdctx"
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/modfile"
+	parsespec "github.com/lucasew/workspaced/internal/parse/spec"
+	"github.com/lucasew/workspaced/internal/semver"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/taskgroup"
+)
+
+// ErrNoMatchingVersion is returned when no upstream version satisfies a
+// lazy tool's constraint.
+var ErrNoMatchingVersion = errors.New("no version satisfies constraint")
+
+// LazyToolScope pairs a workspace with the config whose lazy_tools it locks.
+type LazyToolScope struct {
+	Name      string // "home" or "codebase"
+	Workspace *modfile.Workspace
+	Config    *configcue.Config
+}
+
+// LazyToolScopes returns the home scope plus, when the working directory
+// belongs to another workspace, the codebase scope.
+func LazyToolScopes(ctx context.Context) ([]LazyToolScope, error) {
+	homeWS, err := selectLazyToolWorkspaceFrom(ctx, true, "")
+	if err != nil {
+		return nil, err
+	}
+	homeCfg, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load home config: %w", err)
+	}
+	scopes := []LazyToolScope{{Name: "home", Workspace: homeWS, Config: homeCfg}}
+
+	ws, err := selectLazyToolWorkspaceFrom(ctx, false, "")
+	if err != nil || workspaceRootOrEmpty(ws) == workspaceRootOrEmpty(homeWS) {
+		return scopes, nil
+	}
+	cfg, err := configcue.LoadForWorkspace(ctx, ws.Root)
+	if err != nil {
+		return nil, fmt.Errorf("load workspace config: %w", err)
+	}
+	return append(scopes, LazyToolScope{Name: "codebase", Workspace: ws, Config: cfg}), nil
+}
+
+// LazyToolUpdate compares a locked lazy tool against upstream.
+type LazyToolUpdate struct {
+	Name       string
+	Ref        string
+	Constraint string // "" allows any release
+	Current    string // locked version
+	Wanted     string // newest upstream version allowed by Constraint
+	Latest     string // newest upstream version
+}
+
+// Outdated reports whether the lock can move forward within the constraint.
+func (u LazyToolUpdate) Outdated() bool {
+	if u.Wanted == "" || u.Wanted == u.Current {
+		return false
+	}
+	wanted, current := semver.Parse(u.Wanted), semver.Parse(u.Current)
+	if c := wanted.Compare(current); c != 0 {
+		return c > 0
+	}
+	// 1.2.0-rc.1 -> 1.2.0 compares equal numerically.
+	return current.IsPrerelease() && !wanted.IsPrerelease()
+}
+
+// CheckLazyTools lists upstream versions for every locked lazy tool of cfg
+// (or only names, when given). Tools without a lock entry are skipped; the
+// lockfile is not modified.
+func CheckLazyTools(ctx context.Context, ws *modfile.Workspace, cfg *configcue.Config, names ...string) ([]LazyToolUpdate, error) {
+	if ws == nil {
+		return nil, ErrNilWorkspace
+	}
+	if cfg == nil {
+		return nil, ErrNilConfig
+	}
+	sum, err := ws.LoadSumFile()
+	if err != nil {
+		return nil, err
+	}
+
+	lazyTools := loadLazyTools(cfg)
+	if len(names) == 0 {
+		for name := range lazyTools {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	type candidate struct {
+		name    string
+		spec    parsespec.Spec
+		toolCfg lazyToolConfig
+		update  LazyToolUpdate
+	}
+	var candidates []candidate
+	for _, name := range names {
+		toolCfg, ok := lazyTools[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrLazyToolNotFound, name)
+		}
+		spec, lockRef, err := lazyToolSpec(name, toolCfg)
+		if err != nil {
+			return nil, fmt.Errorf("lazy tool %q: %w", name, err)
+		}
+		locked, ok := sum.Tool(lockRef)
+		if !ok || strings.TrimSpace(locked.Version) == "" {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			name:    name,
+			spec:    spec,
+			toolCfg: toolCfg,
+			update: LazyToolUpdate{
+				Name:       name,
+				Ref:        lockRef,
+				Constraint: strings.TrimSpace(toolCfg.Constraint),
+				Current:    strings.TrimSpace(locked.Version),
+			},
+		})
+	}
+
+	return taskgroup.Map[candidate, LazyToolUpdate]{
+		Name:     "tool-outdated",
+		Items:    candidates,
+		PoolKind: taskgroup.Control,
+		TaskName: func(_ int, c candidate) string { return "tool:" + c.name },
+		Fn: func(ctx context.Context, s *taskgroup.Status, c candidate) (LazyToolUpdate, error) {
+			s.Update("listing versions for " + c.name)
+			versions, err := listToolVersions(ctx, c.spec)
+			if err != nil {
+				return LazyToolUpdate{}, fmt.Errorf("list versions for %q: %w", c.name, err)
+			}
+			u := c.update
+			u.Latest = versions[0]
+			if u.Wanted, err = wantedVersion(c.toolCfg, versions); err != nil {
+				return LazyToolUpdate{}, fmt.Errorf("lazy tool %q: %w", c.name, err)
+			}
+			return u, nil
+		},
+	}.Run(ctx)
+}
+
+// UpgradeLazyTools moves every outdated lock entry (see CheckLazyTools) to
+// its wanted version in a single atomic lockfile write and returns the
+// applied updates. Under --dry-run nothing is written.
+func UpgradeLazyTools(ctx context.Context, ws *modfile.Workspace, cfg *configcue.Config, names ...string) ([]LazyToolUpdate, error) {
+	checked, err := CheckLazyTools(ctx, ws, cfg, names...)
+	if err != nil {
+		return nil, err
+	}
+	var updates []LazyToolUpdate
+	for _, u := range checked {
+		if u.Outdated() {
+			updates = append(updates, u)
+		}
+	}
+	if len(updates) == 0 || cmdctx.IsDryRun(ctx) {
+		return updates, nil
+	}
+
+	lazyTools := loadLazyTools(cfg)
+	locks := make([]modfile.LockedTool, len(updates))
+	for i, u := range updates {
+		spec, _, err := lazyToolSpec(u.Name, lazyTools[u.Name])
+		if err != nil {
+			return nil, err
+		}
+		digest, err := lockDigest(ctx, spec, u.Wanted)
+		if err != nil {
+			return nil, fmt.Errorf("digest for %q: %w", u.Name, err)
+		}
+		locks[i] = lockedToolWithRenovate(u.Ref, u.Wanted, spec)
+		locks[i].Digest = digest
+	}
+
+	if _, err := ws.UpdateSumFile(ctx, func(sum *modfile.SumFile) (bool, error) {
+		changed := false
+		for i, u := range updates {
+			if sum.EnsureTool(u.Name, locks[i]) {
+				changed = true
+			}
+		}
+		return changed, nil
+	}); err != nil {
+		return nil, fmt.Errorf("update tool locks: %w", err)
+	}
+	logger := logging.GetLogger(ctx)
+	for _, u := range updates {
+		logger.Info("upgraded lazy tool lock", "tool", u.Name, "from", u.Current, "to", u.Wanted, "workspace", ws.Root)
+	}
+	return updates, nil
+}
+
+// wantedVersion picks the newest of versions (newest-first, as backends
+// return them) allowed by the tool's constraint. A pinned version without
+// a constraint stays put.
+func wantedVersion(toolCfg lazyToolConfig, versions []string) (string, error) {
+	if constraint := strings.TrimSpace(toolCfg.Constraint); constraint != "" {
+		c, err := semver.ParseConstraint(constraint)
+		if err != nil {
+			return "", err
+		}
+		v, ok := c.Best(versions)
+		if !ok {
+			return "", fmt.Errorf("%w %q", ErrNoMatchingVersion, constraint)
+		}
+		return v, nil
+	}
+	if pinned := strings.TrimSpace(toolCfg.Version); pinned != "" && pinned != "latest" {
+		return pinned, nil
+	}
+	return versions[0], nil
+}
+
+// resolveLazyToolVersion picks the version to lock for a tool that has no
+// lock entry yet: the newest release allowed by its constraint, or latest.
+func resolveLazyToolVersion(ctx context.Context, mgr *Manager, spec parsespec.Spec, toolCfg lazyToolConfig) (string, error) {
+	if strings.TrimSpace(toolCfg.Constraint) == "" {
+		return mgr.ResolveLatestVersion(ctx, spec)
+	}
+	versions, err := listToolVersions(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+	return wantedVersion(toolCfg, versions)
+}
+
+func listToolVersions(ctx context.Context, spec parsespec.Spec) ([]string, error) {
+	p, err := Get(spec.Provider)
+	if err != nil {
+		return nil, err
+	}
+	t, err := p.Tool(spec.Package)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := t.ListVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, ErrNoVersionsFound
+	}
+	return versions, nil
+}
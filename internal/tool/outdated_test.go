@@ -0,0 +1,125 @@
+package tool
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasew/workspaced/internal/cmdctx"
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/modfile"
+	"github.com/lucasew/workspaced/internal/tool/backend"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/taskgroup"
+)
+
+type versionsBackend []string
+
+func (b versionsBackend) Name() string { return "fakeversions" }
+func (b versionsBackend) Tool(string) (backend.Tool, error) {
+	return versionsTool(b), nil
+}
+
+type versionsTool []string
+
+func (t versionsTool) ListVersions(context.Context) ([]string, error) { return t, nil }
+func (t versionsTool) Install(context.Context, string, string) error  { return nil }
+func (t versionsTool) EnrichLockfile(entry *modfile.RenovateDependency) {
+	entry.Datasource = "fakeversions"
+}
+
+func TestUpgradeLazyToolsWithinConstraint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	Register("fakeversions", versionsBackend{"2.0.0", "1.4.1", "1.4.0", "1.3.0", "1.2.0"})
+
+	workspaceRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(workspaceRoot, "workspaced.cue"), `package workspaced
+
+workspaced: lazy_tools: {
+	caret: {ref: "fakeversions:caret", constraint: "^1.2"}
+	tilde: {ref: "fakeversions:tilde", constraint: "~1.3.0"}
+	free: {ref: "fakeversions:free"}
+	pinned: {ref: "fakeversions:pinned", version: "1.2.0"}
+}
+`)
+	writeTestFile(t, filepath.Join(workspaceRoot, "workspaced.lock.json"), `{
+  "dependencies": [
+    {"kind": "tool", "ref": "fakeversions:caret", "currentValue": "1.2.0"},
+    {"kind": "tool", "ref": "fakeversions:tilde", "currentValue": "1.3.0"},
+    {"kind": "tool", "ref": "fakeversions:free", "currentValue": "1.2.0"},
+    {"kind": "tool", "ref": "fakeversions:pinned", "currentValue": "1.2.0"}
+  ]
+}
+`)
+
+	g, ctx := taskgroup.New(logging.NewWriterContext(t.Output()), taskgroup.DefaultLimits())
+	t.Cleanup(func() {
+		if err := g.Wait(); err != nil && !t.Failed() {
+			t.Errorf("group wait: %v", err)
+		}
+	})
+	cfg, err := configcue.LoadForWorkspace(ctx, workspaceRoot)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	ws := modfile.NewWorkspace(workspaceRoot)
+	names := []string{"caret", "tilde", "free", "pinned"}
+
+	checked, err := CheckLazyTools(ctx, ws, cfg, names...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][2]string{ // name -> wanted, latest
+		"caret":  {"1.4.1", "2.0.0"},
+		"tilde":  {"1.3.0", "2.0.0"},
+		"free":   {"2.0.0", "2.0.0"},
+		"pinned": {"1.2.0", "2.0.0"},
+	}
+	for _, u := range checked {
+		if got := [2]string{u.Wanted, u.Latest}; got != want[u.Name] {
+			t.Errorf("%s: wanted/latest = %v, want %v", u.Name, got, want[u.Name])
+		}
+	}
+
+	dry, err := UpgradeLazyTools(cmdctx.WithDryRun(ctx, true), ws, cfg, names...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dry) != 2 {
+		t.Fatalf("dry-run planned %d upgrades, want 2: %+v", len(dry), dry)
+	}
+	if lock := mustLock(t, ws, "fakeversions:caret"); lock.Version != "1.2.0" {
+		t.Fatalf("dry-run modified the lockfile: %s", lock.Version)
+	}
+
+	if _, err := UpgradeLazyTools(ctx, ws, cfg, names...); err != nil {
+		t.Fatal(err)
+	}
+	for ref, version := range map[string]string{
+		"fakeversions:caret":  "1.4.1",
+		"fakeversions:tilde":  "1.3.0",
+		"fakeversions:free":   "2.0.0",
+		"fakeversions:pinned": "1.2.0",
+	} {
+		if lock := mustLock(t, ws, ref); lock.Version != version {
+			t.Errorf("%s locked at %s, want %s", ref, lock.Version, version)
+		}
+	}
+
+	if _, err := CheckLazyTools(ctx, ws, cfg, "missing"); err == nil {
+		t.Fatal("expected ErrLazyToolNotFound")
+	}
+}
+
+func mustLock(t *testing.T, ws *modfile.Workspace, ref string) modfile.LockedTool {
+	t.Helper()
+	sum, err := ws.LoadSumFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, ok := sum.Tool(ref)
+	if !ok {
+		t.Fatalf("missing lock for %s", ref)
+	}
+	return lock
+}
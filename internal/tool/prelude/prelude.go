@@ -4,5 +4,6 @@ import (
 	_ "github.com/lucasew/workspaced/internal/tool/backend/catalog"
 	_ "github.com/lucasew/workspaced/internal/tool/backend/catalog/applications"
 	_ "github.com/lucasew/workspaced/internal/tool/backend/github"
+	_ "github.com/lucasew/workspaced/internal/tool/backend/gomod"
 	_ "github.com/lucasew/workspaced/internal/tool/backend/mise"
 )
@@ -0,0 +1,198 @@
+package tool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/atomicfile"
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/internal/modfile"
+	"github.com/lucasew/workspaced/internal/version"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// toolEnvCache is the on-disk memo of LazyToolBinDirs for one directory.
+// It stays valid while the build, the stat of every file in Stamps and the
+// presence of Dirs / absence of Missing are unchanged, which is a handful
+// of stat calls instead of a CUE evaluation on every cd.
+type toolEnvCache struct {
+	Build   string            `json:"build"`
+	Stamps  map[string]string `json:"stamps"`
+	Dirs    []string          `json:"dirs"`
+	Missing []string          `json:"missing,omitempty"`
+}
+
+// LazyToolBinDirs returns the bin directories of the locked lazy tools of
+// the workspace holding the workspaced.cue in dir, ready to prepend to PATH.
+// Nothing is installed or fetched: tools whose pinned version is not in the
+// store are skipped until they are (lazily) installed.
+func LazyToolBinDirs(ctx context.Context, dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	cachePath, cacheErr := toolEnvCachePath(dir)
+	if cacheErr == nil {
+		if cached, ok := loadToolEnvCache(cachePath); ok {
+			return cached.Dirs, nil
+		}
+	}
+
+	entry, err := resolveToolEnv(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if cacheErr == nil {
+		if data, err := json.Marshal(entry); err == nil {
+			if err := atomicfile.WriteBytes(cachePath, data, 0o644); err != nil {
+				logging.GetLogger(ctx).Debug("write tool env cache failed", "path", cachePath, "error", err)
+			}
+		}
+	}
+	return entry.Dirs, nil
+}
+
+func resolveToolEnv(ctx context.Context, dir string) (*toolEnvCache, error) {
+	ws, err := selectLazyToolWorkspaceFrom(ctx, false, dir)
+	if err != nil {
+		return nil, err
+	}
+	entry := &toolEnvCache{
+		Build:  version.GetBuildID(),
+		Stamps: map[string]string{},
+		Dirs:   []string{},
+	}
+	// Stamp before reading so a concurrent edit invalidates the entry.
+	for _, path := range toolEnvInputs(ctx, ws, dir) {
+		entry.Stamps[path] = statStamp(path)
+	}
+
+	cfg, err := configcue.LoadForWorkspace(ctx, ws.Root)
+	if err != nil {
+		return nil, fmt.Errorf("load workspace config: %w", err)
+	}
+	sum, err := ws.LoadSumFile()
+	if err != nil {
+		return nil, err
+	}
+	toolsDir, err := GetToolsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	lazyTools := loadLazyTools(cfg)
+	names := make([]string, 0, len(lazyTools))
+	for name := range lazyTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		toolCfg := lazyTools[name]
+		spec, lockRef, err := lazyToolSpec(name, toolCfg)
+		if err != nil {
+			continue
+		}
+		locked, ok := sum.Tool(lockRef)
+		if !ok || strings.TrimSpace(locked.Version) == "" {
+			continue
+		}
+		versionDir := filepath.Join(toolsDir, spec.Dir(), normalizeVersion(strings.TrimSpace(locked.Version)))
+		if _, err := os.Stat(versionDir); err != nil {
+			entry.Missing = append(entry.Missing, versionDir)
+			continue
+		}
+		bins := toolCfg.Bins
+		if len(bins) == 0 {
+			bins = []string{name}
+		}
+		for _, bin := range bins {
+			binPath := FindBinary(versionDir, bin)
+			if binPath == "" {
+				continue
+			}
+			if binDir := filepath.Dir(binPath); !seen[binDir] {
+				seen[binDir] = true
+				entry.Dirs = append(entry.Dirs, binDir)
+			}
+		}
+	}
+	return entry, nil
+}
+
+// toolEnvInputs lists the files a tool env entry depends on: every config
+// layer LoadForWorkspace evaluates, each .cue file next to workspaced.cue
+// (and the directories themselves, so adding one is noticed) and the lock.
+func toolEnvInputs(ctx context.Context, ws *modfile.Workspace, dir string) []string {
+	inputs := []string{ws.SumPath()}
+	opts := configcue.DiscoverOptions{Cwd: ws.Root}
+	if dotfilesRoot, err := envdriver.GetDotfilesRoot(ctx); err == nil && filepath.Clean(dotfilesRoot) == filepath.Clean(ws.Root) {
+		opts = configcue.DiscoverOptions{HomeMode: true}
+	}
+	if discovered, err := configcue.DiscoverLayers(ctx, opts); err == nil {
+		for _, layer := range discovered.Layers {
+			inputs = append(inputs, layer.Path)
+		}
+	}
+	for _, d := range []string{dir, ws.Root} {
+		inputs = append(inputs, d, filepath.Join(d, "workspaced.cue"))
+		matches, _ := filepath.Glob(filepath.Join(d, "*.cue"))
+		inputs = append(inputs, matches...)
+	}
+	return inputs
+}
+
+func loadToolEnvCache(path string) (*toolEnvCache, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry toolEnvCache
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Build != version.GetBuildID() {
+		return nil, false
+	}
+	for path, stamp := range entry.Stamps {
+		if statStamp(path) != stamp {
+			return nil, false
+		}
+	}
+	for _, dir := range entry.Dirs {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, false
+		}
+	}
+	for _, dir := range entry.Missing {
+		if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+			return nil, false
+		}
+	}
+	return &entry, true
+}
+
+func toolEnvCachePath(dir string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(cacheDir, "workspaced", "toolenv", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// statStamp is "mtime:size", or "" when path does not exist.
+func statStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
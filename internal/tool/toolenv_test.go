@@ -0,0 +1,113 @@
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	parsespec "github.com/lucasew/workspaced/internal/parse/spec"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func TestLazyToolBinDirsUsesLockAndCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	ctx := logging.NewWriterContext(t.Output())
+
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "workspaced.cue"), `package workspaced
+
+workspaced: lazy_tools: {
+	gh: {ref: "github:cli/cli", bins: ["gh"]}
+	jq: {ref: "github:jqlang/jq", bins: ["jq"]}
+}
+`)
+	lockPath := filepath.Join(root, "workspaced.lock.json")
+	writeTestFile(t, lockPath, `{
+  "dependencies": [
+    {"kind": "tool", "ref": "github:cli/cli", "currentValue": "2.0.0"},
+    {"kind": "tool", "ref": "github:jqlang/jq", "currentValue": "1.7.1"}
+  ]
+}
+`)
+	storeBin := func(ref, version, bin string) string {
+		spec, err := parsespec.Parse(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(home, ".local", "share", "workspaced", "tools", spec.Dir(), version, "bin", bin)
+		writeTestFile(t, path, "#!/bin/sh\n")
+		if err := os.Chmod(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.Dir(path)
+	}
+	ghDir := storeBin("github:cli/cli", "2.0.0", "gh")
+	storeBin("github:cli/cli", "1.0.0", "gh") // not the pinned version
+
+	dirs, err := LazyToolBinDirs(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{ghDir}; !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("dirs = %v, want %v (jq is not installed yet)", dirs, want)
+	}
+
+	// Installing the pinned jq invalidates the cached "missing" entry.
+	jqDir := storeBin("github:jqlang/jq", "1.7.1", "jq")
+	if dirs, err = LazyToolBinDirs(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{ghDir, jqDir}; !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("dirs = %v, want %v", dirs, want)
+	}
+
+	// Bumping the lock invalidates the cache too.
+	writeTestFile(t, lockPath, `{"dependencies": [{"kind": "tool", "ref": "github:cli/cli", "currentValue": "1.0.0"}]}`)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(lockPath, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if dirs, err = LazyToolBinDirs(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 1 || filepath.Base(filepath.Dir(dirs[0])) != "1.0.0" {
+		t.Fatalf("dirs after lock bump = %v", dirs)
+	}
+}
+
+func TestToolEnvCacheStampsEveryCueFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	ctx := logging.NewWriterContext(t.Output())
+
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "workspaced.cue"), "package workspaced\n")
+	writeTestFile(t, filepath.Join(root, "tools.cue"), "package workspaced\n")
+	if _, err := LazyToolBinDirs(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	cachePath, err := toolEnvCachePath(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loadToolEnvCache(cachePath); !ok {
+		t.Fatal("expected a fresh cache entry")
+	}
+
+	writeTestFile(t, filepath.Join(root, "tools.cue"), "package workspaced\n\n// edited\n")
+	if _, ok := loadToolEnvCache(cachePath); ok {
+		t.Fatal("editing tools.cue should invalidate the cache")
+	}
+	if _, err := LazyToolBinDirs(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, "more.cue"), "package workspaced\n")
+	if _, ok := loadToolEnvCache(cachePath); ok {
+		t.Fatal("adding more.cue should invalidate the cache")
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"path/filepath"
 )
 
+// RuntimeDir is $XDG_RUNTIME_DIR, or the systemd default when it is unset.
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return fmt.Sprintf("/run/user/%d", os.Getuid())
+}
+
 // DaemonSocketPath is the unix socket used by the workspaced daemon and its clients.
 func DaemonSocketPath() string {
-	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
-	if runtimeDir == "" {
-		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
-	}
-	return filepath.Join(runtimeDir, "workspaced.sock")
+	return filepath.Join(RuntimeDir(), "workspaced.sock")
 }
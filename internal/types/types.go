@@ -40,7 +40,7 @@ type LogEntry struct {
 // StreamPacket envelopes different types of outputs to be multiplexed over a single connection.
 // This allows interleaving logs, command results, and raw stdio streams.
 type StreamPacket struct {
-	// Type indicates the payload kind: "log", "result", "stdout", "stderr", or "history_event".
+	// Type indicates the payload kind: "log", "result", "stdout", "stderr", "history_event" or "screenrecord".
 	Type    string          `json:"type"`
 	Payload json.RawMessage `json:"payload"`
 }
@@ -53,3 +53,25 @@ type HistoryEvent struct {
 	ExitCode  int    `json:"exit_code"`
 	Duration  int64  `json:"duration_ms"`
 }
+
+// ClipboardEntry is one item of the daemon's clipboard history. Data is
+// only loaded when the entry is fetched by id.
+type ClipboardEntry struct {
+	ID        int64  `json:"id"`
+	Hash      string `json:"-"`
+	MIME      string `json:"mime"`
+	Preview   string `json:"preview"`
+	Size      int64  `json:"size"`
+	Timestamp int64  `json:"timestamp"`
+	Data      []byte `json:"-"`
+}
+
+// ScreenrecordRequest asks the daemon, which owns the recorder process,
+// to start, stop or toggle a screen recording or report its status.
+type ScreenrecordRequest struct {
+	Op     string   `json:"op"` // start, stop, toggle or status
+	Target string   `json:"target,omitempty"`
+	Audio  *bool    `json:"audio,omitempty"`
+	Format string   `json:"format,omitempty"`
+	Env    []string `json:"env"`
+}
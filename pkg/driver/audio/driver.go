@@ -8,4 +8,49 @@ type Driver interface {
 	ToggleMute(ctx context.Context) error
 	GetMute(ctx context.Context) (bool, error)
 	SinkName(ctx context.Context) (string, error)
+
+	ListSinks(ctx context.Context) ([]Device, error)
+	// ListSources lists capture devices, leaving out sink monitors.
+	ListSources(ctx context.Context) ([]Device, error)
+	// SetDefaultSink makes the named sink the default and moves the
+	// playing streams to it.
+	SetDefaultSink(ctx context.Context, name string) error
+
+	// ListStreams lists the playback streams of applications.
+	ListStreams(ctx context.Context) ([]Stream, error)
+	SetStreamVolume(ctx context.Context, id uint32, volume float64) error
+	SetStreamMute(ctx context.Context, id uint32, mute bool) error
+
+	// ToggleSourceMute toggles the default source (microphone) mute.
+	ToggleSourceMute(ctx context.Context) error
+	GetSourceMute(ctx context.Context) (bool, error)
+}
+
+// Device is a sink or a source.
+type Device struct {
+	Index       uint32  `json:"index"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Volume      float64 `json:"volume"`
+	Muted       bool    `json:"muted"`
+	Default     bool    `json:"default"`
+}
+
+// Label is the human name of the device.
+func (d Device) Label() string {
+	if d.Description != "" {
+		return d.Description
+	}
+	return d.Name
+}
+
+// Stream is an application playback stream.
+type Stream struct {
+	ID     uint32  `json:"id"`
+	App    string  `json:"app"`
+	Binary string  `json:"binary,omitempty"`
+	Media  string  `json:"media,omitempty"`
+	Sink   string  `json:"sink"`
+	Volume float64 `json:"volume"`
+	Muted  bool    `json:"muted"`
 }
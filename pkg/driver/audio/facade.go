@@ -2,7 +2,9 @@ package audio
 
 import (
 	"context"
+	"fmt"
 
+	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 )
 
@@ -42,3 +44,84 @@ func ToggleMute(ctx context.Context) error {
 	}
 	return ShowStatus(ctx)
 }
+
+func ListSinks(ctx context.Context) ([]Device, error) {
+	return driver.WithResult(ctx, func(d Driver) ([]Device, error) { return d.ListSinks(ctx) })
+}
+
+func ListSources(ctx context.Context) ([]Device, error) {
+	return driver.WithResult(ctx, func(d Driver) ([]Device, error) { return d.ListSources(ctx) })
+}
+
+func ListStreams(ctx context.Context) ([]Stream, error) {
+	return driver.WithResult(ctx, func(d Driver) ([]Stream, error) { return d.ListStreams(ctx) })
+}
+
+// SwitchSink makes the sink matching query (see FindDevice) the default and
+// shows the volume OSD for it.
+func SwitchSink(ctx context.Context, query string) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	sinks, err := d.ListSinks(ctx)
+	if err != nil {
+		return err
+	}
+	sink, err := FindDevice(sinks, query)
+	if err != nil {
+		return err
+	}
+	if err := d.SetDefaultSink(ctx, sink.Name); err != nil {
+		return err
+	}
+	return ShowStatus(ctx)
+}
+
+// SetStreamVolume sets the volume of every stream matching target (see
+// FindStreams).
+func SetStreamVolume(ctx context.Context, target string, level float64) error {
+	return eachStream(ctx, target, func(d Driver, s Stream) error {
+		return d.SetStreamVolume(ctx, s.ID, driver.Clamp01(level))
+	})
+}
+
+// ToggleStreamMute flips the mute of every stream matching target.
+func ToggleStreamMute(ctx context.Context, target string) error {
+	return eachStream(ctx, target, func(d Driver, s Stream) error {
+		return d.SetStreamMute(ctx, s.ID, !s.Muted)
+	})
+}
+
+func eachStream(ctx context.Context, target string, fn func(Driver, Stream) error) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	streams, err := d.ListStreams(ctx)
+	if err != nil {
+		return err
+	}
+	matches := FindStreams(streams, target)
+	if len(matches) == 0 {
+		return fmt.Errorf("%w: no audio stream matches %q", dapi.ErrNoTargetFound, target)
+	}
+	for _, s := range matches {
+		if err := fn(d, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToggleMicMute toggles the default source mute and shows an OSD.
+func ToggleMicMute(ctx context.Context) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	if err := d.ToggleSourceMute(ctx); err != nil {
+		return err
+	}
+	return ShowMicStatus(ctx)
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+)
+
+// FindDevice picks the device whose index or name equals query, or else
+// the only one whose description contains it (case-insensitive).
+func FindDevice(devices []Device, query string) (*Device, error) {
+	for i, d := range devices {
+		if d.Name == query || strconv.FormatUint(uint64(d.Index), 10) == query {
+			return &devices[i], nil
+		}
+	}
+	var found *Device
+	q := strings.ToLower(query)
+	for i, d := range devices {
+		if !strings.Contains(strings.ToLower(d.Label()), q) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%q matches both %q and %q", query, found.Label(), d.Label())
+		}
+		found = &devices[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: no audio device matches %q", dapi.ErrNoTargetFound, query)
+	}
+	return found, nil
+}
+
+// FindStreams returns the streams whose ID equals target or whose
+// application or binary name equals it (case-insensitive).
+func FindStreams(streams []Stream, target string) []Stream {
+	var ret []Stream
+	for _, s := range streams {
+		if strconv.FormatUint(uint64(s.ID), 10) == target ||
+			strings.EqualFold(s.App, target) ||
+			(s.Binary != "" && strings.EqualFold(s.Binary, target)) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"errors"
+	"testing"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+)
+
+func TestFindDevice(t *testing.T) {
+	devices := []Device{
+		{Index: 55, Name: "alsa_output.analog", Description: "Built-in Audio"},
+		{Index: 71, Name: "bluez_output.1", Description: "WH-1000XM4 Headphones"},
+		{Index: 80, Name: "hdmi", Description: "HDMI Audio"},
+	}
+	for query, want := range map[string]uint32{
+		"71":                 71,
+		"alsa_output.analog": 55,
+		"headphones":         71,
+		"hdmi":               80,
+	} {
+		d, err := FindDevice(devices, query)
+		if err != nil {
+			t.Errorf("FindDevice(%q): %v", query, err)
+			continue
+		}
+		if d.Index != want {
+			t.Errorf("FindDevice(%q) = %d, want %d", query, d.Index, want)
+		}
+	}
+	if _, err := FindDevice(devices, "audio"); err == nil {
+		t.Error("ambiguous query did not fail")
+	}
+	if _, err := FindDevice(devices, "usb"); !errors.Is(err, dapi.ErrNoTargetFound) {
+		t.Errorf("missing device error = %v", err)
+	}
+}
+
+func TestFindStreams(t *testing.T) {
+	streams := []Stream{
+		{ID: 1, App: "Firefox", Binary: "firefox"},
+		{ID: 2, App: "Firefox", Binary: "firefox"},
+		{ID: 3, App: "mpv"},
+	}
+	if got := FindStreams(streams, "firefox"); len(got) != 2 {
+		t.Errorf("firefox matched %d streams, want 2", len(got))
+	}
+	if got := FindStreams(streams, "3"); len(got) != 1 || got[0].App != "mpv" {
+		t.Errorf("id 3 matched %+v", got)
+	}
+	if got := FindStreams(streams, "fire"); len(got) != 0 {
+		t.Errorf("partial name matched %+v", got)
+	}
+}
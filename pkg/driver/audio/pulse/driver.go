@@ -12,7 +12,10 @@ import (
 	"github.com/lucasew/workspaced/pkg/logging"
 )
 
-var sink = "@DEFAULT_SINK@"
+var (
+	sink   = "@DEFAULT_SINK@"
+	source = "@DEFAULT_SOURCE@"
+)
 
 func init() {
 	driver.Register[audio.Driver](&Factory{})
@@ -86,8 +89,5 @@ func (d *Driver) ToggleMute(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	if mute {
-		return execdriver.MustRun(ctx, "pactl", "set-sink-mute", sink, "no").Run()
-	}
-	return execdriver.MustRun(ctx, "pactl", "set-sink-mute", sink, "yes").Run()
+	return execdriver.MustRun(ctx, "pactl", "set-sink-mute", sink, yesNo(!mute)).Run()
 }
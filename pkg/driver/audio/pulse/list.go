@@ -0,0 +1,210 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/driver/audio"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+)
+
+// volumeNorm is PA_VOLUME_NORM, the raw value of 100%.
+const volumeNorm = 65536
+
+type channelVolume struct {
+	Value uint32 `json:"value"`
+}
+
+type pactlDevice struct {
+	Index         uint32                   `json:"index"`
+	Name          string                   `json:"name"`
+	Description   string                   `json:"description"`
+	Mute          bool                     `json:"mute"`
+	Volume        map[string]channelVolume `json:"volume"`
+	MonitorOfSink string                   `json:"monitor_of_sink"`
+}
+
+type pactlStream struct {
+	Index      uint32                   `json:"index"`
+	Sink       uint32                   `json:"sink"`
+	Mute       bool                     `json:"mute"`
+	Volume     map[string]channelVolume `json:"volume"`
+	Properties map[string]string        `json:"properties"`
+}
+
+// average is the mean channel volume, 1.0 being 100%.
+func average(channels map[string]channelVolume) float64 {
+	if len(channels) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, c := range channels {
+		sum += float64(c.Value)
+	}
+	return sum / float64(len(channels)) / volumeNorm
+}
+
+func pactlList(ctx context.Context, kind string) ([]byte, error) {
+	out, err := execdriver.MustRun(ctx, "pactl", "-f", "json", "list", kind).Output()
+	if err != nil {
+		return nil, fmt.Errorf("pactl list %s: %w", kind, err)
+	}
+	return out, nil
+}
+
+func pactlDefault(ctx context.Context, kind string) (string, error) {
+	out, err := execdriver.MustRun(ctx, "pactl", "get-default-"+kind).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// parseDevices decodes `pactl -f json list sinks|sources`. Monitor sources
+// are skipped.
+func parseDevices(data []byte, defaultName string) ([]audio.Device, error) {
+	var raw []pactlDevice
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse pactl devices: %w", err)
+	}
+	devices := make([]audio.Device, 0, len(raw))
+	for _, r := range raw {
+		if r.MonitorOfSink != "" && r.MonitorOfSink != "n/a" {
+			continue
+		}
+		devices = append(devices, audio.Device{
+			Index:       r.Index,
+			Name:        r.Name,
+			Description: r.Description,
+			Volume:      average(r.Volume),
+			Muted:       r.Mute,
+			Default:     r.Name == defaultName,
+		})
+	}
+	return devices, nil
+}
+
+// parseStreams decodes `pactl -f json list sink-inputs`, naming each
+// stream's sink from sinks.
+func parseStreams(data []byte, sinks []audio.Device) ([]audio.Stream, error) {
+	var raw []pactlStream
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse pactl sink inputs: %w", err)
+	}
+	sinkNames := make(map[uint32]string, len(sinks))
+	for _, s := range sinks {
+		sinkNames[s.Index] = s.Name
+	}
+	streams := make([]audio.Stream, 0, len(raw))
+	for _, r := range raw {
+		app := r.Properties["application.name"]
+		if app == "" {
+			app = r.Properties["application.process.binary"]
+		}
+		streams = append(streams, audio.Stream{
+			ID:     r.Index,
+			App:    app,
+			Binary: r.Properties["application.process.binary"],
+			Media:  r.Properties["media.name"],
+			Sink:   sinkNames[r.Sink],
+			Volume: average(r.Volume),
+			Muted:  r.Mute,
+		})
+	}
+	return streams, nil
+}
+
+func (d *Driver) ListSinks(ctx context.Context) ([]audio.Device, error) {
+	out, err := pactlList(ctx, "sinks")
+	if err != nil {
+		return nil, err
+	}
+	def, err := pactlDefault(ctx, "sink")
+	if err != nil {
+		return nil, err
+	}
+	return parseDevices(out, def)
+}
+
+func (d *Driver) ListSources(ctx context.Context) ([]audio.Device, error) {
+	out, err := pactlList(ctx, "sources")
+	if err != nil {
+		return nil, err
+	}
+	def, err := pactlDefault(ctx, "source")
+	if err != nil {
+		return nil, err
+	}
+	return parseDevices(out, def)
+}
+
+func (d *Driver) SetDefaultSink(ctx context.Context, name string) error {
+	if err := execdriver.MustRun(ctx, "pactl", "set-default-sink", name).Run(); err != nil {
+		return fmt.Errorf("set default sink: %w", err)
+	}
+	// PipeWire follows the default on its own, PulseAudio keeps streams
+	// where they are.
+	streams, err := d.ListStreams(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		if s.Sink == name {
+			continue
+		}
+		if err := execdriver.MustRun(ctx, "pactl", "move-sink-input", fmt.Sprint(s.ID), name).Run(); err != nil {
+			return fmt.Errorf("move stream %d: %w", s.ID, err)
+		}
+	}
+	return nil
+}
+
+func (d *Driver) ListStreams(ctx context.Context) ([]audio.Stream, error) {
+	out, err := pactlList(ctx, "sinks")
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := parseDevices(out, "")
+	if err != nil {
+		return nil, err
+	}
+	if out, err = pactlList(ctx, "sink-inputs"); err != nil {
+		return nil, err
+	}
+	return parseStreams(out, sinks)
+}
+
+func (d *Driver) SetStreamVolume(ctx context.Context, id uint32, level float64) error {
+	if err := execdriver.MustRun(ctx, "pactl", "set-sink-input-volume", fmt.Sprint(id), fmt.Sprintf("%d%%", int(level*100))).Run(); err != nil {
+		return fmt.Errorf("set stream volume: %w", err)
+	}
+	return nil
+}
+
+func (d *Driver) SetStreamMute(ctx context.Context, id uint32, mute bool) error {
+	if err := execdriver.MustRun(ctx, "pactl", "set-sink-input-mute", fmt.Sprint(id), yesNo(mute)).Run(); err != nil {
+		return fmt.Errorf("set stream mute: %w", err)
+	}
+	return nil
+}
+
+func (d *Driver) GetSourceMute(ctx context.Context) (bool, error) {
+	out, err := execdriver.MustRun(ctx, "pactl", "get-source-mute", source).Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(out), "yes"), nil
+}
+
+func (d *Driver) ToggleSourceMute(ctx context.Context) error {
+	return execdriver.MustRun(ctx, "pactl", "set-source-mute", source, "toggle").Run()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,65 @@
+package pulse
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/driver/audio"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 0.005 }
+
+func TestParseSinks(t *testing.T) {
+	sinks, err := parseDevices(readFixture(t, "sinks.json"), "bluez_output.00_1B_66_AA_BB_CC.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 2 {
+		t.Fatalf("got %d sinks, want 2", len(sinks))
+	}
+	if s := sinks[0]; s.Index != 55 || s.Label() != "Built-in Audio Analog Stereo" || !near(s.Volume, .65) || s.Muted || s.Default {
+		t.Errorf("sinks[0] = %+v", s)
+	}
+	if s := sinks[1]; !s.Muted || !s.Default || !near(s.Volume, 1) {
+		t.Errorf("sinks[1] = %+v", s)
+	}
+}
+
+func TestParseSourcesSkipsMonitors(t *testing.T) {
+	sources, err := parseDevices(readFixture(t, "sources.json"), "alsa_input.pci-0000_00_1f.3.analog-stereo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 1 || sources[0].Index != 57 || !sources[0].Default || !sources[0].Muted || !near(sources[0].Volume, .5) {
+		t.Errorf("sources = %+v", sources)
+	}
+}
+
+func TestParseStreams(t *testing.T) {
+	sinks := []audio.Device{{Index: 55, Name: "speakers"}, {Index: 71, Name: "headphones"}}
+	streams, err := parseStreams(readFixture(t, "sink-inputs.json"), sinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 2 {
+		t.Fatalf("got %d streams, want 2", len(streams))
+	}
+	if s := streams[0]; s.ID != 81 || s.App != "Firefox" || s.Binary != "firefox" || s.Sink != "speakers" || !near(s.Volume, .8) || s.Muted {
+		t.Errorf("streams[0] = %+v", s)
+	}
+	// Without application.name the binary names the stream.
+	if s := streams[1]; s.App != "mpv" || s.Sink != "headphones" || !s.Muted {
+		t.Errorf("streams[1] = %+v", s)
+	}
+}
@@ -50,3 +50,29 @@ func ShowStatus(ctx context.Context) error {
 	}
 	return notification.Notify(ctx, &n)
 }
+
+// ShowMicStatus displays the default source mute state.
+func ShowMicStatus(ctx context.Context) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	isMuted, err := d.GetSourceMute(ctx)
+	if err != nil {
+		return err
+	}
+
+	icon, message := "microphone-sensitivity-high", "Unmuted"
+	if isMuted {
+		icon, message = "microphone-sensitivity-muted", "Muted"
+	}
+	logging.GetLogger(ctx).Info("microphone updated", "muted", isMuted)
+
+	n := notification.Notification{
+		ID:      notification.StatusNotificationID,
+		Title:   "Microphone",
+		Message: message,
+		Icon:    icon,
+	}
+	return notification.Notify(ctx, &n)
+}
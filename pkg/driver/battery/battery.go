@@ -3,6 +3,7 @@ package battery
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
@@ -13,6 +14,19 @@ type Driver interface {
 	BatteryStatus(ctx context.Context) (Status, error)
 }
 
+// Reporter is implemented by drivers that read more than the status.
+type Reporter interface {
+	Info(ctx context.Context) (*Info, error)
+}
+
+// ThresholdSetter is implemented by drivers that can limit charging, which
+// keeps a laptop that lives on AC from sitting at 100%.
+type ThresholdSetter interface {
+	// SetThresholds makes battery name start charging below start and stop
+	// at end, in percent.
+	SetThresholds(ctx context.Context, name string, start, end int) error
+}
+
 // Status represents the charging status of the battery.
 type Status string
 
@@ -21,5 +35,118 @@ const (
 	Charging    Status = "Charging"
 	Discharging Status = "Discharging"
 	Full        Status = "Full"
+	NotCharging Status = "Not charging"
 	Unknown     Status = "Unknown"
 )
+
+// Battery is one battery. Energies are in watt-hours and zero when the
+// hardware does not report them.
+type Battery struct {
+	Name         string  `json:"name"`
+	Status       Status  `json:"status"`
+	Capacity     int     `json:"capacity"`
+	Energy       float64 `json:"energy_wh,omitempty"`
+	EnergyFull   float64 `json:"energy_full_wh,omitempty"`
+	EnergyDesign float64 `json:"energy_design_wh,omitempty"`
+	// Power is the charge or discharge rate in watts, always positive.
+	Power       float64       `json:"power_w,omitempty"`
+	TimeToEmpty time.Duration `json:"time_to_empty,omitempty"`
+	TimeToFull  time.Duration `json:"time_to_full,omitempty"`
+	CycleCount  int           `json:"cycle_count,omitempty"`
+	Model       string        `json:"model,omitempty"`
+	Technology  string        `json:"technology,omitempty"`
+	Thresholds  *Thresholds   `json:"thresholds,omitempty"`
+}
+
+// Thresholds are the charge limits of a battery, in percent.
+type Thresholds struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Health is the full charge capacity left relative to the design, or 0
+// when unknown.
+func (b Battery) Health() float64 {
+	if b.EnergyDesign <= 0 || b.EnergyFull <= 0 {
+		return 0
+	}
+	return b.EnergyFull / b.EnergyDesign
+}
+
+// Info is every battery plus the external power state.
+type Info struct {
+	Batteries []Battery `json:"batteries"`
+	// OnAC is true when a mains or USB supply is online.
+	OnAC bool `json:"on_ac"`
+}
+
+// Status combines the batteries: charging or discharging if any is, full
+// if all are.
+func (i *Info) Status() Status {
+	if len(i.Batteries) == 0 {
+		return Unknown
+	}
+	full := true
+	for _, b := range i.Batteries {
+		switch b.Status {
+		case Discharging:
+			return Discharging
+		case Charging:
+			return Charging
+		}
+		full = full && b.Status == Full
+	}
+	if full {
+		return Full
+	}
+	return i.Batteries[0].Status
+}
+
+// Capacity is the combined charge in percent, weighted by energy when
+// every battery reports it.
+func (i *Info) Capacity() int {
+	if len(i.Batteries) == 0 {
+		return 0
+	}
+	var now, full float64
+	sum := 0
+	for _, b := range i.Batteries {
+		now += b.Energy
+		full += b.EnergyFull
+		sum += b.Capacity
+	}
+	for _, b := range i.Batteries {
+		if b.EnergyFull <= 0 {
+			return sum / len(i.Batteries)
+		}
+	}
+	return int(now/full*100 + 0.5)
+}
+
+// TimeRemaining is the combined time to empty while discharging or to
+// full while charging, or 0 when it cannot be estimated.
+func (i *Info) TimeRemaining() time.Duration {
+	var energy, power float64
+	charging := i.Status() == Charging
+	for _, b := range i.Batteries {
+		power += b.Power
+		if charging {
+			energy += b.EnergyFull - b.Energy
+		} else {
+			energy += b.Energy
+		}
+	}
+	if power > 0 && energy > 0 {
+		return time.Duration(energy / power * float64(time.Hour)).Round(time.Minute)
+	}
+	// Without energy readings use the estimates the batteries report.
+	var ret time.Duration
+	for _, b := range i.Batteries {
+		if charging {
+			ret = max(ret, b.TimeToFull)
+		} else {
+			ret = max(ret, b.TimeToEmpty)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,60 @@
+package battery
+
+import (
+	"context"
+	"fmt"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver"
+)
+
+// GetInfo reads every battery. Drivers without a Reporter give a single
+// battery with only its status.
+func GetInfo(ctx context.Context) (*Info, error) {
+	return driver.WithResult(ctx, func(d Driver) (*Info, error) {
+		if r, ok := d.(Reporter); ok {
+			return r.Info(ctx)
+		}
+		status, err := d.BatteryStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &Info{Batteries: []Battery{{Status: status}}, OnAC: status != Discharging}, nil
+	})
+}
+
+// SetThresholds sets the charge limits of battery name, or of every
+// battery that has them when name is empty.
+func SetThresholds(ctx context.Context, name string, start, end int) error {
+	return driver.With(ctx, func(d Driver) error {
+		setter, ok := d.(ThresholdSetter)
+		if !ok {
+			return fmt.Errorf("%w: charge thresholds", dapi.ErrNotSupported)
+		}
+		if name != "" {
+			return setter.SetThresholds(ctx, name, start, end)
+		}
+		r, ok := d.(Reporter)
+		if !ok {
+			return fmt.Errorf("%w: listing batteries", dapi.ErrNotSupported)
+		}
+		info, err := r.Info(ctx)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, b := range info.Batteries {
+			if b.Thresholds == nil {
+				continue
+			}
+			found = true
+			if err := setter.SetThresholds(ctx, b.Name, start, end); err != nil {
+				return err
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: no battery with charge thresholds", dapi.ErrNotSupported)
+		}
+		return nil
+	})
+}
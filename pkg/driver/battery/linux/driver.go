@@ -2,14 +2,24 @@ package linux
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/lucasew/workspaced/pkg/driver"
-	"github.com/lucasew/workspaced/pkg/driver/battery"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/battery"
 )
 
+// DefaultRoot is where the kernel lists power supplies.
+const DefaultRoot = "/sys/class/power_supply"
+
 func init() {
 	driver.Register[battery.Driver](&Factory{})
 }
@@ -21,50 +31,204 @@ func (f *Factory) Name() string { return "linux" }
 
 // CheckCompatibility implements [driver.DriverFactory].
 func (f *Factory) CheckCompatibility(ctx context.Context) error {
-	matches, err := filepath.Glob("/sys/class/power_supply/BAT*/status")
+	info, err := NewDriver(DefaultRoot).Info(ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", driver.ErrIncompatible, err)
 	}
-	if len(matches) == 0 {
-		return fmt.Errorf("%w: /sys/class/power_supply/BAT*/status", driver.ErrIncompatible)
+	if len(info.Batteries) == 0 {
+		return fmt.Errorf("%w: no battery in %s", driver.ErrIncompatible, DefaultRoot)
 	}
 	return nil
 }
 
 // New implements [driver.DriverFactory].
 func (f *Factory) New(ctx context.Context) (battery.Driver, error) {
-	return &Driver{}, nil
+	return NewDriver(DefaultRoot), nil
 }
 
-type Driver struct{}
+// Driver reads power supplies from a sysfs power_supply class directory.
+type Driver struct {
+	Root string
+}
+
+// NewDriver reads supplies under root, DefaultRoot outside of tests.
+func NewDriver(root string) *Driver {
+	return &Driver{Root: root}
+}
 
-// GetStatus retrieves the current battery status.
-// It scans /sys/class/power_supply/BAT*/status to find the first available battery
-// and reads its status.
+// BatteryStatus combines the status of every system battery.
 func (d *Driver) BatteryStatus(ctx context.Context) (battery.Status, error) {
-	matches, err := filepath.Glob("/sys/class/power_supply/BAT*/status")
+	info, err := d.Info(ctx)
 	if err != nil {
 		return battery.Unknown, err
 	}
-	if len(matches) == 0 {
+	if len(info.Batteries) == 0 {
 		return battery.Unknown, battery.ErrNoBattery
 	}
+	return info.Status(), nil
+}
 
-	// For now just get the first one
-	data, err := os.ReadFile(matches[0])
+// Info reads every supply. Peripheral batteries (mice, headsets) have
+// scope "Device" and are left out.
+func (d *Driver) Info(ctx context.Context) (*battery.Info, error) {
+	entries, err := os.ReadDir(d.Root)
 	if err != nil {
-		return battery.Unknown, err
+		return nil, err
+	}
+	info := &battery.Info{}
+	for _, e := range entries {
+		dir := filepath.Join(d.Root, e.Name())
+		switch read(dir, "type") {
+		case "Battery":
+			if read(dir, "scope") == "Device" {
+				continue
+			}
+			info.Batteries = append(info.Batteries, readBattery(dir, e.Name()))
+		case "Mains", "USB", "USB_C", "USB_PD":
+			if read(dir, "online") == "1" {
+				info.OnAC = true
+			}
+		}
+	}
+	slices.SortFunc(info.Batteries, func(a, b battery.Battery) int { return strings.Compare(a.Name, b.Name) })
+	return info, nil
+}
+
+func readBattery(dir, name string) battery.Battery {
+	b := battery.Battery{
+		Name:       name,
+		Status:     battery.Status(read(dir, "status")),
+		CycleCount: int(readNumber(dir, "cycle_count")),
+		Model:      read(dir, "model_name"),
+		Technology: read(dir, "technology"),
+	}
+	if b.Status == "" {
+		b.Status = battery.Unknown
+	}
+
+	// Energy is in µWh; batteries reporting charge (µAh) are converted
+	// with the design voltage (µV).
+	if exists(dir, "energy_now") {
+		b.Energy = readNumber(dir, "energy_now") / 1e6
+		b.EnergyFull = readNumber(dir, "energy_full") / 1e6
+		b.EnergyDesign = readNumber(dir, "energy_full_design") / 1e6
+	} else if exists(dir, "charge_now") {
+		volts := readNumber(dir, "voltage_min_design")
+		if volts == 0 {
+			volts = readNumber(dir, "voltage_now")
+		}
+		volts /= 1e6
+		b.Energy = readNumber(dir, "charge_now") / 1e6 * volts
+		b.EnergyFull = readNumber(dir, "charge_full") / 1e6 * volts
+		b.EnergyDesign = readNumber(dir, "charge_full_design") / 1e6 * volts
+	}
+
+	// Some firmware reports discharge as a negative rate.
+	if exists(dir, "power_now") {
+		b.Power = math.Abs(readNumber(dir, "power_now")) / 1e6
+	} else if exists(dir, "current_now") {
+		b.Power = math.Abs(readNumber(dir, "current_now")) / 1e6 * readNumber(dir, "voltage_now") / 1e6
+	}
+
+	if exists(dir, "capacity") {
+		b.Capacity = int(readNumber(dir, "capacity"))
+	} else if b.EnergyFull > 0 {
+		b.Capacity = int(b.Energy/b.EnergyFull*100 + 0.5)
+	}
+
+	switch {
+	case exists(dir, "time_to_empty_now") || exists(dir, "time_to_full_now"):
+		b.TimeToEmpty = time.Duration(readNumber(dir, "time_to_empty_now")) * time.Second
+		b.TimeToFull = time.Duration(readNumber(dir, "time_to_full_now")) * time.Second
+	case b.Power > 0 && b.Status == battery.Discharging:
+		b.TimeToEmpty = hours(b.Energy / b.Power)
+	case b.Power > 0 && b.Status == battery.Charging:
+		b.TimeToFull = hours((b.EnergyFull - b.Energy) / b.Power)
 	}
 
-	s := strings.TrimSpace(string(data))
-	switch s {
-	case "Charging":
-		return battery.Charging, nil
-	case "Discharging":
-		return battery.Discharging, nil
-	case "Full":
-		return battery.Full, nil
-	default:
-		return battery.Status(s), nil
+	if start, end, ok := thresholdFiles(dir); ok {
+		b.Thresholds = &battery.Thresholds{End: int(readNumber(dir, end))}
+		if start != "" {
+			b.Thresholds.Start = int(readNumber(dir, start))
+		}
+	}
+	return b
+}
+
+// thresholdFiles finds the charge limit attributes: the generic names
+// first, then the older ThinkPad ones. Some batteries only limit the end.
+func thresholdFiles(dir string) (start, end string, ok bool) {
+	for _, pair := range [][2]string{
+		{"charge_control_start_threshold", "charge_control_end_threshold"},
+		{"charge_start_threshold", "charge_stop_threshold"},
+	} {
+		if !exists(dir, pair[1]) {
+			continue
+		}
+		if exists(dir, pair[0]) {
+			return pair[0], pair[1], true
+		}
+		return "", pair[1], true
+	}
+	return "", "", false
+}
+
+// SetThresholds writes the charge limits of battery name. The kernel needs
+// start below end at every moment, so the writes are ordered to keep it.
+func (d *Driver) SetThresholds(ctx context.Context, name string, start, end int) error {
+	if start < 0 || end > 100 || start >= end {
+		return fmt.Errorf("invalid thresholds %d-%d: want 0 <= start < end <= 100", start, end)
+	}
+	dir := filepath.Join(d.Root, name)
+	if read(dir, "type") != "Battery" {
+		return fmt.Errorf("%w: %s", battery.ErrNoBattery, name)
+	}
+	startFile, endFile, ok := thresholdFiles(dir)
+	if !ok {
+		return fmt.Errorf("%s has no charge thresholds", name)
+	}
+
+	writes := [][2]string{{endFile, strconv.Itoa(end)}}
+	if startFile != "" {
+		startWrite := [2]string{startFile, strconv.Itoa(start)}
+		if start < int(readNumber(dir, endFile)) {
+			writes = [][2]string{startWrite, writes[0]}
+		} else {
+			writes = append(writes, startWrite)
+		}
+	}
+	for _, w := range writes {
+		if err := os.WriteFile(filepath.Join(dir, w[0]), []byte(w[1]), 0o644); err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return fmt.Errorf("write %s: %w (needs root or a udev rule)", w[0], err)
+			}
+			return fmt.Errorf("write %s: %w", w[0], err)
+		}
+	}
+	return nil
+}
+
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour)).Round(time.Minute)
+}
+
+func exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+func read(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readNumber(dir, name string) float64 {
+	v, err := strconv.ParseFloat(read(dir, name), 64)
+	if err != nil {
+		return 0
 	}
+	return v
 }
@@ -0,0 +1,171 @@
+package linux
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver/battery"
+)
+
+// supply writes a fake power_supply entry under root.
+func supply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, k), []byte(v+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func fakeLaptop(t *testing.T) string {
+	root := t.TempDir()
+	// Energy-reporting battery with generic thresholds.
+	supply(t, root, "BAT0", map[string]string{
+		"type":                           "Battery",
+		"status":                         "Discharging",
+		"capacity":                       "50",
+		"energy_now":                     "20000000",
+		"energy_full":                    "40000000",
+		"energy_full_design":             "50000000",
+		"power_now":                      "10000000",
+		"cycle_count":                    "321",
+		"model_name":                     "5B10W13930",
+		"technology":                     "Li-poly",
+		"charge_control_start_threshold": "75",
+		"charge_control_end_threshold":   "80",
+	})
+	// Charge-reporting battery with a negative current.
+	supply(t, root, "BAT1", map[string]string{
+		"type":               "Battery",
+		"status":             "Discharging",
+		"charge_now":         "1000000",
+		"charge_full":        "2000000",
+		"charge_full_design": "2000000",
+		"voltage_min_design": "10000000",
+		"voltage_now":        "10000000",
+		"current_now":        "-500000",
+	})
+	supply(t, root, "AC", map[string]string{"type": "Mains", "online": "0"})
+	supply(t, root, "hidpp_battery_0", map[string]string{"type": "Battery", "scope": "Device", "capacity": "90"})
+	return root
+}
+
+func TestInfo(t *testing.T) {
+	d := NewDriver(fakeLaptop(t))
+	info, err := d.Info(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.OnAC {
+		t.Error("OnAC with the adapter offline")
+	}
+	if len(info.Batteries) != 2 {
+		t.Fatalf("batteries = %+v, want BAT0 and BAT1", info.Batteries)
+	}
+
+	bat0 := info.Batteries[0]
+	if bat0.Name != "BAT0" || bat0.Capacity != 50 || bat0.Energy != 20 || bat0.EnergyFull != 40 || bat0.Power != 10 {
+		t.Errorf("BAT0 = %+v", bat0)
+	}
+	if bat0.TimeToEmpty != 2*time.Hour {
+		t.Errorf("BAT0 time to empty = %v", bat0.TimeToEmpty)
+	}
+	if h := bat0.Health(); h != 0.8 {
+		t.Errorf("BAT0 health = %v", h)
+	}
+	if bat0.CycleCount != 321 || bat0.Technology != "Li-poly" {
+		t.Errorf("BAT0 details = %+v", bat0)
+	}
+	if bat0.Thresholds == nil || *bat0.Thresholds != (battery.Thresholds{Start: 75, End: 80}) {
+		t.Errorf("BAT0 thresholds = %+v", bat0.Thresholds)
+	}
+
+	bat1 := info.Batteries[1]
+	if bat1.Energy != 10 || bat1.EnergyFull != 20 || bat1.Power != 5 || bat1.Capacity != 50 {
+		t.Errorf("BAT1 = %+v", bat1)
+	}
+	if bat1.Thresholds != nil {
+		t.Errorf("BAT1 thresholds = %+v, want none", bat1.Thresholds)
+	}
+
+	if got := info.Status(); got != battery.Discharging {
+		t.Errorf("status = %v", got)
+	}
+	if got := info.Capacity(); got != 50 {
+		t.Errorf("capacity = %v", got)
+	}
+	// 30Wh left at 15W.
+	if got := info.TimeRemaining(); got != 2*time.Hour {
+		t.Errorf("time remaining = %v", got)
+	}
+}
+
+func TestInfoCharging(t *testing.T) {
+	root := t.TempDir()
+	supply(t, root, "BAT0", map[string]string{
+		"type":        "Battery",
+		"status":      "Charging",
+		"energy_now":  "30000000",
+		"energy_full": "40000000",
+		"power_now":   "20000000",
+	})
+	supply(t, root, "ADP1", map[string]string{"type": "Mains", "online": "1"})
+
+	d := NewDriver(root)
+	info, err := d.Info(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.OnAC {
+		t.Error("OnAC = false with the adapter online")
+	}
+	b := info.Batteries[0]
+	if b.Capacity != 75 || b.TimeToFull != 30*time.Minute {
+		t.Errorf("battery = %+v", b)
+	}
+	status, err := d.BatteryStatus(context.Background())
+	if err != nil || status != battery.Charging {
+		t.Errorf("BatteryStatus = %v, %v", status, err)
+	}
+}
+
+func TestNoBattery(t *testing.T) {
+	root := t.TempDir()
+	supply(t, root, "AC", map[string]string{"type": "Mains", "online": "1"})
+	if _, err := NewDriver(root).BatteryStatus(context.Background()); !errors.Is(err, battery.ErrNoBattery) {
+		t.Fatalf("BatteryStatus err = %v, want ErrNoBattery", err)
+	}
+}
+
+func TestSetThresholds(t *testing.T) {
+	root := fakeLaptop(t)
+	d := NewDriver(root)
+	ctx := context.Background()
+
+	// Raising both past the current end has to write the end first.
+	if err := d.SetThresholds(ctx, "BAT0", 85, 90); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "BAT0")
+	if start, end := read(dir, "charge_control_start_threshold"), read(dir, "charge_control_end_threshold"); start != "85" || end != "90" {
+		t.Errorf("thresholds = %s-%s", start, end)
+	}
+
+	if err := d.SetThresholds(ctx, "BAT0", 90, 80); err == nil {
+		t.Error("expected error for start above end")
+	}
+	if err := d.SetThresholds(ctx, "BAT1", 40, 80); err == nil {
+		t.Error("expected error for a battery without thresholds")
+	}
+	if err := d.SetThresholds(ctx, "AC", 40, 80); !errors.Is(err, battery.ErrNoBattery) {
+		t.Errorf("SetThresholds(AC) err = %v, want ErrNoBattery", err)
+	}
+}
@@ -0,0 +1,197 @@
+package battery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/notification"
+	"github.com/lucasew/workspaced/pkg/driver/power"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// PolicyInterval is how often the daemon checks the battery.
+const PolicyInterval = 30 * time.Second
+
+// CriticalGrace is how long the battery stays critical before the critical
+// action runs, time enough to plug in after the notification.
+const CriticalGrace = time.Minute
+
+// PolicyConfig is workspaced.battery.
+type PolicyConfig struct {
+	Low            int         `json:"low"`
+	Critical       int         `json:"critical"`
+	CriticalAction string      `json:"critical_action"`
+	Thresholds     *Thresholds `json:"charge_thresholds,omitempty"`
+}
+
+// DefaultPolicyConfig mirrors the schema defaults.
+var DefaultPolicyConfig = PolicyConfig{Low: 15, Critical: 5, CriticalAction: "none"}
+
+// LoadPolicyConfig reads workspaced.battery, falling back to the defaults.
+func LoadPolicyConfig(ctx context.Context) (PolicyConfig, error) {
+	cfg := DefaultPolicyConfig
+	c, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return cfg, err
+	}
+	if _, err := c.Lookup("battery"); err == nil {
+		if err := c.Decode("battery", &cfg); err != nil {
+			return cfg, fmt.Errorf("decode battery: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// Level is how urgent the battery charge is.
+type Level int
+
+const (
+	LevelNormal Level = iota
+	LevelLow
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelLow:
+		return "low"
+	case LevelCritical:
+		return "critical"
+	}
+	return "normal"
+}
+
+// Level classifies info. Only a discharging battery is ever low.
+func (c PolicyConfig) Level(info *Info) Level {
+	if len(info.Batteries) == 0 || info.OnAC || info.Status() != Discharging {
+		return LevelNormal
+	}
+	switch capacity := info.Capacity(); {
+	case capacity <= c.Critical:
+		return LevelCritical
+	case capacity <= c.Low:
+		return LevelLow
+	}
+	return LevelNormal
+}
+
+// Monitor tracks the level between checks so each alert fires once.
+type Monitor struct {
+	Config        PolicyConfig
+	level         Level
+	criticalSince time.Time
+	acted         bool
+}
+
+// Update returns the level to alert about when it got worse since the last
+// check (LevelNormal otherwise), and whether the critical action is due.
+func (m *Monitor) Update(now time.Time, info *Info) (alert Level, act bool) {
+	level := m.Config.Level(info)
+	if level > m.level {
+		alert = level
+	}
+	if level == LevelCritical {
+		if m.level != LevelCritical {
+			m.criticalSince = now
+		}
+		hasAction := m.Config.CriticalAction != "" && m.Config.CriticalAction != "none"
+		if hasAction && !m.acted && now.Sub(m.criticalSince) >= CriticalGrace {
+			m.acted = true
+			act = true
+		}
+	} else {
+		m.acted = false
+	}
+	m.level = level
+	return alert, act
+}
+
+// WatchPolicy applies workspaced.battery until ctx is done: the charge
+// thresholds once, then low and critical notifications and the critical
+// action. It does nothing without a battery.
+func WatchPolicy(ctx context.Context) {
+	logger := logging.GetLogger(ctx)
+	cfg, err := LoadPolicyConfig(ctx)
+	if err != nil {
+		logger.Warn("battery policy disabled", "error", err)
+		return
+	}
+	info, err := GetInfo(ctx)
+	if err != nil || len(info.Batteries) == 0 {
+		if err != nil && !errors.Is(err, driver.ErrNotFound) && !errors.Is(err, ErrNoBattery) {
+			logger.Warn("battery policy disabled", "error", err)
+		}
+		return
+	}
+	if t := cfg.Thresholds; t != nil {
+		if err := SetThresholds(ctx, "", t.Start, t.End); err != nil {
+			logging.ReportError(ctx, err, "op", "battery charge thresholds")
+		}
+	}
+
+	m := &Monitor{Config: cfg}
+	ticker := time.NewTicker(PolicyInterval)
+	defer ticker.Stop()
+	for {
+		if info, err := GetInfo(ctx); err != nil {
+			logging.ReportError(ctx, err, "op", "battery policy")
+		} else {
+			alert, act := m.Update(time.Now(), info)
+			if alert != LevelNormal {
+				notifyLevel(ctx, alert, info, cfg)
+			}
+			if act {
+				logger.Warn("battery critical", "action", cfg.CriticalAction, "capacity", info.Capacity())
+				if err := runCriticalAction(ctx, cfg.CriticalAction); err != nil {
+					logging.ReportError(ctx, err, "op", "battery critical action")
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func notifyLevel(ctx context.Context, level Level, info *Info, cfg PolicyConfig) {
+	n := notification.Notification{
+		Title:    "Battery low",
+		Message:  fmt.Sprintf("%d%% remaining", info.Capacity()),
+		Urgency:  "normal",
+		Icon:     "battery-caution",
+		Category: "device",
+	}
+	if left := info.TimeRemaining(); left > 0 {
+		n.Message += fmt.Sprintf(", about %s", left)
+	}
+	if level == LevelCritical {
+		n.Title = "Battery critical"
+		n.Urgency = "critical"
+		n.Icon = "battery-empty"
+		n.Timeout = notification.NeverExpire
+		if a := cfg.CriticalAction; a != "" && a != "none" {
+			n.Message += fmt.Sprintf(". Will %s in %s unless plugged in.", a, CriticalGrace)
+		}
+	}
+	if err := notification.Notify(ctx, &n); err != nil {
+		logging.ReportError(ctx, err, "op", "battery notification")
+	}
+}
+
+func runCriticalAction(ctx context.Context, action string) error {
+	switch action {
+	case "suspend":
+		return power.Suspend(ctx)
+	case "hibernate":
+		return power.Hibernate(ctx)
+	case "shutdown":
+		return power.Shutdown(ctx)
+	}
+	return fmt.Errorf("unknown battery critical action %q", action)
+}
@@ -0,0 +1,87 @@
+package battery
+
+import (
+	"testing"
+	"time"
+)
+
+func discharging(capacity int) *Info {
+	return &Info{Batteries: []Battery{{Status: Discharging, Capacity: capacity}}}
+}
+
+func TestPolicyLevel(t *testing.T) {
+	cfg := DefaultPolicyConfig
+	for _, tc := range []struct {
+		info *Info
+		want Level
+	}{
+		{discharging(50), LevelNormal},
+		{discharging(15), LevelLow},
+		{discharging(5), LevelCritical},
+		{&Info{Batteries: []Battery{{Status: Charging, Capacity: 3}}}, LevelNormal},
+		{&Info{Batteries: []Battery{{Status: Discharging, Capacity: 3}}, OnAC: true}, LevelNormal},
+		{&Info{}, LevelNormal},
+	} {
+		if got := cfg.Level(tc.info); got != tc.want {
+			t.Errorf("Level(%+v) = %v, want %v", tc.info, got, tc.want)
+		}
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &Monitor{Config: PolicyConfig{Low: 15, Critical: 5, CriticalAction: "hibernate"}}
+
+	check := func(at time.Duration, info *Info, wantAlert Level, wantAct bool) {
+		t.Helper()
+		alert, act := m.Update(t0.Add(at), info)
+		if alert != wantAlert || act != wantAct {
+			t.Fatalf("at %v: Update = %v, %v; want %v, %v", at, alert, act, wantAlert, wantAct)
+		}
+	}
+
+	check(0, discharging(20), LevelNormal, false)
+	check(time.Minute, discharging(14), LevelLow, false)
+	check(2*time.Minute, discharging(12), LevelNormal, false)
+	check(3*time.Minute, discharging(5), LevelCritical, false)
+	check(3*time.Minute+30*time.Second, discharging(4), LevelNormal, false)
+	check(4*time.Minute, discharging(4), LevelNormal, true)
+	check(5*time.Minute, discharging(3), LevelNormal, false)
+
+	// Plugging in resets the alerts.
+	check(6*time.Minute, &Info{Batteries: []Battery{{Status: Charging, Capacity: 3}}, OnAC: true}, LevelNormal, false)
+	check(7*time.Minute, discharging(10), LevelLow, false)
+
+	m = &Monitor{Config: DefaultPolicyConfig}
+	check(0, discharging(2), LevelCritical, false)
+	check(time.Hour, discharging(1), LevelNormal, false)
+}
+
+func TestInfoCombined(t *testing.T) {
+	info := &Info{Batteries: []Battery{
+		{Status: Discharging, Capacity: 100, Energy: 30, EnergyFull: 30},
+		{Status: Unknown, Capacity: 0, Energy: 0, EnergyFull: 10, Power: 0},
+	}}
+	if got := info.Status(); got != Discharging {
+		t.Errorf("Status = %v", got)
+	}
+	// Energy weighted, not the 50% mean.
+	if got := info.Capacity(); got != 75 {
+		t.Errorf("Capacity = %v", got)
+	}
+	if got := info.TimeRemaining(); got != 0 {
+		t.Errorf("TimeRemaining without a rate = %v", got)
+	}
+	info.Batteries[0].Power = 15
+	if got := info.TimeRemaining(); got != 2*time.Hour {
+		t.Errorf("TimeRemaining = %v", got)
+	}
+
+	full := &Info{Batteries: []Battery{{Status: Full}, {Status: Full}}}
+	if got := full.Status(); got != Full {
+		t.Errorf("Status of full batteries = %v", got)
+	}
+	if got := (Battery{EnergyFull: 40, EnergyDesign: 50}).Health(); got != 0.8 {
+		t.Errorf("Health = %v", got)
+	}
+}
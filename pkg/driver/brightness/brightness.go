@@ -9,12 +9,35 @@ var (
 	ErrDeviceNotFound = errors.New("brightness device not found")
 )
 
+// Device kinds.
+const (
+	KindBacklight = "backlight"
+	KindExternal  = "external"
+)
+
 type Device struct {
-	Name       string
-	Brightness float64
+	// ID addresses the device in SetDeviceBrightness, e.g.
+	// "intel_backlight" or "ddc:DP-1".
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	Brightness float64 `json:"brightness"`
 }
 
+// Driver controls the built-in display backlight.
 type Driver interface {
 	SetBrightness(ctx context.Context, brightness float64) error
 	Status(ctx context.Context) (*Device, error)
 }
+
+// Devices is implemented by drivers that address several devices.
+type Devices interface {
+	ListDevices(ctx context.Context) ([]Device, error)
+	SetDeviceBrightness(ctx context.Context, id string, brightness float64) error
+}
+
+// ExternalDriver controls external monitors (DDC/CI). It is a driver of
+// its own so monitors are listed next to the backlight of Driver.
+type ExternalDriver interface {
+	Devices
+}
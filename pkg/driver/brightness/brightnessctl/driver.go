@@ -35,9 +35,33 @@ func (d *Driver) Status(ctx context.Context) (*brightness.Device, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get brightness status: %w", err)
 	}
+	devices := parseMachine(string(out))
+	if len(devices) == 0 {
+		return nil, brightness.ErrDeviceNotFound
+	}
+	return &devices[0], nil
+}
+
+// ListDevices implements [brightness.Devices] with the backlight class.
+func (d *Driver) ListDevices(ctx context.Context) ([]brightness.Device, error) {
+	out, err := execdriver.MustRun(ctx, "brightnessctl", "-l", "-m", "-c", "backlight").Output()
+	if err != nil {
+		return nil, fmt.Errorf("list brightness devices: %w", err)
+	}
+	return parseMachine(string(out)), nil
+}
+
+func (d *Driver) SetDeviceBrightness(ctx context.Context, id string, level float64) error {
+	if err := execdriver.MustRun(ctx, "brightnessctl", "-d", id, "s", fmt.Sprintf("%d%%", int(level*100))).Run(); err != nil {
+		return fmt.Errorf("set brightness of %s: %w", id, err)
+	}
+	return nil
+}
 
-	lines := strings.SplitSeq(strings.TrimSpace(string(out)), "\n")
-	for line := range lines {
+// parseMachine reads `brightnessctl -m` lines: device,class,current,percent,max.
+func parseMachine(out string) []brightness.Device {
+	var ret []brightness.Device
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
 		parts := strings.Split(line, ",")
 		if len(parts) < 5 {
 			continue
@@ -50,12 +74,14 @@ func (d *Driver) Status(ctx context.Context) (*brightness.Device, error) {
 		if err != nil {
 			continue
 		}
-		return &brightness.Device{
+		ret = append(ret, brightness.Device{
+			ID:         devname,
 			Name:       devname,
+			Kind:       brightness.KindBacklight,
 			Brightness: float64(l) / 100,
-		}, nil
+		})
 	}
-	return nil, brightness.ErrDeviceNotFound
+	return ret
 }
 
 func (d *Driver) SetBrightness(ctx context.Context, level float64) error {
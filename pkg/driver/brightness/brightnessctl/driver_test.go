@@ -0,0 +1,24 @@
+package brightnessctl
+
+import (
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+)
+
+func TestParseMachine(t *testing.T) {
+	out := "intel_backlight,backlight,4000,50%,8000\namdgpu_bl1,backlight,255,100%,255\nbroken line\n"
+	got := parseMachine(out)
+	want := []brightness.Device{
+		{ID: "intel_backlight", Name: "intel_backlight", Kind: brightness.KindBacklight, Brightness: 0.5},
+		{ID: "amdgpu_bl1", Name: "amdgpu_bl1", Kind: brightness.KindBacklight, Brightness: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("device %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
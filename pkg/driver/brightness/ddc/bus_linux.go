@@ -0,0 +1,26 @@
+//go:build linux
+
+package ddc
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// i2cSlave is the I2C_SLAVE ioctl from linux/i2c-dev.h.
+const i2cSlave = 0x0703
+
+// openBus opens an I2C adapter addressed to the DDC/CI slave.
+func openBus(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.IoctlSetInt(int(f.Fd()), i2cSlave, SlaveAddr); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("address %s: %w", path, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package ddc
+
+import (
+	"fmt"
+	"os"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+)
+
+func openBus(path string) (*os.File, error) {
+	return nil, fmt.Errorf("%w: DDC/CI needs Linux i2c-dev", dapi.ErrNotSupported)
+}
@@ -0,0 +1,142 @@
+// Package ddc controls external monitor brightness over DDC/CI, talking to
+// /dev/i2c-* directly (the user needs access to them, usually through the
+// i2c group).
+package ddc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+const idPrefix = "ddc:"
+
+var (
+	sysRoot = "/sys"
+	devRoot = "/dev"
+)
+
+func init() {
+	driver.Register[brightness.ExternalDriver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "brightness_ddc" }
+func (f *Factory) Name() string { return "DDC/CI (i2c-dev)" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	connectors, err := listConnectors(sysRoot, devRoot)
+	if err != nil {
+		return fmt.Errorf("%w: list drm connectors: %w", driver.ErrIncompatible, err)
+	}
+	for _, c := range connectors {
+		bus, err := openBus(c.Bus)
+		if err == nil {
+			_ = bus.Close()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no accessible DDC bus (is i2c-dev loaded and /dev/i2c-* writable?)", driver.ErrIncompatible)
+}
+
+func (f *Factory) New(ctx context.Context) (brightness.ExternalDriver, error) {
+	return &Driver{}, nil
+}
+
+type Driver struct{}
+
+// busLocks serializes traffic per bus path across Driver instances
+// (driver.Get builds a new one per call); monitors misbehave on
+// interleaved requests.
+var (
+	busLocksMu sync.Mutex
+	busLocks   = map[string]*sync.Mutex{}
+)
+
+// lockBus locks the bus at path and returns the unlock function.
+func lockBus(path string) func() {
+	busLocksMu.Lock()
+	mu, ok := busLocks[path]
+	if !ok {
+		mu = &sync.Mutex{}
+		busLocks[path] = mu
+	}
+	busLocksMu.Unlock()
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (d *Driver) ListDevices(ctx context.Context) ([]brightness.Device, error) {
+	connectors, err := listConnectors(sysRoot, devRoot)
+	if err != nil {
+		return nil, err
+	}
+	var ret []brightness.Device
+	for _, c := range connectors {
+		current, max, err := read(c.Bus)
+		if err != nil {
+			logging.GetLogger(ctx).Debug("skipping monitor without DDC brightness", "output", c.Name, "error", err)
+			continue
+		}
+		name := c.Name
+		if c.Model != "" {
+			name = c.Model + " (" + c.Name + ")"
+		}
+		ret = append(ret, brightness.Device{
+			ID:         idPrefix + c.Name,
+			Name:       name,
+			Kind:       brightness.KindExternal,
+			Brightness: float64(current) / float64(max),
+		})
+	}
+	return ret, nil
+}
+
+func (d *Driver) SetDeviceBrightness(ctx context.Context, id string, level float64) error {
+	name, ok := strings.CutPrefix(id, idPrefix)
+	if !ok {
+		return fmt.Errorf("%w: %q", brightness.ErrDeviceNotFound, id)
+	}
+	connectors, err := listConnectors(sysRoot, devRoot)
+	if err != nil {
+		return err
+	}
+	for _, c := range connectors {
+		if c.Name != name {
+			continue
+		}
+		defer lockBus(c.Bus)()
+		bus, err := openBus(c.Bus)
+		if err != nil {
+			return err
+		}
+		defer logging.Close(ctx, bus)
+		_, max, err := GetVCP(bus, VCPBrightness)
+		if err != nil {
+			return err
+		}
+		return SetVCP(bus, VCPBrightness, uint16(math.Round(level*float64(max))))
+	}
+	return fmt.Errorf("%w: %q", brightness.ErrDeviceNotFound, id)
+}
+
+func read(path string) (current, max uint16, err error) {
+	defer lockBus(path)()
+	bus, err := openBus(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = bus.Close() }()
+	current, max, err = GetVCP(bus, VCPBrightness)
+	if err == nil && max == 0 {
+		err = ErrUnsupported
+	}
+	return current, max, err
+}
@@ -0,0 +1,103 @@
+package ddc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DDC/CI framing, see the VESA DDC/CI and MCCS standards.
+const (
+	// SlaveAddr is the I2C address monitors answer DDC/CI on.
+	SlaveAddr = 0x37
+	// hostAddr is the source byte of host messages.
+	hostAddr = 0x51
+	// displayWrite seeds the checksum of host messages (0x37 << 1).
+	displayWrite = 0x6E
+	// hostRead seeds the checksum of replies, which are XORed as if
+	// addressed to the virtual host 0x50.
+	hostRead = 0x50
+
+	opGetVCP      = 0x01
+	opGetVCPReply = 0x02
+	opSetVCP      = 0x03
+
+	// VCPBrightness is the MCCS luminance control.
+	VCPBrightness = 0x10
+
+	// replyDelay is how long monitors may take to prepare a reply.
+	replyDelay = 40 * time.Millisecond
+	// setDelay is how long monitors may ignore the bus after a write.
+	setDelay = 50 * time.Millisecond
+)
+
+var (
+	ErrChecksum    = errors.New("ddc: bad reply checksum")
+	ErrUnsupported = errors.New("ddc: VCP code not supported by the monitor")
+)
+
+func checksum(seed byte, b []byte) byte {
+	for _, c := range b {
+		seed ^= c
+	}
+	return seed
+}
+
+// frame wraps payload in the source, length and checksum bytes.
+func frame(payload ...byte) []byte {
+	msg := append([]byte{hostAddr, 0x80 | byte(len(payload))}, payload...)
+	return append(msg, checksum(displayWrite, msg))
+}
+
+func encodeGetVCP(code byte) []byte {
+	return frame(opGetVCP, code)
+}
+
+func encodeSetVCP(code byte, value uint16) []byte {
+	return frame(opSetVCP, code, byte(value>>8), byte(value))
+}
+
+// decodeGetVCPReply parses the 11 byte reply to a Get VCP Feature request.
+func decodeGetVCPReply(b []byte, code byte) (current, max uint16, err error) {
+	if len(b) < 11 {
+		return 0, 0, fmt.Errorf("ddc: short reply (%d bytes)", len(b))
+	}
+	b = b[:11]
+	if b[1]&0x7f != 8 || b[2] != opGetVCPReply {
+		return 0, 0, fmt.Errorf("ddc: unexpected reply % x", b)
+	}
+	if checksum(hostRead, b[:10]) != b[10] {
+		return 0, 0, ErrChecksum
+	}
+	if b[3] != 0 {
+		return 0, 0, ErrUnsupported
+	}
+	if b[4] != code {
+		return 0, 0, fmt.Errorf("ddc: reply for VCP %#x, asked %#x", b[4], code)
+	}
+	return uint16(b[8])<<8 | uint16(b[9]), uint16(b[6])<<8 | uint16(b[7]), nil
+}
+
+// GetVCP reads a continuous VCP value and its maximum over rw, an I2C
+// device already addressed to SlaveAddr.
+func GetVCP(rw io.ReadWriter, code byte) (current, max uint16, err error) {
+	if _, err := rw.Write(encodeGetVCP(code)); err != nil {
+		return 0, 0, fmt.Errorf("ddc: write: %w", err)
+	}
+	time.Sleep(replyDelay)
+	reply := make([]byte, 11)
+	if _, err := io.ReadFull(rw, reply); err != nil {
+		return 0, 0, fmt.Errorf("ddc: read: %w", err)
+	}
+	return decodeGetVCPReply(reply, code)
+}
+
+// SetVCP writes a continuous VCP value over rw.
+func SetVCP(rw io.ReadWriter, code byte, value uint16) error {
+	if _, err := rw.Write(encodeSetVCP(code, value)); err != nil {
+		return fmt.Errorf("ddc: write: %w", err)
+	}
+	time.Sleep(setDelay)
+	return nil
+}
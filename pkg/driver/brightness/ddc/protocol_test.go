@@ -0,0 +1,74 @@
+package ddc
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// fakeMonitor answers DDC/CI requests for one continuous VCP code.
+type fakeMonitor struct {
+	code         byte
+	current, max uint16
+	reply        bytes.Buffer
+	writes       [][]byte
+}
+
+func (m *fakeMonitor) Write(b []byte) (int, error) {
+	m.writes = append(m.writes, append([]byte(nil), b...))
+	if checksum(displayWrite, b[:len(b)-1]) != b[len(b)-1] {
+		return 0, errors.New("bad request checksum")
+	}
+	switch b[2] {
+	case opGetVCP:
+		result := byte(0)
+		if b[3] != m.code {
+			result = 1
+		}
+		r := []byte{0x6E, 0x88, opGetVCPReply, result, b[3], 0, byte(m.max >> 8), byte(m.max), byte(m.current >> 8), byte(m.current)}
+		m.reply.Write(append(r, checksum(hostRead, r)))
+	case opSetVCP:
+		m.current = uint16(b[4])<<8 | uint16(b[5])
+	}
+	return len(b), nil
+}
+
+func (m *fakeMonitor) Read(b []byte) (int, error) { return m.reply.Read(b) }
+
+func TestEncode(t *testing.T) {
+	// The well known "getvcp 10" request as sent by ddcutil.
+	if got, want := encodeGetVCP(VCPBrightness), []byte{0x51, 0x82, 0x01, 0x10, 0xAC}; !bytes.Equal(got, want) {
+		t.Errorf("encodeGetVCP = % x, want % x", got, want)
+	}
+	if got, want := encodeSetVCP(VCPBrightness, 0x0132), []byte{0x51, 0x84, 0x03, 0x10, 0x01, 0x32, 0x6E ^ 0x51 ^ 0x84 ^ 0x03 ^ 0x10 ^ 0x01 ^ 0x32}; !bytes.Equal(got, want) {
+		t.Errorf("encodeSetVCP = % x, want % x", got, want)
+	}
+}
+
+func TestGetSetVCP(t *testing.T) {
+	m := &fakeMonitor{code: VCPBrightness, current: 30, max: 100}
+	current, max, err := GetVCP(m, VCPBrightness)
+	if err != nil || current != 30 || max != 100 {
+		t.Fatalf("GetVCP = %d, %d, %v", current, max, err)
+	}
+	if err := SetVCP(m, VCPBrightness, 75); err != nil {
+		t.Fatal(err)
+	}
+	if current, _, _ := GetVCP(m, VCPBrightness); current != 75 {
+		t.Errorf("after SetVCP current = %d, want 75", current)
+	}
+	if _, _, err := GetVCP(m, 0x12); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("unsupported code error = %v", err)
+	}
+}
+
+func TestDecodeRejectsBadChecksum(t *testing.T) {
+	r := []byte{0x6E, 0x88, opGetVCPReply, 0, VCPBrightness, 0, 0, 100, 0, 50}
+	r = append(r, checksum(hostRead, r)^0xFF)
+	if _, _, err := decodeGetVCPReply(r, VCPBrightness); !errors.Is(err, ErrChecksum) {
+		t.Errorf("error = %v, want ErrChecksum", err)
+	}
+	if _, _, err := decodeGetVCPReply(r[:5], VCPBrightness); err == nil {
+		t.Error("short reply accepted")
+	}
+}
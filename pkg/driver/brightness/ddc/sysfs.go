@@ -0,0 +1,70 @@
+package ddc
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// connector is a connected DRM output with a DDC bus.
+type connector struct {
+	Name  string // e.g. "DP-1"
+	Model string // from the EDID, may be empty
+	Bus   string // e.g. "/dev/i2c-5"
+}
+
+var cardConnector = regexp.MustCompile(`^card\d+-(.+)$`)
+
+// listConnectors finds connected outputs under sysRoot/class/drm whose ddc
+// link points at an I2C adapter, mapping it to devRoot/i2c-N.
+func listConnectors(sysRoot, devRoot string) ([]connector, error) {
+	entries, err := os.ReadDir(filepath.Join(sysRoot, "class", "drm"))
+	if err != nil {
+		return nil, err
+	}
+	var ret []connector
+	for _, e := range entries {
+		m := cardConnector.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		dir := filepath.Join(sysRoot, "class", "drm", e.Name())
+		status, err := os.ReadFile(filepath.Join(dir, "status"))
+		if err != nil || strings.TrimSpace(string(status)) != "connected" {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(dir, "ddc"))
+		if err != nil {
+			continue
+		}
+		adapter := filepath.Base(target)
+		if !strings.HasPrefix(adapter, "i2c-") {
+			continue
+		}
+		c := connector{Name: m[1], Bus: filepath.Join(devRoot, adapter)}
+		if edid, err := os.ReadFile(filepath.Join(dir, "edid")); err == nil {
+			c.Model = edidName(edid)
+		}
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret, nil
+}
+
+// edidName returns the monitor name descriptor (tag 0xFC) of an EDID block.
+func edidName(edid []byte) string {
+	if len(edid) < 128 {
+		return ""
+	}
+	for off := 54; off+18 <= 126; off += 18 {
+		d := edid[off : off+18]
+		if d[0] != 0 || d[1] != 0 || d[3] != 0xFC {
+			continue
+		}
+		name, _, _ := strings.Cut(string(d[5:]), "\n")
+		return strings.TrimSpace(name)
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package ddc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func edidWithName(name string) []byte {
+	edid := make([]byte, 128)
+	d := edid[72:90] // second descriptor
+	d[3] = 0xFC
+	copy(d[5:], name+"\n")
+	for i := 5 + len(name) + 1; i < 18; i++ {
+		d[i] = ' '
+	}
+	return edid
+}
+
+func TestListConnectors(t *testing.T) {
+	root := t.TempDir()
+	drm := filepath.Join(root, "class", "drm")
+	output := func(name, status, ddc string, edid []byte) {
+		dir := filepath.Join(drm, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "status"), []byte(status+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if ddc != "" {
+			if err := os.Symlink("../../../"+ddc, filepath.Join(dir, "ddc")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if edid != nil {
+			if err := os.WriteFile(filepath.Join(dir, "edid"), edid, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	output("card1-HDMI-A-1", "connected", "i2c-7", nil)
+	output("card1-DP-1", "connected", "i2c-5", edidWithName("DELL U2720Q"))
+	output("card1-DP-2", "disconnected", "i2c-6", nil)
+	output("card1-eDP-1", "connected", "", nil) // internal panel, backlight only
+	output("card1", "", "", nil)
+
+	got, err := listConnectors(root, "/dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []connector{
+		{Name: "DP-1", Model: "DELL U2720Q", Bus: "/dev/i2c-5"},
+		{Name: "HDMI-A-1", Bus: "/dev/i2c-7"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("connector %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEdidName(t *testing.T) {
+	if got := edidName(edidWithName("LG HDR 4K")); got != "LG HDR 4K" {
+		t.Errorf("edidName = %q", got)
+	}
+	if got := edidName(make([]byte, 128)); got != "" {
+		t.Errorf("edidName without descriptor = %q", got)
+	}
+	if got := edidName([]byte{1, 2}); got != "" {
+		t.Errorf("edidName of short edid = %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package brightness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// target is a device with the driver call that sets it.
+type target struct {
+	Device
+	set func(ctx context.Context, level float64) error
+}
+
+// targets lists backlights from Driver and monitors from ExternalDriver.
+// A missing driver of either kind is not an error.
+func targets(ctx context.Context) ([]target, error) {
+	var ret []target
+	var errs []error
+
+	if d, err := driver.Get[Driver](ctx); err == nil {
+		if lister, ok := d.(Devices); ok {
+			devices, err := lister.ListDevices(ctx)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			for _, dev := range devices {
+				ret = append(ret, target{dev, func(ctx context.Context, level float64) error {
+					return lister.SetDeviceBrightness(ctx, dev.ID, level)
+				}})
+			}
+		} else if dev, err := d.Status(ctx); err == nil {
+			dev.Kind = KindBacklight
+			if dev.ID == "" {
+				dev.ID = dev.Name
+			}
+			ret = append(ret, target{*dev, d.SetBrightness})
+		} else {
+			errs = append(errs, err)
+		}
+	} else if !errors.Is(err, driver.ErrNotFound) {
+		errs = append(errs, err)
+	}
+
+	if d, err := driver.Get[ExternalDriver](ctx); err == nil {
+		devices, err := d.ListDevices(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, dev := range devices {
+			ret = append(ret, target{dev, func(ctx context.Context, level float64) error {
+				return d.SetDeviceBrightness(ctx, dev.ID, level)
+			}})
+		}
+	} else if !errors.Is(err, driver.ErrNotFound) {
+		logging.GetLogger(ctx).Debug("no external monitor brightness", "error", err)
+	}
+
+	if len(ret) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return ret, nil
+}
+
+func findTarget(ctx context.Context, id string) (*target, error) {
+	all, err := targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range all {
+		if t.ID == id || (id == "" && t.Kind == KindBacklight) {
+			return &all[i], nil
+		}
+	}
+	if id == "" && len(all) > 0 {
+		return &all[0], nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrDeviceNotFound, id)
+}
+
+// ListDevices lists the backlights and external monitors.
+func ListDevices(ctx context.Context) ([]Device, error) {
+	all, err := targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Device, len(all))
+	for i, t := range all {
+		ret[i] = t.Device
+	}
+	return ret, nil
+}
+
+// GetDevice returns the device with id; "" is the backlight (or the first
+// device without one).
+func GetDevice(ctx context.Context, id string) (*Device, error) {
+	t, err := findTarget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &t.Device, nil
+}
+
+// SetDevice moves device id (see GetDevice) to level over duration.
+// External monitors jump straight to level: every DDC/CI write costs tens
+// of milliseconds of bus time and wears the monitor's settings storage.
+func SetDevice(ctx context.Context, id string, level float64, duration time.Duration) error {
+	t, err := findTarget(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Kind == KindExternal {
+		return t.set(ctx, driver.Clamp01(level))
+	}
+	return Fade(ctx, t.set, t.Brightness, driver.Clamp01(level), duration, EaseInOut)
+}
+
+// Fade calls set with the levels of a transition from from to to.
+func Fade(ctx context.Context, set func(context.Context, float64) error, from, to float64, duration time.Duration, curve Curve) error {
+	steps := Steps(from, to, duration, FrameInterval, curve)
+	interval := time.Duration(0)
+	if len(steps) > 0 {
+		interval = duration / time.Duration(len(steps))
+	}
+	for i, level := range steps {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		if err := set(ctx, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
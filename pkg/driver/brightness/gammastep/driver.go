@@ -0,0 +1,41 @@
+package gammastep
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+)
+
+func init() {
+	driver.Register[brightness.NightLightDriver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "nightlight_gammastep" }
+func (f *Factory) Name() string { return "gammastep" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	return execdriver.RequireBinary(ctx, "gammastep")
+}
+
+func (f *Factory) New(ctx context.Context) (brightness.NightLightDriver, error) {
+	return &Driver{}, nil
+}
+
+type Driver struct{}
+
+// Command runs gammastep in one-shot manual mode. It exits after setting
+// the ramp on X11 and keeps it (until killed) on Wayland.
+func (d *Driver) Command(ctx context.Context, kelvin int) (*exec.Cmd, error) {
+	return execdriver.MustRun(ctx, "gammastep", Args(kelvin)...), nil
+}
+
+// Args builds the gammastep arguments for kelvin.
+func Args(kelvin int) []string {
+	return []string{"-P", "-O", strconv.Itoa(kelvin)}
+}
@@ -0,0 +1,44 @@
+package hyprsunset
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/brightness"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+)
+
+func init() {
+	driver.Register[brightness.NightLightDriver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "nightlight_hyprsunset" }
+func (f *Factory) Name() string { return "hyprsunset (Hyprland)" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	return execdriver.RequireEnvBinary(ctx, "HYPRLAND_INSTANCE_SIGNATURE", "hyprsunset")
+}
+
+func (f *Factory) New(ctx context.Context) (brightness.NightLightDriver, error) {
+	return &Driver{}, nil
+}
+
+type Driver struct{}
+
+// Command runs hyprsunset, which holds the temperature until killed.
+// Neutral asks for the identity ramp instead of a 6500K tint.
+func (d *Driver) Command(ctx context.Context, kelvin int) (*exec.Cmd, error) {
+	return execdriver.MustRun(ctx, "hyprsunset", Args(kelvin)...), nil
+}
+
+// Args builds the hyprsunset arguments for kelvin.
+func Args(kelvin int) []string {
+	if kelvin >= brightness.NeutralTemperature {
+		return []string{"--identity"}
+	}
+	return []string{"--temperature", strconv.Itoa(kelvin)}
+}
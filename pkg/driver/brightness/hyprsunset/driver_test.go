@@ -0,0 +1,15 @@
+package hyprsunset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	if got := Args(4000); !slices.Equal(got, []string{"--temperature", "4000"}) {
+		t.Errorf("Args(4000) = %q", got)
+	}
+	if got := Args(6500); !slices.Equal(got, []string{"--identity"}) {
+		t.Errorf("Args(6500) = %q", got)
+	}
+}
@@ -0,0 +1,249 @@
+package brightness
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// NeutralTemperature is daylight, the color temperature that leaves the
+// display untouched.
+const NeutralTemperature = 6500
+
+// NightLightDriver tints the display to a color temperature. The command
+// holds the temperature while it runs, since Wayland compositors drop the
+// gamma ramp when its client exits; on X11 it may exit right away.
+type NightLightDriver interface {
+	Command(ctx context.Context, kelvin int) (*exec.Cmd, error)
+}
+
+// NightLightConfig is workspaced.brightness.night_light.
+type NightLightConfig struct {
+	Enable     bool   `json:"enable"`
+	Day        int    `json:"day"`
+	Night      int    `json:"night"`
+	Sunset     string `json:"sunset"`
+	Sunrise    string `json:"sunrise"`
+	Transition string `json:"transition"`
+}
+
+// DefaultNightLightConfig mirrors the schema defaults, without enabling it.
+var DefaultNightLightConfig = NightLightConfig{
+	Day:        NeutralTemperature,
+	Night:      4000,
+	Sunset:     "19:00",
+	Sunrise:    "07:00",
+	Transition: "30m",
+}
+
+// LoadNightLightConfig reads workspaced.brightness.night_light; without
+// that block night light stays disabled.
+func LoadNightLightConfig(ctx context.Context) (NightLightConfig, error) {
+	cfg := DefaultNightLightConfig
+	c, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return cfg, err
+	}
+	if _, err := c.Lookup("brightness.night_light"); err == nil {
+		if err := c.Decode("brightness.night_light", &cfg); err != nil {
+			return cfg, fmt.Errorf("decode brightness.night_light: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// Schedule is a parsed NightLightConfig. Times are offsets from local
+// midnight.
+type Schedule struct {
+	Day, Night      int
+	Sunset, Sunrise time.Duration
+	// Transition is how long the fade after sunset and sunrise takes.
+	Transition time.Duration
+}
+
+// Schedule parses the times of c.
+func (c NightLightConfig) Schedule() (Schedule, error) {
+	s := Schedule{Day: c.Day, Night: c.Night}
+	var err error
+	if s.Sunset, err = parseClock(c.Sunset); err != nil {
+		return s, fmt.Errorf("sunset: %w", err)
+	}
+	if s.Sunrise, err = parseClock(c.Sunrise); err != nil {
+		return s, fmt.Errorf("sunrise: %w", err)
+	}
+	if c.Transition != "" {
+		if s.Transition, err = time.ParseDuration(c.Transition); err != nil {
+			return s, fmt.Errorf("transition: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// TemperatureAt is the scheduled color temperature at t: Night from sunset
+// to sunrise, Day otherwise, fading over Transition after each of them.
+func (s Schedule) TemperatureAt(t time.Time) int {
+	const day = 24 * time.Hour
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	since := func(mark time.Duration) time.Duration { return ((now-mark)%day + day) % day }
+
+	nightLength := ((s.Sunrise-s.Sunset)%day + day) % day
+	var night float64 // 0 is Day, 1 is Night
+	if sinceSunset := since(s.Sunset); sinceSunset < nightLength {
+		night = fade(sinceSunset, s.Transition)
+	} else {
+		night = 1 - fade(since(s.Sunrise), s.Transition)
+	}
+	return int(math.Round(float64(s.Day) + float64(s.Night-s.Day)*night))
+}
+
+// fade is the progress of a transition elapsed into it.
+func fade(elapsed, transition time.Duration) float64 {
+	if transition <= 0 || elapsed >= transition {
+		return 1
+	}
+	return EaseInOut(float64(elapsed) / float64(transition))
+}
+
+// NightLightInterval is how often the daemon re-evaluates the schedule.
+const NightLightInterval = 30 * time.Second
+
+// temperatureStep avoids restarting the gamma tool for unnoticeable
+// changes during a fade.
+const temperatureStep = 50
+
+// WatchNightLight applies the night light schedule until ctx is done. It
+// does nothing unless brightness.night_light is configured and enabled.
+func WatchNightLight(ctx context.Context) {
+	logger := logging.GetLogger(ctx)
+	cfg, err := LoadNightLightConfig(ctx)
+	if err != nil {
+		logger.Warn("night light disabled", "error", err)
+		return
+	}
+	if !cfg.Enable {
+		return
+	}
+	schedule, err := cfg.Schedule()
+	if err != nil {
+		logger.Warn("night light disabled", "error", err)
+		return
+	}
+	d, err := driver.Get[NightLightDriver](ctx)
+	if err != nil {
+		logger.Warn("night light disabled", "error", err)
+		return
+	}
+
+	var l nightLight
+	defer l.stop()
+	ticker := time.NewTicker(NightLightInterval)
+	defer ticker.Stop()
+	for {
+		kelvin := schedule.TemperatureAt(time.Now()) / temperatureStep * temperatureStep
+		if kelvin != l.kelvin || l.exited() {
+			// An exited tool (crash, compositor restart, X11 one-shot)
+			// no longer holds the tint; start it again.
+			if kelvin != l.kelvin {
+				logger.Info("night light", "kelvin", kelvin)
+			} else {
+				logger.Debug("night light tool exited, restarting", "kelvin", kelvin)
+			}
+			if err := l.start(ctx, d, kelvin); err != nil {
+				logging.ReportError(ctx, err, "op", "night light")
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetTemperature tints the display until ctx is done, or returns at once
+// for tools that set the temperature and exit (X11).
+func SetTemperature(ctx context.Context, kelvin int) error {
+	d, err := driver.Get[NightLightDriver](ctx)
+	if err != nil {
+		return err
+	}
+	cmd, err := d.Command(ctx, kelvin)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("set color temperature: %w", err)
+	}
+	return nil
+}
+
+// nightLight holds the process keeping the current temperature.
+type nightLight struct {
+	kelvin int
+	cmd    *exec.Cmd
+	done   chan struct{}
+}
+
+func (l *nightLight) start(ctx context.Context, d NightLightDriver, kelvin int) error {
+	l.stop()
+	cmd, err := d.Command(ctx, kelvin)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", cmd.Path, err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	l.kelvin, l.cmd, l.done = kelvin, cmd, done
+	return nil
+}
+
+// exited reports whether the started process has ended on its own.
+func (l *nightLight) exited() bool {
+	if l.done == nil {
+		return false
+	}
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *nightLight) stop() {
+	if l.cmd == nil {
+		return
+	}
+	select {
+	case <-l.done:
+	default:
+		_ = l.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-l.done:
+		case <-time.After(2 * time.Second):
+			_ = l.cmd.Process.Kill()
+			<-l.done
+		}
+	}
+	l.kelvin, l.cmd, l.done = 0, nil, nil
+}
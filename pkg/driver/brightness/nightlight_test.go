@@ -0,0 +1,124 @@
+package brightness
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+)
+
+func at(clock string) time.Time {
+	t, err := time.ParseInLocation("15:04", clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestTemperatureAt(t *testing.T) {
+	s, err := NightLightConfig{Day: 6500, Night: 3500, Sunset: "19:00", Sunrise: "07:00", Transition: "1h"}.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for clock, want := range map[string]int{
+		"12:00": 6500,
+		"18:59": 6500,
+		"19:00": 6500,
+		"19:30": 5000, // halfway into the evening fade
+		"20:00": 3500,
+		"00:00": 3500,
+		"06:59": 3500,
+		"07:30": 5000,
+		"08:00": 6500,
+	} {
+		if got := s.TemperatureAt(at(clock)); got != want {
+			t.Errorf("TemperatureAt(%s) = %d, want %d", clock, got, want)
+		}
+	}
+	if a, b := s.TemperatureAt(at("19:10")), s.TemperatureAt(at("19:20")); !(6500 > a && a > b && b > 5000) {
+		t.Errorf("evening fade not decreasing: 19:10=%d 19:20=%d", a, b)
+	}
+}
+
+func TestTemperatureAtDaytimeNight(t *testing.T) {
+	// A night shift schedule that does not cross midnight, without fades.
+	s, err := NightLightConfig{Day: 6500, Night: 4000, Sunset: "08:00", Sunrise: "16:00"}.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for clock, want := range map[string]int{"07:59": 6500, "08:00": 4000, "15:59": 4000, "16:00": 6500, "23:00": 6500} {
+		if got := s.TemperatureAt(at(clock)); got != want {
+			t.Errorf("TemperatureAt(%s) = %d, want %d", clock, got, want)
+		}
+	}
+}
+
+func TestScheduleErrors(t *testing.T) {
+	for _, c := range []NightLightConfig{
+		{Sunset: "7pm", Sunrise: "07:00"},
+		{Sunset: "19:00", Sunrise: "25:00"},
+		{Sunset: "19:00", Sunrise: "07:00", Transition: "soon"},
+	} {
+		if _, err := c.Schedule(); err == nil {
+			t.Errorf("%+v: no error", c)
+		}
+	}
+}
+
+type sleepDriver struct{ started []int }
+
+func (d *sleepDriver) Command(ctx context.Context, kelvin int) (*exec.Cmd, error) {
+	d.started = append(d.started, kelvin)
+	return exec.CommandContext(ctx, "sleep", "30"), nil
+}
+
+func TestNightLightReplacesProcess(t *testing.T) {
+	d := &sleepDriver{}
+	var l nightLight
+	ctx := context.Background()
+	if err := l.start(ctx, d, 4000); err != nil {
+		t.Fatal(err)
+	}
+	first := l.done
+	if err := l.start(ctx, d, 3500); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-first:
+	default:
+		t.Error("previous process still running")
+	}
+	if l.kelvin != 3500 || !slices.Equal(d.started, []int{4000, 3500}) {
+		t.Errorf("kelvin=%d started=%v", l.kelvin, d.started)
+	}
+	second := l.done
+	l.stop()
+	select {
+	case <-second:
+	default:
+		t.Error("process still running after stop")
+	}
+}
+
+func TestNightLightNoticesExit(t *testing.T) {
+	d := &sleepDriver{}
+	var l nightLight
+	defer l.stop()
+	if l.exited() {
+		t.Fatal("exited before anything started")
+	}
+	if err := l.start(context.Background(), d, 4000); err != nil {
+		t.Fatal(err)
+	}
+	if l.exited() {
+		t.Fatal("exited while the process runs")
+	}
+	if err := l.cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	<-l.done
+	if !l.exited() {
+		t.Fatal("killed process not noticed")
+	}
+}
@@ -0,0 +1,48 @@
+package brightness
+
+import (
+	"math"
+	"time"
+)
+
+// Curve maps transition progress in [0,1] to eased progress in [0,1].
+type Curve func(t float64) float64
+
+// Linear moves at constant speed.
+func Linear(t float64) float64 { return t }
+
+// EaseInOut starts and ends slowly (smoothstep).
+func EaseInOut(t float64) float64 { return t * t * (3 - 2*t) }
+
+// Perceptual eases in brightness space so each step looks equally large:
+// the eye responds to brightness roughly logarithmically.
+func Perceptual(t float64) float64 { return (math.Pow(10, t) - 1) / 9 }
+
+// FrameInterval is the time between transition steps.
+const FrameInterval = 16 * time.Millisecond
+
+// Steps returns the levels a transition from from to to passes through in
+// duration, one per interval, ending exactly at to. Levels that would not
+// change the rounded percentage are dropped.
+func Steps(from, to float64, duration, interval time.Duration, curve Curve) []float64 {
+	if curve == nil {
+		curve = Linear
+	}
+	n := 1
+	if interval > 0 && duration > interval {
+		n = int(duration / interval)
+	}
+	var ret []float64
+	last := math.Round(from * 100)
+	for i := 1; i <= n; i++ {
+		level := from + (to-from)*curve(float64(i)/float64(n))
+		if i == n {
+			level = to
+		}
+		if p := math.Round(level * 100); p != last || i == n {
+			ret = append(ret, level)
+			last = p
+		}
+	}
+	return ret
+}
@@ -0,0 +1,72 @@
+package brightness
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCurvesEndpoints(t *testing.T) {
+	for name, c := range map[string]Curve{"linear": Linear, "ease": EaseInOut, "perceptual": Perceptual} {
+		if c(0) != 0 || math.Abs(c(1)-1) > 1e-9 {
+			t.Errorf("%s: c(0)=%v c(1)=%v", name, c(0), c(1))
+		}
+		for i := range 10 {
+			x := float64(i) / 10
+			if c(x+0.1) < c(x) {
+				t.Errorf("%s is not monotonic at %v", name, x)
+			}
+		}
+	}
+	if EaseInOut(0.5) != 0.5 || EaseInOut(0.1) >= 0.1 {
+		t.Errorf("EaseInOut(0.1)=%v EaseInOut(0.5)=%v", EaseInOut(0.1), EaseInOut(0.5))
+	}
+}
+
+func TestSteps(t *testing.T) {
+	steps := Steps(0.2, 0.8, 100*time.Millisecond, 10*time.Millisecond, Linear)
+	if len(steps) != 10 {
+		t.Fatalf("got %d steps, want 10: %v", len(steps), steps)
+	}
+	if math.Abs(steps[0]-0.26) > 1e-9 || steps[9] != 0.8 {
+		t.Errorf("steps = %v", steps)
+	}
+
+	// No duration jumps straight to the target.
+	if steps := Steps(0.5, 0.1, 0, FrameInterval, EaseInOut); len(steps) != 1 || steps[0] != 0.1 {
+		t.Errorf("instant steps = %v", steps)
+	}
+
+	// Steps that round to the same percentage are dropped.
+	steps = Steps(0.50, 0.52, time.Second, 10*time.Millisecond, Linear)
+	if len(steps) > 3 || steps[len(steps)-1] != 0.52 {
+		t.Errorf("small transition steps = %v", steps)
+	}
+}
+
+func TestFade(t *testing.T) {
+	var levels []float64
+	set := func(_ context.Context, level float64) error {
+		levels = append(levels, level)
+		return nil
+	}
+	start := time.Now()
+	if err := Fade(context.Background(), set, 0, 1, 80*time.Millisecond, EaseInOut); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("fade took %v, want about 80ms", elapsed)
+	}
+	if len(levels) < 2 || levels[len(levels)-1] != 1 || !slices.IsSorted(levels) {
+		t.Errorf("levels = %v", levels)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	levels = nil
+	if err := Fade(ctx, set, 0, 1, time.Second, Linear); err == nil || len(levels) != 1 {
+		t.Errorf("canceled fade: err=%v levels=%v", err, levels)
+	}
+}
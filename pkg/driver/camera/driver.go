@@ -2,9 +2,13 @@ package camera
 
 import (
 	"context"
+	"errors"
 	"image"
 )
 
+// ErrStop ends a Stream without an error when returned by its callback.
+var ErrStop = errors.New("stop streaming")
+
 // Driver is the entrypoint to the camera framework, allowing discovery of cameras.
 type Driver interface {
 	// List returns a list of all currently available cameras.
@@ -20,3 +24,32 @@ type Camera interface {
 	// Capture takes a single still frame from the camera.
 	Capture(ctx context.Context) (image.Image, error)
 }
+
+// CaptureOptions tune how frames are taken. Zero values leave the choice
+// to the device.
+type CaptureOptions struct {
+	// Width and Height request a resolution; set both or neither.
+	Width  int
+	Height int
+	// PixelFormat is the device input format, e.g. "mjpeg" or "yuyv422".
+	PixelFormat string
+	// FrameRate requests frames per second from the device.
+	FrameRate int
+	// Warmup is how many frames to drop first, giving auto exposure and
+	// white balance time to settle.
+	Warmup int
+}
+
+// Configurable is implemented by cameras that honor CaptureOptions.
+type Configurable interface {
+	CaptureWith(ctx context.Context, opts CaptureOptions) (image.Image, error)
+}
+
+// Streamer is implemented by cameras that can keep the device open and
+// deliver frames continuously.
+type Streamer interface {
+	// Stream calls fn with frames until ctx is done or fn returns an
+	// error, and returns that error. Frames that arrive while fn runs may
+	// be dropped so fn always sees a recent one.
+	Stream(ctx context.Context, opts CaptureOptions, fn func(image.Image) error) error
+}
@@ -0,0 +1,54 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+)
+
+// Capture takes a frame from cam with opts. It returns api.ErrNotSupported
+// when options are given but the camera cannot honor them.
+func Capture(ctx context.Context, cam Camera, opts CaptureOptions) (image.Image, error) {
+	if c, ok := cam.(Configurable); ok {
+		return c.CaptureWith(ctx, opts)
+	}
+	if opts != (CaptureOptions{}) {
+		return nil, fmt.Errorf("%w: %s does not take capture options", dapi.ErrNotSupported, cam.ID())
+	}
+	return cam.Capture(ctx)
+}
+
+// Stream delivers frames from cam to fn until ctx is done or fn returns an
+// error, and returns that error; ErrStop ends it cleanly. Cameras that cannot stream are polled
+// with one capture per frame.
+func Stream(ctx context.Context, cam Camera, opts CaptureOptions, fn func(image.Image) error) error {
+	var err error
+	if s, ok := cam.(Streamer); ok {
+		err = s.Stream(ctx, opts, fn)
+	} else {
+		err = poll(ctx, cam, opts, fn)
+	}
+	if errors.Is(err, ErrStop) {
+		return nil
+	}
+	return err
+}
+
+func poll(ctx context.Context, cam Camera, opts CaptureOptions, fn func(image.Image) error) error {
+	for ctx.Err() == nil {
+		img, err := Capture(ctx, cam, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+		if err := fn(img); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,57 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+)
+
+// stillCamera only implements the base interface.
+type stillCamera struct {
+	shots int
+}
+
+func (c *stillCamera) ID() string   { return "still" }
+func (c *stillCamera) Name() string { return "Still" }
+
+func (c *stillCamera) Capture(ctx context.Context) (image.Image, error) {
+	c.shots++
+	return image.NewGray(image.Rect(0, 0, c.shots, 1)), nil
+}
+
+func TestCaptureOptionsNeedConfigurable(t *testing.T) {
+	cam := &stillCamera{}
+	if _, err := Capture(t.Context(), cam, CaptureOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Capture(t.Context(), cam, CaptureOptions{Width: 640, Height: 480})
+	if !errors.Is(err, dapi.ErrNotSupported) {
+		t.Fatalf("err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestStreamPollsStillCameras(t *testing.T) {
+	cam := &stillCamera{}
+	var widths []int
+	err := Stream(t.Context(), cam, CaptureOptions{}, func(img image.Image) error {
+		widths = append(widths, img.Bounds().Dx())
+		if len(widths) == 3 {
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(widths) != 3 || widths[2] != 3 {
+		t.Fatalf("frames = %v", widths)
+	}
+
+	boom := errors.New("boom")
+	if err := Stream(t.Context(), cam, CaptureOptions{}, func(image.Image) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want callback error", err)
+	}
+}
@@ -1,11 +1,14 @@
 package linux
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	_ "image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -84,31 +87,53 @@ func (c *cameraDevice) ID() string { return c.id }
 func (c *cameraDevice) Name() string { return c.name }
 
 func (c *cameraDevice) Capture(ctx context.Context) (image.Image, error) {
-	if !execdriver.IsBinaryAvailable(ctx, "ffmpeg") {
-		return nil, fmt.Errorf("%w: ffmpeg not found", driver.ErrIncompatible)
-	}
+	return c.CaptureWith(ctx, cameraapi.CaptureOptions{})
+}
+
+// stillOutput writes a single PNG frame to stdout.
+var stillOutput = []string{"-frames:v", "1", "-f", "image2pipe", "-vcodec", "png", "-"}
 
-	cmd := execdriver.MustRun(ctx, "ffmpeg",
+// streamOutput writes raw PPM frames to stdout, cheap to encode and parse.
+var streamOutput = []string{"-f", "image2pipe", "-vcodec", "ppm", "-pix_fmt", "rgb24", "-"}
+
+// Args builds the ffmpeg command line reading device with opts and
+// writing with the output arguments.
+func Args(device string, opts cameraapi.CaptureOptions, output ...string) []string {
+	args := []string{
 		"-hide_banner",
 		"-loglevel", "error",
 		"-nostdin",
 		"-f", "video4linux2",
-		"-i", c.device,
-		"-frames:v", "1",
-		"-f", "image2pipe",
-		"-vcodec", "png",
-		"-")
+	}
+	if opts.PixelFormat != "" {
+		args = append(args, "-input_format", opts.PixelFormat)
+	}
+	if opts.Width > 0 && opts.Height > 0 {
+		args = append(args, "-video_size", fmt.Sprintf("%dx%d", opts.Width, opts.Height))
+	}
+	if opts.FrameRate > 0 {
+		args = append(args, "-framerate", strconv.Itoa(opts.FrameRate))
+	}
+	args = append(args, "-i", device)
+	if opts.Warmup > 0 {
+		args = append(args, "-vf", fmt.Sprintf("select=gte(n\\,%d)", opts.Warmup), "-vsync", "0")
+	}
+	return append(args, output...)
+}
+
+func (c *cameraDevice) CaptureWith(ctx context.Context, opts cameraapi.CaptureOptions) (image.Image, error) {
+	if !execdriver.IsBinaryAvailable(ctx, "ffmpeg") {
+		return nil, fmt.Errorf("%w: ffmpeg not found", driver.ErrIncompatible)
+	}
+
+	cmd := execdriver.MustRun(ctx, "ffmpeg", Args(c.device, opts, stillOutput...)...)
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
-		msg := strings.TrimSpace(stderr.String())
-		if msg != "" {
-			return nil, fmt.Errorf("ffmpeg capture from %s failed: %w: %s", c.device, err, msg)
-		}
-		return nil, fmt.Errorf("ffmpeg capture from %s failed: %w", c.device, err)
+		return nil, c.ffmpegError("capture", err, &stderr)
 	}
 
 	img, _, err := image.Decode(bytes.NewReader(stdout.Bytes()))
@@ -118,6 +143,78 @@ func (c *cameraDevice) Capture(ctx context.Context) (image.Image, error) {
 	return img, nil
 }
 
+func (c *cameraDevice) Stream(ctx context.Context, opts cameraapi.CaptureOptions, fn func(image.Image) error) error {
+	if !execdriver.IsBinaryAvailable(ctx, "ffmpeg") {
+		return fmt.Errorf("%w: ffmpeg not found", driver.ErrIncompatible)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := execdriver.MustRun(ctx, "ffmpeg", Args(c.device, opts, streamOutput...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+
+	// Keep only the newest frame so a slow fn does not fall behind.
+	frames := make(chan image.Image, 1)
+	readErr := make(chan error, 1)
+	go func() {
+		r := bufio.NewReaderSize(stdout, 1<<16)
+		for {
+			img, err := readPPM(r)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case <-frames:
+			default:
+			}
+			frames <- img
+		}
+	}()
+
+	for {
+		select {
+		case img := <-frames:
+			if err := fn(img); err != nil {
+				cancel()
+				_ = cmd.Wait()
+				return err
+			}
+		case err := <-readErr:
+			waitErr := cmd.Wait()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if waitErr != nil {
+				return c.ffmpegError("stream", waitErr, &stderr)
+			}
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("stream from %s ended", c.device)
+			}
+			return fmt.Errorf("read frame from %s: %w", c.device, err)
+		case <-ctx.Done():
+			_ = cmd.Wait()
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *cameraDevice) ffmpegError(what string, err error, stderr *bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if msg != "" {
+		return fmt.Errorf("ffmpeg %s from %s failed: %w: %s", what, c.device, err, msg)
+	}
+	return fmt.Errorf("ffmpeg %s from %s failed: %w", what, c.device, err)
+}
+
 func cameraName(device string) string {
 	base := filepath.Base(device)
 	sysfs := filepath.Join("/sys/class/video4linux", base, "name")
@@ -0,0 +1,84 @@
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image/color"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	cameraapi "github.com/lucasew/workspaced/pkg/driver/camera"
+)
+
+func TestArgs(t *testing.T) {
+	got := Args("/dev/video2", cameraapi.CaptureOptions{
+		Width:       1280,
+		Height:      720,
+		PixelFormat: "mjpeg",
+		FrameRate:   30,
+		Warmup:      5,
+	}, stillOutput...)
+	want := []string{
+		"-hide_banner", "-loglevel", "error", "-nostdin",
+		"-f", "video4linux2",
+		"-input_format", "mjpeg",
+		"-video_size", "1280x720",
+		"-framerate", "30",
+		"-i", "/dev/video2",
+		"-vf", `select=gte(n\,5)`, "-vsync", "0",
+		"-frames:v", "1", "-f", "image2pipe", "-vcodec", "png", "-",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("args:\n got %q\nwant %q", got, want)
+	}
+
+	// Zero options leave everything to the device; a lone width is ignored.
+	got = Args("/dev/video0", cameraapi.CaptureOptions{Width: 640}, streamOutput...)
+	if slices.Contains(got, "-video_size") || slices.Contains(got, "-vf") || slices.Contains(got, "-input_format") {
+		t.Fatalf("unexpected options in %q", got)
+	}
+}
+
+func TestReadPPMStream(t *testing.T) {
+	var buf bytes.Buffer
+	// Two frames back to back, the first with a comment in its header.
+	buf.WriteString("P6\n# ffmpeg\n2 1\n255\n")
+	buf.Write([]byte{255, 0, 0, 0, 0, 255})
+	buf.WriteString("P6 1 2 255 ")
+	buf.Write([]byte{10, 20, 30, 40, 50, 60})
+
+	r := bufio.NewReader(&buf)
+	first, err := readPPM(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Bounds().Dx() != 2 || first.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) || first.RGBAAt(1, 0) != (color.RGBA{0, 0, 255, 255}) {
+		t.Fatalf("first frame = %v", first.Pix)
+	}
+	second, err := readPPM(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Bounds().Dy() != 2 || second.RGBAAt(0, 1) != (color.RGBA{40, 50, 60, 255}) {
+		t.Fatalf("second frame = %v", second.Pix)
+	}
+	if _, err := readPPM(r); !errors.Is(err, io.EOF) {
+		t.Fatalf("err = %v, want io.EOF at the end of the stream", err)
+	}
+}
+
+func TestReadPPMRejects(t *testing.T) {
+	for _, in := range []string{
+		"P5\n1 1\n255\n\x00",
+		"P6\n1 1\n65535\n\x00\x00\x00\x00\x00\x00",
+		"P6\nx 1\n255\n",
+		"P6\n2 2\n255\n\x00\x00\x00",
+	} {
+		if _, err := readPPM(bufio.NewReader(strings.NewReader(in))); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
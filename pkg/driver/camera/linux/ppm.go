@@ -0,0 +1,94 @@
+package linux
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+)
+
+// readPPM reads one binary (P6) PPM frame with 8-bit samples, as ffmpeg's
+// ppm encoder writes them back to back on a pipe.
+func readPPM(r *bufio.Reader) (*image.RGBA, error) {
+	var magic [2]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if magic != [2]byte{'P', '6'} {
+		return nil, fmt.Errorf("not a binary PPM frame: %q", magic[:])
+	}
+	var header [3]int
+	for i := range header {
+		v, err := ppmNumber(r)
+		if err != nil {
+			return nil, fmt.Errorf("PPM header: %w", err)
+		}
+		header[i] = v
+	}
+	// A single whitespace byte separates the header from the samples.
+	if _, err := r.ReadByte(); err != nil {
+		return nil, fmt.Errorf("PPM header: %w", err)
+	}
+	w, h, maxval := header[0], header[1], header[2]
+	if w <= 0 || h <= 0 || w*h > 1<<26 {
+		return nil, fmt.Errorf("PPM frame size %dx%d", w, h)
+	}
+	if maxval <= 0 || maxval > 255 {
+		return nil, fmt.Errorf("PPM maxval %d is not 8-bit", maxval)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	row := make([]byte, 3*w)
+	for y := range h {
+		if _, err := io.ReadFull(r, row); err != nil {
+			return nil, fmt.Errorf("PPM samples: %w", io.ErrUnexpectedEOF)
+		}
+		pix := img.Pix[y*img.Stride:]
+		for x := range w {
+			for c := range 3 {
+				pix[4*x+c] = byte(int(row[3*x+c]) * 255 / maxval)
+			}
+			pix[4*x+3] = 0xff
+		}
+	}
+	return img, nil
+}
+
+// ppmNumber reads a decimal header field, skipping whitespace and
+// comments before it. The byte after the digits is left unread.
+func ppmNumber(r *bufio.Reader) (int, error) {
+	var b byte
+	var err error
+	for {
+		if b, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		if b == '#' {
+			if _, err := r.ReadString('\n'); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if !isSpace(b) {
+			break
+		}
+	}
+	v, digits := 0, 0
+	for ; b >= '0' && b <= '9'; digits++ {
+		v = v*10 + int(b-'0')
+		if v > 1<<20 {
+			return 0, fmt.Errorf("number too large")
+		}
+		if b, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+	if digits == 0 || !isSpace(b) {
+		return 0, fmt.Errorf("unexpected byte %q", b)
+	}
+	return v, r.UnreadByte()
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
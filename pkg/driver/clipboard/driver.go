@@ -8,4 +8,20 @@ import (
 type Driver interface {
 	WriteImage(ctx context.Context, img image.Image) error
 	WriteText(ctx context.Context, text string) error
+	// Write offers data under a single MIME type.
+	Write(ctx context.Context, mime string, data []byte) error
+
+	ReadText(ctx context.Context) (string, error)
+	ReadImage(ctx context.Context) (image.Image, error)
+	// Read returns the selection converted to mime.
+	Read(ctx context.Context, mime string) ([]byte, error)
+	// ListTypes returns the MIME types (and, on X11, other target atoms)
+	// the current selection is offered as.
+	ListTypes(ctx context.Context) ([]string, error)
+}
+
+// Watcher is implemented by drivers that are notified of selection changes.
+// Watch blocks, calling callback after every change, until ctx is done.
+type Watcher interface {
+	Watch(ctx context.Context, callback func()) error
 }
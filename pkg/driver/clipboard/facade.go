@@ -16,3 +16,28 @@ func WriteImage(ctx context.Context, img image.Image) error {
 func WriteText(ctx context.Context, text string) error {
 	return driver.With(ctx, func(d Driver) error { return d.WriteText(ctx, text) })
 }
+
+// Write offers data as mime on the clipboard using the available driver.
+func Write(ctx context.Context, mime string, data []byte) error {
+	return driver.With(ctx, func(d Driver) error { return d.Write(ctx, mime, data) })
+}
+
+// ReadText reads the clipboard as text using the available driver.
+func ReadText(ctx context.Context) (string, error) {
+	return driver.WithResult(ctx, func(d Driver) (string, error) { return d.ReadText(ctx) })
+}
+
+// ReadImage reads and decodes the clipboard image using the available driver.
+func ReadImage(ctx context.Context) (image.Image, error) {
+	return driver.WithResult(ctx, func(d Driver) (image.Image, error) { return d.ReadImage(ctx) })
+}
+
+// Read reads the clipboard as mime using the available driver.
+func Read(ctx context.Context, mime string) ([]byte, error) {
+	return driver.WithResult(ctx, func(d Driver) ([]byte, error) { return d.Read(ctx, mime) })
+}
+
+// ListTypes lists the clipboard's types using the available driver.
+func ListTypes(ctx context.Context) ([]string, error) {
+	return driver.WithResult(ctx, func(d Driver) ([]string, error) { return d.ListTypes(ctx) })
+}
@@ -0,0 +1,148 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// SensitiveTypes are the hints password managers offer alongside a secret
+// so clipboard managers leave it alone (KeePassXC, KDE, macOS convention).
+var SensitiveTypes = []string{
+	"x-kde-passwordManagerHint",
+	"application/x-nspasteboard-concealed-type",
+	"org.nspasteboard.ConcealedType",
+}
+
+// IsSensitive reports whether types carries a password-manager hint.
+func IsSensitive(types []string) bool {
+	for _, t := range types {
+		for _, s := range SensitiveTypes {
+			if strings.EqualFold(t, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Entry is one clipboard snapshot.
+type Entry struct {
+	MIME string
+	Data []byte
+}
+
+// Hash identifies an entry's contents for deduplication.
+func (e *Entry) Hash() string {
+	h := sha256.New()
+	h.Write([]byte(e.MIME))
+	h.Write([]byte{0})
+	h.Write(e.Data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Preview is a one-line summary of the entry for pickers.
+func (e *Entry) Preview(max int) string {
+	if !strings.HasPrefix(e.MIME, "text") && e.MIME != "UTF8_STRING" && e.MIME != "STRING" {
+		return fmt.Sprintf("[%s %s]", e.MIME, humanSize(len(e.Data)))
+	}
+	text := strings.Join(strings.Fields(string(e.Data)), " ")
+	if utf8.RuneCountInString(text) > max {
+		runes := []rune(text)
+		text = string(runes[:max-1]) + "…"
+	}
+	return text
+}
+
+func humanSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// ErrSensitive is returned by Snapshot for password-manager selections.
+var ErrSensitive = errors.New("clipboard holds a secret")
+
+// Snapshot reads the current selection for the history: its image when it
+// has one, else its text. It returns ErrSensitive for selections marked by
+// a password manager and (nil, nil) for an empty or whitespace selection.
+func Snapshot(ctx context.Context, d Driver) (*Entry, error) {
+	types, err := d.ListTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if IsSensitive(types) {
+		return nil, ErrSensitive
+	}
+	mime, ok := ImageType(types)
+	if !ok {
+		if mime, ok = TextType(types); !ok {
+			return nil, nil
+		}
+	}
+	data, err := d.Read(ctx, mime)
+	if err != nil {
+		if errors.Is(err, dapi.ErrNoTargetFound) {
+			return nil, nil // selection changed under us
+		}
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	return &Entry{MIME: mime, Data: data}, nil
+}
+
+// PollInterval is how often Watch samples drivers that are not Watchers.
+var PollInterval = 2 * time.Second
+
+// Watch calls callback with every new selection until ctx is done.
+// Sensitive and empty selections are skipped, as are repeats of the
+// previous one. Drivers without change notification are polled.
+func Watch(ctx context.Context, callback func(*Entry)) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	var last string
+	changed := func() {
+		e, err := Snapshot(ctx, d)
+		if err != nil && !errors.Is(err, ErrSensitive) {
+			logging.GetLogger(ctx).Debug("clipboard snapshot failed", "error", err)
+		}
+		if e == nil {
+			return
+		}
+		if h := e.Hash(); h != last {
+			last = h
+			callback(e)
+		}
+	}
+	if w, ok := d.(Watcher); ok {
+		return w.Watch(ctx, changed)
+	}
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		changed()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
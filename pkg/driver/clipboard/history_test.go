@@ -0,0 +1,132 @@
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"slices"
+	"testing"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// fakeDriver serves a fixed selection.
+type fakeDriver struct {
+	Driver
+	data map[string][]byte
+	// order is the type list, including hint-only types not in data.
+	order []string
+}
+
+func (f *fakeDriver) ListTypes(context.Context) ([]string, error) { return f.order, nil }
+
+func (f *fakeDriver) Read(_ context.Context, mime string) ([]byte, error) {
+	d, ok := f.data[mime]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", dapi.ErrNoTargetFound, mime)
+	}
+	return d, nil
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	for _, tc := range []struct {
+		name    string
+		d       *fakeDriver
+		want    *Entry
+		wantErr error
+	}{
+		{
+			name: "text",
+			d: &fakeDriver{
+				order: []string{"TEXT", "STRING", "UTF8_STRING", "text/plain"},
+				data:  map[string][]byte{"UTF8_STRING": []byte("héllo"), "STRING": []byte("h?llo")},
+			},
+			want: &Entry{MIME: "UTF8_STRING", Data: []byte("héllo")},
+		},
+		{
+			name: "image wins over text",
+			d: &fakeDriver{
+				order: []string{"text/html", "image/jpeg", "image/png"},
+				data:  map[string][]byte{"text/html": []byte("<img>"), "image/png": []byte("\x89PNG")},
+			},
+			want: &Entry{MIME: "image/png", Data: []byte("\x89PNG")},
+		},
+		{
+			name: "password manager",
+			d: &fakeDriver{
+				order: []string{"text/plain;charset=utf-8", "x-kde-passwordManagerHint"},
+				data:  map[string][]byte{"text/plain;charset=utf-8": []byte("hunter2")},
+			},
+			wantErr: ErrSensitive,
+		},
+		{
+			name: "whitespace",
+			d:    &fakeDriver{order: []string{"text/plain"}, data: map[string][]byte{"text/plain": []byte(" \n")}},
+		},
+		{
+			name: "vanished",
+			d:    &fakeDriver{order: []string{"text/plain"}},
+		},
+		{
+			name: "empty",
+			d:    &fakeDriver{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Snapshot(ctx, tc.d)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if (got == nil) != (tc.want == nil) || got != nil && (got.MIME != tc.want.MIME || string(got.Data) != string(tc.want.Data)) {
+				t.Fatalf("entry = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTypes(t *testing.T) {
+	t.Parallel()
+	got := ParseTypes([]byte("TIMESTAMP\nTARGETS\nMULTIPLE\nUTF8_STRING\ntext/plain\n\ntext/plain\nx-kde-passwordManagerHint\n"))
+	want := []string{"UTF8_STRING", "text/plain", "x-kde-passwordManagerHint"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ParseTypes = %q, want %q", got, want)
+	}
+	if !IsSensitive(got) {
+		t.Fatal("password manager hint not detected")
+	}
+}
+
+func TestEntryPreview(t *testing.T) {
+	t.Parallel()
+	text := &Entry{MIME: "text/plain", Data: []byte("  line one\n\tline   two  ")}
+	if got := text.Preview(80); got != "line one line two" {
+		t.Errorf("preview = %q", got)
+	}
+	if got := text.Preview(6); got != "line …" {
+		t.Errorf("truncated preview = %q", got)
+	}
+	img := &Entry{MIME: "image/png", Data: make([]byte, 3<<10)}
+	if got := img.Preview(80); got != "[image/png 3.0 KiB]" {
+		t.Errorf("image preview = %q", got)
+	}
+	if text.Hash() == (&Entry{MIME: "text/html", Data: text.Data}).Hash() {
+		t.Error("hash ignores the MIME type")
+	}
+}
+
+func TestReadImageVia(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	_, err := ReadImageVia(ctx, &fakeDriver{order: []string{"text/plain"}})
+	if !errors.Is(err, dapi.ErrNoTargetFound) {
+		t.Fatalf("no image: err = %v", err)
+	}
+	_, err = ReadImageVia(ctx, &fakeDriver{order: []string{"image/png"}, data: map[string][]byte{"image/png": []byte("nope")}})
+	if !errors.Is(err, image.ErrFormat) {
+		t.Fatalf("corrupt image: err = %v", err)
+	}
+}
@@ -1,9 +1,12 @@
 package clipboard
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"image"
+	_ "image/gif"
+	_ "image/jpeg"
 	"image/png"
 	"io"
 	"strings"
@@ -32,10 +35,101 @@ func WriteImageViaCmd(ctx context.Context, img image.Image, name string, args ..
 
 // WriteTextViaCmd feeds text to name with args on stdin.
 func WriteTextViaCmd(ctx context.Context, text, name string, args ...string) error {
+	return WriteViaCmd(ctx, []byte(text), name, args...)
+}
+
+// WriteViaCmd feeds data to name with args on stdin.
+func WriteViaCmd(ctx context.Context, data []byte, name string, args ...string) error {
 	if !execdriver.IsBinaryAvailable(ctx, name) {
 		return fmt.Errorf("%w: %s", dapi.ErrBinaryNotFound, name)
 	}
 	cmd := execdriver.MustRun(ctx, name, args...)
-	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdin = bytes.NewReader(data)
 	return cmd.Run()
 }
+
+// ReadViaCmd returns the stdout of name with args.
+func ReadViaCmd(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if !execdriver.IsBinaryAvailable(ctx, name) {
+		return nil, fmt.Errorf("%w: %s", dapi.ErrBinaryNotFound, name)
+	}
+	return execdriver.MustRun(ctx, name, args...).Output()
+}
+
+// x11MetaTargets are X11 selection targets that describe the selection
+// rather than carry a format.
+var x11MetaTargets = map[string]bool{
+	"TARGETS":      true,
+	"TIMESTAMP":    true,
+	"MULTIPLE":     true,
+	"SAVE_TARGETS": true,
+	"DELETE":       true,
+}
+
+// ParseTypes splits a one-type-per-line listing, dropping blanks,
+// duplicates and X11 meta targets.
+func ParseTypes(out []byte) []string {
+	var types []string
+	seen := map[string]bool{}
+	for line := range strings.Lines(string(out)) {
+		t := strings.TrimSpace(line)
+		if t == "" || seen[t] || x11MetaTargets[t] {
+			continue
+		}
+		seen[t] = true
+		types = append(types, t)
+	}
+	return types
+}
+
+// ImageType returns the first image/* type of types; PNG is preferred.
+func ImageType(types []string) (string, bool) {
+	var first string
+	for _, t := range types {
+		if t == "image/png" {
+			return t, true
+		}
+		if first == "" && strings.HasPrefix(t, "image/") {
+			first = t
+		}
+	}
+	return first, first != ""
+}
+
+// TextType returns the best text type of types, preferring UTF-8.
+func TextType(types []string) (string, bool) {
+	for _, want := range []string{"text/plain;charset=utf-8", "UTF8_STRING", "text/plain"} {
+		for _, t := range types {
+			if strings.EqualFold(t, want) {
+				return t, true
+			}
+		}
+	}
+	for _, t := range types {
+		if strings.HasPrefix(t, "text/") || t == "STRING" || t == "TEXT" {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// ReadImageVia decodes the selection's image type using d.
+func ReadImageVia(ctx context.Context, d Driver) (image.Image, error) {
+	types, err := d.ListTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mime, ok := ImageType(types)
+	if !ok {
+		return nil, fmt.Errorf("%w: clipboard holds no image (types %v)", dapi.ErrNoTargetFound, types)
+	}
+	data, err := d.Read(ctx, mime)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", mime, err)
+	}
+	return img, nil
+}
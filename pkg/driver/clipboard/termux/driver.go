@@ -32,6 +32,8 @@ func (f *Factory) New(ctx context.Context) (clipboard.Driver, error) {
 	return &Driver{}, nil
 }
 
+// Driver only knows plain text: that is all the Android clipboard API
+// exposed by termux-api offers.
 type Driver struct{}
 
 func (d *Driver) WriteImage(ctx context.Context, img image.Image) error {
@@ -46,3 +48,42 @@ func (d *Driver) WriteText(ctx context.Context, text string) error {
 	cmd.Stdin = strings.NewReader(text)
 	return cmd.Run()
 }
+
+func (d *Driver) Write(ctx context.Context, mime string, data []byte) error {
+	if !isText(mime) {
+		return fmt.Errorf("%w: %s on Termux", dapi.ErrNotSupported, mime)
+	}
+	return d.WriteText(ctx, string(data))
+}
+
+func (d *Driver) ReadText(ctx context.Context) (string, error) {
+	if !execdriver.IsBinaryAvailable(ctx, "termux-clipboard-get") {
+		return "", fmt.Errorf("%w: termux-clipboard-get (install termux-api)", dapi.ErrBinaryNotFound)
+	}
+	out, err := execdriver.MustRun(ctx, "termux-clipboard-get").Output()
+	return string(out), err
+}
+
+func (d *Driver) ReadImage(ctx context.Context) (image.Image, error) {
+	return nil, fmt.Errorf("%w: reading images from clipboard is not supported on Termux", dapi.ErrNotSupported)
+}
+
+func (d *Driver) Read(ctx context.Context, mime string) ([]byte, error) {
+	if !isText(mime) {
+		return nil, fmt.Errorf("%w: %s on Termux", dapi.ErrNotSupported, mime)
+	}
+	text, err := d.ReadText(ctx)
+	return []byte(text), err
+}
+
+func (d *Driver) ListTypes(ctx context.Context) ([]string, error) {
+	text, err := d.ReadText(ctx)
+	if err != nil || text == "" {
+		return nil, err
+	}
+	return []string{"text/plain;charset=utf-8"}, nil
+}
+
+func isText(mime string) bool {
+	return mime == "text" || strings.HasPrefix(mime, "text/")
+}
@@ -1,9 +1,13 @@
 package wlcopy
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
 	"image"
 
+	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 	"github.com/lucasew/workspaced/pkg/driver/clipboard"
 	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
@@ -35,3 +39,61 @@ func (d *Driver) WriteImage(ctx context.Context, img image.Image) error {
 func (d *Driver) WriteText(ctx context.Context, text string) error {
 	return clipboard.WriteTextViaCmd(ctx, text, "wl-copy")
 }
+
+func (d *Driver) Write(ctx context.Context, mime string, data []byte) error {
+	return clipboard.WriteViaCmd(ctx, data, "wl-copy", "-t", mime)
+}
+
+func (d *Driver) ReadText(ctx context.Context) (string, error) {
+	out, err := d.Read(ctx, "text")
+	return string(out), err
+}
+
+func (d *Driver) ReadImage(ctx context.Context) (image.Image, error) {
+	return clipboard.ReadImageVia(ctx, d)
+}
+
+// Read fails with ErrNoTargetFound when the clipboard is empty or has no
+// matching type; wl-paste reports both with exit status 1.
+func (d *Driver) Read(ctx context.Context, mime string) ([]byte, error) {
+	out, err := clipboard.ReadViaCmd(ctx, "wl-paste", "--no-newline", "--type", mime)
+	if err != nil && !errors.Is(err, dapi.ErrBinaryNotFound) {
+		return nil, fmt.Errorf("%w: %s: %w", dapi.ErrNoTargetFound, mime, err)
+	}
+	return out, err
+}
+
+func (d *Driver) ListTypes(ctx context.Context) ([]string, error) {
+	out, err := clipboard.ReadViaCmd(ctx, "wl-paste", "--list-types")
+	if err != nil {
+		if errors.Is(err, dapi.ErrBinaryNotFound) {
+			return nil, err
+		}
+		return nil, nil // empty clipboard
+	}
+	return clipboard.ParseTypes(out), nil
+}
+
+// Watch runs `wl-paste --watch echo`, which prints a line on every
+// selection change (and once at startup).
+func (d *Driver) Watch(ctx context.Context, callback func()) error {
+	if err := execdriver.RequireBinary(ctx, "wl-paste"); err != nil {
+		return err
+	}
+	cmd := execdriver.MustRun(ctx, "wl-paste", "--watch", "echo")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		callback()
+	}
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("wl-paste --watch: %w", err)
+	}
+	return nil
+}
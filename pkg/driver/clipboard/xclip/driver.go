@@ -2,10 +2,14 @@ package xclip
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"image"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 	"github.com/lucasew/workspaced/pkg/driver/clipboard"
 	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
-	"image"
 )
 
 func init() {
@@ -35,3 +39,37 @@ func (d *Driver) WriteImage(ctx context.Context, img image.Image) error {
 func (d *Driver) WriteText(ctx context.Context, text string) error {
 	return clipboard.WriteTextViaCmd(ctx, text, "xclip", "-selection", "clipboard")
 }
+
+func (d *Driver) Write(ctx context.Context, mime string, data []byte) error {
+	return clipboard.WriteViaCmd(ctx, data, "xclip", "-selection", "clipboard", "-t", mime)
+}
+
+func (d *Driver) ReadText(ctx context.Context) (string, error) {
+	out, err := d.Read(ctx, "UTF8_STRING")
+	return string(out), err
+}
+
+func (d *Driver) ReadImage(ctx context.Context) (image.Image, error) {
+	return clipboard.ReadImageVia(ctx, d)
+}
+
+// Read fails with ErrNoTargetFound when the selection has no owner or
+// cannot be converted to mime.
+func (d *Driver) Read(ctx context.Context, mime string) ([]byte, error) {
+	out, err := clipboard.ReadViaCmd(ctx, "xclip", "-selection", "clipboard", "-o", "-t", mime)
+	if err != nil && !errors.Is(err, dapi.ErrBinaryNotFound) {
+		return nil, fmt.Errorf("%w: %s: %w", dapi.ErrNoTargetFound, mime, err)
+	}
+	return out, err
+}
+
+func (d *Driver) ListTypes(ctx context.Context) ([]string, error) {
+	out, err := clipboard.ReadViaCmd(ctx, "xclip", "-selection", "clipboard", "-o", "-t", "TARGETS")
+	if err != nil {
+		if errors.Is(err, dapi.ErrBinaryNotFound) {
+			return nil, err
+		}
+		return nil, nil // no selection owner
+	}
+	return clipboard.ParseTypes(out), nil
+}
@@ -0,0 +1,30 @@
+// Package idle reports when the user stops and resumes interacting with
+// the session.
+package idle
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+)
+
+// Event is an idle state change. Since is when input stopped, which can be
+// earlier than when the event is delivered.
+type Event struct {
+	Idle  bool
+	Since time.Time
+}
+
+// Driver watches the session for idleness. Watch blocks until ctx is done,
+// calling callback on every change, and reports the current state first.
+type Driver interface {
+	Watch(ctx context.Context, callback func(Event)) error
+}
+
+// Watch runs the selected driver.
+func Watch(ctx context.Context, callback func(Event)) error {
+	return driver.With(ctx, func(d Driver) error {
+		return d.Watch(ctx, callback)
+	})
+}
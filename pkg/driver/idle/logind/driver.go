@@ -0,0 +1,102 @@
+package logind
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/idle"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	login1       = "org.freedesktop.login1"
+	sessionIface = "org.freedesktop.login1.Session"
+	// "auto" is the caller's session, or the user's display session when
+	// the caller runs outside one (a systemd user service).
+	sessionPath = dbus.ObjectPath("/org/freedesktop/login1/session/auto")
+	// PollInterval is how often the idle hint is read.
+	PollInterval = 5 * time.Second
+)
+
+func init() {
+	driver.Register[idle.Driver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "idle_logind" }
+func (f *Factory) Name() string { return "logind IdleHint" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return fmt.Errorf("%w: connect to system bus: %w", driver.ErrIncompatible, err)
+	}
+	if _, err := newDriver(conn, sessionPath).state(); err != nil {
+		return fmt.Errorf("%w: %w", driver.ErrIncompatible, err)
+	}
+	return nil
+}
+
+func (f *Factory) New(ctx context.Context) (idle.Driver, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	return newDriver(conn, sessionPath), nil
+}
+
+// Driver polls the IdleHint logind keeps for the session. Something in the
+// session has to set it: GNOME and KDE do, and so does `swayidle idlehint`.
+type Driver struct {
+	session  dbus.BusObject
+	interval time.Duration
+}
+
+func newDriver(conn *dbus.Conn, path dbus.ObjectPath) *Driver {
+	return &Driver{session: conn.Object(login1, path), interval: PollInterval}
+}
+
+func (d *Driver) state() (idle.Event, error) {
+	hint, err := d.session.GetProperty(sessionIface + ".IdleHint")
+	if err != nil {
+		return idle.Event{}, fmt.Errorf("read IdleHint: %w", err)
+	}
+	isIdle, _ := hint.Value().(bool)
+	if !isIdle {
+		return idle.Event{}, nil
+	}
+	ev := idle.Event{Idle: true, Since: time.Now()}
+	// Microseconds since the epoch.
+	if since, err := d.session.GetProperty(sessionIface + ".IdleSinceHint"); err == nil {
+		if us, ok := since.Value().(uint64); ok && us > 0 {
+			ev.Since = time.UnixMicro(int64(us))
+		}
+	}
+	return ev, nil
+}
+
+func (d *Driver) Watch(ctx context.Context, callback func(idle.Event)) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	var last *idle.Event
+	for {
+		ev, err := d.state()
+		if err != nil {
+			return err
+		}
+		if last == nil || last.Idle != ev.Idle {
+			callback(ev)
+			last = &ev
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
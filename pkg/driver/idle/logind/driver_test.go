@@ -0,0 +1,83 @@
+package logind
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/lucasew/workspaced/pkg/driver/dbustest"
+	"github.com/lucasew/workspaced/pkg/driver/idle"
+)
+
+// fakeSession serves the two idle properties of a logind session.
+type fakeSession struct {
+	mu    sync.Mutex
+	idle  bool
+	since uint64
+}
+
+func (f *fakeSession) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch name {
+	case "IdleHint":
+		return dbus.MakeVariant(f.idle), nil
+	case "IdleSinceHint":
+		return dbus.MakeVariant(f.since), nil
+	}
+	return dbus.Variant{}, dbus.MakeFailedError(dbus.ErrMsgNoObject)
+}
+
+func (f *fakeSession) set(idle bool, since time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.idle = idle
+	f.since = uint64(since.UnixMicro())
+}
+
+func TestWatch(t *testing.T) {
+	addr := dbustest.Bus(t)
+	server := dbustest.Connect(t, addr)
+	session := &fakeSession{}
+	path := dbus.ObjectPath("/org/freedesktop/login1/session/test")
+	if err := server.Export(session, path, "org.freedesktop.DBus.Properties"); err != nil {
+		t.Fatal(err)
+	}
+	if reply, err := server.RequestName(login1, 0); err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("request name: %v %v", reply, err)
+	}
+
+	d := newDriver(dbustest.Connect(t, addr), path)
+	d.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan idle.Event, 8)
+	go func() { _ = d.Watch(ctx, func(ev idle.Event) { events <- ev }) }()
+
+	next := func() idle.Event {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an event")
+			return idle.Event{}
+		}
+	}
+
+	if ev := next(); ev.Idle {
+		t.Fatalf("first event = %+v, want active", ev)
+	}
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	session.set(true, since)
+	if ev := next(); !ev.Idle || !ev.Since.Equal(since) {
+		t.Fatalf("event = %+v, want idle since %v", ev, since)
+	}
+	session.set(false, time.Time{})
+	if ev := next(); ev.Idle {
+		t.Fatalf("event = %+v, want active", ev)
+	}
+}
@@ -0,0 +1,88 @@
+package swayidle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/driver/idle"
+)
+
+// Threshold is the inactivity swayidle waits for before reporting idle.
+// The policy steps are timed from when input stopped, so it only delays
+// detection and should stay below the shortest step.
+const Threshold = 10 * time.Second
+
+func init() {
+	driver.Register[idle.Driver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "idle_ext_idle_notify" }
+func (f *Factory) Name() string { return "ext-idle-notify (swayidle)" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	return execdriver.RequireEnvBinary(ctx, "WAYLAND_DISPLAY", "swayidle")
+}
+
+func (f *Factory) New(ctx context.Context) (idle.Driver, error) {
+	return &Driver{}, nil
+}
+
+// Driver runs swayidle, which speaks ext-idle-notify-v1 to the compositor,
+// and reads the state changes it echoes.
+type Driver struct{}
+
+// Args builds the swayidle arguments for a threshold. swayidle already
+// honors inhibitors set through the idle-inhibit protocol (video players,
+// fullscreen browsers) by holding the timeout back.
+func Args(threshold time.Duration) []string {
+	secs := strconv.Itoa(max(1, int(threshold.Seconds())))
+	return []string{"timeout", secs, "echo idle", "resume", "echo active"}
+}
+
+// ParseLine turns a line of swayidle output into an event. The idle
+// threshold has already passed when the line is written, so input stopped
+// that long before now.
+func ParseLine(line string, now time.Time, threshold time.Duration) (idle.Event, bool) {
+	switch strings.TrimSpace(line) {
+	case "idle":
+		return idle.Event{Idle: true, Since: now.Add(-threshold)}, true
+	case "active":
+		return idle.Event{}, true
+	}
+	return idle.Event{}, false
+}
+
+func (d *Driver) Watch(ctx context.Context, callback func(idle.Event)) error {
+	cmd := execdriver.MustRun(ctx, "swayidle", Args(Threshold)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start swayidle: %w", err)
+	}
+
+	callback(idle.Event{})
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if ev, ok := ParseLine(scanner.Text(), time.Now(), Threshold); ok {
+			callback(ev)
+		}
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("swayidle: %w", err)
+	}
+	return fmt.Errorf("swayidle exited")
+}
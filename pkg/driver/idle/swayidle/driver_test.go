@@ -0,0 +1,32 @@
+package swayidle
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestArgs(t *testing.T) {
+	want := []string{"timeout", "10", "echo idle", "resume", "echo active"}
+	if got := Args(10 * time.Second); !slices.Equal(got, want) {
+		t.Errorf("Args(10s) = %q", got)
+	}
+	if got := Args(0); got[1] != "1" {
+		t.Errorf("Args(0) timeout = %q, want 1", got[1])
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ev, ok := ParseLine("idle\n", now, 10*time.Second)
+	if !ok || !ev.Idle || !ev.Since.Equal(now.Add(-10*time.Second)) {
+		t.Errorf("idle = %+v, %v", ev, ok)
+	}
+	ev, ok = ParseLine("active", now, 10*time.Second)
+	if !ok || ev.Idle {
+		t.Errorf("active = %+v, %v", ev, ok)
+	}
+	if _, ok := ParseLine("something else", now, 10*time.Second); ok {
+		t.Error("unexpected event for unknown line")
+	}
+}
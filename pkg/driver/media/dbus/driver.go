@@ -1,15 +1,32 @@
 package dbus
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 	"github.com/lucasew/workspaced/pkg/driver/media"
-	"strings"
+	"github.com/lucasew/workspaced/pkg/logging"
 
 	"github.com/godbus/dbus/v5"
 )
 
+const (
+	mprisPrefix = "org.mpris.MediaPlayer2."
+	mprisPath   = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	rootIface   = "org.mpris.MediaPlayer2"
+	playerIface = "org.mpris.MediaPlayer2.Player"
+	propsIface  = "org.freedesktop.DBus.Properties"
+	// playerctld proxies the other players, listing it would duplicate them.
+	playerctld = "playerctld"
+)
+
 func init() {
 	driver.Register[media.Driver](&Factory{})
 }
@@ -31,7 +48,7 @@ func (f *Factory) CheckCompatibility(ctx context.Context) error {
 	}
 
 	for _, name := range names {
-		if strings.HasPrefix(name, "org.mpris.MediaPlayer2.") {
+		if strings.HasPrefix(name, mprisPrefix) {
 			return nil
 		}
 	}
@@ -44,193 +61,422 @@ func (f *Factory) New(ctx context.Context) (media.Driver, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Driver{conn: conn}, nil
+	return newDriver(conn), nil
+}
+
+func newDriver(conn *dbus.Conn) *Driver {
+	return &Driver{conn: conn, recent: trackerFor(conn)}
 }
 
 type Driver struct {
-	conn *dbus.Conn
+	conn   *dbus.Conn
+	recent *tracker
 }
 
-func (d *Driver) getBestPlayer(ctx context.Context) (dbus.BusObject, string, error) {
-	var names []string
-	err := d.conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names)
-	if err != nil {
-		return nil, "", err
-	}
+// candidate is a player found on the bus.
+type candidate struct {
+	name   string // without mprisPrefix
+	owner  string // unique bus name
+	status media.PlaybackStatus
+	active time.Time
+}
 
-	var players []string
-	for _, name := range names {
-		if strings.HasPrefix(name, "org.mpris.MediaPlayer2.") {
-			players = append(players, name)
+func (c candidate) object(conn *dbus.Conn) dbus.BusObject {
+	return conn.Object(mprisPrefix+c.name, mprisPath)
+}
+
+var statusPriority = map[media.PlaybackStatus]int{media.StatusPlaying: 3, media.StatusPaused: 2, media.StatusStopped: 1}
+
+// pick returns the index of the most recently active player, preferring
+// playing over paused over stopped ones.
+func pick(players []candidate) int {
+	best := -1
+	for i, p := range players {
+		if best < 0 {
+			best = i
+			continue
+		}
+		b := players[best]
+		if c := cmp.Compare(statusPriority[p.status], statusPriority[b.status]); c > 0 || (c == 0 && p.active.After(b.active)) {
+			best = i
 		}
 	}
+	return best
+}
 
-	if len(players) == 0 {
-		return nil, "", driver.ErrNotFound
+// matches reports whether target names the player, either fully or by the
+// part before the first dot ("firefox" for "firefox.instance_1_23").
+func matches(name, target string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	return name == target || base == target
+}
+
+// tracker records when each player last emitted a property change, which
+// drives the "most recently active" choice. driver.Get builds a Driver per
+// call, so the history lives here, one tracker per connection.
+type tracker struct {
+	mu     sync.Mutex
+	active map[string]time.Time // unique bus name -> last player signal
+}
+
+var (
+	trackersMu sync.Mutex
+	trackers   = map[*dbus.Conn]*tracker{}
+)
+
+// trackerFor returns the tracker of conn, subscribing on first use. It
+// stops with the connection, whose close also closes the signal channel.
+func trackerFor(conn *dbus.Conn) *tracker {
+	trackersMu.Lock()
+	defer trackersMu.Unlock()
+	if t, ok := trackers[conn]; ok {
+		return t
+	}
+	t := &tracker{active: make(map[string]time.Time)}
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(mprisPath),
+		dbus.WithMatchInterface(propsIface),
+		dbus.WithMatchMember("PropertiesChanged"),
 	}
+	if err := conn.AddMatchSignal(match...); err != nil {
+		// Not kept, so the next Driver tries again.
+		return t
+	}
+	c := make(chan *dbus.Signal, 16)
+	conn.Signal(c)
+	trackers[conn] = t
+	go func() {
+		for sig := range c {
+			if sig.Path != mprisPath || sig.Name != propsIface+".PropertiesChanged" {
+				continue
+			}
+			t.mu.Lock()
+			t.active[sig.Sender] = time.Now()
+			t.mu.Unlock()
+		}
+		trackersMu.Lock()
+		delete(trackers, conn)
+		trackersMu.Unlock()
+	}()
+	return t
+}
 
-	type playerInfo struct {
-		name   string
-		status string
-		obj    dbus.BusObject
+func (t *tracker) last(owner string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active[owner]
+}
+
+func (d *Driver) candidates(ctx context.Context) ([]candidate, error) {
+	var names []string
+	if err := d.conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		return nil, err
 	}
+	slices.Sort(names)
 
-	var infos []playerInfo
-	for _, p := range players {
-		obj := d.conn.Object(p, "/org/mpris/MediaPlayer2")
-		statusVar, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.PlaybackStatus")
-		if err != nil {
+	var ret []candidate
+	for _, n := range names {
+		name, ok := strings.CutPrefix(n, mprisPrefix)
+		if !ok || name == playerctld {
 			continue
 		}
-		status, ok := statusVar.Value().(string)
-		if !ok {
+		c := candidate{name: name}
+		status, err := c.object(d.conn).GetProperty(playerIface + ".PlaybackStatus")
+		if err != nil {
 			continue
 		}
-		infos = append(infos, playerInfo{
-			name:   p,
-			status: status,
-			obj:    obj,
-		})
+		s, _ := status.Value().(string)
+		c.status = media.PlaybackStatus(s)
+		if err := d.conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetNameOwner", 0, n).Store(&c.owner); err == nil {
+			c.active = d.recent.last(c.owner)
+		}
+		ret = append(ret, c)
 	}
+	return ret, nil
+}
 
-	if len(infos) == 0 {
-		return nil, "", driver.ErrNotFound
+func (d *Driver) resolve(ctx context.Context, target string) (candidate, error) {
+	players, err := d.candidates(ctx)
+	if err != nil {
+		return candidate{}, err
 	}
+	if len(players) == 0 {
+		return candidate{}, driver.ErrNotFound
+	}
+	if target != "" {
+		players = slices.DeleteFunc(players, func(c candidate) bool { return !matches(c.name, target) })
+		if len(players) == 0 {
+			return candidate{}, fmt.Errorf("%w: no media player named %q", dapi.ErrNoTargetFound, target)
+		}
+	}
+	return players[pick(players)], nil
+}
 
-	var best *playerInfo
-	statusPriority := map[string]int{"Playing": 3, "Paused": 2, "Stopped": 1}
-
-	for i := range infos {
-		p := &infos[i]
-		if best == nil || statusPriority[p.status] > statusPriority[best.status] {
-			best = p
+func (d *Driver) ListPlayers(ctx context.Context) ([]media.Player, error) {
+	players, err := d.candidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selected := pick(players)
+	ret := make([]media.Player, len(players))
+	for i, c := range players {
+		ret[i] = media.Player{Name: c.name, Status: c.status, Selected: i == selected}
+		if v, err := c.object(d.conn).GetProperty(rootIface + ".Identity"); err == nil {
+			ret[i].Identity, _ = v.Value().(string)
 		}
 	}
+	return ret, nil
+}
 
-	return best.obj, best.name, nil
+func (d *Driver) call(ctx context.Context, player, method string, args ...any) error {
+	c, err := d.resolve(ctx, player)
+	if err != nil {
+		return err
+	}
+	return c.object(d.conn).CallWithContext(ctx, playerIface+"."+method, 0, args...).Err
 }
 
-func (d *Driver) callAction(ctx context.Context, action string) error {
-	obj, _, err := d.getBestPlayer(ctx)
+func (d *Driver) set(ctx context.Context, player, property string, value any) error {
+	c, err := d.resolve(ctx, player)
 	if err != nil {
 		return err
 	}
-	return obj.Call("org.mpris.MediaPlayer2.Player."+action, 0).Err
+	return c.object(d.conn).CallWithContext(ctx, propsIface+".Set", 0, playerIface, property, dbus.MakeVariant(value)).Err
+}
+
+func (d *Driver) Next(ctx context.Context, player string) error {
+	return d.call(ctx, player, "Next")
 }
 
-func (d *Driver) Next(ctx context.Context) error      { return d.callAction(ctx, "Next") }
-func (d *Driver) Previous(ctx context.Context) error  { return d.callAction(ctx, "Previous") }
-func (d *Driver) PlayPause(ctx context.Context) error { return d.callAction(ctx, "PlayPause") }
-func (d *Driver) Stop(ctx context.Context) error      { return d.callAction(ctx, "Stop") }
+func (d *Driver) Previous(ctx context.Context, player string) error {
+	return d.call(ctx, player, "Previous")
+}
+
+func (d *Driver) PlayPause(ctx context.Context, player string) error {
+	return d.call(ctx, player, "PlayPause")
+}
+
+func (d *Driver) Stop(ctx context.Context, player string) error {
+	return d.call(ctx, player, "Stop")
+}
+
+func (d *Driver) Seek(ctx context.Context, player string, offset time.Duration) error {
+	return d.call(ctx, player, "Seek", offset.Microseconds())
+}
 
-func (d *Driver) GetMetadata(ctx context.Context) (*media.Metadata, error) {
-	obj, name, err := d.getBestPlayer(ctx)
+func (d *Driver) SetPosition(ctx context.Context, player string, position time.Duration) error {
+	c, err := d.resolve(ctx, player)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	statusVar, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.PlaybackStatus")
+	meta, err := d.metadata(ctx, c)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	status, ok := statusVar.Value().(string)
-	if !ok {
-		return nil, fmt.Errorf("playback status: unexpected type %T", statusVar.Value())
+	if meta.TrackID == "" {
+		return fmt.Errorf("%w: %s does not report a track id", dapi.ErrNotSupported, c.name)
 	}
+	return c.object(d.conn).CallWithContext(ctx, playerIface+".SetPosition", 0, dbus.ObjectPath(meta.TrackID), position.Microseconds()).Err
+}
+
+func (d *Driver) SetVolume(ctx context.Context, player string, volume float64) error {
+	return d.set(ctx, player, "Volume", volume)
+}
+
+func (d *Driver) SetShuffle(ctx context.Context, player string, shuffle bool) error {
+	return d.set(ctx, player, "Shuffle", shuffle)
+}
+
+func (d *Driver) SetLoop(ctx context.Context, player string, loop media.LoopStatus) error {
+	return d.set(ctx, player, "LoopStatus", string(loop))
+}
 
-	metadataVar, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Metadata")
+func (d *Driver) GetMetadata(ctx context.Context, player string) (*media.Metadata, error) {
+	c, err := d.resolve(ctx, player)
 	if err != nil {
 		return nil, err
 	}
+	return d.metadata(ctx, c)
+}
 
-	m, ok := metadataVar.Value().(map[string]dbus.Variant)
-	if !ok {
-		return nil, fmt.Errorf("metadata: unexpected type %T", metadataVar.Value())
+func (d *Driver) metadata(ctx context.Context, c candidate) (*media.Metadata, error) {
+	var props map[string]dbus.Variant
+	if err := c.object(d.conn).CallWithContext(ctx, propsIface+".GetAll", 0, playerIface).Store(&props); err != nil {
+		return nil, err
 	}
-	res := &media.Metadata{
-		Player: name,
-		Status: media.PlaybackStatus(status),
+	return parseProperties(c.name, props)
+}
+
+// parseProperties builds Metadata from the properties of the MPRIS Player
+// interface.
+func parseProperties(name string, props map[string]dbus.Variant) (*media.Metadata, error) {
+	res := &media.Metadata{Player: name}
+	if v, ok := props["PlaybackStatus"]; ok {
+		status, ok := v.Value().(string)
+		if !ok {
+			return nil, fmt.Errorf("playback status: unexpected type %T", v.Value())
+		}
+		res.Status = media.PlaybackStatus(status)
+	}
+	if v, ok := props["Volume"]; ok {
+		res.Volume, _ = v.Value().(float64)
+	}
+	if v, ok := props["Shuffle"]; ok {
+		res.Shuffle, _ = v.Value().(bool)
+	}
+	if v, ok := props["LoopStatus"]; ok {
+		loop, _ := v.Value().(string)
+		res.Loop = media.LoopStatus(loop)
+	}
+	if v, ok := props["Position"]; ok {
+		res.Position = toInt64(v.Value())
 	}
 
+	v, ok := props["Metadata"]
+	if !ok {
+		return res, nil
+	}
+	m, ok := v.Value().(map[string]dbus.Variant)
+	if !ok {
+		return nil, fmt.Errorf("metadata: unexpected type %T", v.Value())
+	}
 	if v, ok := m["xesam:title"]; ok {
-		if title, ok := v.Value().(string); ok {
-			res.Title = title
-		}
+		res.Title, _ = v.Value().(string)
 	}
 	if v, ok := m["xesam:artist"]; ok {
-		switch val := v.Value().(type) {
-		case []string:
-			res.Artist = strings.Join(val, ", ")
-		case []any:
-			var artists []string
-			for _, a := range val {
-				if s, ok := a.(string); ok {
-					artists = append(artists, s)
-				}
-			}
-			res.Artist = strings.Join(artists, ", ")
-		case string:
-			res.Artist = val
-		}
+		res.Artist = joinStrings(v.Value())
+	}
+	if v, ok := m["xesam:album"]; ok {
+		res.Album, _ = v.Value().(string)
 	}
 	if v, ok := m["mpris:artUrl"]; ok {
-		if artUrl, ok := v.Value().(string); ok {
-			res.ArtUrl = artUrl
-		}
+		res.ArtUrl, _ = v.Value().(string)
 	}
 	if v, ok := m["mpris:length"]; ok {
-		switch val := v.Value().(type) {
-		case int64:
-			res.Length = val
-		case uint64:
-			res.Length = int64(val)
+		res.Length = toInt64(v.Value())
+	}
+	if v, ok := m["mpris:trackid"]; ok {
+		switch id := v.Value().(type) {
+		case dbus.ObjectPath:
+			res.TrackID = string(id)
+		case string:
+			res.TrackID = id
 		}
 	}
+	return res, nil
+}
 
-	posVar, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Position")
-	if err == nil {
-		switch val := posVar.Value().(type) {
-		case int64:
-			res.Position = val
-		case uint64:
-			res.Position = int64(val)
+func joinStrings(v any) string {
+	switch val := v.(type) {
+	case []string:
+		return strings.Join(val, ", ")
+	case []any:
+		var parts []string
+		for _, a := range val {
+			if s, ok := a.(string); ok {
+				parts = append(parts, s)
+			}
 		}
+		return strings.Join(parts, ", ")
+	case string:
+		return val
 	}
+	return ""
+}
 
-	return res, nil
+func toInt64(v any) int64 {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case uint64:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case uint32:
+		return int64(val)
+	}
+	return 0
 }
 
-func (d *Driver) Watch(ctx context.Context, callback func(*media.Metadata)) error {
-	rule := "type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged',path='/org/mpris/MediaPlayer2'"
-	if err := d.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, rule).Err; err != nil {
-		return err
+func (d *Driver) Watch(ctx context.Context, player string, callback func(*media.Metadata)) error {
+	for _, match := range [][]dbus.MatchOption{
+		{
+			dbus.WithMatchObjectPath(mprisPath),
+			dbus.WithMatchInterface(propsIface),
+			dbus.WithMatchMember("PropertiesChanged"),
+		},
+		{
+			dbus.WithMatchInterface("org.freedesktop.DBus"),
+			dbus.WithMatchMember("NameOwnerChanged"),
+			dbus.WithMatchArg0Namespace(strings.TrimSuffix(mprisPrefix, ".")),
+		},
+	} {
+		if err := d.conn.AddMatchSignal(match...); err != nil {
+			return err
+		}
+		defer func() {
+			if err := d.conn.RemoveMatchSignal(match...); err != nil {
+				logging.ReportError(ctx, err, "op", "remove media match rule")
+			}
+		}()
 	}
 
-	c := make(chan *dbus.Signal, 10)
+	c := make(chan *dbus.Signal, 16)
 	d.conn.Signal(c)
+	defer d.conn.RemoveSignal(c)
+
+	var last *media.Metadata
+	emit := func() {
+		meta, err := d.GetMetadata(ctx, player)
+		if err != nil {
+			meta = nil
+		}
+		if sameState(last, meta) {
+			return
+		}
+		last = meta
+		callback(meta)
+	}
+	emit()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case signal := <-c:
-			if len(signal.Body) < 2 {
-				continue
-			}
-			iface, ok := signal.Body[0].(string)
-			if !ok || iface != "org.mpris.MediaPlayer2.Player" {
-				continue
-			}
-			changed, ok := signal.Body[1].(map[string]dbus.Variant)
+		case sig, ok := <-c:
 			if !ok {
-				continue
+				return fmt.Errorf("%w: session bus connection closed", dapi.ErrIPC)
 			}
-			if _, ok := changed["Metadata"]; ok {
-				meta, err := d.GetMetadata(ctx)
-				if err == nil {
-					callback(meta)
+			switch sig.Name {
+			case propsIface + ".PropertiesChanged":
+				if sig.Path != mprisPath || len(sig.Body) < 1 {
+					continue
 				}
+				if iface, _ := sig.Body[0].(string); iface != playerIface {
+					continue
+				}
+			case "org.freedesktop.DBus.NameOwnerChanged":
+				if len(sig.Body) < 1 {
+					continue
+				}
+				if name, _ := sig.Body[0].(string); !strings.HasPrefix(name, mprisPrefix) {
+					continue
+				}
+			default:
+				continue
 			}
+			emit()
 		}
 	}
 }
+
+// sameState compares metadata ignoring Position, which moves on its own
+// and is not signalled.
+func sameState(a, b *media.Metadata) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	x, y := *a, *b
+	x.Position, y.Position = 0, 0
+	return x == y
+}
@@ -0,0 +1,374 @@
+package dbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/dbustest"
+	"github.com/lucasew/workspaced/pkg/driver/media"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// fakePlayer is a minimal MPRIS player on its own connection.
+type fakePlayer struct {
+	conn  *dbus.Conn
+	props *fakeProps
+	mu    sync.Mutex
+	calls []string
+	seek  int64
+	pos   [2]any
+}
+
+// fakeProps implements org.freedesktop.DBus.Properties. Values are replaced,
+// never mutated, so replies being encoded never race with changes.
+type fakeProps struct {
+	conn   *dbus.Conn
+	mu     sync.Mutex
+	values map[string]map[string]any
+}
+
+func (f *fakeProps) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.values[iface][name]
+	if !ok {
+		return dbus.Variant{}, dbus.MakeFailedError(errors.New("no such property"))
+	}
+	return dbus.MakeVariant(v), nil
+}
+
+func (f *fakeProps) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ret := make(map[string]dbus.Variant, len(f.values[iface]))
+	for k, v := range f.values[iface] {
+		ret[k] = dbus.MakeVariant(v)
+	}
+	return ret, nil
+}
+
+func (f *fakeProps) Set(iface, name string, v dbus.Variant) *dbus.Error {
+	if err := f.change(iface, name, v.Value()); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// change sets a property and emits PropertiesChanged.
+func (f *fakeProps) change(iface, name string, v any) error {
+	f.mu.Lock()
+	f.values[iface][name] = v
+	f.mu.Unlock()
+	return f.conn.Emit(mprisPath, propsIface+".PropertiesChanged", iface, map[string]dbus.Variant{name: dbus.MakeVariant(v)}, []string{})
+}
+
+func (p *fakePlayer) set(t *testing.T, name string, v any) {
+	t.Helper()
+	if err := p.props.change(playerIface, name, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (p *fakePlayer) record(name string) *dbus.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, name)
+	return nil
+}
+
+func (p *fakePlayer) Next() *dbus.Error      { return p.record("Next") }
+func (p *fakePlayer) Previous() *dbus.Error  { return p.record("Previous") }
+func (p *fakePlayer) PlayPause() *dbus.Error { return p.record("PlayPause") }
+func (p *fakePlayer) Stop() *dbus.Error      { return p.record("Stop") }
+
+// SeekBy is exported as Seek; the Go name would clash with io.Seeker.
+func (p *fakePlayer) SeekBy(offset int64) *dbus.Error {
+	p.mu.Lock()
+	p.seek = offset
+	p.mu.Unlock()
+	return p.record("Seek")
+}
+
+func (p *fakePlayer) SetPosition(track dbus.ObjectPath, position int64) *dbus.Error {
+	p.mu.Lock()
+	p.pos = [2]any{track, position}
+	p.mu.Unlock()
+	return p.record("SetPosition")
+}
+
+func (p *fakePlayer) lastCall() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.calls) == 0 {
+		return ""
+	}
+	return p.calls[len(p.calls)-1]
+}
+
+func track(id, title string) map[string]dbus.Variant {
+	return map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath(id)),
+		"xesam:title":   dbus.MakeVariant(title),
+		"xesam:artist":  dbus.MakeVariant([]string{"Artist", "Guest"}),
+		"mpris:length":  dbus.MakeVariant(int64(180_000_000)),
+	}
+}
+
+func startPlayer(t *testing.T, addr, name string, status media.PlaybackStatus) *fakePlayer {
+	t.Helper()
+	p := &fakePlayer{conn: dbustest.Connect(t, addr)}
+	if err := p.conn.ExportWithMap(p, map[string]string{"SeekBy": "Seek"}, mprisPath, playerIface); err != nil {
+		t.Fatal(err)
+	}
+	p.props = &fakeProps{conn: p.conn, values: map[string]map[string]any{
+		rootIface: {
+			"Identity": "Fake " + name,
+		},
+		playerIface: {
+			"PlaybackStatus": string(status),
+			"Metadata":       track("/track/1", name+" song"),
+			"Position":       int64(42_000_000),
+			"Volume":         1.0,
+			"Shuffle":        false,
+			"LoopStatus":     "None",
+		},
+	}}
+	if err := p.conn.Export(p.props, mprisPath, propsIface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := p.conn.RequestName(mprisPrefix+name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("request name %s: %v %v", name, reply, err)
+	}
+	return p
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func selected(t *testing.T, d *Driver) string {
+	t.Helper()
+	players, err := d.ListPlayers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range players {
+		if p.Selected {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+func TestTargeting(t *testing.T) {
+	addr := dbustest.Bus(t)
+	alpha := startPlayer(t, addr, "alpha", media.StatusPaused)
+	beta := startPlayer(t, addr, "beta.instance_2", media.StatusPlaying)
+	startPlayer(t, addr, playerctld, media.StatusPlaying)
+	d := newDriver(dbustest.Connect(t, addr))
+	ctx := context.Background()
+
+	players, err := d.ListPlayers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 2 || players[0].Name != "alpha" || players[0].Identity != "Fake alpha" || !players[1].Selected {
+		t.Fatalf("players = %+v", players)
+	}
+
+	if err := d.Next(ctx, ""); err != nil || beta.lastCall() != "Next" {
+		t.Errorf("Next on default player: err=%v beta=%q", err, beta.lastCall())
+	}
+	if err := d.Previous(ctx, "alpha"); err != nil || alpha.lastCall() != "Previous" {
+		t.Errorf("Previous on alpha: err=%v alpha=%q", err, alpha.lastCall())
+	}
+	if err := d.Stop(ctx, "beta"); err != nil || beta.lastCall() != "Stop" {
+		t.Errorf("Stop on beta by prefix: err=%v beta=%q", err, beta.lastCall())
+	}
+	if err := d.PlayPause(ctx, "gamma"); !errors.Is(err, dapi.ErrNoTargetFound) {
+		t.Errorf("unknown player error = %v", err)
+	}
+}
+
+func TestMostRecentlyActive(t *testing.T) {
+	addr := dbustest.Bus(t)
+	alpha := startPlayer(t, addr, "alpha", media.StatusPaused)
+	startPlayer(t, addr, "beta", media.StatusPaused)
+	d := newDriver(dbustest.Connect(t, addr))
+
+	if got := selected(t, d); got != "alpha" {
+		t.Fatalf("selected = %q before any activity", got)
+	}
+	// Equal status: the player that last changed wins over name order.
+	gamma := startPlayer(t, addr, "gamma", media.StatusPaused)
+	gamma.set(t, "Metadata", track("/track/2", "new"))
+	eventually(t, "gamma to be selected", func() bool { return selected(t, d) == "gamma" })
+
+	// A playing player still beats a more recent paused one.
+	alpha.set(t, "PlaybackStatus", string(media.StatusPlaying))
+	eventually(t, "alpha to be selected", func() bool { return selected(t, d) == "alpha" })
+}
+
+func TestRecencyAcrossGet(t *testing.T) {
+	addr := dbustest.Bus(t)
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", addr)
+	startPlayer(t, addr, "alpha", media.StatusPaused)
+	beta := startPlayer(t, addr, "beta", media.StatusPaused)
+	ctx := logging.NewWriterContext(t.Output())
+
+	// Each command gets a fresh Driver; the activity seen through the first
+	// must still pick the player for the second.
+	first, err := driver.Get[media.Driver](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta.set(t, "Metadata", track("/track/2", "new"))
+	eventually(t, "beta to be selected", func() bool { return selected(t, first.(*Driver)) == "beta" })
+
+	second, err := driver.Get[media.Driver](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := selected(t, second.(*Driver)); got != "beta" {
+		t.Fatalf("second driver selected %q, want beta", got)
+	}
+}
+
+func TestControls(t *testing.T) {
+	addr := dbustest.Bus(t)
+	p := startPlayer(t, addr, "alpha", media.StatusPlaying)
+	d := newDriver(dbustest.Connect(t, addr))
+	ctx := context.Background()
+
+	if err := d.Seek(ctx, "", -5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetPosition(ctx, "", 30*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	p.mu.Lock()
+	if p.seek != -5_000_000 {
+		t.Errorf("seek offset = %d", p.seek)
+	}
+	if p.pos != [2]any{dbus.ObjectPath("/track/1"), int64(30_000_000)} {
+		t.Errorf("set position = %v", p.pos)
+	}
+	p.mu.Unlock()
+
+	if err := d.SetVolume(ctx, "", 0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetShuffle(ctx, "", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetLoop(ctx, "", media.LoopPlaylist); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := d.GetMetadata(ctx, "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := media.Metadata{
+		Title:    "alpha song",
+		Artist:   "Artist, Guest",
+		TrackID:  "/track/1",
+		Length:   180_000_000,
+		Position: 42_000_000,
+		Status:   media.StatusPlaying,
+		Volume:   0.25,
+		Shuffle:  true,
+		Loop:     media.LoopPlaylist,
+		Player:   "alpha",
+	}
+	if *meta != want {
+		t.Errorf("metadata = %+v, want %+v", *meta, want)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	addr := dbustest.Bus(t)
+	p := startPlayer(t, addr, "alpha", media.StatusPlaying)
+	d := newDriver(dbustest.Connect(t, addr))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *media.Metadata, 16)
+	done := make(chan error, 1)
+	go func() { done <- d.Watch(ctx, "alpha", func(m *media.Metadata) { events <- m }) }()
+	next := func() *media.Metadata {
+		t.Helper()
+		select {
+		case m := <-events:
+			return m
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for watch event")
+		}
+		return nil
+	}
+
+	if m := next(); m == nil || m.Title != "alpha song" {
+		t.Fatalf("initial event = %+v", m)
+	}
+	p.set(t, "Metadata", track("/track/2", "second"))
+	if m := next(); m == nil || m.Title != "second" {
+		t.Fatalf("track change event = %+v", m)
+	}
+	p.set(t, "PlaybackStatus", string(media.StatusPaused))
+	if m := next(); m == nil || m.Status != media.StatusPaused {
+		t.Fatalf("status change event = %+v", m)
+	}
+	if _, err := p.conn.ReleaseName(mprisPrefix + "alpha"); err != nil {
+		t.Fatal(err)
+	}
+	if m := next(); m != nil {
+		t.Fatalf("event after player left = %+v, want nil", m)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch returned %v", err)
+	}
+}
+
+func TestWatchConnectionClosed(t *testing.T) {
+	addr := dbustest.Bus(t)
+	startPlayer(t, addr, "alpha", media.StatusPlaying)
+	conn := dbustest.Connect(t, addr)
+	d := newDriver(conn)
+
+	events := make(chan *media.Metadata, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Watch(logging.NewWriterContext(t.Output()), "alpha", func(m *media.Metadata) { events <- m })
+	}()
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the initial event")
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, dapi.ErrIPC) {
+			t.Fatalf("Watch returned %v, want ErrIPC", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after the connection closed")
+	}
+}
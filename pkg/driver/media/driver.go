@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type PlaybackStatus string
@@ -22,24 +23,62 @@ const (
 	StatusStopped PlaybackStatus = "Stopped"
 )
 
+// LoopStatus is the MPRIS repeat mode.
+type LoopStatus string
+
+const (
+	LoopNone     LoopStatus = "None"
+	LoopTrack    LoopStatus = "Track"
+	LoopPlaylist LoopStatus = "Playlist"
+)
+
 type Metadata struct {
-	Title    string
-	Artist   string
-	ArtUrl   string
-	Length   int64 // in microseconds
-	Position int64 // in microseconds
-	Status   PlaybackStatus
-	Player   string // player name/bus name
+	Title    string         `json:"title"`
+	Artist   string         `json:"artist"`
+	Album    string         `json:"album,omitempty"`
+	ArtUrl   string         `json:"art_url,omitempty"`
+	TrackID  string         `json:"track_id,omitempty"`
+	Length   int64          `json:"length"`   // in microseconds
+	Position int64          `json:"position"` // in microseconds
+	Status   PlaybackStatus `json:"status"`
+	Volume   float64        `json:"volume"`
+	Shuffle  bool           `json:"shuffle"`
+	Loop     LoopStatus     `json:"loop,omitempty"`
+	Player   string         `json:"player"` // player name/bus name
+}
+
+// Player is a media player that can be targeted by name.
+type Player struct {
+	// Name is the bus name without the MPRIS prefix, e.g. "spotify" or
+	// "firefox.instance_1_23".
+	Name     string         `json:"name"`
+	Identity string         `json:"identity,omitempty"`
+	Status   PlaybackStatus `json:"status"`
+	// Selected marks the player an empty target resolves to.
+	Selected bool `json:"selected"`
 }
 
+// Every player method takes a player target: "" picks the most recently
+// active player, anything else is a player name or the part of it before
+// the first dot.
 type Driver interface {
-	Next(ctx context.Context) error
-	Previous(ctx context.Context) error
-	PlayPause(ctx context.Context) error
-	Stop(ctx context.Context) error
-	GetMetadata(ctx context.Context) (*Metadata, error)
-	// Watch blocks and calls callback when metadata changes
-	Watch(ctx context.Context, callback func(*Metadata)) error
+	ListPlayers(ctx context.Context) ([]Player, error)
+	Next(ctx context.Context, player string) error
+	Previous(ctx context.Context, player string) error
+	PlayPause(ctx context.Context, player string) error
+	Stop(ctx context.Context, player string) error
+	GetMetadata(ctx context.Context, player string) (*Metadata, error)
+	// Seek moves the position by offset, which may be negative.
+	Seek(ctx context.Context, player string, offset time.Duration) error
+	SetPosition(ctx context.Context, player string, position time.Duration) error
+	// SetVolume sets the player volume, 1.0 being 100%.
+	SetVolume(ctx context.Context, player string, volume float64) error
+	SetShuffle(ctx context.Context, player string, shuffle bool) error
+	SetLoop(ctx context.Context, player string, loop LoopStatus) error
+	// Watch blocks and calls callback with the target's metadata whenever
+	// the track, status, volume, shuffle or loop change, or the target
+	// player itself changes. The callback gets nil when no player is left.
+	Watch(ctx context.Context, player string, callback func(*Metadata)) error
 }
 
 func GetArtCachePath(ctx context.Context, url string) (string, error) {
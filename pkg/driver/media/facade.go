@@ -9,7 +9,10 @@ import (
 	"time"
 )
 
-func RunAction(ctx context.Context, action string) error {
+// RunAction runs a playback action ("next", "previous", "play-pause",
+// "stop" or "show") on player ("" for the most recently active one) and
+// shows the resulting status.
+func RunAction(ctx context.Context, player, action string) error {
 	d, err := driver.Get[Driver](ctx)
 	if err != nil {
 		return err
@@ -17,13 +20,13 @@ func RunAction(ctx context.Context, action string) error {
 
 	switch action {
 	case "next":
-		err = d.Next(ctx)
+		err = d.Next(ctx, player)
 	case "previous":
-		err = d.Previous(ctx)
+		err = d.Previous(ctx, player)
 	case "play-pause":
-		err = d.PlayPause(ctx)
+		err = d.PlayPause(ctx, player)
 	case "stop":
-		err = d.Stop(ctx)
+		err = d.Stop(ctx, player)
 	case "show":
 		// just show
 	default:
@@ -39,23 +42,67 @@ func RunAction(ctx context.Context, action string) error {
 		time.Sleep(200 * time.Millisecond)
 	}
 
-	return ShowStatus(ctx)
+	return ShowPlayerStatus(ctx, player)
 }
 
+// ShowStatus notifies the metadata of the most recently active player.
 func ShowStatus(ctx context.Context) error {
-	d, err := driver.Get[Driver](ctx)
-	if err != nil {
-		return err
-	}
+	return ShowPlayerStatus(ctx, "")
+}
 
-	meta, err := d.GetMetadata(ctx)
+func ShowPlayerStatus(ctx context.Context, player string) error {
+	meta, err := GetMetadata(ctx, player)
 	if err != nil {
 		return err
 	}
-
 	return Notify(ctx, meta)
 }
 
+func GetMetadata(ctx context.Context, player string) (*Metadata, error) {
+	return driver.WithResult(ctx, func(d Driver) (*Metadata, error) { return d.GetMetadata(ctx, player) })
+}
+
+func ListPlayers(ctx context.Context) ([]Player, error) {
+	return driver.WithResult(ctx, func(d Driver) ([]Player, error) { return d.ListPlayers(ctx) })
+}
+
+func Seek(ctx context.Context, player string, offset time.Duration) error {
+	return driver.With(ctx, func(d Driver) error { return d.Seek(ctx, player, offset) })
+}
+
+func SetPosition(ctx context.Context, player string, position time.Duration) error {
+	return driver.With(ctx, func(d Driver) error { return d.SetPosition(ctx, player, position) })
+}
+
+func SetVolume(ctx context.Context, player string, volume float64) error {
+	return driver.With(ctx, func(d Driver) error { return d.SetVolume(ctx, player, driver.Clamp01(volume)) })
+}
+
+func SetShuffle(ctx context.Context, player string, shuffle bool) error {
+	return driver.With(ctx, func(d Driver) error { return d.SetShuffle(ctx, player, shuffle) })
+}
+
+// ToggleShuffle flips shuffle and returns the new state.
+func ToggleShuffle(ctx context.Context, player string) (bool, error) {
+	return driver.WithResult(ctx, func(d Driver) (bool, error) {
+		meta, err := d.GetMetadata(ctx, player)
+		if err != nil {
+			return false, err
+		}
+		return !meta.Shuffle, d.SetShuffle(ctx, player, !meta.Shuffle)
+	})
+}
+
+func SetLoop(ctx context.Context, player string, loop LoopStatus) error {
+	return driver.With(ctx, func(d Driver) error { return d.SetLoop(ctx, player, loop) })
+}
+
+// WatchMetadata blocks and calls callback on every change of player, see
+// Driver.Watch.
+func WatchMetadata(ctx context.Context, player string, callback func(*Metadata)) error {
+	return driver.With(ctx, func(d Driver) error { return d.Watch(ctx, player, callback) })
+}
+
 func Notify(ctx context.Context, meta *Metadata) error {
 	if meta == nil || meta.Title == "" {
 		logger := logging.GetLogger(ctx)
@@ -107,20 +154,28 @@ func Notify(ctx context.Context, meta *Metadata) error {
 	return notification.Notify(ctx, &n)
 }
 
+// Watch notifies track changes of the most recently active player until
+// ctx is done.
 func Watch(ctx context.Context) {
-	d, err := driver.Get[Driver](ctx)
-	if err != nil {
-		logger := logging.GetLogger(ctx)
-		logger.Error("failed to get media driver for watch", "error", err)
-		return
-	}
-
-	err = d.Watch(ctx, func(meta *Metadata) {
+	var lastTrack string
+	first := true
+	err := WatchMetadata(ctx, "", func(meta *Metadata) {
+		// Only a new track is worth a notification, not pausing or a
+		// volume change, nor what was playing when the watch started.
+		track := ""
+		if meta != nil {
+			track = meta.Player + "\x00" + meta.Title + "\x00" + meta.Artist + "\x00" + meta.ArtUrl
+		}
+		skip := first || meta == nil || track == lastTrack
+		first, lastTrack = false, track
+		if skip {
+			return
+		}
 		if err := Notify(ctx, meta); err != nil {
 			logging.ReportError(ctx, err)
 		}
 	})
-	if err != nil {
+	if err != nil && ctx.Err() == nil {
 		logger := logging.GetLogger(ctx)
 		logger.Error("media watch failed", "error", err)
 	}
@@ -7,10 +7,19 @@ import (
 	"github.com/lucasew/workspaced/pkg/driver/notification"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/godbus/dbus/v5"
 )
 
+const (
+	busName   = "org.freedesktop.Notifications"
+	objPath   = dbus.ObjectPath("/org/freedesktop/Notifications")
+	iface     = "org.freedesktop.Notifications"
+	appName   = "workspaced"
+	eventsBuf = 8
+)
+
 func init() {
 	driver.Register[notification.Driver](&Factory{})
 }
@@ -31,11 +40,11 @@ func (f *Factory) CheckCompatibility(ctx context.Context) error {
 		return fmt.Errorf("%w: list dbus names: %w", driver.ErrIncompatible, err)
 	}
 
-	found := slices.Contains(names, "org.freedesktop.Notifications")
+	found := slices.Contains(names, busName)
 
 	if !found {
 		// Try to see if it can be started
-		err = conn.BusObject().Call("org.freedesktop.DBus.StartServiceByName", 0, "org.freedesktop.Notifications", uint32(0)).Err
+		err = conn.BusObject().Call("org.freedesktop.DBus.StartServiceByName", 0, busName, uint32(0)).Err
 		if err != nil {
 			return fmt.Errorf("%w: notifications service not found and could not be started: %w", driver.ErrIncompatible, err)
 		}
@@ -49,31 +58,236 @@ func (f *Factory) New(ctx context.Context) (notification.Driver, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Driver{
-		conn: conn,
-		ids:  make(map[uint32]uint32),
-	}, nil
+	return newDriver(conn), nil
 }
 
+func newDriver(conn *dbus.Conn) *Driver {
+	return &Driver{bus: busFor(conn)}
+}
+
+// Driver is cheap: driver.Get builds one per call, so the state that must
+// outlive it lives in the bus shared by every Driver on the connection.
 type Driver struct {
-	conn *dbus.Conn
-	mu   sync.Mutex
-	ids  map[uint32]uint32 // requested ID -> actual server ID
+	*bus
+}
+
+// bus is the per-connection state: the requested-to-server ID mapping, the
+// signal subscription (made once) and the event waiters.
+type bus struct {
+	conn    *dbus.Conn
+	mu      sync.Mutex
+	ids     map[uint32]uint32 // requested ID -> actual server ID
+	waiters map[uint32]*waiter
+	listen  sync.Once
+	err     error
+}
+
+var (
+	busesMu sync.Mutex
+	buses   = map[*dbus.Conn]*bus{}
+)
+
+func busFor(conn *dbus.Conn) *bus {
+	busesMu.Lock()
+	defer busesMu.Unlock()
+	b, ok := buses[conn]
+	if !ok {
+		b = &bus{
+			conn:    conn,
+			ids:     make(map[uint32]uint32),
+			waiters: make(map[uint32]*waiter),
+		}
+		buses[conn] = b
+	}
+	return b
+}
+
+// waiter receives the events of one server notification.
+type waiter struct {
+	mu     sync.Mutex
+	ch     chan notification.Event
+	stop   chan struct{}
+	closed bool
+}
+
+// deliver queues ev without blocking; a consumer that fell eventsBuf
+// events behind loses the newest ones.
+func (w *waiter) deliver(ev notification.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.ch <- ev:
+	default:
+	}
+}
+
+func (w *waiter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.closed {
+		w.closed = true
+		close(w.stop)
+		close(w.ch)
+	}
 }
 
 func (d *Driver) Notify(ctx context.Context, n *notification.Notification) error {
-	obj := d.conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.send(n)
+	return err
+}
+
+// NotifyEvents shows n and streams its action and close signals until the
+// notification closes or ctx is done.
+func (d *Driver) NotifyEvents(ctx context.Context, n *notification.Notification) (<-chan notification.Event, error) {
+	d.listen.Do(d.subscribe)
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	// The lock is held from the call to the registration so the dispatcher
+	// cannot see a signal for this notification before its waiter exists.
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	serverID, err := d.send(n)
+	if err != nil {
+		return nil, err
+	}
+	w := &waiter{
+		ch:   make(chan notification.Event, eventsBuf),
+		stop: make(chan struct{}),
+	}
+	if old, ok := d.waiters[serverID]; ok {
+		d.drop(serverID, old)
+	}
+	d.waiters[serverID] = w
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.mu.Lock()
+			d.drop(serverID, w)
+			d.mu.Unlock()
+		case <-w.stop:
+		}
+	}()
+	return w.ch, nil
+}
 
+func (d *Driver) Close(ctx context.Context, id uint32) error {
 	d.mu.Lock()
+	if actualID, ok := d.ids[id]; ok {
+		id = actualID
+	}
+	d.mu.Unlock()
+	return d.conn.Object(busName, objPath).CallWithContext(ctx, iface+".CloseNotification", 0, id).Err
+}
+
+// send calls Notify on the server. b.mu must be held.
+func (b *bus) send(n *notification.Notification) (uint32, error) {
 	replacesID := n.ID
-	if actualID, ok := d.ids[n.ID]; ok && n.ID != 0 {
+	if actualID, ok := b.ids[n.ID]; ok && n.ID != 0 {
 		replacesID = actualID
 	}
-	d.mu.Unlock()
 
-	hints := make(map[string]dbus.Variant)
+	var serverID uint32
+	err := b.conn.Object(busName, objPath).Call(iface+".Notify", 0,
+		appName,
+		replacesID,
+		n.Icon,
+		n.Title,
+		n.Message,
+		actions(n.Actions),
+		hints(n),
+		expireTimeout(n.Timeout),
+	).Store(&serverID)
+	if err != nil {
+		return 0, err
+	}
+
+	if n.ID != 0 {
+		b.ids[n.ID] = serverID
+	}
+	return serverID, nil
+}
+
+// drop unregisters w and closes its channel. b.mu must be held.
+func (b *bus) drop(serverID uint32, w *waiter) {
+	if b.waiters[serverID] != w {
+		return
+	}
+	delete(b.waiters, serverID)
+	w.close()
+}
+
+func (b *bus) subscribe() {
+	err := b.conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(objPath),
+		dbus.WithMatchInterface(iface),
+	)
+	if err != nil {
+		b.err = fmt.Errorf("subscribe to notification signals: %w", err)
+		return
+	}
+	signals := make(chan *dbus.Signal, eventsBuf)
+	b.conn.Signal(signals)
+	go b.dispatch(signals)
+}
+
+func (b *bus) dispatch(signals <-chan *dbus.Signal) {
+	for sig := range signals {
+		if sig.Path != objPath || len(sig.Body) < 2 {
+			continue
+		}
+		serverID, ok := sig.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+		var ev notification.Event
+		switch sig.Name {
+		case iface + ".ActionInvoked":
+			key, _ := sig.Body[1].(string)
+			ev = notification.Event{Kind: notification.EventAction, Action: key}
+		case iface + ".NotificationClosed":
+			reason, _ := sig.Body[1].(uint32)
+			ev = notification.Event{Kind: notification.EventClosed, Reason: notification.CloseReason(reason)}
+		default:
+			continue
+		}
+
+		b.mu.Lock()
+		w, ok := b.waiters[serverID]
+		if ok && ev.Kind == notification.EventClosed {
+			delete(b.waiters, serverID)
+		}
+		b.mu.Unlock()
+		if !ok {
+			continue
+		}
+		w.deliver(ev)
+		if ev.Kind == notification.EventClosed {
+			w.close()
+		}
+	}
+}
+
+func actions(as []notification.Action) []string {
+	ret := make([]string, 0, len(as)*2)
+	for _, a := range as {
+		ret = append(ret, a.Key, a.Label)
+	}
+	return ret
+}
+
+func hints(n *notification.Notification) map[string]dbus.Variant {
+	ret := make(map[string]dbus.Variant, len(n.Hints)+3)
+	for k, v := range n.Hints {
+		ret[k] = dbus.MakeVariant(v)
+	}
 
-	// Urgency
 	var urgency byte = 1 // normal
 	switch n.Urgency {
 	case "low":
@@ -81,40 +295,25 @@ func (d *Driver) Notify(ctx context.Context, n *notification.Notification) error
 	case "critical":
 		urgency = 2
 	}
-	hints["urgency"] = dbus.MakeVariant(urgency)
+	ret["urgency"] = dbus.MakeVariant(urgency)
 
-	// Progress
 	if n.HasProgress {
-		hints["value"] = dbus.MakeVariant(int32(n.Progress * 100))
+		ret["value"] = dbus.MakeVariant(int32(n.Progress * 100))
 	}
-
-	// Actions (empty for now)
-	actions := []string{}
-
-	// Expire timeout (-1 for default)
-	expireTimeout := int32(-1)
-
-	var serverID uint32
-	err := obj.Call("org.freedesktop.Notifications.Notify", 0,
-		"workspaced",  // app_name
-		replacesID,    // replaces_id
-		n.Icon,        // app_icon
-		n.Title,       // summary
-		n.Message,     // body
-		actions,       // actions
-		hints,         // hints
-		expireTimeout, // expire_timeout
-	).Store(&serverID)
-
-	if err != nil {
-		return err
+	if n.Category != "" {
+		ret["category"] = dbus.MakeVariant(n.Category)
 	}
+	return ret
+}
 
-	if n.ID != 0 {
-		d.mu.Lock()
-		d.ids[n.ID] = serverID
-		d.mu.Unlock()
+// expireTimeout maps Timeout to the spec's milliseconds, where -1 is the
+// server default and 0 never expires.
+func expireTimeout(t time.Duration) int32 {
+	switch {
+	case t == notification.NeverExpire:
+		return 0
+	case t <= 0:
+		return -1
 	}
-
-	return nil
+	return int32(max(t.Milliseconds(), 1))
 }
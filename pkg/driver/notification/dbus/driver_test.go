@@ -0,0 +1,234 @@
+package dbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/lucasew/workspaced/pkg/driver/dbustest"
+	"github.com/lucasew/workspaced/pkg/driver/notification"
+)
+
+type call struct {
+	ReplacesID uint32
+	Summary    string
+	Actions    []string
+	Hints      map[string]dbus.Variant
+	Expire     int32
+}
+
+// fakeServer implements the parts of org.freedesktop.Notifications the
+// driver uses and records every Notify call.
+type fakeServer struct {
+	conn   *dbus.Conn
+	mu     sync.Mutex
+	nextID uint32
+	calls  []call
+}
+
+func (s *fakeServer) Notify(app string, replacesID uint32, icon, summary, body string, actions []string, hints map[string]dbus.Variant, expire int32) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call{replacesID, summary, actions, hints, expire})
+	if replacesID != 0 && replacesID <= s.nextID {
+		return replacesID, nil
+	}
+	s.nextID++
+	return s.nextID, nil
+}
+
+func (s *fakeServer) CloseNotification(id uint32) *dbus.Error {
+	if err := s.emit("NotificationClosed", id, uint32(notification.CloseCalled)); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+func (s *fakeServer) emit(name string, args ...any) error {
+	return s.conn.Emit(objPath, iface+"."+name, args...)
+}
+
+func (s *fakeServer) last(t *testing.T) call {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.calls) == 0 {
+		t.Fatal("no Notify call")
+	}
+	return s.calls[len(s.calls)-1]
+}
+
+func setup(t *testing.T) (*Driver, *fakeServer) {
+	addr := dbustest.Bus(t)
+	srv := &fakeServer{conn: dbustest.Connect(t, addr)}
+	if err := srv.conn.Export(srv, objPath, iface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := srv.conn.RequestName(busName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("request name: %v %v", reply, err)
+	}
+	return newDriver(dbustest.Connect(t, addr)), srv
+}
+
+func next(t *testing.T, ch <-chan notification.Event) (notification.Event, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		return ev, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return notification.Event{}, false
+}
+
+func TestNotifyArguments(t *testing.T) {
+	d, srv := setup(t)
+	ctx := context.Background()
+
+	err := d.Notify(ctx, &notification.Notification{
+		ID:       notification.StatusNotificationID,
+		Title:    "hi",
+		Urgency:  "critical",
+		Actions:  []notification.Action{{Key: "default", Label: "Open"}, {Key: "retry", Label: "Retry"}},
+		Timeout:  1500 * time.Millisecond,
+		Category: "transfer.complete",
+		Hints:    map[string]any{"transient": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := srv.last(t)
+	if want := []string{"default", "Open", "retry", "Retry"}; len(c.Actions) != 4 || c.Actions[2] != want[2] || c.Actions[3] != want[3] {
+		t.Errorf("actions = %v, want %v", c.Actions, want)
+	}
+	if c.Expire != 1500 {
+		t.Errorf("expire = %d, want 1500", c.Expire)
+	}
+	if v := c.Hints["category"].Value(); v != "transfer.complete" {
+		t.Errorf("category hint = %v", v)
+	}
+	if v := c.Hints["urgency"].Value(); v != byte(2) {
+		t.Errorf("urgency hint = %v", v)
+	}
+	if v := c.Hints["transient"].Value(); v != true {
+		t.Errorf("transient hint = %v", v)
+	}
+
+	// The same requested ID replaces the server notification.
+	if err := d.Notify(ctx, &notification.Notification{ID: notification.StatusNotificationID, Timeout: notification.NeverExpire}); err != nil {
+		t.Fatal(err)
+	}
+	if c := srv.last(t); c.ReplacesID != 1 || c.Expire != 0 {
+		t.Errorf("replace call = %+v", c)
+	}
+}
+
+func TestNotifyEvents(t *testing.T) {
+	d, srv := setup(t)
+	ctx := context.Background()
+
+	ch, err := d.NotifyEvents(ctx, &notification.Notification{Title: "a", Actions: []notification.Action{{Key: "yes", Label: "Yes"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := d.NotifyEvents(ctx, &notification.Notification{Title: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.emit("ActionInvoked", uint32(1), "yes"); err != nil {
+		t.Fatal(err)
+	}
+	if ev, _ := next(t, ch); ev.Kind != notification.EventAction || ev.Action != "yes" {
+		t.Errorf("event = %+v, want action yes", ev)
+	}
+	if err := srv.emit("NotificationClosed", uint32(1), uint32(notification.CloseDismissed)); err != nil {
+		t.Fatal(err)
+	}
+	if ev, _ := next(t, ch); ev.Kind != notification.EventClosed || ev.Reason != notification.CloseDismissed {
+		t.Errorf("event = %+v, want closed dismissed", ev)
+	}
+	if _, ok := next(t, ch); ok {
+		t.Error("channel not closed after close event")
+	}
+
+	// Signals for one notification do not reach another.
+	if err := d.Close(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if ev, _ := next(t, other); ev.Kind != notification.EventClosed || ev.Reason != notification.CloseCalled {
+		t.Errorf("other event = %+v, want closed by call", ev)
+	}
+}
+
+func TestNotifyEventsCancel(t *testing.T) {
+	d, _ := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := d.NotifyEvents(ctx, &notification.Notification{Title: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, ok := next(t, ch); ok {
+		t.Error("channel not closed after cancel")
+	}
+}
+
+func TestDriversShareBus(t *testing.T) {
+	d, srv := setup(t)
+	ctx := context.Background()
+	// driver.Get builds a Driver per call; the second one must see the ID
+	// mapping and subscription of the first.
+	d2 := newDriver(d.conn)
+
+	if err := d.Notify(ctx, &notification.Notification{ID: notification.StatusNotificationID, Title: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d2.Notify(ctx, &notification.Notification{ID: notification.StatusNotificationID, Title: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if c := srv.last(t); c.ReplacesID != 1 {
+		t.Errorf("second driver replaced %d, want 1", c.ReplacesID)
+	}
+
+	stalled, err := d.NotifyEvents(ctx, &notification.Notification{Title: "stalled"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := d2.NotifyEvents(ctx, &notification.Notification{Title: "live"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nobody reads stalled; overflowing it must not hold up the others.
+	for range eventsBuf * 2 {
+		if err := srv.emit("ActionInvoked", uint32(2), "spam"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := srv.emit("ActionInvoked", uint32(3), "go"); err != nil {
+		t.Fatal(err)
+	}
+	if ev, _ := next(t, live); ev.Action != "go" {
+		t.Errorf("live event = %+v, want action go", ev)
+	}
+	if n := len(stalled); n != eventsBuf {
+		t.Errorf("stalled buffered %d events, want %d", n, eventsBuf)
+	}
+}
+
+func TestExpireTimeout(t *testing.T) {
+	for in, want := range map[time.Duration]int32{
+		0:                        -1,
+		notification.NeverExpire: 0,
+		time.Microsecond:         1,
+		3 * time.Second:          3000,
+	} {
+		if got := expireTimeout(in); got != want {
+			t.Errorf("expireTimeout(%v) = %d, want %d", in, got, want)
+		}
+	}
+}
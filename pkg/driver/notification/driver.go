@@ -2,6 +2,7 @@ package notification
 
 import (
 	"context"
+	"time"
 )
 
 // StatusNotificationID is the reserved ID for system status notifications (e.g. volume, brightness).
@@ -12,6 +13,9 @@ const (
 	BackupNotificationID   uint32 = 102
 )
 
+// NeverExpire as Notification.Timeout keeps the notification until dismissed.
+const NeverExpire time.Duration = -1
+
 // Notification represents a system notification.
 type Notification struct {
 	ID          uint32
@@ -21,8 +25,76 @@ type Notification struct {
 	Icon        string
 	Progress    float64 // 0.0-1.0
 	HasProgress bool
+	// Actions are buttons; the server reports the chosen Key. The key
+	// "default" is the click on the notification itself.
+	Actions []Action
+	// Timeout is how long the notification stays: 0 is the server
+	// default, NeverExpire keeps it.
+	Timeout time.Duration
+	// Category is a freedesktop category such as "device" or "transfer.complete".
+	Category string
+	// Hints are extra server hints (e.g. "x-canonical-private-synchronous").
+	// Values must be bool, string, byte, int32 or uint32-compatible.
+	Hints map[string]any
+}
+
+// Action is one notification button.
+type Action struct {
+	Key   string
+	Label string
+}
+
+// EventKind tells what happened to a notification.
+type EventKind string
+
+const (
+	EventAction EventKind = "action"
+	EventClosed EventKind = "closed"
+)
+
+// CloseReason is why a notification closed, as in the freedesktop spec.
+type CloseReason uint32
+
+const (
+	CloseExpired   CloseReason = 1
+	CloseDismissed CloseReason = 2
+	CloseCalled    CloseReason = 3
+	CloseUndefined CloseReason = 4
+)
+
+func (r CloseReason) String() string {
+	switch r {
+	case CloseExpired:
+		return "expired"
+	case CloseDismissed:
+		return "dismissed"
+	case CloseCalled:
+		return "closed"
+	}
+	return "undefined"
+}
+
+// Event is an action click or the close of a notification.
+type Event struct {
+	Kind EventKind
+	// Action is the Key of the invoked action (EventAction).
+	Action string
+	// Reason is set for EventClosed.
+	Reason CloseReason
 }
 
 type Driver interface {
 	Notify(ctx context.Context, n *Notification) error
 }
+
+// Interactive is implemented by drivers that report what the user did with
+// a notification. The channel gets action events and finally one
+// EventClosed, then closes; it also closes when ctx is done.
+type Interactive interface {
+	NotifyEvents(ctx context.Context, n *Notification) (<-chan Event, error)
+}
+
+// Closer is implemented by drivers that can withdraw a notification.
+type Closer interface {
+	Close(ctx context.Context, id uint32) error
+}
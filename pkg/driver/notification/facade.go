@@ -2,10 +2,44 @@ package notification
 
 import (
 	"context"
+	"fmt"
 
+	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 )
 
 func Notify(ctx context.Context, n *Notification) error {
 	return driver.With(ctx, func(d Driver) error { return d.Notify(ctx, n) })
 }
+
+// NotifyEvents shows n and returns its events. Drivers that cannot report
+// events still show it (without buttons they cannot honour) and return a
+// channel that is already closed.
+func NotifyEvents(ctx context.Context, n *Notification) (<-chan Event, error) {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := d.(Interactive); ok {
+		return i.NotifyEvents(ctx, n)
+	}
+	if err := d.Notify(ctx, n); err != nil {
+		return nil, err
+	}
+	ch := make(chan Event)
+	close(ch)
+	return ch, nil
+}
+
+// Close withdraws the notification with id.
+func Close(ctx context.Context, id uint32) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	c, ok := d.(Closer)
+	if !ok {
+		return fmt.Errorf("%w: %T cannot close notifications", dapi.ErrNotSupported, d)
+	}
+	return c.Close(ctx, id)
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/lucasew/workspaced/pkg/driver"
 	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
 	"github.com/lucasew/workspaced/pkg/driver/notification"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"maps"
+	"slices"
 )
 
 func init() {
@@ -28,6 +31,17 @@ func (f *Factory) New(ctx context.Context) (notification.Driver, error) {
 type Driver struct{}
 
 func (d *Driver) Notify(ctx context.Context, n *notification.Notification) error {
+	if len(n.Actions) > 0 {
+		// notify-send can only report actions by blocking until the
+		// notification closes, so buttons are left out.
+		logging.GetLogger(ctx).Debug("notify-send: dropping notification actions", "count", len(n.Actions))
+	}
+	return execdriver.MustRun(ctx, "notify-send", Args(n)...).Run()
+}
+
+// Args builds the notify-send command line for n. Hints whose type
+// notify-send cannot express are skipped.
+func Args(n *notification.Notification) []string {
 	args := []string{}
 	if n.Urgency != "" {
 		args = append(args, "-u", n.Urgency)
@@ -41,7 +55,38 @@ func (d *Driver) Notify(ctx context.Context, n *notification.Notification) error
 	if n.ID != 0 {
 		args = append(args, "-r", fmt.Sprintf("%d", n.ID))
 	}
+	if n.Category != "" {
+		args = append(args, "-c", n.Category)
+	}
+	switch {
+	case n.Timeout == notification.NeverExpire:
+		args = append(args, "-t", "0")
+	case n.Timeout > 0:
+		args = append(args, "-t", fmt.Sprintf("%d", max(n.Timeout.Milliseconds(), 1)))
+	}
+	for _, k := range slices.Sorted(maps.Keys(n.Hints)) {
+		if h, ok := hint(k, n.Hints[k]); ok {
+			args = append(args, "-h", h)
+		}
+	}
+
+	return append(args, n.Title, n.Message)
+}
 
-	args = append(args, n.Title, n.Message)
-	return execdriver.MustRun(ctx, "notify-send", args...).Run()
+func hint(name string, v any) (string, bool) {
+	switch v := v.(type) {
+	case bool:
+		return fmt.Sprintf("boolean:%s:%t", name, v), true
+	case string:
+		return fmt.Sprintf("string:%s:%s", name, v), true
+	case byte:
+		return fmt.Sprintf("byte:%s:%d", name, v), true
+	case int:
+		return fmt.Sprintf("int:%s:%d", name, v), true
+	case int32:
+		return fmt.Sprintf("int:%s:%d", name, v), true
+	case uint32:
+		return fmt.Sprintf("int:%s:%d", name, v), true
+	}
+	return "", false
 }
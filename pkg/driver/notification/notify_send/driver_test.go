@@ -0,0 +1,30 @@
+package notify_send
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/driver/notification"
+)
+
+func TestArgs(t *testing.T) {
+	got := Args(&notification.Notification{
+		ID:       7,
+		Title:    "t",
+		Message:  "m",
+		Category: "device",
+		Timeout:  2 * time.Second,
+		Actions:  []notification.Action{{Key: "a", Label: "A"}},
+		Hints:    map[string]any{"transient": true, "desktop-entry": "foot", "skip": 1.5},
+	})
+	want := []string{"-r", "7", "-c", "device", "-t", "2000", "-h", "string:desktop-entry:foot", "-h", "boolean:transient:true", "t", "m"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Args = %q, want %q", got, want)
+	}
+
+	got = Args(&notification.Notification{Title: "t", Timeout: notification.NeverExpire})
+	if want := []string{"-t", "0", "t", ""}; !slices.Equal(got, want) {
+		t.Errorf("Args = %q, want %q", got, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/lucasew/workspaced/pkg/driver"
 	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
 	"github.com/lucasew/workspaced/pkg/driver/notification"
+	"github.com/lucasew/workspaced/pkg/logging"
 )
 
 func init() {
@@ -35,5 +36,16 @@ func (d *Driver) Notify(ctx context.Context, n *notification.Notification) error
 	if n.ID != 0 {
 		args = append(args, "--id", fmt.Sprintf("%d", n.ID))
 	}
+	switch n.Urgency {
+	case "low":
+		args = append(args, "--priority", "low")
+	case "critical":
+		args = append(args, "--priority", "high")
+	}
+	// Termux buttons run shell commands instead of reporting back, and
+	// there is no expiry, so actions, timeout and hints are dropped.
+	if len(n.Actions) > 0 || n.Timeout != 0 || len(n.Hints) > 0 {
+		logging.GetLogger(ctx).Debug("termux-notification: dropping unsupported notification fields")
+	}
 	return execdriver.MustRun(ctx, "termux-notification", args...).Run()
 }
@@ -26,6 +26,10 @@ func Suspend(ctx context.Context) error {
 	return driver.With(ctx, func(d Driver) error { return d.Suspend(ctx) })
 }
 
+func Hibernate(ctx context.Context) error {
+	return driver.With(ctx, func(d Driver) error { return d.Hibernate(ctx) })
+}
+
 func Wake(ctx context.Context, host string) error {
 	cfg, err := configcue.LoadForWorkspace(ctx, "")
 	if err != nil {
@@ -4,6 +4,9 @@ import (
 	_ "github.com/lucasew/workspaced/pkg/driver/audio/pulse"
 	_ "github.com/lucasew/workspaced/pkg/driver/battery/linux"
 	_ "github.com/lucasew/workspaced/pkg/driver/brightness/brightnessctl"
+	_ "github.com/lucasew/workspaced/pkg/driver/brightness/ddc"
+	_ "github.com/lucasew/workspaced/pkg/driver/brightness/gammastep"
+	_ "github.com/lucasew/workspaced/pkg/driver/brightness/hyprsunset"
 	_ "github.com/lucasew/workspaced/pkg/driver/camera/linux"
 	_ "github.com/lucasew/workspaced/pkg/driver/clipboard/termux"
 	_ "github.com/lucasew/workspaced/pkg/driver/clipboard/wlcopy"
@@ -19,6 +22,8 @@ import (
 	_ "github.com/lucasew/workspaced/pkg/driver/fetchurl/fetchurl"
 	_ "github.com/lucasew/workspaced/pkg/driver/httpclient/native"
 	_ "github.com/lucasew/workspaced/pkg/driver/httpclient/termux"
+	_ "github.com/lucasew/workspaced/pkg/driver/idle/logind"
+	_ "github.com/lucasew/workspaced/pkg/driver/idle/swayidle"
 	_ "github.com/lucasew/workspaced/pkg/driver/media/dbus"
 	_ "github.com/lucasew/workspaced/pkg/driver/notification/dbus"
 	_ "github.com/lucasew/workspaced/pkg/driver/notification/notify_send"
@@ -31,6 +36,8 @@ import (
 	_ "github.com/lucasew/workspaced/pkg/driver/rsync/native"
 	_ "github.com/lucasew/workspaced/pkg/driver/screen/sway"
 	_ "github.com/lucasew/workspaced/pkg/driver/screen/x11"
+	_ "github.com/lucasew/workspaced/pkg/driver/screenrecord/ffmpeg"
+	_ "github.com/lucasew/workspaced/pkg/driver/screenrecord/wfrecorder"
 	_ "github.com/lucasew/workspaced/pkg/driver/screenshot/grim"
 	_ "github.com/lucasew/workspaced/pkg/driver/screenshot/maim"
 	_ "github.com/lucasew/workspaced/pkg/driver/shell/bash"
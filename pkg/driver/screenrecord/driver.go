@@ -0,0 +1,43 @@
+package screenrecord
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+)
+
+var (
+	ErrAlreadyRecording = errors.New("already recording")
+	ErrNotRecording     = errors.New("not recording")
+	ErrNoDaemon         = errors.New("screen recording state lives in the daemon, which is not running")
+)
+
+// Options describes one recording.
+type Options struct {
+	// Rect is the region to record; nil records everything.
+	Rect *api.Rect
+	// Audio also records the default audio source.
+	Audio bool
+	// Path is the output file; its extension selects the container.
+	Path string
+	// Format is the container (mp4, mkv, webm); Path's extension wins.
+	Format string
+}
+
+// Driver builds the recorder command for opts. The command must record
+// until it receives SIGINT and then finalize the file and exit.
+type Driver interface {
+	Command(ctx context.Context, opts Options) (*exec.Cmd, error)
+}
+
+// Status is the state of the recorder.
+type Status struct {
+	Recording bool      `json:"recording"`
+	Path      string    `json:"path,omitempty"`
+	Target    string    `json:"target,omitempty"`
+	Audio     bool      `json:"audio,omitempty"`
+	Started   time.Time `json:"started,omitzero"`
+}
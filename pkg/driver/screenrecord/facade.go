@@ -0,0 +1,144 @@
+package screenrecord
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/driver/notification"
+	"github.com/lucasew/workspaced/pkg/driver/screenshot"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// recorder is the process-wide recorder; in practice the daemon's.
+var recorder Recorder
+
+// StartOptions are the per-invocation choices; unset ones come from
+// workspaced.screenrecord.
+type StartOptions struct {
+	Target screenshot.TargetType
+	Audio  *bool
+	Format string
+}
+
+// Config is workspaced.screenrecord.
+type Config struct {
+	Dir    string `json:"dir"`
+	Format string `json:"format"`
+	Audio  bool   `json:"audio"`
+}
+
+// LoadConfig reads workspaced.screenrecord, defaulting the directory to
+// the screenshot one.
+func LoadConfig(ctx context.Context) (Config, error) {
+	cfg := Config{Format: "mp4"}
+	c, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return cfg, err
+	}
+	if _, err := c.Lookup("screenrecord"); err == nil {
+		if err := c.Decode("screenrecord", &cfg); err != nil {
+			return cfg, fmt.Errorf("decode screenrecord: %w", err)
+		}
+	}
+	if strings.TrimSpace(cfg.Dir) == "" {
+		var shot struct {
+			Dir string `json:"dir"`
+		}
+		if _, err := c.Lookup("screenshot"); err == nil {
+			if err := c.Decode("screenshot", &shot); err != nil {
+				return cfg, fmt.Errorf("decode screenshot: %w", err)
+			}
+		}
+		cfg.Dir = shot.Dir
+	}
+	if strings.TrimSpace(cfg.Dir) == "" {
+		return cfg, screenshot.ErrDirNotConfigured
+	}
+	return cfg, nil
+}
+
+// Start resolves the target like a screenshot would and starts recording.
+func Start(ctx context.Context, so StartOptions) (Status, error) {
+	if st := recorder.Status(); st.Recording {
+		return st, ErrAlreadyRecording
+	}
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	cfg, err := LoadConfig(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	rect, err := screenshot.ResolveRect(ctx, so.Target)
+	if err != nil {
+		return Status{}, err
+	}
+	opts := Options{Rect: rect, Audio: cfg.Audio, Format: cfg.Format}
+	if so.Audio != nil {
+		opts.Audio = *so.Audio
+	}
+	if so.Format != "" {
+		opts.Format = so.Format
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return Status{}, fmt.Errorf("create recording dir: %w", err)
+	}
+	opts.Path = filepath.Join(cfg.Dir, fmt.Sprintf("Recording_%s.%s", time.Now().Format("2006-01-02_15-04-05"), opts.Format))
+
+	bg := context.WithoutCancel(ctx)
+	st, err := recorder.Start(ctx, d, opts, so.Target.Label(), func(st Status, err error) {
+		notify(bg, "Recording Failed", fmt.Sprintf("%s: %v", st.Path, err), "dialog-error")
+	})
+	if err != nil {
+		return st, err
+	}
+	notify(ctx, fmt.Sprintf("Recording (%s)", st.Target), "Run the toggle again to stop", "media-record")
+	return st, nil
+}
+
+// Stop finishes the current recording.
+func Stop(ctx context.Context) (Status, error) {
+	st, err := recorder.Stop(ctx)
+	if err != nil {
+		return st, err
+	}
+	notify(ctx, fmt.Sprintf("Recording Saved (%s)", st.Target), st.Path, "media-record")
+	return st, nil
+}
+
+// Toggle stops the current recording or starts a new one.
+func Toggle(ctx context.Context, so StartOptions) (Status, error) {
+	if recorder.Status().Recording {
+		return Stop(ctx)
+	}
+	return Start(ctx, so)
+}
+
+// CurrentStatus reports the current recording, if any.
+func CurrentStatus() Status {
+	return recorder.Status()
+}
+
+// Run records in the foreground until ctx is done, for when there is no
+// daemon to keep the recorder alive.
+func Run(ctx context.Context, so StartOptions) (Status, error) {
+	if _, err := Start(ctx, so); err != nil {
+		return Status{}, err
+	}
+	<-ctx.Done()
+	return Stop(context.WithoutCancel(ctx))
+}
+
+func notify(ctx context.Context, title, message, icon string) {
+	n := notification.Notification{Title: title, Message: message, Icon: icon}
+	if err := notification.Notify(ctx, &n); err != nil {
+		logging.ReportError(ctx, err)
+	}
+}
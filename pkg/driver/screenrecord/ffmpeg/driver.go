@@ -0,0 +1,71 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/executil"
+	"github.com/lucasew/workspaced/pkg/driver"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+)
+
+func init() {
+	driver.Register[screenrecord.Driver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "screenrecord_ffmpeg" }
+func (f *Factory) Name() string { return "FFmpeg x11grab (X11)" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	return execdriver.RequireEnvBinary(ctx, "DISPLAY", "ffmpeg")
+}
+
+func (f *Factory) New(ctx context.Context) (screenrecord.Driver, error) {
+	return &Driver{}, nil
+}
+
+type Driver struct{}
+
+func (d *Driver) Command(ctx context.Context, opts screenrecord.Options) (*exec.Cmd, error) {
+	display := executil.GetEnv(ctx, "DISPLAY")
+	if display == "" {
+		return nil, fmt.Errorf("%w: DISPLAY not set", driver.ErrIncompatible)
+	}
+	return execdriver.MustRun(ctx, "ffmpeg", Args(display, opts)...), nil
+}
+
+// Args builds the ffmpeg arguments for opts on display. x11grab captures
+// the whole screen when no size is given; audio comes from the PulseAudio
+// (or PipeWire-Pulse) default source.
+func Args(display string, opts screenrecord.Options) []string {
+	args := []string{"-hide_banner", "-loglevel", "error", "-y", "-f", "x11grab", "-framerate", "30"}
+	input := display
+	if r := opts.Rect; r != nil {
+		// yuv420p needs even dimensions.
+		args = append(args, "-video_size", fmt.Sprintf("%dx%d", r.Width&^1, r.Height&^1))
+		input = fmt.Sprintf("%s+%d,%d", display, r.X, r.Y)
+	}
+	args = append(args, "-i", input)
+	if opts.Audio {
+		args = append(args, "-f", "pulse", "-i", "default")
+	}
+	switch strings.TrimPrefix(filepath.Ext(opts.Path), ".") {
+	case "webm":
+		args = append(args, "-c:v", "libvpx-vp9", "-deadline", "realtime", "-pix_fmt", "yuv420p")
+		if opts.Audio {
+			args = append(args, "-c:a", "libopus")
+		}
+	default:
+		args = append(args, "-c:v", "libx264", "-preset", "ultrafast", "-pix_fmt", "yuv420p")
+		if opts.Audio {
+			args = append(args, "-c:a", "aac")
+		}
+	}
+	return append(args, opts.Path)
+}
@@ -0,0 +1,28 @@
+package ffmpeg
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+)
+
+func TestArgs(t *testing.T) {
+	t.Parallel()
+	got := Args(":0", screenrecord.Options{
+		Rect:  &api.Rect{X: 10, Y: 20, Width: 641, Height: 480},
+		Audio: true,
+		Path:  "/tmp/r.webm",
+	})
+	want := "-hide_banner -loglevel error -y -f x11grab -framerate 30 -video_size 640x480 -i :0+10,20 -f pulse -i default -c:v libvpx-vp9 -deadline realtime -pix_fmt yuv420p -c:a libopus /tmp/r.webm"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("args =\n%s\nwant\n%s", strings.Join(got, " "), want)
+	}
+
+	got = Args(":1", screenrecord.Options{Path: "/tmp/r.mp4"})
+	if !slices.Contains(got, ":1") || slices.Contains(got, "-video_size") || slices.Contains(got, "pulse") || got[len(got)-1] != "/tmp/r.mp4" {
+		t.Fatalf("full-screen args = %q", got)
+	}
+}
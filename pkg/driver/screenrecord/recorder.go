@@ -0,0 +1,135 @@
+package screenrecord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// stopTimeout bounds how long a recorder may take to finalize after SIGINT.
+const stopTimeout = 10 * time.Second
+
+// Recorder runs at most one recording at a time. The daemon keeps one so
+// that start/stop/toggle from separate invocations act on the same process.
+type Recorder struct {
+	mu     sync.Mutex
+	active *session
+}
+
+type session struct {
+	cmd    *exec.Cmd
+	status Status
+	done   chan struct{}
+	err    error
+	// stopping is set when the exit was requested.
+	stopping bool
+}
+
+// OnExit is called when a recording ends without being stopped (e.g. the
+// recorder crashed), with the status it had and the exit error.
+type OnExit func(Status, error)
+
+// Start runs the recorder command for opts. The process outlives ctx's
+// cancellation; it ends with Stop or on its own, in which case onExit (if
+// set) is called.
+func (r *Recorder) Start(ctx context.Context, d Driver, opts Options, target string, onExit OnExit) (Status, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.active != nil {
+		return r.active.status, ErrAlreadyRecording
+	}
+	cmd, err := d.Command(context.WithoutCancel(ctx), opts)
+	if err != nil {
+		return Status{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Status{}, fmt.Errorf("start %s: %w", cmd.Path, err)
+	}
+	s := &session{
+		cmd:  cmd,
+		done: make(chan struct{}),
+		status: Status{
+			Recording: true,
+			Path:      opts.Path,
+			Target:    target,
+			Audio:     opts.Audio,
+			Started:   time.Now(),
+		},
+	}
+	r.active = s
+	logging.GetLogger(ctx).Info("recording started", "path", opts.Path, "cmd", cmd.Args)
+	go func() {
+		s.err = cmd.Wait()
+		close(s.done)
+		r.mu.Lock()
+		stopping := s.stopping
+		if r.active == s {
+			r.active = nil
+		}
+		r.mu.Unlock()
+		if !stopping && onExit != nil {
+			onExit(s.status, s.err)
+		}
+	}()
+	return s.status, nil
+}
+
+// Stop interrupts the recording and waits for the file to be finalized.
+func (r *Recorder) Stop(ctx context.Context) (Status, error) {
+	r.mu.Lock()
+	s := r.active
+	if s == nil {
+		r.mu.Unlock()
+		return Status{}, ErrNotRecording
+	}
+	s.stopping = true
+	r.active = nil
+	r.mu.Unlock()
+
+	if err := s.cmd.Process.Signal(os.Interrupt); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return s.status, err
+	}
+	select {
+	case <-s.done:
+	case <-time.After(stopTimeout):
+		logging.GetLogger(ctx).Warn("recorder ignored SIGINT, killing", "pid", s.cmd.Process.Pid)
+		_ = s.cmd.Process.Kill()
+		<-s.done
+	}
+	st := s.status
+	st.Recording = false
+	if s.err != nil && !interrupted(s.err) {
+		return st, fmt.Errorf("recorder exited: %w", s.err)
+	}
+	return st, nil
+}
+
+// Status reports the current recording, if any.
+func (r *Recorder) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.active == nil {
+		return Status{}
+	}
+	return r.active.status
+}
+
+// interrupted reports whether err is the exit of a process that died from
+// the SIGINT we sent (ffmpeg exits 255 after finalizing).
+func interrupted(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() && ws.Signal() == syscall.SIGINT {
+		return true
+	}
+	return exitErr.ExitCode() == 255
+}
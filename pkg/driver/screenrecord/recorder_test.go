@@ -0,0 +1,103 @@
+package screenrecord
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// scriptDriver "records" by running a shell script that writes the output
+// file when interrupted, like a real recorder finalizing its container.
+type scriptDriver struct {
+	script string
+}
+
+func (d *scriptDriver) Command(ctx context.Context, opts Options) (*exec.Cmd, error) {
+	return exec.CommandContext(ctx, "sh", "-c", d.script, "sh", opts.Path), nil
+}
+
+const finalizeOnInt = `trap 'echo finalized > "$1"; exit 0' INT; echo started > "$1.started"; while :; do sleep 0.05; done`
+
+func waitFile(t *testing.T, path string) {
+	t.Helper()
+	for range 200 {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s never appeared", path)
+}
+
+func TestRecorderStartStop(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(logging.NewWriterContext(t.Output()))
+	path := filepath.Join(t.TempDir(), "rec.mp4")
+	var r Recorder
+	d := &scriptDriver{script: finalizeOnInt}
+
+	st, err := r.Start(ctx, d, Options{Path: path, Audio: true}, "All screens", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Recording || st.Path != path || !st.Audio || st.Target != "All screens" {
+		t.Fatalf("start status = %+v", st)
+	}
+	waitFile(t, path+".started")
+	cancel() // the request that started it is gone; recording goes on
+
+	if _, err := r.Start(logging.NewWriterContext(t.Output()), d, Options{Path: path}, "", nil); !errors.Is(err, ErrAlreadyRecording) {
+		t.Fatalf("second start err = %v", err)
+	}
+	if !r.Status().Recording {
+		t.Fatal("not recording after the starting context was cancelled")
+	}
+
+	st, err = r.Stop(logging.NewWriterContext(t.Output()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Recording || st.Path != path {
+		t.Fatalf("stop status = %+v", st)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "finalized\n" {
+		t.Fatalf("output = %q, %v", data, err)
+	}
+	if r.Status().Recording {
+		t.Fatal("still recording after stop")
+	}
+	if _, err := r.Stop(logging.NewWriterContext(t.Output())); !errors.Is(err, ErrNotRecording) {
+		t.Fatalf("second stop err = %v", err)
+	}
+}
+
+func TestRecorderCrash(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	var r Recorder
+	exited := make(chan error, 1)
+	_, err := r.Start(ctx, &scriptDriver{script: "exit 3"}, Options{Path: "x"}, "", func(st Status, err error) {
+		exited <- err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-exited:
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+			t.Fatalf("onExit err = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onExit not called")
+	}
+	if r.Status().Recording {
+		t.Fatal("crashed recording still active")
+	}
+}
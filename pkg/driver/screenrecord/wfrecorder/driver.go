@@ -0,0 +1,48 @@
+package wfrecorder
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/lucasew/workspaced/pkg/driver"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+)
+
+func init() {
+	driver.Register[screenrecord.Driver](&Factory{})
+}
+
+type Factory struct{}
+
+func (f *Factory) ID() string   { return "screenrecord_wfrecorder" }
+func (f *Factory) Name() string { return "wf-recorder (Wayland)" }
+
+func (f *Factory) CheckCompatibility(ctx context.Context) error {
+	return execdriver.RequireEnvBinary(ctx, "WAYLAND_DISPLAY", "wf-recorder")
+}
+
+func (f *Factory) New(ctx context.Context) (screenrecord.Driver, error) {
+	return &Driver{}, nil
+}
+
+type Driver struct{}
+
+// Command runs wf-recorder, which picks the muxer from the file extension
+// and finalizes the file on SIGINT.
+func (d *Driver) Command(ctx context.Context, opts screenrecord.Options) (*exec.Cmd, error) {
+	return execdriver.MustRun(ctx, "wf-recorder", Args(opts)...), nil
+}
+
+// Args builds the wf-recorder arguments for opts.
+func Args(opts screenrecord.Options) []string {
+	args := []string{"--overwrite", "-f", opts.Path}
+	if r := opts.Rect; r != nil {
+		args = append(args, "-g", fmt.Sprintf("%d,%d %dx%d", r.X, r.Y, r.Width, r.Height))
+	}
+	if opts.Audio {
+		args = append(args, "--audio")
+	}
+	return args
+}
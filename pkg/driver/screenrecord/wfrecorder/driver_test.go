@@ -0,0 +1,21 @@
+package wfrecorder
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/driver/screenrecord"
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+)
+
+func TestArgs(t *testing.T) {
+	t.Parallel()
+	got := Args(screenrecord.Options{Rect: &api.Rect{X: 1, Y: 2, Width: 300, Height: 200}, Audio: true, Path: "/tmp/r.mkv"})
+	want := []string{"--overwrite", "-f", "/tmp/r.mkv", "-g", "1,2 300x200", "--audio"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("args = %q, want %q", got, want)
+	}
+	if got := Args(screenrecord.Options{Path: "a.mp4"}); !slices.Equal(got, []string{"--overwrite", "-f", "a.mp4"}) {
+		t.Fatalf("full-screen args = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// ErrNoAnnotator is returned when no annotation tool is configured or found.
+var ErrNoAnnotator = errors.New("no screenshot annotator found (install satty or swappy, or set cmd)")
+
+func init() {
+	RegisterAction[AnnotateAction]("annotate")
+}
+
+// annotators are tried in order when cmd is unset; each edits {path} in place.
+var annotators = [][]string{
+	{"satty", "--filename", "{path}", "--output-filename", "{path}", "--early-exit"},
+	{"swappy", "-f", "{path}", "-o", "{path}"},
+}
+
+// AnnotateAction opens the shot in an editor, waits for it to close and
+// reloads the (possibly edited) file.
+type AnnotateAction struct {
+	ActionBase
+	Cmd []string `json:"cmd"`
+}
+
+func (a AnnotateAction) Run(ctx context.Context, shot *Shot) error {
+	argv, err := a.command(ctx)
+	if err != nil {
+		return err
+	}
+	argv = expandPath(argv, shot.Path)
+	if err := execdriver.MustRun(ctx, argv[0], argv[1:]...).Run(); err != nil {
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
+	f, err := os.Open(shot.Path)
+	if err != nil {
+		return err
+	}
+	defer logging.Close(ctx, f, "path", shot.Path)
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("reload annotated screenshot: %w", err)
+	}
+	shot.Image = img
+	return nil
+}
+
+func (a AnnotateAction) command(ctx context.Context) ([]string, error) {
+	if len(a.Cmd) > 0 {
+		return a.Cmd, nil
+	}
+	for _, argv := range annotators {
+		if execdriver.IsBinaryAvailable(ctx, argv[0]) {
+			return argv, nil
+		}
+	}
+	return nil, ErrNoAnnotator
+}
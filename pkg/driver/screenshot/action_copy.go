@@ -0,0 +1,24 @@
+package screenshot
+
+import (
+	"context"
+
+	"github.com/lucasew/workspaced/pkg/driver/clipboard"
+)
+
+func init() {
+	RegisterAction[CopyAction]("copy")
+}
+
+// CopyAction copies the shot's text when an earlier action produced one,
+// else the image.
+type CopyAction struct {
+	ActionBase
+}
+
+func (a CopyAction) Run(ctx context.Context, shot *Shot) error {
+	if shot.Text != "" {
+		return clipboard.WriteText(ctx, shot.Text)
+	}
+	return clipboard.WriteImage(ctx, shot.Image)
+}
@@ -0,0 +1,61 @@
+package screenshot
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/tool"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func init() {
+	RegisterAction[OCRAction]("ocr")
+}
+
+// OCRAction recognizes the shot's text with tesseract, ensured through
+// lazy_tools.tesseract when configured and taken from PATH otherwise.
+type OCRAction struct {
+	ActionBase
+	// Lang is a tesseract language spec such as "eng" or "eng+por".
+	Lang string `json:"lang"`
+}
+
+func (a OCRAction) Run(ctx context.Context, shot *Shot) error {
+	cmd, err := tesseract(ctx, a.args(shot.Path)...)
+	if err != nil {
+		return err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("tesseract: %w", err)
+	}
+	text := strings.TrimSpace(string(out))
+	if text == "" {
+		return fmt.Errorf("no text recognized in %s", shot.Path)
+	}
+	shot.Text = text
+	return nil
+}
+
+func (a OCRAction) args(path string) []string {
+	args := []string{path, "-"}
+	if a.Lang != "" {
+		args = append(args, "-l", a.Lang)
+	}
+	return args
+}
+
+func tesseract(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	cmd, err := tool.EnsureAndRunLazy(ctx, "tesseract", "tesseract", args...)
+	if err == nil {
+		return cmd, nil
+	}
+	logging.GetLogger(ctx).Debug("lazy tesseract unavailable, trying PATH", "error", err)
+	if err := execdriver.RequireBinary(ctx, "tesseract"); err != nil {
+		return nil, fmt.Errorf("tesseract: add it to lazy_tools or install it: %w", err)
+	}
+	return execdriver.MustRun(ctx, "tesseract", args...), nil
+}
@@ -0,0 +1,48 @@
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	"github.com/lucasew/workspaced/pkg/driver/notification"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// ErrUploadNeedsCmd is returned when an upload action has no command.
+var ErrUploadNeedsCmd = errors.New("upload action requires cmd")
+
+func init() {
+	RegisterAction[UploadAction]("upload")
+}
+
+// UploadAction runs cmd with {path} replaced and keeps the last line it
+// prints (the URL) as the shot's text, so `--then upload,copy` copies it.
+type UploadAction struct {
+	ActionBase
+	Cmd []string `json:"cmd"`
+}
+
+func (a UploadAction) Run(ctx context.Context, shot *Shot) error {
+	if len(a.Cmd) == 0 {
+		return ErrUploadNeedsCmd
+	}
+	argv := expandPath(a.Cmd, shot.Path)
+	out, err := execdriver.MustRun(ctx, argv[0], argv[1:]...).Output()
+	if err != nil {
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	url := strings.TrimSpace(lines[len(lines)-1])
+	if url == "" {
+		return fmt.Errorf("%s printed nothing", argv[0])
+	}
+	shot.Text = url
+	n := notification.Notification{Title: "Screenshot Uploaded", Message: url, Icon: "camera-photo"}
+	if err := notification.Notify(ctx, &n); err != nil {
+		logging.ReportError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package screenshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+)
+
+var (
+	// ErrUnknownAction is returned for --then names that are neither
+	// configured nor built in.
+	ErrUnknownAction = errors.New("unknown screenshot action")
+	// ErrUnknownActionKind is returned for configured actions of an
+	// unregistered kind.
+	ErrUnknownActionKind = errors.New("unknown screenshot action kind")
+)
+
+// Shot is what post-capture actions work on. Actions run in order, so one
+// may leave Text for the next: `--then ocr,copy` copies the recognized text.
+type Shot struct {
+	Path   string
+	Image  image.Image
+	Target TargetType
+	// Text is the textual result of the last action that produced one
+	// (OCR output, upload URL); copy prefers it over the image.
+	Text string
+}
+
+// Action is a post-capture step, configured under
+// workspaced.screenshot.actions.<name> and selected by name.
+type Action interface {
+	GetName() string
+	GetKind() string
+	Run(ctx context.Context, shot *Shot) error
+}
+
+type ActionBase struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+func (a ActionBase) GetName() string { return a.Name }
+func (a ActionBase) GetKind() string { return a.Kind }
+
+func (a *ActionBase) setName(name string) { a.Name = name }
+
+var actionDecoders = map[string]func(name string, raw json.RawMessage) (Action, error){}
+
+// RegisterAction makes kind usable in config. Every kind is also available
+// unconfigured under its own name, decoded from {"kind": kind}. The action
+// name is the config key, so T should embed ActionBase to carry it.
+func RegisterAction[T Action](kind string) {
+	actionDecoders[kind] = func(name string, raw json.RawMessage) (Action, error) {
+		var a T
+		if err := json.Unmarshal(raw, &a); err != nil {
+			return nil, fmt.Errorf("decode %s action: %w", kind, err)
+		}
+		if named, ok := any(&a).(interface{ setName(string) }); ok {
+			named.setName(name)
+		}
+		return a, nil
+	}
+}
+
+// DefaultThen is what runs after a capture when neither --then nor
+// screenshot.then says otherwise.
+var DefaultThen = []string{"copy"}
+
+// ResolveActions maps names to actions, looking first at the configured
+// ones and then at the registered kinds.
+func ResolveActions(configured map[string]json.RawMessage, names []string) ([]Action, error) {
+	actions := make([]Action, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		raw, ok := configured[name]
+		if !ok {
+			if _, builtin := actionDecoders[name]; !builtin {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownAction, name)
+			}
+			raw = json.RawMessage(fmt.Sprintf(`{"kind":%q}`, name))
+		}
+		var base ActionBase
+		if err := json.Unmarshal(raw, &base); err != nil {
+			return nil, fmt.Errorf("decode screenshot action %s: %w", name, err)
+		}
+		decode, ok := actionDecoders[base.Kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s (action %s)", ErrUnknownActionKind, base.Kind, name)
+		}
+		action, err := decode(name, raw)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// RunActions runs actions in order, stopping at the first failure since
+// later steps usually depend on earlier ones.
+func RunActions(ctx context.Context, shot *Shot, actions []Action) error {
+	for _, a := range actions {
+		name := a.GetName()
+		if name == "" {
+			name = a.GetKind()
+		}
+		if err := a.Run(ctx, shot); err != nil {
+			return fmt.Errorf("screenshot action %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// expandPath replaces {path} in every argument.
+func expandPath(args []string, path string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = strings.ReplaceAll(a, "{path}", path)
+	}
+	return out
+}
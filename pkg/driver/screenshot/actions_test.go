@@ -0,0 +1,122 @@
+package screenshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// recordAction appends its name to the shot text, or fails.
+type recordAction struct {
+	ActionBase
+	Fail bool `json:"fail"`
+}
+
+func (a recordAction) Run(_ context.Context, shot *Shot) error {
+	if a.Fail {
+		return errors.New("boom")
+	}
+	shot.Text += a.Name + ";"
+	return nil
+}
+
+func init() {
+	RegisterAction[recordAction]("test-record")
+}
+
+func TestResolveActions(t *testing.T) {
+	t.Parallel()
+	configured := map[string]json.RawMessage{
+		"imgur": json.RawMessage(`{"kind":"upload","cmd":["imgur","{path}"]}`),
+		"pt":    json.RawMessage(`{"kind":"ocr","lang":"por"}`),
+		"bad":   json.RawMessage(`{"kind":"nope"}`),
+	}
+	actions, err := ResolveActions(configured, []string{"pt", " copy", "", "imgur"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds, names []string
+	for _, a := range actions {
+		kinds = append(kinds, a.GetKind())
+		names = append(names, a.GetName())
+	}
+	if !slices.Equal(kinds, []string{"ocr", "copy", "upload"}) {
+		t.Fatalf("kinds = %v", kinds)
+	}
+	if !slices.Equal(names, []string{"pt", "copy", "imgur"}) {
+		t.Fatalf("names = %v", names)
+	}
+	if ocr := actions[0].(OCRAction); ocr.Lang != "por" || !slices.Equal(ocr.args("/s.png"), []string{"/s.png", "-", "-l", "por"}) {
+		t.Fatalf("ocr = %+v", ocr)
+	}
+	if up := actions[2].(UploadAction); !slices.Equal(expandPath(up.Cmd, "/s.png"), []string{"imgur", "/s.png"}) {
+		t.Fatalf("upload cmd = %v", up.Cmd)
+	}
+
+	if _, err := ResolveActions(configured, []string{"ocr", "typo"}); !errors.Is(err, ErrUnknownAction) {
+		t.Fatalf("unknown name err = %v", err)
+	}
+	if _, err := ResolveActions(configured, []string{"bad"}); !errors.Is(err, ErrUnknownActionKind) {
+		t.Fatalf("unknown kind err = %v", err)
+	}
+}
+
+func TestRunActions(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	actions, err := ResolveActions(map[string]json.RawMessage{
+		"a":    json.RawMessage(`{"kind":"test-record"}`),
+		"b":    json.RawMessage(`{"kind":"test-record"}`),
+		"fail": json.RawMessage(`{"kind":"test-record","fail":true}`),
+	}, []string{"a", "b", "fail", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shot := &Shot{}
+	err = RunActions(ctx, shot, actions)
+	if err == nil || err.Error() != "screenshot action fail: boom" {
+		t.Fatalf("err = %v", err)
+	}
+	if shot.Text != "a;b;" {
+		t.Fatalf("ran %q, want a then b and a stop at fail", shot.Text)
+	}
+}
+
+func TestUploadAndAnnotateActions(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := writePNGAtomic(path, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	shot := &Shot{Path: path}
+
+	up := UploadAction{Cmd: []string{"sh", "-c", `echo "uploading $0" >&2; echo progress; echo "https://example.invalid/$(basename $0)"`, "{path}"}}
+	if err := up.Run(ctx, shot); err != nil {
+		t.Fatal(err)
+	}
+	if shot.Text != "https://example.invalid/shot.png" {
+		t.Fatalf("upload text = %q", shot.Text)
+	}
+	if err := (UploadAction{}).Run(ctx, shot); !errors.Is(err, ErrUploadNeedsCmd) {
+		t.Fatalf("upload without cmd err = %v", err)
+	}
+
+	// An "editor" that grows the image; the action must reload it.
+	grow := []string{"sh", "-c", `cp "$0.big" "$0"`, "{path}"}
+	if err := writePNGAtomic(path+".big", image.NewRGBA(image.Rect(0, 0, 8, 6))); err != nil {
+		t.Fatal(err)
+	}
+	if err := (AnnotateAction{Cmd: grow}).Run(ctx, shot); err != nil {
+		t.Fatal(err)
+	}
+	if b := shot.Image.Bounds(); b.Dx() != 8 || b.Dy() != 6 {
+		t.Fatalf("annotated image bounds = %v", b)
+	}
+}
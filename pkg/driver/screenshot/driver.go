@@ -26,6 +26,37 @@ const (
 	TargetSelection
 )
 
+// Label is the human name of the target, as used in notifications.
+func (t TargetType) Label() string {
+	switch t {
+	case TargetAll:
+		return "All screens"
+	case TargetOutput:
+		return "Current monitor"
+	case TargetWindow:
+		return "Current window"
+	case TargetSelection:
+		return "Selected area"
+	}
+	return "Unknown"
+}
+
+// ParseTarget maps the CLI target names (all/full, output, window,
+// select/selection) to a TargetType.
+func ParseTarget(name string) (TargetType, error) {
+	switch name {
+	case "all", "full", "":
+		return TargetAll, nil
+	case "output":
+		return TargetOutput, nil
+	case "window":
+		return TargetWindow, nil
+	case "select", "selection":
+		return TargetSelection, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownTargetType, name)
+}
+
 type Driver interface {
 	Capture(ctx context.Context, rect *api.Rect) (image.Image, error)
 	SelectArea(ctx context.Context) (*api.Rect, error)
@@ -50,3 +50,20 @@ func TestResolveRectUnknownTarget(t *testing.T) {
 		t.Fatalf("err=%v want errors.Is(..., ErrUnknownTargetType)", err)
 	}
 }
+
+func TestParseTarget(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string]TargetType{
+		"": TargetAll, "all": TargetAll, "full": TargetAll,
+		"output": TargetOutput, "window": TargetWindow,
+		"select": TargetSelection, "selection": TargetSelection,
+	} {
+		got, err := ParseTarget(name)
+		if err != nil || got != want {
+			t.Errorf("ParseTarget(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseTarget("region"); !errors.Is(err, ErrUnknownTargetType) {
+		t.Errorf("ParseTarget(region) err = %v", err)
+	}
+}
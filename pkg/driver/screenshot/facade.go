@@ -2,6 +2,7 @@ package screenshot
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"image"
 	"os"
@@ -12,7 +13,6 @@ import (
 	"github.com/lucasew/workspaced/internal/atomicfile"
 	"github.com/lucasew/workspaced/internal/configcue"
 	"github.com/lucasew/workspaced/pkg/driver"
-	"github.com/lucasew/workspaced/pkg/driver/clipboard"
 	"github.com/lucasew/workspaced/pkg/driver/notification"
 	"github.com/lucasew/workspaced/pkg/driver/wm"
 	"github.com/lucasew/workspaced/pkg/logging"
@@ -38,30 +38,51 @@ func ResolveRect(ctx context.Context, targetType TargetType) (*wm.Rect, error) {
 	}
 }
 
+// Capture saves a screenshot of target and runs the post-capture actions
+// from screenshot.then (copy to clipboard by default).
 func Capture(ctx context.Context, targetType TargetType) (string, error) {
+	return CaptureThen(ctx, targetType, nil)
+}
+
+// CaptureThen is Capture running the named actions instead; nil means the
+// configured default.
+func CaptureThen(ctx context.Context, targetType TargetType, then []string) (string, error) {
 	d, err := driver.Get[Driver](ctx)
 	if err != nil {
 		return "", err
 	}
 
-	rect, err := ResolveRect(ctx, targetType)
+	cfg, err := configcue.LoadHome(ctx)
 	if err != nil {
 		return "", err
 	}
-
-	img, err := d.Capture(ctx, rect)
+	var screenshot struct {
+		Dir     string                     `json:"dir"`
+		Then    []string                   `json:"then"`
+		Actions map[string]json.RawMessage `json:"actions"`
+	}
+	if err := cfg.Decode("screenshot", &screenshot); err != nil {
+		return "", err
+	}
+	if then == nil {
+		then = screenshot.Then
+	}
+	if then == nil {
+		then = DefaultThen
+	}
+	// Fail on a typo before the user drags a selection.
+	actions, err := ResolveActions(screenshot.Actions, then)
 	if err != nil {
 		return "", err
 	}
 
-	cfg, err := configcue.LoadHome(ctx)
+	rect, err := ResolveRect(ctx, targetType)
 	if err != nil {
 		return "", err
 	}
-	var screenshot struct {
-		Dir string `json:"dir"`
-	}
-	if err := cfg.Decode("screenshot", &screenshot); err != nil {
+
+	img, err := d.Capture(ctx, rect)
+	if err != nil {
 		return "", err
 	}
 
@@ -81,34 +102,15 @@ func Capture(ctx context.Context, targetType TargetType) (string, error) {
 		return "", err
 	}
 
-	// Post-processing: Clipboard
-	go func() {
-		if err := clipboard.WriteImage(ctx, img); err != nil {
-			logging.ReportError(ctx, err)
-		}
-	}()
-
-	// Post-processing: Notification
 	notifySaved(ctx, path, targetType)
 
-	return path, nil
+	shot := &Shot{Path: path, Image: img, Target: targetType}
+	return path, RunActions(ctx, shot, actions)
 }
 
 func notifySaved(ctx context.Context, path string, target TargetType) {
-	strategy := "Unknown"
-	switch target {
-	case TargetAll:
-		strategy = "All screens"
-	case TargetOutput:
-		strategy = "Current monitor"
-	case TargetWindow:
-		strategy = "Current window"
-	case TargetSelection:
-		strategy = "Selected area"
-	}
-
 	n := notification.Notification{
-		Title:   fmt.Sprintf("Screenshot Saved (%s)", strategy),
+		Title:   fmt.Sprintf("Screenshot Saved (%s)", target.Label()),
 		Message: path,
 		Icon:    "camera-photo",
 	}
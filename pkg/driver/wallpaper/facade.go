@@ -19,28 +19,37 @@ import (
 	"github.com/lucasew/workspaced/pkg/logging"
 )
 
+// Random picks an image from desktop.wallpaper.dir.
+func Random(ctx context.Context) (string, error) {
+	cfg, err := configcue.LoadForWorkspace(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	var wallpaper struct {
+		Dir string `json:"dir"`
+	}
+	if err := cfg.Decode("desktop.wallpaper", &wallpaper); err != nil {
+		return "", err
+	}
+	wallpaperDir := wallpaper.Dir
+	files, err := filepath.Glob(filepath.Join(wallpaperDir, "*"))
+	if err != nil {
+		return "", fmt.Errorf("error when listing wallpaper candidates: %w", err)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("%w: wallpapers in %s", api.ErrNoTargetFound, wallpaperDir)
+	}
+	return files[rand.Intn(len(files))], nil
+}
+
+// SetStatic sets path as the wallpaper, or a Random one when path is "".
 func SetStatic(ctx context.Context, path string) error {
 	logger := logging.GetLogger(ctx)
 	if path == "" {
-		cfg, err := configcue.LoadForWorkspace(ctx, "")
-		if err != nil {
-			return err
-		}
-		var wallpaper struct {
-			Dir string `json:"dir"`
-		}
-		if err := cfg.Decode("desktop.wallpaper", &wallpaper); err != nil {
+		var err error
+		if path, err = Random(ctx); err != nil {
 			return err
 		}
-		wallpaperDir := wallpaper.Dir
-		files, err := filepath.Glob(filepath.Join(wallpaperDir, "*"))
-		if err != nil {
-			return fmt.Errorf("error when listing wallpaper candidates: %w", err)
-		}
-		if len(files) == 0 {
-			return fmt.Errorf("%w: wallpapers in %s", api.ErrNoTargetFound, wallpaperDir)
-		}
-		path = files[rand.Intn(len(files))]
 	}
 
 	logger.Info("setting wallpaper", "path", path)
@@ -77,7 +86,18 @@ type APODResponse struct {
 	URL   string `json:"url"`
 }
 
+// SetAPOD sets the NASA Astronomy Picture of the Day as the wallpaper.
 func SetAPOD(ctx context.Context) error {
+	path, err := FetchAPOD(ctx)
+	if err != nil {
+		return err
+	}
+	return SetStatic(ctx, path)
+}
+
+// FetchAPOD downloads the NASA Astronomy Picture of the Day and returns
+// where it was saved.
+func FetchAPOD(ctx context.Context) (string, error) {
 	logger := logging.GetLogger(ctx)
 	apiKey := os.Getenv("NASA_API_KEY")
 	if apiKey == "" {
@@ -88,22 +108,22 @@ func SetAPOD(ctx context.Context) error {
 
 	httpDriver, err := driver.Get[httpclient.Driver](ctx)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	apiURL := fmt.Sprintf("https://api.nasa.gov/planetary/apod?api_key=%s", apiKey)
 	resp, err := httpDriver.Client().Get(apiURL)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer logging.Close(ctx, resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GET %s: %s", apiURL, resp.Status)
+		return "", fmt.Errorf("GET %s: %s", apiURL, resp.Status)
 	}
 
 	var apod APODResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apod); err != nil {
-		return err
+		return "", err
 	}
 
 	url := apod.HDURL
@@ -111,33 +131,33 @@ func SetAPOD(ctx context.Context) error {
 		url = apod.URL
 	}
 	if url == "" {
-		return fmt.Errorf("APOD response missing image URL")
+		return "", fmt.Errorf("APOD response missing image URL")
 	}
 
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return "", err
 	}
 	cacheDir := filepath.Join(home, ".cache/workspaced")
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return err
+		return "", err
 	}
 	outPath := filepath.Join(cacheDir, "apod.jpg")
 
 	imgResp, err := httpDriver.Client().Get(url)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer logging.Close(ctx, imgResp.Body)
 	if imgResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GET %s: %s", url, imgResp.Status)
+		return "", fmt.Errorf("GET %s: %s", url, imgResp.Status)
 	}
 
 	// Temp + rename so a mid-download failure cannot truncate a previous good
 	// apod.jpg (wallpaper still points at that path after a failed refresh).
 	if err := atomicfile.Write(outPath, imgResp.Body, 0); err != nil {
-		return err
+		return "", err
 	}
 
-	return SetStatic(ctx, outPath)
+	return outPath, nil
 }
@@ -2,6 +2,7 @@ package wm
 
 import (
 	"context"
+	"encoding/json"
 )
 
 // Rect represents a geometry rectangle.
@@ -20,19 +21,66 @@ type Workspace struct {
 }
 
 // Output represents a display output as returned by Sway/i3 IPC.
+// Make, Model and Serial are empty on i3; Scale is 0 when unknown.
 type Output struct {
-	Name             string `json:"name"`
-	CurrentWorkspace string `json:"current_workspace"`
-	Rect             Rect   `json:"rect"`
-	Focused          bool   `json:"focused"`
+	Name             string  `json:"name"`
+	Make             string  `json:"make,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	Serial           string  `json:"serial,omitempty"`
+	Active           bool    `json:"active"`
+	Scale            float64 `json:"scale,omitempty"`
+	CurrentWorkspace string  `json:"current_workspace"`
+	Rect             Rect    `json:"rect"`
+	Focused          bool    `json:"focused"`
+}
+
+// Point is an output position in the global layout.
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// OutputConfig changes an output; zero fields are left alone.
+type OutputConfig struct {
+	Position *Point  `json:"position,omitempty"`
+	Scale    float64 `json:"scale,omitempty"`
 }
 
 // Node represents a node in the Sway/i3 tree.
 type Node struct {
-	Rect          Rect    `json:"rect"`
-	Focused       bool    `json:"focused"`
-	Nodes         []*Node `json:"nodes"`
-	FloatingNodes []*Node `json:"floating_nodes"`
+	ID               int64             `json:"id"`
+	Type             string            `json:"type"`
+	Name             string            `json:"name"`
+	AppID            string            `json:"app_id"`
+	PID              int               `json:"pid"`
+	Window           int64             `json:"window"`
+	WindowProperties *WindowProperties `json:"window_properties"`
+	Rect             Rect              `json:"rect"`
+	Focused          bool              `json:"focused"`
+	Nodes            []*Node           `json:"nodes"`
+	FloatingNodes    []*Node           `json:"floating_nodes"`
+}
+
+// WindowProperties are the X11 properties of an i3 (or Xwayland) window.
+type WindowProperties struct {
+	Class    string `json:"class"`
+	Instance string `json:"instance"`
+}
+
+// Window is a toplevel window. ID is the compositor's handle (con_id on
+// Sway/i3, the client address on Hyprland). AppID is the Wayland app id;
+// Class the X11 class (Hyprland reports the same value for both).
+type Window struct {
+	ID        int64  `json:"id"`
+	AppID     string `json:"app_id,omitempty"`
+	Class     string `json:"class,omitempty"`
+	Title     string `json:"title"`
+	Workspace string `json:"workspace"`
+	Output    string `json:"output,omitempty"`
+	Rect      Rect   `json:"rect"`
+	PID       int    `json:"pid,omitempty"`
+	Focused   bool   `json:"focused"`
+	Floating  bool   `json:"floating"`
 }
 
 type Driver interface {
@@ -43,4 +91,48 @@ type Driver interface {
 	GetOutputs(ctx context.Context) ([]Output, error)
 	GetWorkspaces(ctx context.Context) ([]Workspace, error)
 	MoveWorkspaceToOutput(ctx context.Context, workspace string, output string) error
+	ListWindows(ctx context.Context) ([]Window, error)
+	FocusWindow(ctx context.Context, id int64) error
+	MoveWindowToWorkspace(ctx context.Context, id int64, workspace string) error
+	CloseWindow(ctx context.Context, id int64) error
+}
+
+// EventKind selects a class of window manager events.
+type EventKind string
+
+const (
+	EventWorkspace EventKind = "workspace"
+	EventWindow    EventKind = "window"
+	EventOutput    EventKind = "output"
+)
+
+// Event is one window manager event. Change is the compositor's change
+// name ("focus", "init", "new", "close", ...); Workspace and Output are set
+// when the event names them. Payload is the raw event body.
+type Event struct {
+	Kind      EventKind       `json:"kind"`
+	Change    string          `json:"change"`
+	Workspace string          `json:"workspace,omitempty"`
+	Output    string          `json:"output,omitempty"`
+	WindowID  int64           `json:"window_id,omitempty"`
+	Title     string          `json:"title,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// Watcher is implemented by drivers that can stream events.
+type Watcher interface {
+	// Watch blocks and calls callback for each event of the given kinds
+	// (all kinds when empty) until ctx is done or the compositor exits.
+	Watch(ctx context.Context, kinds []EventKind, callback func(Event)) error
+}
+
+// OutputConfigurer is implemented by drivers that can move and scale outputs.
+type OutputConfigurer interface {
+	ConfigureOutput(ctx context.Context, output string, cfg OutputConfig) error
+}
+
+// WorkspaceAssigner is implemented by drivers that can pin a workspace to an
+// output, so it opens there even if it does not exist yet.
+type WorkspaceAssigner interface {
+	AssignWorkspace(ctx context.Context, workspace string, output string) error
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/lucasew/workspaced/internal/atomicfile"
+	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 	"github.com/lucasew/workspaced/pkg/driver/media"
 	"github.com/lucasew/workspaced/pkg/logging"
@@ -169,3 +170,17 @@ func GetFocusedWindowRect(ctx context.Context) (*Rect, error) {
 	}
 	return d.GetFocusedWindowRect(ctx)
 }
+
+// Watch streams window manager events to callback until ctx is done. It
+// returns api.ErrNotSupported when the active driver cannot watch.
+func Watch(ctx context.Context, callback func(Event), kinds ...EventKind) error {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return err
+	}
+	w, ok := d.(Watcher)
+	if !ok {
+		return fmt.Errorf("%w: %T cannot watch events", dapi.ErrNotSupported, d)
+	}
+	return w.Watch(ctx, kinds, callback)
+}
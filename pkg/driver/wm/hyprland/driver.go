@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+
 	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
@@ -27,10 +30,13 @@ func (f *Factory) CheckCompatibility(ctx context.Context) error {
 }
 
 func (f *Factory) New(ctx context.Context) (api.Driver, error) {
-	return &Driver{}, nil
+	return &Driver{EventSocket: eventSocketPath(ctx)}, nil
 }
 
-type Driver struct{}
+// Driver drives Hyprland through hyprctl; events come from socket2.
+type Driver struct {
+	EventSocket string
+}
 
 func (d *Driver) MoveWorkspaceToOutput(ctx context.Context, workspace string, output string) error {
 	return execdriver.MustRun(ctx, "hyprctl", "dispatch", "moveworkspacetomonitor", workspace, output).Run()
@@ -48,29 +54,52 @@ func (d *Driver) ToggleScratchpad(ctx context.Context) error {
 	return execdriver.MustRun(ctx, "hyprctl", "dispatch", "togglespecialworkspace").Run()
 }
 
-func (d *Driver) GetOutputs(ctx context.Context) ([]api.Output, error) {
+// monitor is an entry of `hyprctl monitors -j`.
+type monitor struct {
+	Name            string  `json:"name"`
+	Make            string  `json:"make"`
+	Model           string  `json:"model"`
+	Serial          string  `json:"serial"`
+	Scale           float64 `json:"scale"`
+	RefreshRate     float64 `json:"refreshRate"`
+	Transform       int     `json:"transform"`
+	Disabled        bool    `json:"disabled"`
+	Focused         bool    `json:"focused"`
+	X               int     `json:"x"`
+	Y               int     `json:"y"`
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	ActiveWorkspace struct {
+		Name string `json:"name"`
+	} `json:"activeWorkspace"`
+}
+
+func monitors(ctx context.Context) ([]monitor, error) {
 	out, err := execdriver.MustRun(ctx, "hyprctl", "monitors", "-j").Output()
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
 	}
-	var monitors []struct {
-		Name            string `json:"name"`
-		Focused         bool   `json:"focused"`
-		X               int    `json:"x"`
-		Y               int    `json:"y"`
-		Width           int    `json:"width"`
-		Height          int    `json:"height"`
-		ActiveWorkspace struct {
-			Name string `json:"name"`
-		} `json:"activeWorkspace"`
-	}
-	if err := json.Unmarshal(out, &monitors); err != nil {
+	var ms []monitor
+	if err := json.Unmarshal(out, &ms); err != nil {
 		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
 	}
+	return ms, nil
+}
+
+func (d *Driver) GetOutputs(ctx context.Context) ([]api.Output, error) {
+	ms, err := monitors(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var outputs []api.Output
-	for _, m := range monitors {
+	for _, m := range ms {
 		outputs = append(outputs, api.Output{
 			Name:             m.Name,
+			Make:             m.Make,
+			Model:            m.Model,
+			Serial:           m.Serial,
+			Active:           !m.Disabled,
+			Scale:            m.Scale,
 			Focused:          m.Focused,
 			CurrentWorkspace: m.ActiveWorkspace.Name,
 			Rect:             api.Rect{X: m.X, Y: m.Y, Width: m.Width, Height: m.Height},
@@ -79,6 +108,48 @@ func (d *Driver) GetOutputs(ctx context.Context) ([]api.Output, error) {
 	return outputs, nil
 }
 
+// ConfigureOutput re-declares the monitor rule with the new position and
+// scale, keeping the current mode, transform and whatever cfg leaves unset.
+func (d *Driver) ConfigureOutput(ctx context.Context, output string, cfg api.OutputConfig) error {
+	ms, err := monitors(ctx)
+	if err != nil {
+		return err
+	}
+	var current *monitor
+	for i := range ms {
+		if ms[i].Name == output && !ms[i].Disabled {
+			current = &ms[i]
+		}
+	}
+	return execdriver.MustRun(ctx, "hyprctl", "keyword", "monitor", monitorRule(output, current, cfg)).Run()
+}
+
+// monitorRule renders a monitor keyword for output. Values not set in cfg
+// come from current, or "preferred"/"auto" when the monitor is unknown.
+func monitorRule(output string, current *monitor, cfg api.OutputConfig) string {
+	mode, position, scale := "preferred", "auto", "auto"
+	if current != nil {
+		mode = fmt.Sprintf("%dx%d@%s", current.Width, current.Height, strconv.FormatFloat(current.RefreshRate, 'f', 2, 64))
+		position = fmt.Sprintf("%dx%d", current.X, current.Y)
+		scale = strconv.FormatFloat(current.Scale, 'f', -1, 64)
+	}
+	if cfg.Position != nil {
+		position = fmt.Sprintf("%dx%d", cfg.Position.X, cfg.Position.Y)
+	}
+	if cfg.Scale > 0 {
+		scale = strconv.FormatFloat(cfg.Scale, 'f', -1, 64)
+	}
+	fields := []string{output, mode, position, scale}
+	if current != nil && current.Transform != 0 {
+		fields = append(fields, "transform", strconv.Itoa(current.Transform))
+	}
+	return strings.Join(fields, ",")
+}
+
+func (d *Driver) AssignWorkspace(ctx context.Context, workspace string, output string) error {
+	return execdriver.MustRun(ctx, "hyprctl", "keyword", "workspace", workspace+",monitor:"+output).Run()
+}
+
 func (d *Driver) GetWorkspaces(ctx context.Context) ([]api.Workspace, error) {
 	out, err := execdriver.MustRun(ctx, "hyprctl", "workspaces", "-j").Output()
 	if err != nil {
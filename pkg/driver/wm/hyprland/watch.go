@@ -0,0 +1,118 @@
+package hyprland
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/executil"
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// eventSocketPath locates socket2 of the running instance:
+// $XDG_RUNTIME_DIR/hypr/<sig> on current Hyprland, /tmp/hypr/<sig> before.
+func eventSocketPath(ctx context.Context) string {
+	sig := executil.GetEnv(ctx, "HYPRLAND_INSTANCE_SIGNATURE")
+	path := filepath.Join(executil.GetEnv(ctx, "XDG_RUNTIME_DIR"), "hypr", sig, ".socket2.sock")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return filepath.Join(os.TempDir(), "hypr", sig, ".socket2.sock")
+}
+
+// Watch reads socket2 ("event>>data" lines) and delivers the workspace,
+// window and monitor events among kinds (all when empty).
+func (d *Driver) Watch(ctx context.Context, kinds []api.EventKind, callback func(api.Event)) error {
+	for _, k := range kinds {
+		if k != api.EventWorkspace && k != api.EventWindow && k != api.EventOutput {
+			return fmt.Errorf("%w: event kind %q", dapi.ErrNotSupported, k)
+		}
+	}
+	var dialer net.Dialer
+	c, err := dialer.DialContext(ctx, "unix", d.EventSocket)
+	if err != nil {
+		return fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	defer logging.Close(ctx, c)
+	stop := context.AfterFunc(ctx, func() { _ = c.SetDeadline(time.Now()) })
+	defer stop()
+
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		ev, ok := parseEvent(scanner.Text())
+		if ok && (len(kinds) == 0 || slices.Contains(kinds, ev.Kind)) {
+			callback(ev)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	return nil
+}
+
+// parseEvent maps one socket2 line to an api.Event.
+func parseEvent(line string) (api.Event, bool) {
+	name, data, found := strings.Cut(line, ">>")
+	if !found {
+		return api.Event{}, false
+	}
+	field := func(n int) string {
+		parts := strings.SplitN(data, ",", n+2)
+		if n < len(parts) {
+			return parts[n]
+		}
+		return ""
+	}
+	window := func(change string) api.Event {
+		id, _ := strconv.ParseInt(field(0), 16, 64)
+		return api.Event{Kind: api.EventWindow, Change: change, WindowID: id}
+	}
+	switch name {
+	case "monitoradded":
+		return api.Event{Kind: api.EventOutput, Change: "added", Output: data}, true
+	case "monitorremoved":
+		return api.Event{Kind: api.EventOutput, Change: "removed", Output: data}, true
+	case "workspace":
+		return api.Event{Kind: api.EventWorkspace, Change: "focus", Workspace: data}, true
+	case "focusedmon":
+		return api.Event{Kind: api.EventWorkspace, Change: "focus", Output: field(0), Workspace: field(1)}, true
+	case "createworkspace":
+		return api.Event{Kind: api.EventWorkspace, Change: "init", Workspace: data}, true
+	case "destroyworkspace":
+		return api.Event{Kind: api.EventWorkspace, Change: "empty", Workspace: data}, true
+	case "moveworkspace":
+		return api.Event{Kind: api.EventWorkspace, Change: "move", Workspace: field(0), Output: field(1)}, true
+	case "openwindow":
+		// ADDRESS,WORKSPACE,CLASS,TITLE (the title may contain commas).
+		ev := window("new")
+		ev.Workspace = field(1)
+		if parts := strings.SplitN(data, ",", 4); len(parts) == 4 {
+			ev.Title = parts[3]
+		}
+		return ev, true
+	case "closewindow":
+		return window("close"), true
+	case "activewindowv2":
+		return window("focus"), true
+	case "windowtitle":
+		return window("title"), true
+	case "movewindow":
+		ev := window("move")
+		ev.Workspace = field(1)
+		return ev, true
+	}
+	return api.Event{}, false
+}
@@ -0,0 +1,68 @@
+package hyprland
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		line string
+		want api.Event
+		ok   bool
+	}{
+		{"monitoradded>>DP-3", api.Event{Kind: api.EventOutput, Change: "added", Output: "DP-3"}, true},
+		{"monitorremoved>>DP-3", api.Event{Kind: api.EventOutput, Change: "removed", Output: "DP-3"}, true},
+		{"focusedmon>>eDP-1,2", api.Event{Kind: api.EventWorkspace, Change: "focus", Output: "eDP-1", Workspace: "2"}, true},
+		{"workspace>>3", api.Event{Kind: api.EventWorkspace, Change: "focus", Workspace: "3"}, true},
+		{"openwindow>>55d1f0a0,1,kitty,vim a.go, b.go", api.Event{Kind: api.EventWindow, Change: "new", WindowID: 0x55d1f0a0, Workspace: "1", Title: "vim a.go, b.go"}, true},
+		{"closewindow>>55d1f0a0", api.Event{Kind: api.EventWindow, Change: "close", WindowID: 0x55d1f0a0}, true},
+		{"submap>>resize", api.Event{}, false},
+		{"garbage", api.Event{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := parseEvent(tc.line)
+		if ok != tc.ok || got.Kind != tc.want.Kind || got.Change != tc.want.Change ||
+			got.Output != tc.want.Output || got.Workspace != tc.want.Workspace ||
+			got.WindowID != tc.want.WindowID || got.Title != tc.want.Title {
+			t.Errorf("parseEvent(%q) = %+v, %v; want %+v, %v", tc.line, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestWatchFiltersKinds(t *testing.T) {
+	t.Parallel()
+	socket := filepath.Join(t.TempDir(), ".socket2.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = c.Write([]byte("workspace>>2\nmonitoradded>>DP-3\nactivewindowv2>>abc\nmonitorremoved>>DP-3\n"))
+		_ = c.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(logging.NewWriterContext(t.Output()))
+	defer cancel()
+	var got []string
+	d := &Driver{EventSocket: socket}
+	if err := d.Watch(ctx, []api.EventKind{api.EventOutput}, func(ev api.Event) {
+		got = append(got, ev.Change+" "+ev.Output)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "added DP-3" || got[1] != "removed DP-3" {
+		t.Fatalf("events = %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package hyprland
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+)
+
+type client struct {
+	Address   string `json:"address"`
+	Mapped    bool   `json:"mapped"`
+	At        []int  `json:"at"`
+	Size      []int  `json:"size"`
+	Workspace struct {
+		Name string `json:"name"`
+	} `json:"workspace"`
+	Floating bool   `json:"floating"`
+	Class    string `json:"class"`
+	Title    string `json:"title"`
+	PID      int    `json:"pid"`
+}
+
+func (d *Driver) ListWindows(ctx context.Context) ([]api.Window, error) {
+	out, err := execdriver.MustRun(ctx, "hyprctl", "clients", "-j").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	var clients []client
+	if err := json.Unmarshal(out, &clients); err != nil {
+		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	active, err := execdriver.MustRun(ctx, "hyprctl", "activewindow", "-j").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	var focused struct {
+		Address string `json:"address"`
+	}
+	// No focused window prints "{}" or an "Invalid" line; neither is fatal.
+	_ = json.Unmarshal(active, &focused)
+	return windowsFromClients(clients, focused.Address), nil
+}
+
+func windowsFromClients(clients []client, focused string) []api.Window {
+	var out []api.Window
+	for _, c := range clients {
+		if !c.Mapped {
+			continue
+		}
+		id, err := parseAddress(c.Address)
+		if err != nil {
+			continue
+		}
+		w := api.Window{
+			ID:        id,
+			AppID:     c.Class,
+			Class:     c.Class,
+			Title:     c.Title,
+			Workspace: c.Workspace.Name,
+			PID:       c.PID,
+			Focused:   c.Address == focused,
+			Floating:  c.Floating,
+		}
+		if len(c.At) == 2 && len(c.Size) == 2 {
+			w.Rect = api.Rect{X: c.At[0], Y: c.At[1], Width: c.Size[0], Height: c.Size[1]}
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+func parseAddress(addr string) (int64, error) {
+	return strconv.ParseInt(strings.TrimPrefix(addr, "0x"), 16, 64)
+}
+
+func address(id int64) string {
+	return "address:0x" + strconv.FormatInt(id, 16)
+}
+
+func (d *Driver) FocusWindow(ctx context.Context, id int64) error {
+	return execdriver.MustRun(ctx, "hyprctl", "dispatch", "focuswindow", address(id)).Run()
+}
+
+func (d *Driver) MoveWindowToWorkspace(ctx context.Context, id int64, workspace string) error {
+	return execdriver.MustRun(ctx, "hyprctl", "dispatch", "movetoworkspacesilent", workspace+","+address(id)).Run()
+}
+
+func (d *Driver) CloseWindow(ctx context.Context, id int64) error {
+	return execdriver.MustRun(ctx, "hyprctl", "dispatch", "closewindow", address(id)).Run()
+}
@@ -0,0 +1,57 @@
+package hyprland
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+)
+
+func TestWindowsFromClients(t *testing.T) {
+	t.Parallel()
+	const reply = `[
+  {"address":"0x55d1c0a0","mapped":true,"at":[10,20],"size":[800,600],"workspace":{"id":1,"name":"1"},"floating":false,"class":"kitty","title":"~","pid":100},
+  {"address":"0x55d1c0b0","mapped":false,"class":"hidden"},
+  {"address":"0x55d1c0c0","mapped":true,"at":[0,0],"size":[300,200],"workspace":{"id":-98,"name":"special:scratch"},"floating":true,"class":"pavucontrol","title":"Volume","pid":101},
+  {"address":"garbage","mapped":true,"class":"broken"}
+]`
+	var clients []client
+	if err := json.Unmarshal([]byte(reply), &clients); err != nil {
+		t.Fatal(err)
+	}
+	got := windowsFromClients(clients, "0x55d1c0c0")
+	want := []api.Window{
+		{ID: 0x55d1c0a0, AppID: "kitty", Class: "kitty", Title: "~", Workspace: "1", PID: 100, Rect: api.Rect{X: 10, Y: 20, Width: 800, Height: 600}},
+		{ID: 0x55d1c0c0, AppID: "pavucontrol", Class: "pavucontrol", Title: "Volume", Workspace: "special:scratch", PID: 101, Focused: true, Floating: true, Rect: api.Rect{Width: 300, Height: 200}},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("windows =\n%+v\nwant\n%+v", got, want)
+	}
+	if a := address(got[0].ID); a != "address:0x55d1c0a0" {
+		t.Fatalf("address = %q", a)
+	}
+}
+
+func TestMonitorRule(t *testing.T) {
+	t.Parallel()
+	const reply = `[{"name":"DP-3","width":2560,"height":1440,"refreshRate":143.97200,"x":1920,"y":0,"scale":1.25,"transform":1}]`
+	var ms []monitor
+	if err := json.Unmarshal([]byte(reply), &ms); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		current *monitor
+		cfg     api.OutputConfig
+		want    string
+	}{
+		{&ms[0], api.OutputConfig{}, "DP-3,2560x1440@143.97,1920x0,1.25,transform,1"},
+		{&ms[0], api.OutputConfig{Position: &api.Point{X: 0, Y: 0}}, "DP-3,2560x1440@143.97,0x0,1.25,transform,1"},
+		{&ms[0], api.OutputConfig{Scale: 1}, "DP-3,2560x1440@143.97,1920x0,1,transform,1"},
+		{nil, api.OutputConfig{Scale: 2}, "DP-3,preferred,auto,2"},
+	} {
+		if got := monitorRule("DP-3", tt.current, tt.cfg); got != tt.want {
+			t.Errorf("monitorRule(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+}
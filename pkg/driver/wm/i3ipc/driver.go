@@ -2,8 +2,11 @@ package i3ipc
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/executil"
 	dapi "github.com/lucasew/workspaced/pkg/api"
 	"github.com/lucasew/workspaced/pkg/driver"
 	execdriver "github.com/lucasew/workspaced/pkg/driver/exec"
@@ -20,11 +23,14 @@ type SwayFactory struct{}
 func (f *SwayFactory) ID() string   { return "screen_wayland_sway" }
 func (f *SwayFactory) Name() string { return "Sway" }
 func (f *SwayFactory) CheckCompatibility(ctx context.Context) error {
-	return execdriver.RequireEnvBinary(ctx, "WAYLAND_DISPLAY", "swaymsg")
+	if err := driver.RequireEnv(ctx, "WAYLAND_DISPLAY"); err != nil {
+		return err
+	}
+	return driver.RequireEnv(ctx, "SWAYSOCK")
 }
 
 func (f *SwayFactory) New(ctx context.Context) (api.Driver, error) {
-	return &Driver{Binary: "swaymsg"}, nil
+	return &Driver{Socket: executil.GetEnv(ctx, "SWAYSOCK"), Sway: true}, nil
 }
 
 type I3Factory struct{}
@@ -32,52 +38,81 @@ type I3Factory struct{}
 func (f *I3Factory) ID() string   { return "screen_x11_i3" }
 func (f *I3Factory) Name() string { return "i3" }
 func (f *I3Factory) CheckCompatibility(ctx context.Context) error {
-	return execdriver.RequireEnvBinary(ctx, "DISPLAY", "i3-msg")
+	if err := driver.RequireEnv(ctx, "DISPLAY"); err != nil {
+		return err
+	}
+	if executil.GetEnv(ctx, "I3SOCK") != "" {
+		return nil
+	}
+	return execdriver.RequireBinary(ctx, "i3")
 }
 
 func (f *I3Factory) New(ctx context.Context) (api.Driver, error) {
-	return &Driver{Binary: "i3-msg"}, nil
+	socket := executil.GetEnv(ctx, "I3SOCK")
+	if socket == "" {
+		// i3 exports I3SOCK to its children; ask it when started elsewhere.
+		out, err := execdriver.MustRun(ctx, "i3", "--get-socketpath").Output()
+		if err != nil {
+			return nil, fmt.Errorf("%w: i3 socket path: %w", dapi.ErrIPC, err)
+		}
+		socket = strings.TrimSpace(string(out))
+	}
+	return &Driver{Socket: socket}, nil
 }
 
+// Driver talks to i3 or sway over the IPC socket.
 type Driver struct {
-	Binary string
+	Socket string
+	// Sway enables the sway-only commands (output configuration).
+	Sway bool
 }
 
 func (d *Driver) MoveWorkspaceToOutput(ctx context.Context, workspace string, output string) error {
-	return execdriver.MustRun(ctx, d.Binary, fmt.Sprintf("[workspace=%q] move workspace to output %q", workspace, output)).Run()
+	return runCommand(ctx, d.Socket, fmt.Sprintf("[workspace=%s] move workspace to output %s", quote(workspace), quote(output)))
 }
 
 func (d *Driver) SwitchToWorkspace(ctx context.Context, ws string, move bool) error {
 	if move {
-		return execdriver.MustRun(ctx, d.Binary, "move", "container", "to", "workspace", ws).Run()
+		return runCommand(ctx, d.Socket, "move container to workspace "+quote(ws))
 	}
-	return execdriver.MustRun(ctx, d.Binary, "workspace", ws).Run()
+	return runCommand(ctx, d.Socket, "workspace "+quote(ws))
 }
 
 func (d *Driver) ToggleScratchpad(ctx context.Context) error {
-	return execdriver.MustRun(ctx, d.Binary, "scratchpad", "show").Run()
+	return runCommand(ctx, d.Socket, "scratchpad show")
 }
 
 func (d *Driver) GetOutputs(ctx context.Context) ([]api.Output, error) {
-	return getIPCJSON[[]api.Output](ctx, d.Binary, "get_outputs")
+	return requestJSON[[]api.Output](ctx, d.Socket, msgGetOutputs, nil)
 }
 
 func (d *Driver) GetWorkspaces(ctx context.Context) ([]api.Workspace, error) {
-	return getIPCJSON[[]api.Workspace](ctx, d.Binary, "get_workspaces")
+	return requestJSON[[]api.Workspace](ctx, d.Socket, msgGetWorkspaces, nil)
 }
 
-// getIPCJSON runs `binary -t <msg>` and unmarshals the JSON reply into T.
-func getIPCJSON[T any](ctx context.Context, binary, msg string) (T, error) {
-	var zero T
-	out, err := execdriver.MustRun(ctx, binary, "-t", msg).Output()
-	if err != nil {
-		return zero, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+// ConfigureOutput positions and scales an output. Only sway has an output
+// command; i3 leaves that to xrandr.
+func (d *Driver) ConfigureOutput(ctx context.Context, output string, cfg api.OutputConfig) error {
+	if !d.Sway {
+		return fmt.Errorf("%w: i3 cannot configure outputs", dapi.ErrNotSupported)
 	}
-	var v T
-	if err := json.Unmarshal(out, &v); err != nil {
-		return zero, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	cmd := "output " + quote(output)
+	if cfg.Position != nil {
+		cmd += fmt.Sprintf(" position %d %d", cfg.Position.X, cfg.Position.Y)
 	}
-	return v, nil
+	if cfg.Scale > 0 {
+		cmd += " scale " + strconv.FormatFloat(cfg.Scale, 'f', -1, 64)
+	}
+	return runCommand(ctx, d.Socket, cmd)
+}
+
+func (d *Driver) AssignWorkspace(ctx context.Context, workspace string, output string) error {
+	return runCommand(ctx, d.Socket, fmt.Sprintf("workspace %s output %s", quote(workspace), quote(output)))
+}
+
+// quote renders s as an i3 command string argument.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
 }
 
 func (d *Driver) GetFocusedOutput(ctx context.Context) (string, *api.Rect, error) {
@@ -117,14 +152,9 @@ func (d *Driver) GetFocusedOutput(ctx context.Context) (string, *api.Rect, error
 }
 
 func (d *Driver) GetFocusedWindowRect(ctx context.Context) (*api.Rect, error) {
-	out, err := execdriver.MustRun(ctx, d.Binary, "-t", "get_tree").Output()
+	root, err := requestJSON[api.Node](ctx, d.Socket, msgGetTree, nil)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
-	}
-
-	var root api.Node
-	if err := json.Unmarshal(out, &root); err != nil {
-		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+		return nil, err
 	}
 
 	found := findFocusedNode(&root)
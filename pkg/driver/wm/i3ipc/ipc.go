@@ -0,0 +1,169 @@
+package i3ipc
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// i3 IPC wire format: "i3-ipc", payload length and message type as native
+// endian uint32, then the JSON payload. Event replies set the high bit of
+// the type. See https://i3wm.org/docs/ipc.html (sway speaks the same).
+const magic = "i3-ipc"
+
+const (
+	msgRunCommand    uint32 = 0
+	msgGetWorkspaces uint32 = 1
+	msgSubscribe     uint32 = 2
+	msgGetOutputs    uint32 = 3
+	msgGetTree       uint32 = 4
+)
+
+const (
+	eventBit uint32 = 1 << 31
+
+	eventWorkspace = eventBit | 0
+	eventOutput    = eventBit | 1
+	eventWindow    = eventBit | 3
+	eventShutdown  = eventBit | 6
+)
+
+// maxPayload bounds a single reply; trees of large sessions are a few MB.
+const maxPayload = 64 << 20
+
+var errPayloadTooLarge = errors.New("i3 ipc payload too large")
+
+// conn is one IPC connection. Requests are strictly request/reply, so a
+// conn must not be shared between goroutines.
+type conn struct {
+	c net.Conn
+}
+
+func dial(ctx context.Context, socket string) (*conn, error) {
+	if socket == "" {
+		return nil, fmt.Errorf("%w: no IPC socket", dapi.ErrIPC)
+	}
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	return &conn{c: c}, nil
+}
+
+func (c *conn) Close() error {
+	return c.c.Close()
+}
+
+// request sends one message and reads its reply. Events received on a
+// subscribed connection are skipped.
+func (c *conn) request(ctx context.Context, typ uint32, payload []byte) ([]byte, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := c.c.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+		defer func() { _ = c.c.SetDeadline(time.Time{}) }()
+	}
+	stop := context.AfterFunc(ctx, func() { _ = c.c.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := writeMessage(c.c, typ, payload); err != nil {
+		return nil, c.wrap(ctx, err)
+	}
+	for {
+		got, reply, err := readMessage(c.c)
+		if err != nil {
+			return nil, c.wrap(ctx, err)
+		}
+		if got == typ {
+			return reply, nil
+		}
+		if got&eventBit == 0 {
+			return nil, fmt.Errorf("%w: reply type %d to request %d", dapi.ErrIPC, got, typ)
+		}
+	}
+}
+
+func (c *conn) wrap(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+}
+
+func writeMessage(w io.Writer, typ uint32, payload []byte) error {
+	buf := make([]byte, 0, len(magic)+8+len(payload))
+	buf = append(buf, magic...)
+	buf = binary.NativeEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = binary.NativeEndian.AppendUint32(buf, typ)
+	buf = append(buf, payload...)
+	_, err := w.Write(buf)
+	return err
+}
+
+func readMessage(r io.Reader) (uint32, []byte, error) {
+	var header [len(magic) + 8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	if string(header[:len(magic)]) != magic {
+		return 0, nil, fmt.Errorf("bad magic %q", header[:len(magic)])
+	}
+	size := binary.NativeEndian.Uint32(header[len(magic):])
+	typ := binary.NativeEndian.Uint32(header[len(magic)+4:])
+	if size > maxPayload {
+		return 0, nil, fmt.Errorf("%w: %d bytes", errPayloadTooLarge, size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return typ, payload, nil
+}
+
+// requestJSON sends one message on a fresh connection and decodes the reply.
+func requestJSON[T any](ctx context.Context, socket string, typ uint32, payload []byte) (T, error) {
+	var zero T
+	c, err := dial(ctx, socket)
+	if err != nil {
+		return zero, err
+	}
+	defer logging.Close(ctx, c)
+	reply, err := c.request(ctx, typ, payload)
+	if err != nil {
+		return zero, err
+	}
+	var v T
+	if err := json.Unmarshal(reply, &v); err != nil {
+		return zero, fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+	}
+	return v, nil
+}
+
+type commandResult struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// runCommand executes an i3/sway command and fails when any of its
+// sub-commands did.
+func runCommand(ctx context.Context, socket, command string) error {
+	results, err := requestJSON[[]commandResult](ctx, socket, msgRunCommand, []byte(command))
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if !r.Success {
+			return fmt.Errorf("%w: %s: %s", dapi.ErrIPC, command, r.Error)
+		}
+	}
+	return nil
+}
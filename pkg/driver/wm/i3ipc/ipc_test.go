@@ -0,0 +1,235 @@
+package i3ipc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+type fakeEvent struct {
+	typ  uint32
+	body string
+}
+
+// fakeWM is an i3 IPC server answering from canned replies.
+type fakeWM struct {
+	socket   string
+	replies  map[uint32]string
+	events   []fakeEvent // sent after SUBSCRIBE
+	mu       sync.Mutex
+	commands []string
+	subs     []string
+}
+
+func newFakeWM(t *testing.T) *fakeWM {
+	t.Helper()
+	f := &fakeWM{
+		socket:  filepath.Join(t.TempDir(), "ipc.sock"),
+		replies: map[uint32]string{},
+	}
+	l, err := net.Listen("unix", f.socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(c)
+		}
+	}()
+	return f
+}
+
+func (f *fakeWM) serve(c net.Conn) {
+	defer func() { _ = c.Close() }()
+	for {
+		typ, payload, err := readMessage(c)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		switch typ {
+		case msgRunCommand:
+			f.commands = append(f.commands, string(payload))
+		case msgSubscribe:
+			f.subs = append(f.subs, string(payload))
+		}
+		reply, ok := f.replies[typ]
+		f.mu.Unlock()
+		if !ok {
+			reply = `[{"success":true}]`
+			if typ == msgSubscribe {
+				reply = `{"success":true}`
+			}
+		}
+		if err := writeMessage(c, typ, []byte(reply)); err != nil {
+			return
+		}
+		if typ == msgSubscribe {
+			for _, ev := range f.events {
+				if err := writeMessage(c, ev.typ, []byte(ev.body)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestDriverQueries(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	wm := newFakeWM(t)
+	wm.replies[msgGetWorkspaces] = `[{"name":"1","focused":false,"output":"DP-1"},{"name":"2","focused":true,"output":"HDMI-A-1"}]`
+	wm.replies[msgGetOutputs] = `[{"name":"DP-1","current_workspace":"1","rect":{"x":0,"y":0,"width":1920,"height":1080}},{"name":"HDMI-A-1","current_workspace":"2","rect":{"x":1920,"y":0,"width":1280,"height":1024}}]`
+	wm.replies[msgGetTree] = `{"rect":{"width":3200},"nodes":[{"rect":{"x":1},"nodes":[{"focused":true,"rect":{"x":1930,"y":10,"width":600,"height":400}}]}]}`
+	d := &Driver{Socket: wm.socket}
+
+	name, rect, err := d.GetFocusedOutput(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "HDMI-A-1" || rect.X != 1920 || rect.Width != 1280 {
+		t.Fatalf("focused output = %s %+v", name, rect)
+	}
+	win, err := d.GetFocusedWindowRect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *win != (api.Rect{X: 1930, Y: 10, Width: 600, Height: 400}) {
+		t.Fatalf("focused window = %+v", win)
+	}
+}
+
+func TestDriverCommands(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	wm := newFakeWM(t)
+	d := &Driver{Socket: wm.socket}
+
+	steps := []error{
+		d.SwitchToWorkspace(ctx, "3", false),
+		d.SwitchToWorkspace(ctx, `web "main"`, true),
+		d.MoveWorkspaceToOutput(ctx, "1", "DP-1"),
+		d.ToggleScratchpad(ctx),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	want := []string{
+		`workspace "3"`,
+		`move container to workspace "web \"main\""`,
+		`[workspace="1"] move workspace to output "DP-1"`,
+		"scratchpad show",
+	}
+	wm.mu.Lock()
+	commands := slices.Clone(wm.commands)
+	wm.mu.Unlock()
+	if !slices.Equal(commands, want) {
+		t.Fatalf("commands = %q, want %q", commands, want)
+	}
+
+	wm.mu.Lock()
+	wm.replies[msgRunCommand] = `[{"success":false,"error":"No output matched"}]`
+	wm.mu.Unlock()
+	err := d.MoveWorkspaceToOutput(ctx, "1", "nope")
+	if !errors.Is(err, dapi.ErrIPC) {
+		t.Fatalf("failed command error = %v, want ErrIPC", err)
+	}
+}
+
+func TestDriverConfigureOutput(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	wm := newFakeWM(t)
+	cfg := api.OutputConfig{Position: &api.Point{X: 1920, Y: 0}, Scale: 1.5}
+
+	i3 := &Driver{Socket: wm.socket}
+	if err := i3.ConfigureOutput(ctx, "DP-1", cfg); !errors.Is(err, dapi.ErrNotSupported) {
+		t.Fatalf("i3 ConfigureOutput = %v, want ErrNotSupported", err)
+	}
+	sway := &Driver{Socket: wm.socket, Sway: true}
+	if err := sway.ConfigureOutput(ctx, "DP-1", cfg); err != nil {
+		t.Fatal(err)
+	}
+	wm.mu.Lock()
+	commands := slices.Clone(wm.commands)
+	wm.mu.Unlock()
+	if want := []string{`output "DP-1" position 1920 0 scale 1.5`}; !slices.Equal(commands, want) {
+		t.Fatalf("commands = %q, want %q", commands, want)
+	}
+}
+
+func TestDriverWatch(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	wm := newFakeWM(t)
+	wm.events = []fakeEvent{
+		{eventWorkspace, `{"change":"focus","current":{"name":"2","output":"HDMI-A-1"},"old":{"name":"1"}}`},
+		{eventBit | 5, `{"change":"run"}`},
+		{eventOutput, `{"change":"unspecified"}`},
+		{eventWindow, `{"change":"new","container":{"id":42,"name":"kitty"}}`},
+		{eventShutdown, `{"change":"exit"}`},
+	}
+	d := &Driver{Socket: wm.socket}
+
+	var got []api.Event
+	if err := d.Watch(ctx, nil, func(ev api.Event) { got = append(got, ev) }); err != nil {
+		t.Fatal(err)
+	}
+	wm.mu.Lock()
+	subs := slices.Clone(wm.subs)
+	wm.mu.Unlock()
+	if want := `["shutdown","workspace","window","output"]`; len(subs) != 1 || subs[0] != want {
+		t.Fatalf("subscribe payload = %q, want %s", subs, want)
+	}
+	if len(got) != 3 {
+		t.Fatalf("events = %+v, want 3", got)
+	}
+	if got[0].Kind != api.EventWorkspace || got[0].Change != "focus" || got[0].Workspace != "2" || got[0].Output != "HDMI-A-1" {
+		t.Errorf("workspace event = %+v", got[0])
+	}
+	if got[1].Kind != api.EventOutput || got[1].Change != "unspecified" {
+		t.Errorf("output event = %+v", got[1])
+	}
+	if got[2].Kind != api.EventWindow || got[2].WindowID != 42 || got[2].Title != "kitty" {
+		t.Errorf("window event = %+v", got[2])
+	}
+}
+
+func TestDriverWatchCancel(t *testing.T) {
+	t.Parallel()
+	wm := newFakeWM(t)
+	d := &Driver{Socket: wm.socket}
+	ctx, cancel := context.WithCancel(logging.NewWriterContext(t.Output()))
+	done := make(chan error, 1)
+	go func() { done <- d.Watch(ctx, []api.EventKind{api.EventOutput}, func(api.Event) {}) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Watch after cancel = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+
+	if err := d.Watch(logging.NewWriterContext(t.Output()), []api.EventKind{"mode"}, nil); !errors.Is(err, dapi.ErrNotSupported) {
+		t.Fatalf("unknown kind error = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package i3ipc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+var eventNames = map[api.EventKind]string{
+	api.EventWorkspace: "workspace",
+	api.EventWindow:    "window",
+	api.EventOutput:    "output",
+}
+
+// Watch subscribes to workspace/window/output events (all when kinds is
+// empty) and delivers them until ctx is done or the compositor shuts down.
+func (d *Driver) Watch(ctx context.Context, kinds []api.EventKind, callback func(api.Event)) error {
+	if len(kinds) == 0 {
+		kinds = []api.EventKind{api.EventWorkspace, api.EventWindow, api.EventOutput}
+	}
+	names := []string{"shutdown"}
+	for _, k := range kinds {
+		name, ok := eventNames[k]
+		if !ok {
+			return fmt.Errorf("%w: event kind %q", dapi.ErrNotSupported, k)
+		}
+		names = append(names, name)
+	}
+	payload, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	c, err := dial(ctx, d.Socket)
+	if err != nil {
+		return err
+	}
+	defer logging.Close(ctx, c)
+	reply, err := c.request(ctx, msgSubscribe, payload)
+	if err != nil {
+		return err
+	}
+	var ok commandResult
+	if err := json.Unmarshal(reply, &ok); err != nil || !ok.Success {
+		return fmt.Errorf("%w: subscribe %s rejected: %s", dapi.ErrIPC, payload, reply)
+	}
+
+	stop := context.AfterFunc(ctx, func() { _ = c.c.SetDeadline(time.Now()) })
+	defer stop()
+	for {
+		typ, body, err := readMessage(c.c)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%w: %w", dapi.ErrIPC, err)
+		}
+		if typ == eventShutdown {
+			return nil
+		}
+		ev, ok, err := decodeEvent(typ, body)
+		if err != nil {
+			logging.GetLogger(ctx).Warn("skipping malformed wm event", "type", typ, "error", err)
+			continue
+		}
+		if ok {
+			callback(ev)
+		}
+	}
+}
+
+type ipcContainer struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Output string `json:"output"`
+}
+
+// decodeEvent maps an IPC event to api.Event; ok is false for event types
+// that were not asked for.
+func decodeEvent(typ uint32, body []byte) (api.Event, bool, error) {
+	var raw struct {
+		Change    string        `json:"change"`
+		Current   *ipcContainer `json:"current"`
+		Container *ipcContainer `json:"container"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return api.Event{}, false, err
+	}
+	ev := api.Event{Change: raw.Change, Payload: json.RawMessage(body)}
+	switch typ {
+	case eventWorkspace:
+		ev.Kind = api.EventWorkspace
+		if raw.Current != nil {
+			ev.Workspace, ev.Output = raw.Current.Name, raw.Current.Output
+		}
+	case eventWindow:
+		ev.Kind = api.EventWindow
+		if raw.Container != nil {
+			ev.WindowID, ev.Title = raw.Container.ID, raw.Container.Name
+		}
+	case eventOutput:
+		ev.Kind = api.EventOutput
+	default:
+		return api.Event{}, false, nil
+	}
+	return ev, true, nil
+}
@@ -0,0 +1,71 @@
+package i3ipc
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+)
+
+func (d *Driver) ListWindows(ctx context.Context) ([]api.Window, error) {
+	root, err := requestJSON[api.Node](ctx, d.Socket, msgGetTree, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []api.Window
+	collectWindows(&root, "", "", false, &out)
+	return out, nil
+}
+
+// collectWindows walks the tree in order, tracking the enclosing output and
+// workspace. Leaves backed by a client (app_id, X11 window or pid) are windows.
+func collectWindows(n *api.Node, output, workspace string, floating bool, out *[]api.Window) {
+	switch n.Type {
+	case "output":
+		output = n.Name
+	case "workspace":
+		workspace = n.Name
+	}
+	if isClient(n) {
+		w := api.Window{
+			ID:        n.ID,
+			AppID:     n.AppID,
+			Title:     n.Name,
+			Workspace: workspace,
+			Output:    output,
+			Rect:      n.Rect,
+			PID:       n.PID,
+			Focused:   n.Focused,
+			Floating:  floating,
+		}
+		if n.WindowProperties != nil {
+			w.Class = n.WindowProperties.Class
+		}
+		*out = append(*out, w)
+	}
+	for _, c := range n.Nodes {
+		collectWindows(c, output, workspace, floating, out)
+	}
+	for _, c := range n.FloatingNodes {
+		collectWindows(c, output, workspace, true, out)
+	}
+}
+
+func isClient(n *api.Node) bool {
+	if n.Type != "con" && n.Type != "floating_con" {
+		return false
+	}
+	return len(n.Nodes) == 0 && (n.AppID != "" || n.Window != 0 || n.PID > 0)
+}
+
+func (d *Driver) FocusWindow(ctx context.Context, id int64) error {
+	return runCommand(ctx, d.Socket, fmt.Sprintf("[con_id=%d] focus", id))
+}
+
+func (d *Driver) MoveWindowToWorkspace(ctx context.Context, id int64, workspace string) error {
+	return runCommand(ctx, d.Socket, fmt.Sprintf("[con_id=%d] move container to workspace %s", id, quote(workspace)))
+}
+
+func (d *Driver) CloseWindow(ctx context.Context, id int64) error {
+	return runCommand(ctx, d.Socket, fmt.Sprintf("[con_id=%d] kill", id))
+}
@@ -0,0 +1,77 @@
+package i3ipc
+
+import (
+	"slices"
+	"testing"
+
+	api "github.com/lucasew/workspaced/pkg/driver/wm"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+const testTree = `{"id":1,"type":"root","name":"root","nodes":[
+  {"id":2,"type":"output","name":"__i3","nodes":[]},
+  {"id":3,"type":"output","name":"DP-1","nodes":[
+    {"id":4,"type":"workspace","name":"1","nodes":[
+      {"id":5,"type":"con","name":"split","nodes":[
+        {"id":6,"type":"con","name":"~","app_id":"kitty","pid":100,"focused":true,"rect":{"x":0,"y":0,"width":960,"height":1080}},
+        {"id":7,"type":"con","name":"Firefox","window":4194307,"pid":101,"window_properties":{"class":"firefox","instance":"Navigator"}}
+      ]}
+    ],"floating_nodes":[
+      {"id":8,"type":"floating_con","name":"pavucontrol","app_id":"pavucontrol","pid":102}
+    ]}
+  ]},
+  {"id":9,"type":"output","name":"HDMI-A-1","nodes":[
+    {"id":10,"type":"workspace","name":"2","nodes":[
+      {"id":11,"type":"con","name":"placeholder","nodes":[]}
+    ]}
+  ]}
+]}`
+
+func TestListWindows(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	wm := newFakeWM(t)
+	wm.replies[msgGetTree] = testTree
+	d := &Driver{Socket: wm.socket}
+
+	windows, err := d.ListWindows(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []api.Window{
+		{ID: 6, AppID: "kitty", Title: "~", Workspace: "1", Output: "DP-1", PID: 100, Focused: true, Rect: api.Rect{Width: 960, Height: 1080}},
+		{ID: 7, Class: "firefox", Title: "Firefox", Workspace: "1", Output: "DP-1", PID: 101},
+		{ID: 8, AppID: "pavucontrol", Title: "pavucontrol", Workspace: "1", Output: "DP-1", PID: 102, Floating: true},
+	}
+	if !slices.Equal(windows, want) {
+		t.Fatalf("windows =\n%+v\nwant\n%+v", windows, want)
+	}
+}
+
+func TestWindowCommands(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	wm := newFakeWM(t)
+	d := &Driver{Socket: wm.socket}
+
+	for i, err := range []error{
+		d.FocusWindow(ctx, 6),
+		d.MoveWindowToWorkspace(ctx, 7, "web"),
+		d.CloseWindow(ctx, 8),
+	} {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	want := []string{
+		"[con_id=6] focus",
+		`[con_id=7] move container to workspace "web"`,
+		"[con_id=8] kill",
+	}
+	wm.mu.Lock()
+	commands := slices.Clone(wm.commands)
+	wm.mu.Unlock()
+	if !slices.Equal(commands, want) {
+		t.Fatalf("commands = %q, want %q", commands, want)
+	}
+}
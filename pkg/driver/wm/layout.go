@@ -0,0 +1,413 @@
+package wm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lucasew/workspaced/internal/configcue"
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// ErrNoLayout is returned when no configured layout fits the connected outputs.
+var ErrNoLayout = errors.New("no layout matches the connected outputs")
+
+// OutputMatch selects a connected output. Every set field is a regular
+// expression that must match the whole corresponding Output field.
+type OutputMatch struct {
+	Name   string `json:"name,omitempty"`
+	Make   string `json:"make,omitempty"`
+	Model  string `json:"model,omitempty"`
+	Serial string `json:"serial,omitempty"`
+}
+
+// LayoutOutput is one output of a layout: how to find it, where to put it
+// and which workspaces live on it.
+type LayoutOutput struct {
+	Match      OutputMatch `json:"match"`
+	Position   *Point      `json:"position,omitempty"`
+	Scale      float64     `json:"scale,omitempty"`
+	Workspaces []string    `json:"workspaces,omitempty"`
+}
+
+// Layout is workspaced.desktop.layouts.<name>: a set of outputs that must
+// all be connected. With Exact (the default) no other output may be.
+type Layout struct {
+	Outputs map[string]LayoutOutput `json:"outputs"`
+	Exact   bool                    `json:"exact"`
+}
+
+// LayoutPlan is a layout bound to the connected outputs.
+type LayoutPlan struct {
+	Layout string `json:"layout"`
+	// Outputs maps layout output keys to connected output names.
+	Outputs map[string]string `json:"outputs"`
+	// Configure holds position/scale changes by connected output name.
+	Configure map[string]OutputConfig `json:"configure,omitempty"`
+	// Workspaces maps WM workspace names to connected output names.
+	Workspaces map[string]string `json:"workspaces,omitempty"`
+}
+
+// LayoutConfig is the layout part of the config.
+type LayoutConfig struct {
+	Layouts map[string]Layout
+	// Aliases are workspaced.workspaces (name -> workspace number), so
+	// layouts can list "www" instead of "1".
+	Aliases map[string]int
+}
+
+// LoadLayoutConfig reads desktop.layouts and workspaces; ok is false when
+// no layout is configured.
+func LoadLayoutConfig(ctx context.Context) (LayoutConfig, bool, error) {
+	cfg, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return LayoutConfig{}, false, fmt.Errorf("load config: %w", err)
+	}
+	var lc LayoutConfig
+	if _, err := cfg.Lookup("desktop.layouts"); err != nil {
+		return lc, false, nil
+	}
+	if err := cfg.Decode("desktop.layouts", &lc.Layouts); err != nil {
+		return lc, false, fmt.Errorf("decode desktop.layouts: %w", err)
+	}
+	if _, err := cfg.Lookup("workspaces"); err == nil {
+		if err := cfg.Decode("workspaces", &lc.Aliases); err != nil {
+			return lc, false, fmt.Errorf("decode workspaces: %w", err)
+		}
+	}
+	return lc, len(lc.Layouts) > 0, nil
+}
+
+// Plan picks the layout for the active outputs. When name is empty the
+// matching layout with the most outputs wins (ties by name); otherwise only
+// the named layout is tried.
+func (lc LayoutConfig) Plan(outputs []Output, name string) (*LayoutPlan, error) {
+	var active []Output
+	for _, o := range outputs {
+		if o.Active {
+			active = append(active, o)
+		}
+	}
+	names := make([]string, 0, len(lc.Layouts))
+	for n := range lc.Layouts {
+		if name == "" || n == name {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: layout %q not configured", ErrNoLayout, name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		a, b := len(lc.Layouts[names[i]].Outputs), len(lc.Layouts[names[j]].Outputs)
+		if a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+	for _, n := range names {
+		bound, err := bindOutputs(lc.Layouts[n], active)
+		if err != nil {
+			return nil, fmt.Errorf("layout %s: %w", n, err)
+		}
+		if bound != nil {
+			return lc.plan(n, bound), nil
+		}
+	}
+	connected := make([]string, 0, len(active))
+	for _, o := range active {
+		connected = append(connected, o.Name)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNoLayout, connected)
+}
+
+func (lc LayoutConfig) plan(name string, bound map[string]string) *LayoutPlan {
+	l := lc.Layouts[name]
+	p := &LayoutPlan{Layout: name, Outputs: bound, Configure: map[string]OutputConfig{}, Workspaces: map[string]string{}}
+	for key, lo := range l.Outputs {
+		out := bound[key]
+		if lo.Position != nil || lo.Scale > 0 {
+			p.Configure[out] = OutputConfig{Position: lo.Position, Scale: lo.Scale}
+		}
+		for _, ws := range lo.Workspaces {
+			if n, ok := lc.Aliases[ws]; ok {
+				ws = strconv.Itoa(n)
+			}
+			p.Workspaces[ws] = out
+		}
+	}
+	return p
+}
+
+// bindOutputs assigns each layout output a distinct connected output, or
+// returns nil when the layout does not fit. Outputs are tried in key order
+// with backtracking, so a broad match cannot steal a narrow one's output.
+func bindOutputs(l Layout, outputs []Output) (map[string]string, error) {
+	if len(l.Outputs) > len(outputs) || (l.Exact && len(l.Outputs) != len(outputs)) {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(l.Outputs))
+	for k := range l.Outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	candidates := make([][]string, len(keys))
+	for i, k := range keys {
+		for _, o := range outputs {
+			ok, err := l.Outputs[k].Match.matches(o)
+			if err != nil {
+				return nil, fmt.Errorf("output %s: %w", k, err)
+			}
+			if ok {
+				candidates[i] = append(candidates[i], o.Name)
+			}
+		}
+	}
+	bound := map[string]string{}
+	var assign func(i int) bool
+	assign = func(i int) bool {
+		if i == len(keys) {
+			return true
+		}
+		for _, name := range candidates[i] {
+			taken := false
+			for _, used := range bound {
+				taken = taken || used == name
+			}
+			if taken {
+				continue
+			}
+			bound[keys[i]] = name
+			if assign(i + 1) {
+				return true
+			}
+			delete(bound, keys[i])
+		}
+		return false
+	}
+	if !assign(0) {
+		return nil, nil
+	}
+	return bound, nil
+}
+
+func (m OutputMatch) matches(o Output) (bool, error) {
+	for _, f := range [][2]string{{m.Name, o.Name}, {m.Make, o.Make}, {m.Model, o.Model}, {m.Serial, o.Serial}} {
+		if f[0] == "" {
+			continue
+		}
+		re, err := regexp.Compile(`^(?:` + f[0] + `)$`)
+		if err != nil {
+			return false, err
+		}
+		if !re.MatchString(f[1]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ApplyLayout plans the configured layout for the connected outputs and
+// applies it: outputs are positioned/scaled when the driver supports it,
+// workspaces are pinned to (and moved onto) their outputs, and focus
+// returns to the workspace that had it. name forces a layout.
+func ApplyLayout(ctx context.Context, lc LayoutConfig, name string) (*LayoutPlan, error) {
+	wmMu.Lock()
+	defer wmMu.Unlock()
+
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := d.GetOutputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := lc.Plan(outputs, name)
+	if err != nil {
+		return nil, err
+	}
+	return plan, applyPlan(ctx, d, outputs, plan)
+}
+
+// PlanLayout is ApplyLayout without changing anything.
+func PlanLayout(ctx context.Context, lc LayoutConfig, name string) (*LayoutPlan, error) {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := d.GetOutputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lc.Plan(outputs, name)
+}
+
+// applyPlan applies plan on top of outputs, the state it was planned from.
+// Outputs already at their planned position and scale are not configured
+// again: on sway that would emit an output event and retrigger WatchLayouts.
+func applyPlan(ctx context.Context, d Driver, outputs []Output, plan *LayoutPlan) error {
+	logger := logging.GetLogger(ctx)
+	var errs []error
+
+	if len(plan.Configure) > 0 {
+		current := map[string]Output{}
+		for _, o := range outputs {
+			current[o.Name] = o
+		}
+		c, ok := d.(OutputConfigurer)
+		for _, out := range slices.Sorted(maps.Keys(plan.Configure)) {
+			if !ok {
+				break
+			}
+			cfg := plan.Configure[out]
+			if o, found := current[out]; found && outputConfigured(o, cfg) {
+				continue
+			}
+			err := c.ConfigureOutput(ctx, out, cfg)
+			if errors.Is(err, dapi.ErrNotSupported) {
+				ok = false
+			} else if err != nil {
+				errs = append(errs, fmt.Errorf("configure %s: %w", out, err))
+			}
+		}
+		if !ok {
+			logger.Warn("driver cannot position outputs, skipping", "driver", fmt.Sprintf("%T", d))
+		}
+	}
+
+	workspaces, err := d.GetWorkspaces(ctx)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	var focused string
+	existing := map[string]string{}
+	for _, w := range workspaces {
+		existing[w.Name] = w.Output
+		if w.Focused {
+			focused = w.Name
+		}
+	}
+	assigner, _ := d.(WorkspaceAssigner)
+	for _, ws := range slices.Sorted(maps.Keys(plan.Workspaces)) {
+		out := plan.Workspaces[ws]
+		if assigner != nil {
+			if err := assigner.AssignWorkspace(ctx, ws, out); err != nil {
+				errs = append(errs, fmt.Errorf("assign workspace %s: %w", ws, err))
+			}
+		}
+		current, ok := existing[ws]
+		if !ok || current == out {
+			continue
+		}
+		logger.Debug("moving workspace", "workspace", ws, "from", current, "to", out)
+		if err := d.MoveWorkspaceToOutput(ctx, ws, out); err != nil {
+			errs = append(errs, fmt.Errorf("move workspace %s: %w", ws, err))
+		}
+	}
+	if focused != "" {
+		if err := d.SwitchToWorkspace(ctx, focused, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// outputConfigured reports whether o already matches every field cfg sets.
+func outputConfigured(o Output, cfg OutputConfig) bool {
+	if cfg.Position != nil && (o.Rect.X != cfg.Position.X || o.Rect.Y != cfg.Position.Y) {
+		return false
+	}
+	return cfg.Scale <= 0 || math.Abs(o.Scale-cfg.Scale) < 1e-3
+}
+
+// hotplugDebounce coalesces the burst of output events a dock produces.
+const hotplugDebounce = 750 * time.Millisecond
+
+// The event stream is resubscribed after it drops (e.g. the compositor
+// restarted), waiting between watchRetryMin and watchRetryMax.
+const (
+	watchRetryMin = time.Second
+	watchRetryMax = time.Minute
+)
+
+// WatchLayouts applies the configured layout once and again after every
+// output hotplug, until ctx is done. It returns quietly when no layout is
+// configured or the driver cannot watch events.
+func WatchLayouts(ctx context.Context) {
+	logger := logging.GetLogger(ctx)
+	lc, ok, err := LoadLayoutConfig(ctx)
+	if err != nil {
+		logger.Warn("layout watcher disabled", "error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	apply := func() {
+		if ctx.Err() != nil {
+			return
+		}
+		plan, err := ApplyLayout(ctx, lc, "")
+		if err != nil {
+			if errors.Is(err, ErrNoLayout) {
+				logger.Info("no layout for outputs", "error", err)
+				return
+			}
+			logging.ReportError(ctx, err, "op", "apply layout")
+			return
+		}
+		logger.Info("layout applied", "layout", plan.Layout, "outputs", plan.Outputs)
+	}
+	apply()
+
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+	debounce := func(Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(hotplugDebounce, apply)
+	}
+	defer context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+	})()
+
+	delay := watchRetryMin
+	for {
+		started := time.Now()
+		err := Watch(ctx, debounce, EventOutput)
+		if ctx.Err() != nil || errors.Is(err, dapi.ErrNotSupported) {
+			return
+		}
+		if time.Since(started) > watchRetryMax {
+			delay = watchRetryMin
+		}
+		logger.Warn("layout watcher stopped, resubscribing", "error", err, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, watchRetryMax)
+		// Outputs may have changed while nobody was listening.
+		apply()
+	}
+}
@@ -0,0 +1,207 @@
+package wm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+var testLayouts = LayoutConfig{
+	Aliases: map[string]int{"www": 1, "meet": 2},
+	Layouts: map[string]Layout{
+		"laptop": {Exact: true, Outputs: map[string]LayoutOutput{
+			"internal": {Match: OutputMatch{Name: "eDP-.*"}, Scale: 1.5, Workspaces: []string{"www", "meet", "3"}},
+		}},
+		"docked": {Exact: true, Outputs: map[string]LayoutOutput{
+			"internal": {Match: OutputMatch{Name: "eDP-.*"}, Position: &Point{X: 0, Y: 1440}, Workspaces: []string{"meet"}},
+			"dell":     {Match: OutputMatch{Make: "Dell Inc.", Model: "U27.*"}, Position: &Point{X: 0, Y: 0}, Workspaces: []string{"www", "3"}},
+		}},
+		// Any two monitors; must lose to "docked" when the Dell is there.
+		"two": {Exact: true, Outputs: map[string]LayoutOutput{
+			"a": {Match: OutputMatch{}},
+			"b": {Match: OutputMatch{}},
+		}},
+	},
+}
+
+var (
+	edp  = Output{Name: "eDP-1", Active: true}
+	dell = Output{Name: "DP-3", Make: "Dell Inc.", Model: "U2720Q", Active: true, Rect: Rect{X: 1920}}
+	lg   = Output{Name: "HDMI-A-1", Make: "LG", Model: "27GL850", Active: true}
+)
+
+func TestLayoutPlan(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		outputs []Output
+		force   string
+		want    string
+	}{
+		{"laptop only", []Output{edp}, "", "laptop"},
+		{"inactive outputs ignored", []Output{edp, {Name: "DP-9", Make: "Dell Inc.", Model: "U2720Q"}}, "", "laptop"},
+		{"dell docked", []Output{dell, edp}, "", "docked"},
+		{"other monitor", []Output{lg, edp}, "", "two"},
+		{"forced", []Output{dell, edp}, "two", "two"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			plan, err := testLayouts.Plan(tc.outputs, tc.force)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Layout != tc.want {
+				t.Fatalf("layout = %s, want %s", plan.Layout, tc.want)
+			}
+		})
+	}
+
+	plan, err := testLayouts.Plan([]Output{edp, dell}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Outputs["dell"] != "DP-3" || plan.Outputs["internal"] != "eDP-1" {
+		t.Fatalf("outputs = %v", plan.Outputs)
+	}
+	if plan.Workspaces["1"] != "DP-3" || plan.Workspaces["3"] != "DP-3" || plan.Workspaces["2"] != "eDP-1" {
+		t.Fatalf("workspaces = %v (aliases must resolve)", plan.Workspaces)
+	}
+	if p := plan.Configure["eDP-1"].Position; p == nil || p.Y != 1440 {
+		t.Fatalf("configure = %v", plan.Configure)
+	}
+
+	if _, err := testLayouts.Plan([]Output{edp, dell, lg}, ""); !errors.Is(err, ErrNoLayout) {
+		t.Fatalf("three outputs: err = %v, want ErrNoLayout", err)
+	}
+	bad := LayoutConfig{Layouts: map[string]Layout{"x": {Outputs: map[string]LayoutOutput{"a": {Match: OutputMatch{Name: "("}}}}}}
+	if _, err := bad.Plan([]Output{edp}, ""); err == nil || errors.Is(err, ErrNoLayout) {
+		t.Fatalf("bad regexp: err = %v", err)
+	}
+}
+
+// fakeDriver records layout operations.
+type fakeDriver struct {
+	Driver
+	workspaces   []Workspace
+	calls        []string
+	configureErr error
+}
+
+func (f *fakeDriver) GetWorkspaces(context.Context) ([]Workspace, error) { return f.workspaces, nil }
+
+func (f *fakeDriver) MoveWorkspaceToOutput(_ context.Context, ws, out string) error {
+	f.calls = append(f.calls, "move "+ws+" "+out)
+	return nil
+}
+
+func (f *fakeDriver) SwitchToWorkspace(_ context.Context, ws string, move bool) error {
+	f.calls = append(f.calls, "focus "+ws)
+	return nil
+}
+
+func (f *fakeDriver) ConfigureOutput(_ context.Context, out string, cfg OutputConfig) error {
+	f.calls = append(f.calls, "configure "+out)
+	return f.configureErr
+}
+
+func (f *fakeDriver) AssignWorkspace(_ context.Context, ws, out string) error {
+	f.calls = append(f.calls, "assign "+ws+" "+out)
+	return nil
+}
+
+func TestApplyPlan(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	plan, err := testLayouts.Plan([]Output{edp, dell}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &fakeDriver{workspaces: []Workspace{
+		{Name: "1", Output: "eDP-1"},
+		{Name: "2", Output: "eDP-1", Focused: true},
+		{Name: "7", Output: "DP-3"},
+	}}
+	if err := applyPlan(ctx, d, []Output{edp, dell}, plan); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"configure DP-3",
+		"configure eDP-1",
+		"assign 1 DP-3",
+		"move 1 DP-3", // "2" is already home, "3" does not exist yet
+		"assign 2 eDP-1",
+		"assign 3 DP-3",
+		"focus 2",
+	}
+	if !slices.Equal(d.calls, want) {
+		t.Fatalf("calls = %q\nwant %q", d.calls, want)
+	}
+}
+
+func TestApplyPlanOutputsNotSupported(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	plan, err := testLayouts.Plan([]Output{edp, dell}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &fakeDriver{
+		workspaces:   []Workspace{{Name: "2", Output: "eDP-1", Focused: true}},
+		configureErr: fmt.Errorf("%w: i3 cannot configure outputs", dapi.ErrNotSupported),
+	}
+	if err := applyPlan(ctx, d, []Output{edp, dell}, plan); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"configure DP-3", // the rest are skipped once the driver says no
+		"assign 1 DP-3",
+		"assign 2 eDP-1",
+		"assign 3 DP-3",
+		"focus 2",
+	}
+	if !slices.Equal(d.calls, want) {
+		t.Fatalf("calls = %q\nwant %q", d.calls, want)
+	}
+}
+
+func TestApplyPlanSkipsConfiguredOutputs(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	plan, err := testLayouts.Plan([]Output{edp, dell}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// What the WM reports once the plan has been applied.
+	docked := []Output{
+		{Name: "eDP-1", Active: true, Scale: 1, Rect: Rect{Y: 1440}},
+		{Name: "DP-3", Make: "Dell Inc.", Model: "U2720Q", Active: true, Scale: 1},
+	}
+	d := &fakeDriver{workspaces: []Workspace{{Name: "2", Output: "eDP-1", Focused: true}}}
+	if err := applyPlan(ctx, d, docked, plan); err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range d.calls {
+		if strings.HasPrefix(call, "configure ") {
+			t.Fatalf("calls = %q: outputs already match the plan", d.calls)
+		}
+	}
+
+	laptop, err := testLayouts.Plan([]Output{edp}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d = &fakeDriver{}
+	if err := applyPlan(ctx, d, []Output{{Name: "eDP-1", Active: true, Scale: 1.5}}, laptop); err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(d.calls, "configure eDP-1") {
+		t.Fatalf("calls = %q: scale already matches", d.calls)
+	}
+}
@@ -0,0 +1,176 @@
+package wm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	dapi "github.com/lucasew/workspaced/pkg/api"
+	"github.com/lucasew/workspaced/pkg/driver"
+)
+
+// Criteria selects windows. String fields are regular expressions matched
+// anywhere in the field (anchor with ^...$); empty fields match anything.
+// AppID also matches Class, so one pattern covers Wayland and X11 clients.
+type Criteria struct {
+	AppID     string
+	Title     string
+	Workspace string
+	// ID selects a single window when non-zero.
+	ID int64
+	// Focused restricts the match to the focused window.
+	Focused bool
+}
+
+type compiledCriteria struct {
+	Criteria
+	appID, title, workspace *regexp.Regexp
+}
+
+func (c Criteria) compile() (*compiledCriteria, error) {
+	out := &compiledCriteria{Criteria: c}
+	for _, f := range []struct {
+		name    string
+		pattern string
+		dst     **regexp.Regexp
+	}{
+		{"app id", c.AppID, &out.appID},
+		{"title", c.Title, &out.title},
+		{"workspace", c.Workspace, &out.workspace},
+	} {
+		if f.pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(f.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s criteria: %w", f.name, err)
+		}
+		*f.dst = re
+	}
+	return out, nil
+}
+
+func (c *compiledCriteria) match(w Window) bool {
+	if c.ID != 0 && w.ID != c.ID {
+		return false
+	}
+	if c.Focused && !w.Focused {
+		return false
+	}
+	if c.appID != nil && !c.appID.MatchString(w.AppID) && !c.appID.MatchString(w.Class) {
+		return false
+	}
+	if c.title != nil && !c.title.MatchString(w.Title) {
+		return false
+	}
+	if c.workspace != nil && !c.workspace.MatchString(w.Workspace) {
+		return false
+	}
+	return true
+}
+
+// FilterWindows returns the windows matching c, in input order.
+func FilterWindows(windows []Window, c Criteria) ([]Window, error) {
+	cc, err := c.compile()
+	if err != nil {
+		return nil, err
+	}
+	var out []Window
+	for _, w := range windows {
+		if cc.match(w) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+// ListWindows returns the windows matching c.
+func ListWindows(ctx context.Context, c Criteria) ([]Window, error) {
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := d.ListWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FilterWindows(windows, c)
+}
+
+// FocusWindow focuses the first window matching c and returns it. When the
+// focused window itself matches, the next match is focused instead, so
+// repeated calls cycle through the matches.
+func FocusWindow(ctx context.Context, c Criteria) (*Window, error) {
+	wmMu.Lock()
+	defer wmMu.Unlock()
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := d.ListWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := FilterWindows(windows, c)
+	if err != nil {
+		return nil, err
+	}
+	target := nextMatch(matches)
+	if target == nil {
+		return nil, fmt.Errorf("%w: no window matches", dapi.ErrNoTargetFound)
+	}
+	return target, d.FocusWindow(ctx, target.ID)
+}
+
+// nextMatch is the match after the focused one (wrapping), else the first.
+func nextMatch(matches []Window) *Window {
+	if len(matches) == 0 {
+		return nil
+	}
+	for i, w := range matches {
+		if w.Focused {
+			return &matches[(i+1)%len(matches)]
+		}
+	}
+	return &matches[0]
+}
+
+// MoveWindows moves every window matching c to workspace and returns them.
+func MoveWindows(ctx context.Context, c Criteria, workspace string) ([]Window, error) {
+	return eachWindow(ctx, c, func(d Driver, w Window) error {
+		return d.MoveWindowToWorkspace(ctx, w.ID, workspace)
+	})
+}
+
+// CloseWindows closes every window matching c and returns them.
+func CloseWindows(ctx context.Context, c Criteria) ([]Window, error) {
+	return eachWindow(ctx, c, func(d Driver, w Window) error {
+		return d.CloseWindow(ctx, w.ID)
+	})
+}
+
+func eachWindow(ctx context.Context, c Criteria, fn func(Driver, Window) error) ([]Window, error) {
+	wmMu.Lock()
+	defer wmMu.Unlock()
+	d, err := driver.Get[Driver](ctx)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := d.ListWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := FilterWindows(windows, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: no window matches", dapi.ErrNoTargetFound)
+	}
+	for _, w := range matches {
+		if err := fn(d, w); err != nil {
+			return nil, fmt.Errorf("window %d: %w", w.ID, err)
+		}
+	}
+	return matches, nil
+}
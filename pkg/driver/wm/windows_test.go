@@ -0,0 +1,74 @@
+package wm
+
+import (
+	"slices"
+	"testing"
+)
+
+var testWindows = []Window{
+	{ID: 1, AppID: "kitty", Title: "~", Workspace: "1"},
+	{ID: 2, Class: "firefox", Title: "YouTube - Mozilla Firefox", Workspace: "2", Focused: true},
+	{ID: 3, AppID: "kitty", Title: "vim main.go", Workspace: "3"},
+	{ID: 4, AppID: "org.gnome.Nautilus", Title: "Home", Workspace: "10"},
+}
+
+func windowIDs(ws []Window) []int64 {
+	var ids []int64
+	for _, w := range ws {
+		ids = append(ids, w.ID)
+	}
+	return ids
+}
+
+func TestFilterWindows(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		c    Criteria
+		want []int64
+	}{
+		{"empty matches all", Criteria{}, []int64{1, 2, 3, 4}},
+		{"app id", Criteria{AppID: "^kitty$"}, []int64{1, 3}},
+		{"app id matches class", Criteria{AppID: "firefox"}, []int64{2}},
+		{"title", Criteria{Title: `\.go$`}, []int64{3}},
+		{"workspace unanchored", Criteria{Workspace: "1"}, []int64{1, 4}},
+		{"workspace anchored", Criteria{Workspace: "^1$"}, []int64{1}},
+		{"focused", Criteria{Focused: true}, []int64{2}},
+		{"id", Criteria{ID: 4}, []int64{4}},
+		{"combined", Criteria{AppID: "kitty", Workspace: "^3$"}, []int64{3}},
+		{"none", Criteria{AppID: "kitty", Focused: true}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FilterWindows(testWindows, tc.c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := windowIDs(got); !slices.Equal(ids, tc.want) {
+				t.Fatalf("ids = %v, want %v", ids, tc.want)
+			}
+		})
+	}
+
+	if _, err := FilterWindows(testWindows, Criteria{Title: "("}); err == nil {
+		t.Fatal("invalid regex accepted")
+	}
+}
+
+func TestNextMatch(t *testing.T) {
+	t.Parallel()
+	if nextMatch(nil) != nil {
+		t.Fatal("nextMatch(nil) != nil")
+	}
+	kitties := []Window{{ID: 1}, {ID: 3}}
+	if got := nextMatch(kitties); got.ID != 1 {
+		t.Fatalf("unfocused: got %d, want 1", got.ID)
+	}
+	kitties[0].Focused = true
+	if got := nextMatch(kitties); got.ID != 3 {
+		t.Fatalf("after 1: got %d, want 3", got.ID)
+	}
+	kitties[0].Focused, kitties[1].Focused = false, true
+	if got := nextMatch(kitties); got.ID != 1 {
+		t.Fatalf("wrap: got %d, want 1", got.ID)
+	}
+}
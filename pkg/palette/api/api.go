@@ -9,6 +9,9 @@ import (
 // ErrDriverNotFound is returned by Get for unknown palette driver names.
 var ErrDriverNotFound = errors.New("palette driver not found")
 
+// ErrInvalidHex is returned by ParseHex for malformed RRGGBB strings.
+var ErrInvalidHex = errors.New("invalid hex color")
+
 // LAB represents a color in CIELAB color space.
 // L is lightness [0-100]; A is green-red; B is blue-yellow.
 type LAB struct {
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"cmp"
+	"fmt"
 	"image"
 	"image/color"
 	"math"
+	"slices"
 	"strings"
 )
 
@@ -12,6 +15,36 @@ func NormalizeHex(s string) string {
 	return strings.TrimPrefix(s, "#")
 }
 
+// ParseHex parses RRGGBB (with or without '#') into an opaque color.
+func ParseHex(s string) (color.RGBA, error) {
+	h := NormalizeHex(strings.TrimSpace(s))
+	if len(h) != 6 {
+		return color.RGBA{}, fmt.Errorf("%w: %q", ErrInvalidHex, s)
+	}
+	var v [3]uint8
+	for i := range v {
+		hi, ok1 := hexNibble(h[2*i])
+		lo, ok2 := hexNibble(h[2*i+1])
+		if !ok1 || !ok2 {
+			return color.RGBA{}, fmt.Errorf("%w: %q", ErrInvalidHex, s)
+		}
+		v[i] = hi<<4 | lo
+	}
+	return color.RGBA{R: v[0], G: v[1], B: v[2], A: 255}, nil
+}
+
+func hexNibble(c byte) (uint8, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
 // PaletteFromHexes builds a Palette from hex strings (with or without '#').
 // At most 24 entries are used; missing entries leave the corresponding field empty.
 func PaletteFromHexes(hexes []string) *Palette {
@@ -175,6 +208,17 @@ func Lightness(c color.Color) float64 {
 	return RGBToLAB(c).L
 }
 
+// Variant returns "light" when p's background (Base00) is lighter than its
+// foreground (Base05), else "dark".
+func Variant(p *Palette) string {
+	bg, errBG := ParseHex(p.Base00)
+	fg, errFG := ParseHex(p.Base05)
+	if errBG == nil && errFG == nil && Lightness(bg) > Lightness(fg) {
+		return "light"
+	}
+	return "dark"
+}
+
 // SampleImage extracts unique opaque colors from img.
 // When maxSamples > 0 and the image is larger, pixels are strided so at most
 // about maxSamples positions are visited.
@@ -294,3 +338,11 @@ func sampleGeneric(img image.Image, bounds image.Rectangle, stride int, colorSet
 		}
 	}
 }
+
+// SortRGBA orders colors by packed RGB. SampleImage returns colors in map
+// order; deterministic drivers sort before using them.
+func SortRGBA(colors []color.RGBA) {
+	slices.SortFunc(colors, func(a, b color.RGBA) int {
+		return cmp.Compare(uint32(a.R)<<16|uint32(a.G)<<8|uint32(a.B), uint32(b.R)<<16|uint32(b.G)<<8|uint32(b.B))
+	})
+}
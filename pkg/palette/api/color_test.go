@@ -22,6 +22,25 @@ func TestNormalizeHex(t *testing.T) {
 	}
 }
 
+func TestParseHex(t *testing.T) {
+	t.Parallel()
+	got, err := ParseHex("#4285F4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (color.RGBA{R: 0x42, G: 0x85, B: 0xf4, A: 255}); got != want {
+		t.Fatalf("ParseHex = %#v, want %#v", got, want)
+	}
+	if ToHex(got) != "4285f4" {
+		t.Fatalf("round trip = %q", ToHex(got))
+	}
+	for _, bad := range []string{"", "#fff", "12345g", "1234567"} {
+		if _, err := ParseHex(bad); err == nil {
+			t.Errorf("ParseHex(%q) succeeded, want error", bad)
+		}
+	}
+}
+
 func TestPaletteFromHexes(t *testing.T) {
 	t.Parallel()
 	p := PaletteFromHexes([]string{"#112233", "445566"})
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"image/color"
+	"math"
+)
+
+// OKLab is a color in Björn Ottosson's OKLab space. L is perceived
+// lightness [0-1]; A is green-red and B is blue-yellow (roughly ±0.4).
+// Euclidean distance is a good perceptual difference, which makes it the
+// space of choice for clustering.
+type OKLab struct {
+	L, A, B float64
+}
+
+// Chroma returns the colorfulness (radius in the a/b plane).
+func (c OKLab) Chroma() float64 {
+	return math.Hypot(c.A, c.B)
+}
+
+// Hue returns the hue angle in degrees [0, 360).
+func (c OKLab) Hue() float64 {
+	h := math.Atan2(c.B, c.A) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// OKLabFromLCh builds a color from lightness, chroma and hue (degrees).
+func OKLabFromLCh(l, chroma, hue float64) OKLab {
+	rad := hue * math.Pi / 180
+	return OKLab{L: l, A: chroma * math.Cos(rad), B: chroma * math.Sin(rad)}
+}
+
+// DistSq returns the squared Euclidean distance between two OKLab colors.
+func (c OKLab) DistSq(o OKLab) float64 {
+	dl, da, db := c.L-o.L, c.A-o.A, c.B-o.B
+	return dl*dl + da*da + db*db
+}
+
+// RGBToOKLab converts an sRGB color to OKLab.
+func RGBToOKLab(c color.Color) OKLab {
+	r, g, b, _ := c.RGBA()
+	lr := srgbToLinear(float64(r) / 65535)
+	lg := srgbToLinear(float64(g) / 65535)
+	lb := srgbToLinear(float64(b) / 65535)
+
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+
+	return OKLab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// OKLabToRGB converts OKLab to sRGB. Out-of-gamut colors are clipped per
+// channel; use InGamut first when hue must be preserved.
+func OKLabToRGB(c OKLab) color.RGBA {
+	lr, lg, lb := okLabToLinear(c)
+	to8 := func(v float64) uint8 {
+		return uint8(math.Round(clamp(linearToSRGB(v), 0, 1) * 255))
+	}
+	return color.RGBA{R: to8(lr), G: to8(lg), B: to8(lb), A: 255}
+}
+
+// InGamut reports whether c maps into sRGB without clipping.
+func (c OKLab) InGamut() bool {
+	const eps = 1e-4
+	lr, lg, lb := okLabToLinear(c)
+	return lr >= -eps && lr <= 1+eps && lg >= -eps && lg <= 1+eps && lb >= -eps && lb <= 1+eps
+}
+
+func okLabToLinear(c OKLab) (r, g, b float64) {
+	l := c.L + 0.3963377774*c.A + 0.2158037573*c.B
+	m := c.L - 0.1055613458*c.A - 0.0638541728*c.B
+	s := c.L - 0.0894841775*c.A - 1.2914855480*c.B
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	r = 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g = -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b = -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+	return r, g, b
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"math"
+	"slices"
+)
+
+// Cluster is a representative image color with its share of the samples,
+// as produced by quantizing drivers (k-means, median cut).
+type Cluster struct {
+	Color  OKLab
+	Weight float64
+}
+
+// accentRole is the conventional hue of a base16 accent slot (OKLCh degrees).
+type accentRole struct {
+	hue  float64
+	dark bool // base0F: brown, drawn darker than the other accents
+}
+
+// base08..base0F: red, orange, yellow, green, cyan, blue, magenta, brown.
+var accentRoles = [8]accentRole{
+	{hue: 29}, {hue: 60}, {hue: 105}, {hue: 142},
+	{hue: 195}, {hue: 260}, {hue: 320}, {hue: 50, dark: true},
+}
+
+// Lightness ramps for base00..base07 (dark; light is mirrored) and the
+// base24 extra backgrounds base10/base11.
+var (
+	darkRamp    = [8]float64{0.20, 0.25, 0.31, 0.58, 0.70, 0.86, 0.92, 0.97}
+	lightRamp   = [8]float64{0.98, 0.93, 0.87, 0.66, 0.52, 0.32, 0.26, 0.20}
+	darkDeeper  = [2]float64{0.16, 0.12}
+	lightDeeper = [2]float64{0.995, 1.0}
+)
+
+const (
+	// maxAccentHueShift bounds how far a slot's hue may drift toward the
+	// image so red stays red and blue stays blue; clusters further than
+	// accentHueWindow from the role lend only their chroma.
+	maxAccentHueShift = 15.0
+	accentHueWindow   = 45.0
+	// minChroma separates chromatic clusters from grays.
+	minChroma = 0.03
+)
+
+// PaletteFromClusters maps quantized image colors onto base16 roles.
+//
+// Base00..Base07 are a lightness ramp tinted with the hue of the dominant
+// cluster; Base08..Base0F take the chroma of each accent role's nearest
+// chromatic cluster and drift at most 15° toward its hue, at a lightness
+// that reads on the background. opts.Polarity picks the ramp direction; with
+// PolarityAny the image's weighted mean lightness decides. ColorCount 24
+// adds Base10..Base17 (deeper backgrounds and bright accents).
+func PaletteFromClusters(clusters []Cluster, opts Options) *Palette {
+	dark := opts.Polarity == PolarityDark
+	if opts.Polarity == PolarityAny {
+		dark = meanLightness(clusters) < 0.6
+	}
+
+	ramp, deeper := darkRamp, darkDeeper
+	accentL, brownL, brightShift := 0.72, 0.69, 0.08
+	if !dark {
+		ramp, deeper = lightRamp, lightDeeper
+		accentL, brownL, brightShift = 0.55, 0.45, -0.08
+	}
+
+	// Neutrals carry a hint of the dominant color.
+	tintHue, tintChroma := 0.0, 0.0
+	if dom, ok := dominant(clusters); ok {
+		tintHue, tintChroma = dom.Hue(), math.Min(dom.Chroma()*0.5, 0.02)
+	}
+	hexes := make([]string, 0, 24)
+	for i, l := range ramp {
+		// Fade the tint toward the foreground end of the ramp.
+		c := tintChroma * (1 - float64(i)/float64(len(ramp)))
+		hexes = append(hexes, okToHex(OKLabFromLCh(l, c, tintHue)))
+	}
+
+	chromatic := make([]OKLab, 0, len(clusters))
+	for _, c := range clusters {
+		if c.Color.Chroma() >= minChroma {
+			chromatic = append(chromatic, c.Color)
+		}
+	}
+	accents := make([]OKLab, len(accentRoles))
+	for i, role := range accentRoles {
+		hue, chroma := role.hue, 0.1
+		if src, ok := nearestHue(chromatic, role.hue); ok {
+			if d := hueDiff(role.hue, src.Hue()); math.Abs(d) <= accentHueWindow {
+				hue += clamp(d, -maxAccentHueShift, maxAccentHueShift)
+			}
+			chroma = clamp(src.Chroma(), 0.08, 0.2)
+		}
+		l := accentL
+		if role.dark {
+			l = brownL
+			chroma *= 0.7
+		}
+		accents[i] = OKLabFromLCh(l, chroma, hue)
+		hexes = append(hexes, okToHex(accents[i]))
+	}
+
+	if opts.ColorCount >= 24 {
+		for _, l := range deeper {
+			hexes = append(hexes, okToHex(OKLabFromLCh(l, tintChroma, tintHue)))
+		}
+		// base12..base17: bright red, yellow, green, cyan, blue, magenta.
+		for _, i := range []int{0, 2, 3, 4, 5, 6} {
+			a := accents[i]
+			hexes = append(hexes, okToHex(OKLabFromLCh(a.L+brightShift, a.Chroma(), a.Hue())))
+		}
+	}
+	return PaletteFromHexes(hexes)
+}
+
+func meanLightness(clusters []Cluster) float64 {
+	var sum, total float64
+	for _, c := range clusters {
+		sum += c.Color.L * c.Weight
+		total += c.Weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// dominant returns the heaviest cluster; ties go to the earliest.
+func dominant(clusters []Cluster) (OKLab, bool) {
+	if len(clusters) == 0 {
+		return OKLab{}, false
+	}
+	i := 0
+	for j, c := range clusters {
+		if c.Weight > clusters[i].Weight {
+			i = j
+		}
+	}
+	return clusters[i].Color, true
+}
+
+// nearestHue returns the color whose hue is closest to hue.
+func nearestHue(colors []OKLab, hue float64) (OKLab, bool) {
+	if len(colors) == 0 {
+		return OKLab{}, false
+	}
+	return slices.MinFunc(colors, func(a, b OKLab) int {
+		da, db := math.Abs(hueDiff(hue, a.Hue())), math.Abs(hueDiff(hue, b.Hue()))
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	}), true
+}
+
+// hueDiff returns the signed shortest angle from a to b in (-180, 180].
+func hueDiff(a, b float64) float64 {
+	d := math.Mod(b-a+540, 360) - 180
+	if d == -180 {
+		return 180
+	}
+	return d
+}
+
+// okToHex reduces chroma until c fits sRGB, keeping lightness and hue.
+func okToHex(c OKLab) string {
+	c.L = clamp(c.L, 0, 1)
+	if !c.InGamut() {
+		l, hue := c.L, c.Hue()
+		lo, hi := 0.0, c.Chroma()
+		for range 24 {
+			mid := (lo + hi) / 2
+			if OKLabFromLCh(l, mid, hue).InGamut() {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		c = OKLabFromLCh(l, lo, hue)
+	}
+	return ToHex(OKLabToRGB(c))
+}
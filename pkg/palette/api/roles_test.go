@@ -0,0 +1,85 @@
+package api
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestOKLabRoundTrip(t *testing.T) {
+	t.Parallel()
+	white := RGBToOKLab(color.RGBA{255, 255, 255, 255})
+	if math.Abs(white.L-1) > 1e-4 || white.Chroma() > 1e-4 {
+		t.Fatalf("white = %+v, want L=1 C=0", white)
+	}
+	for _, hex := range []string{"000000", "ffffff", "4285f4", "e53935", "fbc02d", "808080"} {
+		c, _ := ParseHex(hex)
+		lab := RGBToOKLab(c)
+		if !lab.InGamut() {
+			t.Errorf("%s: sRGB color reported out of gamut", hex)
+		}
+		if got := ToHex(OKLabToRGB(lab)); got != hex {
+			t.Errorf("round trip %s -> %+v -> %s", hex, lab, got)
+		}
+	}
+	if OKLabFromLCh(0.7, 0.4, 145).InGamut() {
+		t.Error("C=0.4 green reported in gamut")
+	}
+}
+
+func TestPaletteFromClustersPolarity(t *testing.T) {
+	t.Parallel()
+	clusters := []Cluster{
+		{Color: RGBToOKLab(color.RGBA{0x1e, 0x88, 0xe5, 255}), Weight: 0.5},
+		{Color: RGBToOKLab(color.RGBA{0xe5, 0x39, 0x35, 255}), Weight: 0.3},
+		{Color: RGBToOKLab(color.RGBA{0x43, 0xa0, 0x47, 255}), Weight: 0.2},
+	}
+
+	dark := PaletteFromClusters(clusters, Options{Polarity: PolarityDark, ColorCount: 16})
+	if Variant(dark) != "dark" {
+		t.Errorf("dark polarity gave %s palette", Variant(dark))
+	}
+	if dark.Base10 != "" {
+		t.Errorf("base16 palette has base10 %q", dark.Base10)
+	}
+	light := PaletteFromClusters(clusters, Options{Polarity: PolarityLight, ColorCount: 24})
+	if Variant(light) != "light" {
+		t.Errorf("light polarity gave %s palette", Variant(light))
+	}
+	if light.Base10 == "" || light.Base17 == "" {
+		t.Errorf("base24 slots empty: base10=%q base17=%q", light.Base10, light.Base17)
+	}
+
+	// A mostly white image picks light on its own.
+	bright := []Cluster{{Color: RGBToOKLab(color.RGBA{0xf0, 0xf0, 0xf0, 255}), Weight: 1}}
+	if v := Variant(PaletteFromClusters(bright, Options{ColorCount: 16})); v != "light" {
+		t.Errorf("PolarityAny on a white image gave %s", v)
+	}
+}
+
+func TestPaletteFromClustersAccentHues(t *testing.T) {
+	t.Parallel()
+	// Only blue in the image: red must stay red, blue follows the image.
+	blue := RGBToOKLab(color.RGBA{0x1e, 0x88, 0xe5, 255})
+	p := PaletteFromClusters([]Cluster{{Color: blue, Weight: 1}}, Options{Polarity: PolarityDark, ColorCount: 16})
+
+	hueOf := func(hex string) float64 {
+		c, err := ParseHex(hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return RGBToOKLab(c).Hue()
+	}
+	if d := math.Abs(hueDiff(accentRoles[0].hue, hueOf(p.Base08))); d > 5 {
+		t.Errorf("base08 %s is %.0f° from red", p.Base08, d)
+	}
+	if d := math.Abs(hueDiff(blue.Hue(), hueOf(p.Base0D))); d > math.Abs(hueDiff(accentRoles[5].hue, blue.Hue())) {
+		t.Errorf("base0D %s did not move toward the image blue", p.Base0D)
+	}
+
+	// Grays only: accents fall back to the conventional hues.
+	gray := PaletteFromClusters([]Cluster{{Color: RGBToOKLab(color.RGBA{0x40, 0x40, 0x40, 255}), Weight: 1}}, Options{ColorCount: 16})
+	if gray.Base0B == "" || gray.Base0B == gray.Base0D {
+		t.Errorf("gray image accents not distinct: base0B=%q base0D=%q", gray.Base0B, gray.Base0D)
+	}
+}
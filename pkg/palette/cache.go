@@ -0,0 +1,76 @@
+package palette
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/lucasew/workspaced/internal/atomicfile"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+// ExtractCached is ExtractFromFile memoized by image content: the result is
+// stored under ~/.cache/workspaced/palette keyed by the image's SHA-256,
+// driver and options, so the same wallpaper never runs a driver twice.
+func ExtractCached(ctx context.Context, path string, driver string, opts api.Options) (*api.Palette, error) {
+	logger := logging.GetLogger(ctx)
+	cachePath, err := cacheEntry(ctx, path, driver, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, err := os.Open(cachePath); err == nil {
+		p, decodeErr := DecodeJSON(f)
+		logging.Close(ctx, f)
+		if decodeErr == nil {
+			logger.Debug("palette cache hit", "image", path, "entry", cachePath)
+			return p, nil
+		}
+		logger.Warn("ignoring corrupt palette cache entry", "entry", cachePath, "error", decodeErr)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	p, err := ExtractFromFile(ctx, path, driver, opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := atomicfile.WriteBytes(cachePath, data, 0o644); err != nil {
+		// The palette is still good; a read-only cache only costs speed.
+		logging.ReportError(ctx, err, "op", "write palette cache", "entry", cachePath)
+	}
+	return p, nil
+}
+
+// cacheEntry returns the cache file for path extracted with driver/opts.
+func cacheEntry(ctx context.Context, path string, driver string, opts api.Options) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open image: %w", err)
+	}
+	defer logging.Close(ctx, f)
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash image: %w", err)
+	}
+
+	home, err := envdriver.GetHomeDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s-p%d-c%d-s%d.json", hex.EncodeToString(h.Sum(nil)), driver, opts.Polarity, opts.ColorCount, opts.MaxSamples)
+	return filepath.Join(home, ".cache", "workspaced", "palette", name), nil
+}
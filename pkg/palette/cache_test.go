@@ -0,0 +1,54 @@
+package palette_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/lucasew/workspaced/pkg/driver/env/native"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func TestExtractCached(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ctx := logging.NewWriterContext(t.Output())
+	img := palettetest.Path(t, "bliss.jpg")
+	opts := api.Options{Polarity: api.PolarityDark, ColorCount: 16, MaxSamples: 10000}
+
+	first, err := palette.ExtractCached(ctx, img, "kmeans", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := filepath.Glob(filepath.Join(home, ".cache", "workspaced", "palette", "*-kmeans-*.json"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache entries = %v (%v), want one", entries, err)
+	}
+
+	// A hit is served from the entry without running the driver again.
+	if err := os.WriteFile(entries[0], []byte(`{"base00":"123456"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hit, err := palette.ExtractCached(ctx, img, "kmeans", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit.Base00 != "123456" {
+		t.Fatalf("base00 = %q, want the cached value", hit.Base00)
+	}
+
+	// A corrupt entry is ignored and rewritten.
+	if err := os.WriteFile(entries[0], []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := palette.ExtractCached(ctx, img, "kmeans", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Base00 != first.Base00 {
+		t.Fatalf("base00 = %q, want %q", again.Base00, first.Base00)
+	}
+}
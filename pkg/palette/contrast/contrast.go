@@ -0,0 +1,173 @@
+// Package contrast checks and repairs the readability of base16 palettes.
+//
+// Pairs follow the base16 styling roles: Base05 is body text on the
+// Base00..Base02 backgrounds, Base08..Base0F are accents drawn on Base00 and
+// Base01, and Base03/Base04 are muted text (comments, line numbers). Each
+// pair is measured with both WCAG 2.x contrast ratio and APCA Lc; Fix moves
+// only foreground tones, so backgrounds (and the scheme's look) stay put.
+package contrast
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+// ErrUnknownLevel is returned by ParseLevel for unknown level names.
+var ErrUnknownLevel = errors.New("unknown contrast level")
+
+// Level selects the target table.
+type Level int
+
+const (
+	// LevelAA targets WCAG AA: 4.5 for text, 3 for accents.
+	LevelAA Level = iota
+	// LevelAAA targets WCAG AAA: 7 for text, 4.5 for accents.
+	LevelAAA
+)
+
+// ParseLevel converts "aa" or "aaa" (any case) to a Level.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "aa":
+		return LevelAA, nil
+	case "aaa":
+		return LevelAAA, nil
+	}
+	return LevelAA, fmt.Errorf("%w: %q (must be 'aa' or 'aaa')", ErrUnknownLevel, s)
+}
+
+func (l Level) String() string {
+	if l == LevelAAA {
+		return "aaa"
+	}
+	return "aa"
+}
+
+// Role is how a pair's foreground is used.
+type Role int
+
+const (
+	RoleText Role = iota
+	RoleAccent
+	RoleMuted
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleAccent:
+		return "accent"
+	case RoleMuted:
+		return "muted"
+	}
+	return "text"
+}
+
+// Target is the minimum contrast for one role.
+type Target struct {
+	WCAG float64 // contrast ratio, 1..21
+	APCA float64 // |Lc|, 0..~108
+}
+
+// Targets returns the minimums for role at level.
+func (l Level) Targets(role Role) Target {
+	table := map[Role][2]Target{
+		RoleText:   {{WCAG: 4.5, APCA: 60}, {WCAG: 7, APCA: 75}},
+		RoleAccent: {{WCAG: 3, APCA: 45}, {WCAG: 4.5, APCA: 60}},
+		RoleMuted:  {{WCAG: 2, APCA: 30}, {WCAG: 3, APCA: 45}},
+	}
+	t := table[role]
+	if l == LevelAAA {
+		return t[1]
+	}
+	return t[0]
+}
+
+// Pair is one foreground/background combination that must stay readable.
+type Pair struct {
+	FG, BG string // slot names, e.g. "base05", "base00"
+	Role   Role
+}
+
+func (p Pair) String() string {
+	return p.FG + "/" + p.BG
+}
+
+// Pairs returns the checked combinations for p; base24 bright accents
+// (Base12..Base17) are included when set.
+func Pairs(p *api.Palette) []Pair {
+	pairs := []Pair{
+		{"base05", "base00", RoleText},
+		{"base05", "base01", RoleText},
+		{"base05", "base02", RoleText},
+		{"base04", "base00", RoleMuted},
+		{"base03", "base00", RoleMuted},
+	}
+	accents := []string{"base08", "base09", "base0A", "base0B", "base0C", "base0D", "base0E", "base0F"}
+	if p.Base12 != "" {
+		accents = append(accents, "base12", "base13", "base14", "base15", "base16", "base17")
+	}
+	for _, fg := range accents {
+		pairs = append(pairs, Pair{fg, "base00", RoleAccent}, Pair{fg, "base01", RoleAccent})
+	}
+	return pairs
+}
+
+// WCAG returns the WCAG 2.x contrast ratio of fg on bg (1..21).
+func WCAG(fg, bg color.Color) float64 {
+	l1, l2 := relativeLuminance(fg), relativeLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+func relativeLuminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	lin := func(v uint32) float64 {
+		x := float64(v) / 65535
+		if x <= 0.04045 {
+			return x / 12.92
+		}
+		return math.Pow((x+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(r) + 0.7152*lin(g) + 0.0722*lin(b)
+}
+
+// APCA returns the APCA-W3 (0.0.98G-4g) lightness contrast Lc of text fg on
+// bg. It is signed: positive for dark text on light, negative for light on
+// dark. Compare targets against the absolute value.
+func APCA(fg, bg color.Color) float64 {
+	ytxt, ybg := apcaY(fg), apcaY(bg)
+	if math.Abs(ybg-ytxt) < 0.0005 {
+		return 0
+	}
+	var sapc, out float64
+	if ybg > ytxt {
+		sapc = (math.Pow(ybg, 0.56) - math.Pow(ytxt, 0.57)) * 1.14
+		if sapc >= 0.1 {
+			out = sapc - 0.027
+		}
+	} else {
+		sapc = (math.Pow(ybg, 0.65) - math.Pow(ytxt, 0.62)) * 1.14
+		if sapc <= -0.1 {
+			out = sapc + 0.027
+		}
+	}
+	return out * 100
+}
+
+func apcaY(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	ch := func(v uint32) float64 { return math.Pow(float64(v)/65535, 2.4) }
+	y := 0.2126729*ch(r) + 0.7151522*ch(g) + 0.0721750*ch(b)
+	// Soft clamp for near-black, per APCA.
+	if y < 0.022 {
+		y += math.Pow(0.022-y, 1.414)
+	}
+	return y
+}
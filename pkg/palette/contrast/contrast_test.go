@@ -0,0 +1,135 @@
+package contrast
+
+import (
+	"encoding/json"
+	"image/color"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func loadPalette(t testing.TB, name string) *api.Palette {
+	t.Helper()
+	b, err := os.ReadFile(palettetest.Path(t, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+	return &p
+}
+
+func TestMetricsReferenceValues(t *testing.T) {
+	t.Parallel()
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+	gray := color.RGBA{0x88, 0x88, 0x88, 255}
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		// APCA reference values from the APCA-W3 0.0.98G-4g test suite.
+		{"apca black on white", APCA(black, white), 106.04},
+		{"apca white on black", APCA(white, black), -107.88},
+		{"apca #888 on white", APCA(gray, white), 63.06},
+		{"apca white on #888", APCA(white, gray), -68.54},
+		{"wcag black on white", WCAG(black, white), 21},
+		{"wcag symmetric", WCAG(white, black), 21},
+		{"wcag #888 on white", WCAG(gray, white), 3.54},
+	}
+	for _, tc := range cases {
+		if math.Abs(tc.got-tc.want) > 0.01 {
+			t.Errorf("%s = %.3f, want %.2f", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestFixMeetsTargets(t *testing.T) {
+	t.Parallel()
+	for _, level := range []Level{LevelAA, LevelAAA} {
+		p := loadPalette(t, "materialyou_dark16_bliss.json")
+		before, err := Check(p, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		failing := 0
+		for _, r := range before {
+			if !r.Pass() {
+				failing++
+			}
+		}
+		if failing == 0 {
+			t.Fatalf("%s: fixture already passes; test proves nothing", level)
+		}
+
+		fixed, changes, err := Fix(p, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := Check(fixed, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range after {
+			if !r.Pass() {
+				t.Errorf("%s: %s still fails: wcag %.2f apca %.1f (target %+v)", level, r.Pair, r.WCAG, r.APCA, r.Target)
+			}
+		}
+		if fixed.Base00 != p.Base00 || fixed.Base01 != p.Base01 || fixed.Base02 != p.Base02 {
+			t.Errorf("%s: backgrounds moved", level)
+		}
+		for _, c := range changes {
+			if !c.Met || c.DeltaE <= 0 {
+				t.Errorf("%s: change %+v", level, c)
+			}
+		}
+		// The input is not modified.
+		if *p != *loadPalette(t, "materialyou_dark16_bliss.json") {
+			t.Errorf("%s: Fix mutated its input", level)
+		}
+	}
+}
+
+func TestFixKeepsPassingSlots(t *testing.T) {
+	t.Parallel()
+	p := api.PaletteFromHexes([]string{
+		"000000", "111111", "222222", "909090", "a0a0a0", "ffffff", "ffffff", "ffffff",
+		"ff6060", "ffa040", "ffff60", "60ff60", "60ffff", "80a0ff", "ff80ff", "e0a080",
+	})
+	fixed, changes, err := Fix(p, LevelAA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 || *fixed != *p {
+		t.Fatalf("passing palette changed: %+v", changes)
+	}
+}
+
+func TestFixPicksSmallestDrift(t *testing.T) {
+	t.Parallel()
+	// A mid gray accent on black only needs a small lift, not a jump to white.
+	p := loadPalette(t, "materialyou_dark16_bliss.json")
+	p.Base0D = "505050"
+	fixed, changes, err := Fix(p, LevelAA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got *Change
+	for i := range changes {
+		if changes[i].Slot == "base0D" {
+			got = &changes[i]
+		}
+	}
+	if got == nil {
+		t.Fatalf("base0D not changed: %+v", changes)
+	}
+	c, _ := api.ParseHex(fixed.Base0D)
+	if l := api.Lightness(c); l > 70 {
+		t.Fatalf("base0D lifted to L*=%.1f (%s), expected the nearest passing tone", l, fixed.Base0D)
+	}
+}
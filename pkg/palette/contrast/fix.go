@@ -0,0 +1,159 @@
+package contrast
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/materialyou"
+)
+
+// Result is the measured contrast of one pair.
+type Result struct {
+	Pair   Pair
+	FGHex  string
+	BGHex  string
+	WCAG   float64
+	APCA   float64 // absolute Lc
+	Target Target
+}
+
+// Pass reports whether both metrics meet the target.
+func (r Result) Pass() bool {
+	return r.WCAG >= r.Target.WCAG && r.APCA >= r.Target.APCA
+}
+
+// Check measures every pair of p at level.
+func Check(p *api.Palette, level Level) ([]Result, error) {
+	s := slots(p)
+	var out []Result
+	for _, pair := range Pairs(p) {
+		fg, err := api.ParseHex(*s[pair.FG])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pair.FG, err)
+		}
+		bg, err := api.ParseHex(*s[pair.BG])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pair.BG, err)
+		}
+		out = append(out, Result{
+			Pair:   pair,
+			FGHex:  api.ToHex(fg),
+			BGHex:  api.ToHex(bg),
+			WCAG:   WCAG(fg, bg),
+			APCA:   math.Abs(APCA(fg, bg)),
+			Target: level.Targets(pair.Role),
+		})
+	}
+	return out, nil
+}
+
+// Change records one foreground slot moved by Fix.
+type Change struct {
+	Slot     string
+	From, To string
+	DeltaE   float64 // CIE76 drift between From and To
+	// Met is false when no tone satisfies every pair of the slot; To is
+	// then the tone with the best worst-case contrast.
+	Met bool
+}
+
+// Fix returns a copy of p whose foreground slots meet level against every
+// background they are paired with. Each failing slot keeps its HCT hue and
+// chroma; its tone is searched over 0..100 and the passing candidate with
+// the smallest DeltaE from the original wins. Backgrounds never change.
+func Fix(p *api.Palette, level Level) (*api.Palette, []Change, error) {
+	results, err := Check(p, level)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := *p
+	s := slots(&out)
+
+	// Group constraints by foreground slot, preserving Pairs order.
+	var order []string
+	bySlot := map[string][]Result{}
+	failing := map[string]bool{}
+	for _, r := range results {
+		if _, seen := bySlot[r.Pair.FG]; !seen {
+			order = append(order, r.Pair.FG)
+		}
+		bySlot[r.Pair.FG] = append(bySlot[r.Pair.FG], r)
+		if !r.Pass() {
+			failing[r.Pair.FG] = true
+		}
+	}
+
+	var changes []Change
+	for _, slot := range order {
+		if !failing[slot] {
+			continue
+		}
+		from := *s[slot]
+		to, met := fixSlot(from, bySlot[slot])
+		if to == from {
+			continue
+		}
+		*s[slot] = to
+		fromC, _ := api.ParseHex(from)
+		toC, _ := api.ParseHex(to)
+		changes = append(changes, Change{
+			Slot:   slot,
+			From:   from,
+			To:     to,
+			DeltaE: api.DeltaE(api.RGBToLAB(fromC), api.RGBToLAB(toC)),
+			Met:    met,
+		})
+	}
+	return &out, changes, nil
+}
+
+// fixSlot searches tones for hex against the backgrounds in constraints.
+func fixSlot(hex string, constraints []Result) (string, bool) {
+	orig, _ := api.ParseHex(hex)
+	origLAB := api.RGBToLAB(orig)
+	hct := materialyou.HCTFromHex(hex)
+
+	bestHex, bestDE := "", math.Inf(1)
+	fallbackHex, fallbackScore := hex, -1.0
+	for step := 0; step <= 200; step++ {
+		hct.Tone = float64(step) / 2
+		cand := api.NormalizeHex(hct.Hex())
+		c, err := api.ParseHex(cand)
+		if err != nil {
+			continue
+		}
+		ok, score := true, math.Inf(1)
+		for _, r := range constraints {
+			bg, _ := api.ParseHex(r.BGHex)
+			w, a := WCAG(c, bg), math.Abs(APCA(c, bg))
+			if w < r.Target.WCAG || a < r.Target.APCA {
+				ok = false
+			}
+			score = math.Min(score, math.Min(w/r.Target.WCAG, a/r.Target.APCA))
+		}
+		if ok {
+			if de := api.DeltaE(origLAB, api.RGBToLAB(c)); de < bestDE {
+				bestHex, bestDE = cand, de
+			}
+		} else if score > fallbackScore {
+			fallbackHex, fallbackScore = cand, score
+		}
+	}
+	if bestHex != "" {
+		return bestHex, true
+	}
+	return fallbackHex, false
+}
+
+// slots maps lowercase-prefixed slot names ("base0A") to palette fields.
+func slots(p *api.Palette) map[string]*string {
+	return map[string]*string{
+		"base00": &p.Base00, "base01": &p.Base01, "base02": &p.Base02, "base03": &p.Base03,
+		"base04": &p.Base04, "base05": &p.Base05, "base06": &p.Base06, "base07": &p.Base07,
+		"base08": &p.Base08, "base09": &p.Base09, "base0A": &p.Base0A, "base0B": &p.Base0B,
+		"base0C": &p.Base0C, "base0D": &p.Base0D, "base0E": &p.Base0E, "base0F": &p.Base0F,
+		"base10": &p.Base10, "base11": &p.Base11, "base12": &p.Base12, "base13": &p.Base13,
+		"base14": &p.Base14, "base15": &p.Base15, "base16": &p.Base16, "base17": &p.Base17,
+	}
+}
@@ -0,0 +1,62 @@
+package palette_test
+
+import (
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+	_ "github.com/lucasew/workspaced/pkg/palette/prelude"
+)
+
+// BenchmarkDrivers runs every registered driver over the same fixtures so
+// their costs compare directly:
+//
+//	go test ./pkg/palette -run '^$' -bench Drivers
+func BenchmarkDrivers(b *testing.B) {
+	ctx := logging.NewWriterContext(b.Output())
+	opts := api.Options{Polarity: api.PolarityDark, ColorCount: 16, MaxSamples: 10000}
+	for _, fixture := range []string{"gradient_256.png", "bliss.jpg"} {
+		img := palettetest.LoadImage(b, fixture)
+		for _, d := range api.List() {
+			b.Run(d.Name()+"/"+fixture, func(b *testing.B) {
+				if d.Name() == "genetic" && testing.Short() {
+					b.Skip("skipping full genetic extract benchmark in short mode")
+				}
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := d.Extract(ctx, img, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDriversDeterministic(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	img := palettetest.LoadImage(t, "gradient_256.png")
+	opts := api.Options{Polarity: api.PolarityAny, ColorCount: 24, MaxSamples: 10000}
+	for _, name := range []string{"kmeans", "mediancut", "materialyou"} {
+		d, err := api.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := d.Extract(ctx, img, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		second, err := d.Extract(ctx, img, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if *first != *second {
+			t.Errorf("%s not deterministic\nfirst  %#v\nsecond %#v", name, first, second)
+		}
+		if first.Base17 == "" {
+			t.Errorf("%s: base24 slots empty", name)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{Name: "vscode", Description: "VS Code color theme JSON", Encode: encodeVSCode})
+	Register(Format{Name: "vim", Description: "Vim/Neovim colorscheme (colors/<name>.vim)", Encode: encodeVim})
+}
+
+type vscodeTheme struct {
+	Schema      string            `json:"$schema"`
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Colors      map[string]string `json:"colors"`
+	TokenColors []vscodeToken     `json:"tokenColors"`
+}
+
+type vscodeToken struct {
+	Name     string            `json:"name"`
+	Scope    []string          `json:"scope"`
+	Settings map[string]string `json:"settings"`
+}
+
+func encodeVSCode(w io.Writer, s *Scheme) error {
+	colors := map[string]string{
+		"foreground":                        s.Base05,
+		"focusBorder":                       s.Base0D,
+		"editor.background":                 s.Base00,
+		"editor.foreground":                 s.Base05,
+		"editor.lineHighlightBackground":    s.Base01,
+		"editor.selectionBackground":        s.Base02,
+		"editorCursor.foreground":           s.Base05,
+		"editorLineNumber.foreground":       s.Base03,
+		"editorLineNumber.activeForeground": s.Base04,
+		"editorWhitespace.foreground":       s.Base03,
+		"editorError.foreground":            s.Base08,
+		"editorWarning.foreground":          s.Base0A,
+		"editorInfo.foreground":             s.Base0D,
+		"activityBar.background":            s.Base01,
+		"activityBar.foreground":            s.Base05,
+		"sideBar.background":                s.Base01,
+		"sideBar.foreground":                s.Base05,
+		"statusBar.background":              s.Base0D,
+		"statusBar.foreground":              s.Base00,
+		"titleBar.activeBackground":         s.Base01,
+		"titleBar.activeForeground":         s.Base05,
+		"tab.activeBackground":              s.Base00,
+		"tab.inactiveBackground":            s.Base01,
+		"panel.background":                  s.Base00,
+		"terminal.background":               s.Base00,
+		"terminal.foreground":               s.Base05,
+	}
+	for i, name := range ansiNames {
+		title := strings.ToUpper(name[:1]) + name[1:]
+		colors["terminal.ansi"+title] = s.ANSI[i]
+		colors["terminal.ansiBright"+title] = s.ANSI[i+8]
+	}
+	for k, v := range colors {
+		colors[k] = Hash(v)
+	}
+
+	token := func(name, fg string, scopes ...string) vscodeToken {
+		return vscodeToken{Name: name, Scope: scopes, Settings: map[string]string{"foreground": Hash(fg)}}
+	}
+	return writeJSON(w, vscodeTheme{
+		Schema: "vscode://schemas/color-theme",
+		Name:   s.Name,
+		Type:   s.Variant,
+		Colors: colors,
+		TokenColors: []vscodeToken{
+			token("Comment", s.Base03, "comment", "punctuation.definition.comment"),
+			token("Variable", s.Base08, "variable", "entity.name.tag"),
+			token("Number, Constant", s.Base09, "constant.numeric", "constant.language", "constant.character"),
+			token("Class, Type", s.Base0A, "entity.name.class", "entity.name.type", "support.class"),
+			token("String", s.Base0B, "string", "markup.inserted"),
+			token("Escape, Regex", s.Base0C, "constant.character.escape", "string.regexp", "support.function"),
+			token("Function", s.Base0D, "entity.name.function", "meta.function-call"),
+			token("Keyword", s.Base0E, "keyword", "storage.type", "storage.modifier"),
+			token("Deprecated", s.Base0F, "invalid.deprecated"),
+		},
+	})
+}
+
+// vimGroups maps highlight groups to {fg, bg} palette slots ("" = NONE).
+// The base16 styling guidelines assign roles to slots; this follows them.
+func vimGroups(s *Scheme) [][3]string {
+	return [][3]string{
+		{"Normal", s.Base05, s.Base00},
+		{"NormalFloat", s.Base05, s.Base01},
+		{"Comment", s.Base03, ""},
+		{"Constant", s.Base09, ""},
+		{"String", s.Base0B, ""},
+		{"Character", s.Base08, ""},
+		{"Number", s.Base09, ""},
+		{"Boolean", s.Base09, ""},
+		{"Identifier", s.Base08, ""},
+		{"Function", s.Base0D, ""},
+		{"Statement", s.Base0E, ""},
+		{"Keyword", s.Base0E, ""},
+		{"Operator", s.Base05, ""},
+		{"PreProc", s.Base0A, ""},
+		{"Type", s.Base0A, ""},
+		{"Special", s.Base0C, ""},
+		{"Delimiter", s.Base0F, ""},
+		{"Todo", s.Base0A, s.Base01},
+		{"Error", s.Base00, s.Base08},
+		{"ErrorMsg", s.Base08, s.Base00},
+		{"WarningMsg", s.Base08, ""},
+		{"Cursor", s.Base00, s.Base05},
+		{"CursorLine", "", s.Base01},
+		{"CursorLineNr", s.Base04, s.Base01},
+		{"LineNr", s.Base03, s.Base00},
+		{"SignColumn", s.Base03, s.Base00},
+		{"ColorColumn", "", s.Base01},
+		{"Visual", "", s.Base02},
+		{"Search", s.Base01, s.Base0A},
+		{"IncSearch", s.Base01, s.Base09},
+		{"MatchParen", "", s.Base03},
+		{"Pmenu", s.Base05, s.Base01},
+		{"PmenuSel", s.Base01, s.Base05},
+		{"StatusLine", s.Base04, s.Base02},
+		{"StatusLineNC", s.Base03, s.Base01},
+		{"VertSplit", s.Base02, s.Base00},
+		{"WinSeparator", s.Base02, s.Base00},
+		{"Folded", s.Base03, s.Base01},
+		{"NonText", s.Base03, ""},
+		{"Title", s.Base0D, ""},
+		{"Directory", s.Base0D, ""},
+		{"DiffAdd", s.Base0B, s.Base01},
+		{"DiffChange", s.Base03, s.Base01},
+		{"DiffDelete", s.Base08, s.Base01},
+		{"DiffText", s.Base0D, s.Base01},
+	}
+}
+
+func encodeVim(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\" %s (%s), generated by workspaced\n", s.Name, s.Variant)
+	b.WriteString("highlight clear\n")
+	b.WriteString("if exists('syntax_on')\n  syntax reset\nendif\n")
+	fmt.Fprintf(&b, "set background=%s\n", s.Variant)
+	fmt.Fprintf(&b, "let g:colors_name = %q\n\n", s.Name)
+
+	quoted := make([]string, len(s.ANSI))
+	for i, c := range s.ANSI {
+		quoted[i] = fmt.Sprintf("'%s'", Hash(c))
+	}
+	fmt.Fprintf(&b, "let g:terminal_ansi_colors = [%s]\n", strings.Join(quoted, ", "))
+	b.WriteString("if has('nvim')\n")
+	for i, c := range s.ANSI {
+		fmt.Fprintf(&b, "  let g:terminal_color_%d = '%s'\n", i, Hash(c))
+	}
+	b.WriteString("endif\n\n")
+
+	for _, g := range vimGroups(s) {
+		fg, bg := "NONE", "NONE"
+		if g[1] != "" {
+			fg = Hash(g[1])
+		}
+		if g[2] != "" {
+			bg = Hash(g[2])
+		}
+		fmt.Fprintf(&b, "highlight %s guifg=%s guibg=%s gui=NONE cterm=NONE\n", g[0], fg, bg)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
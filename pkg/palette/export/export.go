@@ -0,0 +1,107 @@
+// Package export encodes an api.Palette into application color configs.
+//
+// Every format derives its terminal colors from the same base16 ANSI
+// mapping (see ANSI), so a palette exported to kitty, foot and Xresources
+// renders identically everywhere.
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+// ErrFormatNotFound is returned by Get for unknown export format names.
+var ErrFormatNotFound = errors.New("palette export format not found")
+
+// ErrIncompletePalette is returned when a base16 slot is empty or not hex.
+var ErrIncompletePalette = errors.New("incomplete palette")
+
+// DefaultName is the scheme name used when Options.Name is empty.
+const DefaultName = "workspaced"
+
+// Options configures an export.
+type Options struct {
+	// Name is the scheme name embedded by formats that carry one
+	// (VS Code, Windows Terminal, Vim colors_name, tinted YAML).
+	Name string
+	// Author is written by formats with an author field.
+	Author string
+}
+
+// Format encodes a palette for one application.
+type Format struct {
+	// Name is the CLI slug used with --format (e.g. "kitty").
+	Name string
+	// Description is a short summary shown in help text.
+	Description string
+	// Encode writes the palette. The palette has already been validated.
+	Encode func(w io.Writer, s *Scheme) error
+}
+
+// formats holds export formats keyed by Name.
+// Registration happens during init(); no mutex is needed at runtime.
+var formats = map[string]Format{}
+
+// Register adds an export format.
+// Panics if name is empty or already registered.
+func Register(f Format) {
+	if f.Name == "" {
+		panic("palette export: format name cannot be empty")
+	}
+	if _, ok := formats[f.Name]; ok {
+		panic(fmt.Sprintf("palette export: format %q registered twice", f.Name))
+	}
+	formats[f.Name] = f
+}
+
+// Get returns the registered format with the given name.
+func Get(name string) (Format, error) {
+	f, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("%w: %s (available: %v)", ErrFormatNotFound, name, Names())
+	}
+	return f, nil
+}
+
+// List returns registered formats sorted by name.
+func List() []Format {
+	names := Names()
+	out := make([]Format, 0, len(names))
+	for _, name := range names {
+		out = append(out, formats[name])
+	}
+	return out
+}
+
+// Names returns registered format names in sorted order.
+func Names() []string {
+	return slices.Sorted(maps.Keys(formats))
+}
+
+// Encode writes p to w in the named format.
+func Encode(w io.Writer, format string, p *api.Palette, opts Options) error {
+	f, err := Get(format)
+	if err != nil {
+		return err
+	}
+	s, err := NewScheme(p, opts)
+	if err != nil {
+		return err
+	}
+	return f.Encode(w, s)
+}
+
+// String is Encode into a string.
+func String(format string, p *api.Palette, opts Options) (string, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, format, p, opts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
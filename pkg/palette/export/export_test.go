@@ -0,0 +1,162 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func loadPalette(t testing.TB, name string) *api.Palette {
+	t.Helper()
+	b, err := os.ReadFile(palettetest.Path(t, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+	return &p
+}
+
+func TestFormatsRegistered(t *testing.T) {
+	t.Parallel()
+	want := []string{"alacritty", "foot", "gtk", "kitty", "kvantum", "qtct", "tinted", "vim", "vscode", "windows-terminal", "xresources"}
+	if got := strings.Join(Names(), ","); got != strings.Join(want, ",") {
+		t.Fatalf("Names() = %s, want %s", got, strings.Join(want, ","))
+	}
+	if _, err := Get("nope"); !errors.Is(err, ErrFormatNotFound) {
+		t.Fatalf("Get(nope) error = %v, want ErrFormatNotFound", err)
+	}
+}
+
+func TestEncodeLines(t *testing.T) {
+	t.Parallel()
+	p := loadPalette(t, "materialyou_dark16_bliss.json")
+	cases := []struct {
+		format string
+		want   []string
+	}{
+		{"kitty", []string{"background #" + p.Base00, "foreground #" + p.Base05, "color1 #" + p.Base08, "color15 #" + p.Base07}},
+		{"alacritty", []string{"[colors.primary]", `background = "#` + p.Base00 + `"`, "[colors.bright]", `blue = "#` + p.Base0D + `"`}},
+		{"foot", []string{"[colors]", "background=" + p.Base00, "regular2=" + p.Base0B, "bright0=" + p.Base03}},
+		{"xresources", []string{"*.background: #" + p.Base00, "*.color4: #" + p.Base0D}},
+		{"vim", []string{"set background=dark", `let g:colors_name = "bliss"`, "highlight Normal guifg=#" + p.Base05 + " guibg=#" + p.Base00 + " gui=NONE cterm=NONE"}},
+		{"gtk", []string{"@define-color base0A #" + p.Base0A + ";", "@define-color accent_color #" + p.Base0D + ";"}},
+		{"qtct", []string{"[ColorScheme]", "active_colors=#ff" + p.Base05 + ", #ff" + p.Base01 + ", #ff" + p.Base03 + ", #ff" + p.Base02 + ", #ff" + p.Base00 + ", #ff" + p.Base01 + ", #ff" + p.Base05 + ", #ff" + p.Base07 + ", #ff" + p.Base05 + ", #ff" + p.Base00 + ", #ff" + p.Base00 + ", #ff000000, #ff" + p.Base0D + ", #ff" + p.Base00 + ", #ff" + p.Base0D + ", #ff" + p.Base0E + ", #ff" + p.Base01 + ", #ff" + p.Base00 + ", #ff" + p.Base01 + ", #ff" + p.Base05 + ", #ff" + p.Base03}},
+		{"kvantum", []string{"[%General]", "[GeneralColors]", "window.color=#" + p.Base00, "highlight.color=#" + p.Base0D, "disabled.text.color=#" + p.Base03}},
+		{"tinted", []string{`system: "base16"`, `name: "bliss"`, `variant: "dark"`, `  base0F: "#` + p.Base0F + `"`}},
+	}
+	for _, tc := range cases {
+		out, err := String(tc.format, p, Options{Name: "bliss"})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.format, err)
+		}
+		lines := strings.Split(out, "\n")
+		for _, want := range tc.want {
+			found := false
+			for _, line := range lines {
+				if line == want {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("%s: missing line %q in\n%s", tc.format, want, out)
+			}
+		}
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	t.Parallel()
+	p := loadPalette(t, "materialyou_dark16_bliss.json")
+
+	out, err := String("windows-terminal", p, Options{Name: "bliss"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wt map[string]string
+	if err := json.Unmarshal([]byte(out), &wt); err != nil {
+		t.Fatalf("windows-terminal: %v\n%s", err, out)
+	}
+	if wt["name"] != "bliss" || wt["background"] != "#"+p.Base00 || wt["brightBlack"] != "#"+p.Base03 {
+		t.Fatalf("windows-terminal scheme = %v", wt)
+	}
+
+	out, err = String("vscode", p, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var theme struct {
+		Name   string            `json:"name"`
+		Type   string            `json:"type"`
+		Colors map[string]string `json:"colors"`
+	}
+	if err := json.Unmarshal([]byte(out), &theme); err != nil {
+		t.Fatalf("vscode: %v\n%s", err, out)
+	}
+	if theme.Name != DefaultName || theme.Type != "dark" || theme.Colors["editor.background"] != "#"+p.Base00 {
+		t.Fatalf("vscode theme = %+v", theme)
+	}
+	if theme.Colors["terminal.ansiBrightWhite"] != "#"+p.Base07 {
+		t.Fatalf("terminal.ansiBrightWhite = %q", theme.Colors["terminal.ansiBrightWhite"])
+	}
+}
+
+func TestSchemeVariantAndBase24(t *testing.T) {
+	t.Parallel()
+	light := loadPalette(t, "materialyou_light24_4285f4.json")
+	s, err := NewScheme(light, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Variant != "light" || !s.Base24 {
+		t.Fatalf("variant=%s base24=%v, want light base24", s.Variant, s.Base24)
+	}
+	if s.ANSI[9] != light.Base12 || s.ANSI[12] != light.Base16 || s.ANSI[8] != light.Base02 {
+		t.Fatalf("base24 bright colors not mapped: %v", s.ANSI)
+	}
+	out, err := String("tinted", light, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `system: "base24"`) || !strings.Contains(out, "  base17: ") {
+		t.Fatalf("tinted base24 output:\n%s", out)
+	}
+}
+
+func TestIncompletePalette(t *testing.T) {
+	t.Parallel()
+	p := loadPalette(t, "materialyou_dark16_bliss.json")
+	p.Base0C = "zzz"
+	if _, err := String("kitty", p, Options{}); !errors.Is(err, ErrIncompletePalette) {
+		t.Fatalf("error = %v, want ErrIncompletePalette", err)
+	}
+	if _, err := String("kitty", nil, Options{}); !errors.Is(err, ErrIncompletePalette) {
+		t.Fatalf("nil palette error = %v, want ErrIncompletePalette", err)
+	}
+}
+
+func TestNormalizesInput(t *testing.T) {
+	t.Parallel()
+	p := loadPalette(t, "materialyou_dark16_bliss.json")
+	want, err := String("foot", p, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := *p
+	hashed.Base00 = "#" + strings.ToUpper(p.Base00)
+	got, err := String("foot", &hashed, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("'#'/uppercase input changed output:\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{Name: "gtk", Description: "GTK CSS @define-color (base slots plus libadwaita names)", Encode: encodeGTK})
+}
+
+func encodeGTK(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "/* %s (%s), generated by workspaced */\n", s.Name, s.Variant)
+	slots := Slots(s.Palette)
+	n := 16
+	if s.Base24 {
+		n = 24
+	}
+	for i, c := range slots[:n] {
+		fmt.Fprintf(&b, "@define-color base%02X %s;\n", i, Hash(c))
+	}
+	b.WriteString("\n")
+	// libadwaita named colors, so GTK4 apps follow without a full theme.
+	named := [][2]string{
+		{"accent_color", s.Base0D},
+		{"accent_bg_color", s.Base0D},
+		{"accent_fg_color", s.Base00},
+		{"destructive_color", s.Base08},
+		{"destructive_bg_color", s.Base08},
+		{"destructive_fg_color", s.Base00},
+		{"success_color", s.Base0B},
+		{"warning_color", s.Base0A},
+		{"error_color", s.Base08},
+		{"window_bg_color", s.Base00},
+		{"window_fg_color", s.Base05},
+		{"view_bg_color", s.Base00},
+		{"view_fg_color", s.Base05},
+		{"headerbar_bg_color", s.Base01},
+		{"headerbar_fg_color", s.Base05},
+		{"sidebar_bg_color", s.Base01},
+		{"sidebar_fg_color", s.Base05},
+		{"card_bg_color", s.Base01},
+		{"card_fg_color", s.Base05},
+		{"dialog_bg_color", s.Base01},
+		{"dialog_fg_color", s.Base05},
+		{"popover_bg_color", s.Base01},
+		{"popover_fg_color", s.Base05},
+	}
+	for _, kv := range named {
+		fmt.Fprintf(&b, "@define-color %s %s;\n", kv[0], Hash(kv[1]))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,100 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{Name: "qtct", Description: "qt5ct/qt6ct color scheme (colors/<name>.conf)", Encode: encodeQtct})
+	Register(Format{Name: "kvantum", Description: "Kvantum theme config (<name>/<name>.kvconfig)", Encode: encodeKvantum})
+}
+
+// qtRoles returns the QPalette colors in ColorRole order (WindowText ..
+// PlaceholderText) for one color group.
+func qtRoles(s *Scheme, group string) []string {
+	light, midlight, mid, dark, bright := s.Base03, s.Base02, s.Base01, s.Base00, s.Base07
+	if s.Variant == "light" {
+		light, midlight, mid, dark, bright = s.Base00, s.Base00, s.Base02, s.Base03, s.Base00
+	}
+	text, highlight, highlightText := s.Base05, s.Base0D, s.Base00
+	switch group {
+	case "disabled":
+		text = s.Base03
+		highlight, highlightText = s.Base02, s.Base03
+	case "inactive":
+		highlight, highlightText = s.Base02, s.Base05
+	}
+	return []string{
+		text,          // WindowText
+		s.Base01,      // Button
+		light,         // Light
+		midlight,      // Midlight
+		dark,          // Dark
+		mid,           // Mid
+		text,          // Text
+		bright,        // BrightText
+		text,          // ButtonText
+		s.Base00,      // Base
+		s.Base00,      // Window
+		"000000",      // Shadow
+		highlight,     // Highlight
+		highlightText, // HighlightedText
+		s.Base0D,      // Link
+		s.Base0E,      // LinkVisited
+		s.Base01,      // AlternateBase
+		s.Base00,      // NoRole
+		s.Base01,      // ToolTipBase
+		s.Base05,      // ToolTipText
+		s.Base03,      // PlaceholderText
+	}
+}
+
+func encodeQtct(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s), generated by workspaced\n", s.Name, s.Variant)
+	b.WriteString("[ColorScheme]\n")
+	for _, group := range []string{"active", "disabled", "inactive"} {
+		roles := qtRoles(s, group)
+		for i, c := range roles {
+			roles[i] = "#ff" + c
+		}
+		fmt.Fprintf(&b, "%s_colors=%s\n", group, strings.Join(roles, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func encodeKvantum(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%%General]\nauthor=%s\ncomment=%s (%s), generated by workspaced\n", s.Author, s.Name, s.Variant)
+	b.WriteString("\n[GeneralColors]\n")
+	disabled := s.Base03
+	pairs := [][2]string{
+		{"window.color", s.Base00},
+		{"base.color", s.Base00},
+		{"alt.base.color", s.Base01},
+		{"button.color", s.Base01},
+		{"light.color", s.Base02},
+		{"mid.light.color", s.Base02},
+		{"dark.color", s.Base00},
+		{"mid.color", s.Base01},
+		{"highlight.color", s.Base0D},
+		{"inactive.highlight.color", s.Base02},
+		{"text.color", s.Base05},
+		{"window.text.color", s.Base05},
+		{"button.text.color", s.Base05},
+		{"disabled.text.color", disabled},
+		{"tooltip.text.color", s.Base05},
+		{"highlight.text.color", s.Base00},
+		{"link.color", s.Base0D},
+		{"link.visited.color", s.Base0E},
+		{"progress.indicator.text.color", s.Base00},
+	}
+	for _, kv := range pairs {
+		fmt.Fprintf(&b, "%s=%s\n", kv[0], Hash(kv[1]))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+// Scheme is a validated palette plus the derived values encoders share.
+// All colors are lowercase RRGGBB without '#'; use Hash to add one.
+type Scheme struct {
+	*api.Palette
+	Name    string
+	Author  string
+	Variant string // "dark" or "light", from Base00 vs Base05 lightness
+	// Base24 reports whether Base10..Base17 are all set.
+	Base24 bool
+	// ANSI holds terminal colors 0..15.
+	ANSI [16]string
+}
+
+// NewScheme validates p and derives the shared encoder values.
+func NewScheme(p *api.Palette, opts Options) (*Scheme, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: nil palette", ErrIncompletePalette)
+	}
+	p = normalize(p)
+	slots := Slots(p)
+	for i, hex := range slots[:16] {
+		if _, err := api.ParseHex(hex); err != nil {
+			return nil, fmt.Errorf("%w: base%02X: %v", ErrIncompletePalette, i, err)
+		}
+	}
+	s := &Scheme{Palette: p, Name: opts.Name, Author: opts.Author, Base24: true}
+	if s.Name == "" {
+		s.Name = DefaultName
+	}
+	if s.Author == "" {
+		s.Author = DefaultName
+	}
+	for _, hex := range slots[16:] {
+		if _, err := api.ParseHex(hex); err != nil {
+			s.Base24 = false
+			break
+		}
+	}
+
+	s.Variant = api.Variant(p)
+	s.ANSI = ANSI(p, s.Base24)
+	return s, nil
+}
+
+// normalize returns a copy of p with lowercase hex and no '#', so palettes
+// written by hand (or by other tools) encode the same as generated ones.
+func normalize(p *api.Palette) *api.Palette {
+	out := *p
+	for _, slot := range []*string{
+		&out.Base00, &out.Base01, &out.Base02, &out.Base03, &out.Base04, &out.Base05, &out.Base06, &out.Base07,
+		&out.Base08, &out.Base09, &out.Base0A, &out.Base0B, &out.Base0C, &out.Base0D, &out.Base0E, &out.Base0F,
+		&out.Base10, &out.Base11, &out.Base12, &out.Base13, &out.Base14, &out.Base15, &out.Base16, &out.Base17,
+	} {
+		*slot = strings.ToLower(api.NormalizeHex(strings.TrimSpace(*slot)))
+	}
+	return &out
+}
+
+// Slots returns the 24 palette fields in base00..base17 order.
+func Slots(p *api.Palette) [24]string {
+	return [24]string{
+		p.Base00, p.Base01, p.Base02, p.Base03, p.Base04, p.Base05, p.Base06, p.Base07,
+		p.Base08, p.Base09, p.Base0A, p.Base0B, p.Base0C, p.Base0D, p.Base0E, p.Base0F,
+		p.Base10, p.Base11, p.Base12, p.Base13, p.Base14, p.Base15, p.Base16, p.Base17,
+	}
+}
+
+// ANSI maps a palette to the 16 terminal colors using the tinted-theming
+// base16 terminal mapping. With base24 the bright colors come from
+// Base12..Base17 instead of repeating the normal ones.
+func ANSI(p *api.Palette, base24 bool) [16]string {
+	c := [16]string{
+		p.Base00, p.Base08, p.Base0B, p.Base0A, p.Base0D, p.Base0E, p.Base0C, p.Base05,
+		p.Base03, p.Base08, p.Base0B, p.Base0A, p.Base0D, p.Base0E, p.Base0C, p.Base07,
+	}
+	if base24 {
+		c[8] = p.Base02
+		c[9], c[10], c[11], c[12], c[13], c[14] = p.Base12, p.Base14, p.Base13, p.Base16, p.Base17, p.Base15
+	}
+	return c
+}
+
+// Hash prefixes hex with '#'.
+func Hash(hex string) string {
+	return "#" + hex
+}
+
+// ansiNames are the conventional names of terminal colors 0..7.
+var ansiNames = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{Name: "kitty", Description: "kitty.conf color include", Encode: encodeKitty})
+	Register(Format{Name: "alacritty", Description: "Alacritty TOML [colors] import", Encode: encodeAlacritty})
+	Register(Format{Name: "foot", Description: "foot.ini [colors] section", Encode: encodeFoot})
+	Register(Format{Name: "xresources", Description: "X resources (*.color0..15)", Encode: encodeXresources})
+	Register(Format{Name: "windows-terminal", Description: "Windows Terminal settings.json scheme", Encode: encodeWindowsTerminal})
+}
+
+func encodeKitty(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s), generated by workspaced\n", s.Name, s.Variant)
+	pairs := [][2]string{
+		{"foreground", s.Base05},
+		{"background", s.Base00},
+		{"selection_foreground", s.Base05},
+		{"selection_background", s.Base02},
+		{"cursor", s.Base05},
+		{"cursor_text_color", s.Base00},
+		{"url_color", s.Base04},
+		{"active_border_color", s.Base03},
+		{"inactive_border_color", s.Base01},
+		{"active_tab_foreground", s.Base00},
+		{"active_tab_background", s.Base0D},
+		{"inactive_tab_foreground", s.Base05},
+		{"inactive_tab_background", s.Base01},
+		{"tab_bar_background", s.Base01},
+	}
+	for _, kv := range pairs {
+		fmt.Fprintf(&b, "%s %s\n", kv[0], Hash(kv[1]))
+	}
+	for i, c := range s.ANSI {
+		fmt.Fprintf(&b, "color%d %s\n", i, Hash(c))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func encodeAlacritty(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s), generated by workspaced\n", s.Name, s.Variant)
+	section := func(name string, pairs ...string) {
+		fmt.Fprintf(&b, "\n[colors.%s]\n", name)
+		for i := 0; i < len(pairs); i += 2 {
+			fmt.Fprintf(&b, "%s = %q\n", pairs[i], Hash(pairs[i+1]))
+		}
+	}
+	section("primary", "background", s.Base00, "foreground", s.Base05)
+	section("cursor", "text", s.Base00, "cursor", s.Base05)
+	section("selection", "text", s.Base05, "background", s.Base02)
+	normal := make([]string, 0, 16)
+	bright := make([]string, 0, 16)
+	for i, name := range ansiNames {
+		normal = append(normal, name, s.ANSI[i])
+		bright = append(bright, name, s.ANSI[i+8])
+	}
+	section("normal", normal...)
+	section("bright", bright...)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func encodeFoot(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s), generated by workspaced\n", s.Name, s.Variant)
+	b.WriteString("[colors]\n")
+	fmt.Fprintf(&b, "foreground=%s\n", s.Base05)
+	fmt.Fprintf(&b, "background=%s\n", s.Base00)
+	fmt.Fprintf(&b, "selection-foreground=%s\n", s.Base05)
+	fmt.Fprintf(&b, "selection-background=%s\n", s.Base02)
+	fmt.Fprintf(&b, "urls=%s\n", s.Base04)
+	for i := range 8 {
+		fmt.Fprintf(&b, "regular%d=%s\n", i, s.ANSI[i])
+	}
+	for i := range 8 {
+		fmt.Fprintf(&b, "bright%d=%s\n", i, s.ANSI[i+8])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func encodeXresources(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "! %s (%s), generated by workspaced\n", s.Name, s.Variant)
+	fmt.Fprintf(&b, "*.foreground: %s\n", Hash(s.Base05))
+	fmt.Fprintf(&b, "*.background: %s\n", Hash(s.Base00))
+	fmt.Fprintf(&b, "*.cursorColor: %s\n", Hash(s.Base05))
+	for i, c := range s.ANSI {
+		fmt.Fprintf(&b, "*.color%d: %s\n", i, Hash(c))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// windowsTerminalScheme is one entry of settings.json "schemes"; field
+// order follows the Windows Terminal docs.
+type windowsTerminalScheme struct {
+	Name                string `json:"name"`
+	Background          string `json:"background"`
+	Foreground          string `json:"foreground"`
+	CursorColor         string `json:"cursorColor"`
+	SelectionBackground string `json:"selectionBackground"`
+	Black               string `json:"black"`
+	Red                 string `json:"red"`
+	Green               string `json:"green"`
+	Yellow              string `json:"yellow"`
+	Blue                string `json:"blue"`
+	Purple              string `json:"purple"`
+	Cyan                string `json:"cyan"`
+	White               string `json:"white"`
+	BrightBlack         string `json:"brightBlack"`
+	BrightRed           string `json:"brightRed"`
+	BrightGreen         string `json:"brightGreen"`
+	BrightYellow        string `json:"brightYellow"`
+	BrightBlue          string `json:"brightBlue"`
+	BrightPurple        string `json:"brightPurple"`
+	BrightCyan          string `json:"brightCyan"`
+	BrightWhite         string `json:"brightWhite"`
+}
+
+func encodeWindowsTerminal(w io.Writer, s *Scheme) error {
+	a := s.ANSI
+	return writeJSON(w, windowsTerminalScheme{
+		Name:                s.Name,
+		Background:          Hash(s.Base00),
+		Foreground:          Hash(s.Base05),
+		CursorColor:         Hash(s.Base05),
+		SelectionBackground: Hash(s.Base02),
+		Black:               Hash(a[0]),
+		Red:                 Hash(a[1]),
+		Green:               Hash(a[2]),
+		Yellow:              Hash(a[3]),
+		Blue:                Hash(a[4]),
+		Purple:              Hash(a[5]),
+		Cyan:                Hash(a[6]),
+		White:               Hash(a[7]),
+		BrightBlack:         Hash(a[8]),
+		BrightRed:           Hash(a[9]),
+		BrightGreen:         Hash(a[10]),
+		BrightYellow:        Hash(a[11]),
+		BrightBlue:          Hash(a[12]),
+		BrightPurple:        Hash(a[13]),
+		BrightCyan:          Hash(a[14]),
+		BrightWhite:         Hash(a[15]),
+	})
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
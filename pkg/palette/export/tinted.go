@@ -0,0 +1,32 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{Name: "tinted", Description: "tinted-theming scheme YAML (base16/base24)", Encode: encodeTinted})
+}
+
+// encodeTinted writes the tinted-theming scheme format
+// (https://github.com/tinted-theming/home/blob/main/styling.md).
+func encodeTinted(w io.Writer, s *Scheme) error {
+	var b strings.Builder
+	system, n := "base16", 16
+	if s.Base24 {
+		system, n = "base24", 24
+	}
+	fmt.Fprintf(&b, "system: %q\n", system)
+	fmt.Fprintf(&b, "name: %q\n", s.Name)
+	fmt.Fprintf(&b, "author: %q\n", s.Author)
+	fmt.Fprintf(&b, "variant: %q\n", s.Variant)
+	b.WriteString("palette:\n")
+	slots := Slots(s.Palette)
+	for i, c := range slots[:n] {
+		fmt.Fprintf(&b, "  base%02X: %q\n", i, Hash(c))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,160 @@
+// Package kmeans extracts palettes by k-means++ clustering in OKLab.
+package kmeans
+
+import (
+	"context"
+	"image"
+	"math/rand"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+const (
+	// numClusters is enough to find one candidate per accent role plus
+	// background tones; more only splits gradients.
+	numClusters   = 16
+	maxIterations = 50
+	seed          = 42
+)
+
+func init() {
+	api.Register(&Driver{})
+}
+
+type Driver struct{}
+
+func (d *Driver) Name() string {
+	return "kmeans"
+}
+
+func (d *Driver) Description() string {
+	return "k-means++ clustering in OKLab (seeded, deterministic and fast)"
+}
+
+func (d *Driver) Extract(ctx context.Context, img image.Image, opts api.Options) (*api.Palette, error) {
+	logger := logging.GetLogger(ctx)
+
+	colors := api.SampleImage(img, opts.MaxSamples)
+	if len(colors) == 0 {
+		return nil, ctx.Err()
+	}
+	api.SortRGBA(colors)
+	points := make([]api.OKLab, len(colors))
+	for i, c := range colors {
+		points[i] = api.RGBToOKLab(c)
+	}
+
+	clusters, iterations, err := cluster(ctx, points, numClusters, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("k-means converged", "points", len(points), "clusters", len(clusters), "iterations", iterations)
+	return api.PaletteFromClusters(clusters, opts), nil
+}
+
+// cluster runs Lloyd's algorithm from k-means++ seeds. Weights are the
+// number of points assigned to each center; empty clusters are dropped.
+func cluster(ctx context.Context, points []api.OKLab, k int, rng *rand.Rand) ([]api.Cluster, int, error) {
+	k = min(k, len(points))
+	centers := seedCenters(points, k, rng)
+	assign := make([]int, len(points))
+	for i := range assign {
+		assign[i] = -1
+	}
+
+	iterations := 0
+	for iterations < maxIterations {
+		if err := ctx.Err(); err != nil {
+			return nil, iterations, err
+		}
+		iterations++
+
+		changed := false
+		for i, p := range points {
+			best := nearest(centers, p)
+			if best != assign[i] {
+				assign[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([]api.OKLab, k)
+		counts := make([]int, k)
+		for i, p := range points {
+			c := assign[i]
+			sums[c].L += p.L
+			sums[c].A += p.A
+			sums[c].B += p.B
+			counts[c]++
+		}
+		for c := range centers {
+			if counts[c] == 0 {
+				continue // keep the old center; it may win points back
+			}
+			n := float64(counts[c])
+			centers[c] = api.OKLab{L: sums[c].L / n, A: sums[c].A / n, B: sums[c].B / n}
+		}
+	}
+
+	counts := make([]int, k)
+	for _, c := range assign {
+		counts[c]++
+	}
+	out := make([]api.Cluster, 0, k)
+	for c, center := range centers {
+		if counts[c] > 0 {
+			out = append(out, api.Cluster{Color: center, Weight: float64(counts[c]) / float64(len(points))})
+		}
+	}
+	return out, iterations, nil
+}
+
+// seedCenters picks k initial centers with k-means++: each next center is
+// drawn with probability proportional to its squared distance from the
+// closest center chosen so far.
+func seedCenters(points []api.OKLab, k int, rng *rand.Rand) []api.OKLab {
+	centers := make([]api.OKLab, 0, k)
+	centers = append(centers, points[rng.Intn(len(points))])
+	dist := make([]float64, len(points))
+	for i, p := range points {
+		dist[i] = p.DistSq(centers[0])
+	}
+
+	for len(centers) < k {
+		var total float64
+		for _, d := range dist {
+			total += d
+		}
+		next := 0
+		if total > 0 {
+			target := rng.Float64() * total
+			for i, d := range dist {
+				target -= d
+				if target <= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		c := points[next]
+		centers = append(centers, c)
+		for i, p := range points {
+			dist[i] = min(dist[i], p.DistSq(c))
+		}
+	}
+	return centers
+}
+
+func nearest(centers []api.OKLab, p api.OKLab) int {
+	best, bestDist := 0, p.DistSq(centers[0])
+	for i := 1; i < len(centers); i++ {
+		if d := p.DistSq(centers[i]); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
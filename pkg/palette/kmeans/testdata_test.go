@@ -0,0 +1,83 @@
+package kmeans
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func loadGoldenPalette(t testing.TB, name string) *api.Palette {
+	t.Helper()
+	b, err := os.ReadFile(palettetest.Path(t, name))
+	if err != nil {
+		t.Fatalf("read golden %s: %v", name, err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatalf("parse golden %s: %v", name, err)
+	}
+	return &p
+}
+
+// MaxSamples must match the golden generator (CLI default 10000).
+func TestKMeansFromTestdataBliss(t *testing.T) {
+	t.Parallel()
+	img := palettetest.LoadImage(t, "bliss.jpg")
+	ctx := logging.NewWriterContext(t.Output())
+	d := &Driver{}
+
+	dark, err := d.Extract(ctx, img, api.Options{Polarity: api.PolarityDark, ColorCount: 16, MaxSamples: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := loadGoldenPalette(t, "kmeans_dark16_bliss.json"); *dark != *want {
+		t.Fatalf("bliss dark16 mismatch\ngot  %#v\nwant %#v", dark, want)
+	}
+
+	light, err := d.Extract(ctx, img, api.Options{Polarity: api.PolarityLight, ColorCount: 24, MaxSamples: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := loadGoldenPalette(t, "kmeans_light24_bliss.json"); *light != *want {
+		t.Fatalf("bliss light24 mismatch\ngot  %#v\nwant %#v", light, want)
+	}
+}
+
+func TestKMeansClusterBlocks(t *testing.T) {
+	t.Parallel()
+	// Four unique colors: k is clamped and every color becomes its own cluster.
+	colors := api.SampleImage(palettetest.LoadImage(t, "blocks_64.png"), 0)
+	api.SortRGBA(colors)
+	points := make([]api.OKLab, len(colors))
+	for i, c := range colors {
+		points[i] = api.RGBToOKLab(c)
+	}
+	clusters, _, err := cluster(context.Background(), points, numClusters, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 4 {
+		t.Fatalf("clusters = %d, want 4", len(clusters))
+	}
+	for _, c := range clusters {
+		if c.Weight != 0.25 {
+			t.Errorf("cluster %+v weight = %v, want 0.25", c.Color, c.Weight)
+		}
+	}
+}
+
+func TestKMeansCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(logging.NewWriterContext(t.Output()))
+	cancel()
+	img := palettetest.LoadImage(t, "gradient_256.png")
+	if _, err := (&Driver{}).Extract(ctx, img, api.Options{ColorCount: 16, MaxSamples: 10000}); err == nil {
+		t.Fatal("expected context error")
+	}
+}
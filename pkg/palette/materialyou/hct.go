@@ -263,12 +263,24 @@ func rgbToHex(c RGB) string {
 	return string(buf[:])
 }
 
+// HCT is a color in Material's hue/chroma/tone space; Tone is CIELAB L*.
 type HCT struct {
 	Hue    float64
 	Chroma float64
 	Tone   float64
 }
 
+// HCTFromHex converts RRGGBB (with or without '#') to HCT.
+func HCTFromHex(hex string) HCT {
+	return hctFromHex(hex)
+}
+
+// Hex returns the in-gamut "#rrggbb" closest to h, keeping hue and tone
+// and reducing chroma when needed.
+func (h HCT) Hex() string {
+	return hexFromHct(h)
+}
+
 func hctFromHex(hex string) HCT {
 	rgb := hexToRgb(hex)
 	c := cam16(rgb)
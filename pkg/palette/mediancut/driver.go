@@ -0,0 +1,125 @@
+// Package mediancut extracts palettes with Heckbert's median-cut quantizer.
+package mediancut
+
+import (
+	"cmp"
+	"context"
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+// numBoxes matches the kmeans driver so both hand the role mapper the same
+// amount of detail.
+const numBoxes = 16
+
+func init() {
+	api.Register(&Driver{})
+}
+
+type Driver struct{}
+
+func (d *Driver) Name() string {
+	return "mediancut"
+}
+
+func (d *Driver) Description() string {
+	return "Classic median-cut quantization in RGB (deterministic and fast)"
+}
+
+func (d *Driver) Extract(ctx context.Context, img image.Image, opts api.Options) (*api.Palette, error) {
+	logger := logging.GetLogger(ctx)
+
+	colors := api.SampleImage(img, opts.MaxSamples)
+	if len(colors) == 0 {
+		return nil, ctx.Err()
+	}
+	api.SortRGBA(colors)
+
+	boxes := split(colors, numBoxes)
+	clusters := make([]api.Cluster, len(boxes))
+	for i, b := range boxes {
+		clusters[i] = api.Cluster{
+			Color:  api.RGBToOKLab(b.mean()),
+			Weight: float64(len(b)) / float64(len(colors)),
+		}
+	}
+	logger.Debug("median cut done", "colors", len(colors), "boxes", len(boxes))
+	return api.PaletteFromClusters(clusters, opts), nil
+}
+
+// box is a set of colors; its bounds are recomputed on demand.
+type box []color.RGBA
+
+// widest returns the channel (0=R, 1=G, 2=B) with the largest range and
+// that range.
+func (b box) widest() (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	var hi [3]uint8
+	for _, c := range b {
+		for ch, v := range [3]uint8{c.R, c.G, c.B} {
+			lo[ch] = min(lo[ch], v)
+			hi[ch] = max(hi[ch], v)
+		}
+	}
+	axis, span := 0, -1
+	for ch := range 3 {
+		if r := int(hi[ch]) - int(lo[ch]); r > span {
+			axis, span = ch, r
+		}
+	}
+	return axis, span
+}
+
+func (b box) mean() color.RGBA {
+	var r, g, bl int
+	for _, c := range b {
+		r += int(c.R)
+		g += int(c.G)
+		bl += int(c.B)
+	}
+	n := len(b)
+	return color.RGBA{R: uint8((r + n/2) / n), G: uint8((g + n/2) / n), B: uint8((bl + n/2) / n), A: 255}
+}
+
+// split repeatedly cuts the box with the widest channel range at its
+// median until n boxes exist or no box can be split further.
+func split(colors []color.RGBA, n int) []box {
+	boxes := []box{box(colors)}
+	for len(boxes) < n {
+		target, axis, span := -1, 0, 0
+		for i, b := range boxes {
+			if len(b) < 2 {
+				continue
+			}
+			if a, s := b.widest(); s > span {
+				target, axis, span = i, a, s
+			}
+		}
+		if target < 0 {
+			break
+		}
+
+		b := boxes[target]
+		slices.SortStableFunc(b, func(x, y color.RGBA) int {
+			return cmp.Compare(channel(x, axis), channel(y, axis))
+		})
+		mid := len(b) / 2
+		boxes[target] = b[:mid]
+		boxes = append(boxes, b[mid:])
+	}
+	return boxes
+}
+
+func channel(c color.RGBA, axis int) uint8 {
+	switch axis {
+	case 1:
+		return c.G
+	case 2:
+		return c.B
+	}
+	return c.R
+}
@@ -0,0 +1,71 @@
+package mediancut
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func loadGoldenPalette(t testing.TB, name string) *api.Palette {
+	t.Helper()
+	b, err := os.ReadFile(palettetest.Path(t, name))
+	if err != nil {
+		t.Fatalf("read golden %s: %v", name, err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatalf("parse golden %s: %v", name, err)
+	}
+	return &p
+}
+
+// MaxSamples must match the golden generator (CLI default 10000).
+func TestMedianCutFromTestdataBliss(t *testing.T) {
+	t.Parallel()
+	img := palettetest.LoadImage(t, "bliss.jpg")
+	ctx := logging.NewWriterContext(t.Output())
+	d := &Driver{}
+
+	dark, err := d.Extract(ctx, img, api.Options{Polarity: api.PolarityDark, ColorCount: 16, MaxSamples: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := loadGoldenPalette(t, "mediancut_dark16_bliss.json"); *dark != *want {
+		t.Fatalf("bliss dark16 mismatch\ngot  %#v\nwant %#v", dark, want)
+	}
+
+	light, err := d.Extract(ctx, img, api.Options{Polarity: api.PolarityLight, ColorCount: 24, MaxSamples: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := loadGoldenPalette(t, "mediancut_light24_bliss.json"); *light != *want {
+		t.Fatalf("bliss light24 mismatch\ngot  %#v\nwant %#v", light, want)
+	}
+}
+
+func TestMedianCutSplitBlocks(t *testing.T) {
+	t.Parallel()
+	// Four unique colors can only be cut into four single-color boxes.
+	colors := api.SampleImage(palettetest.LoadImage(t, "blocks_64.png"), 0)
+	api.SortRGBA(colors)
+	boxes := split(colors, numBoxes)
+	if len(boxes) != 4 {
+		t.Fatalf("boxes = %d, want 4", len(boxes))
+	}
+	seen := map[string]bool{}
+	for _, b := range boxes {
+		if len(b) != 1 {
+			t.Fatalf("box has %d colors, want 1", len(b))
+		}
+		seen[api.ToHex(b.mean())] = true
+	}
+	for _, hex := range []string{"e53935", "43a047", "1e88e5", "fbc02d"} {
+		if !seen[hex] {
+			t.Errorf("missing box for %s", hex)
+		}
+	}
+}
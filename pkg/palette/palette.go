@@ -2,12 +2,15 @@ package palette
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/lucasew/workspaced/pkg/logging"
@@ -37,8 +40,8 @@ func DriverNames() []string {
 	return api.Names()
 }
 
-// ExtractFromFile loads an image from a file and extracts a color palette.
-func ExtractFromFile(ctx context.Context, path string, driver string, opts api.Options) (*api.Palette, error) {
+// LoadImage opens and decodes an image file (PNG, JPEG or GIF).
+func LoadImage(ctx context.Context, path string) (image.Image, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open image: %w", err)
@@ -49,6 +52,15 @@ func ExtractFromFile(ctx context.Context, path string, driver string, opts api.O
 	if err != nil {
 		return nil, fmt.Errorf("decode image: %w", err)
 	}
+	return img, nil
+}
+
+// ExtractFromFile loads an image from a file and extracts a color palette.
+func ExtractFromFile(ctx context.Context, path string, driver string, opts api.Options) (*api.Palette, error) {
+	img, err := LoadImage(ctx, path)
+	if err != nil {
+		return nil, err
+	}
 
 	d, err := GetDriver(ctx, driver)
 	if err != nil {
@@ -57,3 +69,40 @@ func ExtractFromFile(ctx context.Context, path string, driver string, opts api.O
 
 	return d.Extract(ctx, img, opts)
 }
+
+// LoadFile reads palette JSON from a .json file, or extracts a palette
+// from any other (image) file with driver and opts.
+func LoadFile(ctx context.Context, path string, driver string, opts api.Options) (*api.Palette, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return ExtractFromFile(ctx, path, driver, opts)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer logging.Close(ctx, f)
+	return DecodeJSON(f)
+}
+
+// DecodeJSON reads a palette in the `palette generate` JSON shape.
+func DecodeJSON(r io.Reader) (*api.Palette, error) {
+	var p api.Palette
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, fmt.Errorf("decode palette: %w", err)
+	}
+	return &p, nil
+}
+
+// ParsePolarity converts "any", "dark" or "light" to a Polarity.
+func ParsePolarity(s string) (api.Polarity, error) {
+	switch strings.ToLower(s) {
+	case "any":
+		return api.PolarityAny, nil
+	case "dark":
+		return api.PolarityDark, nil
+	case "light":
+		return api.PolarityLight, nil
+	default:
+		return api.PolarityAny, fmt.Errorf("invalid polarity: %s (must be 'dark', 'light', or 'any')", s)
+	}
+}
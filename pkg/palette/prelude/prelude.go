@@ -2,5 +2,7 @@ package prelude
 
 import (
 	_ "github.com/lucasew/workspaced/pkg/palette/genetic"
+	_ "github.com/lucasew/workspaced/pkg/palette/kmeans"
 	_ "github.com/lucasew/workspaced/pkg/palette/materialyou"
+	_ "github.com/lucasew/workspaced/pkg/palette/mediancut"
 )
@@ -0,0 +1,68 @@
+package preview
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+const reset = "\x1b[0m"
+
+func bgSeq(c color.RGBA) string {
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+func fgSeq(c color.RGBA) string {
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+// ANSI writes e as truecolor blocks: a title line, then rows of eight
+// two-line cells labeled with the slot name and hex.
+func ANSI(w io.Writer, e Entry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", e.Name, api.Variant(e.Palette))
+	ss := slots(e.Palette)
+	for start := 0; start < len(ss); start += perRow {
+		row := ss[start:min(start+perRow, len(ss))]
+		for _, line := range []func(slot) string{
+			func(s slot) string { return s.Name },
+			func(s slot) string { return "#" + api.ToHex(s.Color) },
+		} {
+			for _, s := range row {
+				fmt.Fprintf(&b, "%s%s %-9s", bgSeq(s.Color), fgSeq(labelColor(s.Color)), line(s))
+			}
+			b.WriteString(reset + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CompareANSI writes one line of color blocks per entry, slots aligned
+// under a base00..base17 header.
+func CompareANSI(w io.Writer, entries []Entry) error {
+	nameW, cols := 4, 16
+	for _, e := range entries {
+		nameW = max(nameW, len(e.Name))
+		cols = max(cols, len(slots(e.Palette)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s ", nameW, "")
+	for i := range cols {
+		fmt.Fprintf(&b, "%02X  ", i)
+	}
+	b.WriteString("\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%-*s ", nameW, e.Name)
+		for _, s := range slots(e.Palette) {
+			b.WriteString(bgSeq(s.Color) + "    ")
+		}
+		b.WriteString(reset + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,147 @@
+// Package preview renders palettes for eyeballing: PNG swatch sheets with
+// hex labels and truecolor ANSI blocks for the terminal. Compare variants
+// line several palettes up slot by slot so drivers can be judged side by
+// side.
+package preview
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/contrast"
+	"github.com/lucasew/workspaced/pkg/palette/export"
+)
+
+// Entry is one palette to render, titled by Name (a scheme, file or driver).
+type Entry struct {
+	Name    string
+	Palette *api.Palette
+}
+
+// slot is one filled palette color.
+type slot struct {
+	Name  string // "base0D"
+	Color color.RGBA
+}
+
+// slots returns the palette's colors in base00..base17 order, stopping at
+// base0F for base16 palettes. Unparseable slots are skipped.
+func slots(p *api.Palette) []slot {
+	all := export.Slots(p)
+	n := 16
+	if p.Base10 != "" {
+		n = 24
+	}
+	out := make([]slot, 0, n)
+	for i, hex := range all[:n] {
+		c, err := api.ParseHex(hex)
+		if err != nil {
+			continue
+		}
+		out = append(out, slot{Name: fmt.Sprintf("base%02X", i), Color: c})
+	}
+	return out
+}
+
+// labelColor picks black or white, whichever reads better on bg.
+func labelColor(bg color.Color) color.RGBA {
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+	if contrast.WCAG(white, bg) >= contrast.WCAG(black, bg) {
+		return white
+	}
+	return black
+}
+
+// Sheet geometry, in pixels. Labels use the 7x13 X11 fixed font.
+const (
+	pad        = 8
+	cellW      = 96
+	cellH      = 56
+	perRow     = 8
+	titleH     = 24
+	stripCellW = 64
+	stripCellH = 36
+	nameW      = 112
+	headerH    = 20
+)
+
+var face = basicfont.Face7x13
+
+// Swatches renders e as a grid of labeled swatches, eight per row, under a
+// title bar drawn in the palette's own Base00/Base05.
+func Swatches(e Entry) *image.RGBA {
+	ss := slots(e.Palette)
+	rows := (len(ss) + perRow - 1) / perRow
+	w := 2*pad + perRow*cellW
+	h := 2*pad + titleH + rows*cellH
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	bg, fg := color.RGBA{0x20, 0x20, 0x20, 255}, color.RGBA{0xe0, 0xe0, 0xe0, 255}
+	if len(ss) > 5 {
+		bg, fg = ss[0].Color, ss[5].Color
+	}
+	fill(img, img.Bounds(), bg)
+	drawText(img, pad, pad+titleH/2+4, e.Name+"  ("+api.Variant(e.Palette)+")", fg)
+
+	for i, s := range ss {
+		x := pad + (i%perRow)*cellW
+		y := pad + titleH + (i/perRow)*cellH
+		r := image.Rect(x, y, x+cellW, y+cellH)
+		fill(img, r, s.Color)
+		label := labelColor(s.Color)
+		drawText(img, x+6, y+20, s.Name, label)
+		drawText(img, x+6, y+38, export.Hash(api.ToHex(s.Color)), label)
+	}
+	return img
+}
+
+// Compare renders one strip per entry with slots aligned in columns, so
+// the same role from every palette sits in one vertical line.
+func Compare(entries []Entry) *image.RGBA {
+	cols := 16
+	for _, e := range entries {
+		cols = max(cols, len(slots(e.Palette)))
+	}
+	w := 2*pad + nameW + cols*stripCellW
+	h := 2*pad + headerH + len(entries)*stripCellH
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	bg, fg := color.RGBA{0x18, 0x18, 0x18, 255}, color.RGBA{0xd8, 0xd8, 0xd8, 255}
+	fill(img, img.Bounds(), bg)
+
+	for i := range cols {
+		drawText(img, pad+nameW+i*stripCellW+6, pad+headerH-6, fmt.Sprintf("%02X", i), fg)
+	}
+	for row, e := range entries {
+		y := pad + headerH + row*stripCellH
+		drawText(img, pad, y+stripCellH/2+4, e.Name, fg)
+		for i, s := range slots(e.Palette) {
+			x := pad + nameW + i*stripCellW
+			fill(img, image.Rect(x, y, x+stripCellW, y+stripCellH), s.Color)
+			drawText(img, x+6, y+stripCellH/2+4, export.Hash(api.ToHex(s.Color)), labelColor(s.Color))
+		}
+	}
+	return img
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawText draws s with its baseline at (x, y).
+func drawText(img draw.Image, x, y int, s string, c color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
@@ -0,0 +1,116 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func loadPalette(t testing.TB, name string) *api.Palette {
+	t.Helper()
+	b, err := os.ReadFile(palettetest.Path(t, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	var p api.Palette
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+	return &p
+}
+
+func TestSwatches(t *testing.T) {
+	t.Parallel()
+	p := loadPalette(t, "materialyou_light24_bliss.json")
+	img := Swatches(Entry{Name: "bliss", Palette: p})
+
+	// Three rows of eight for base24.
+	if got, want := img.Bounds().Dy(), 2*pad+titleH+3*cellH; got != want {
+		t.Fatalf("height = %d, want %d", got, want)
+	}
+	// base0D is row 1, column 5; its bottom-right corner is unlabeled.
+	x, y := pad+5*cellW+cellW-2, pad+titleH+cellH+cellH-2
+	want, _ := api.ParseHex(p.Base0D)
+	if got := img.RGBAAt(x, y); got != want {
+		t.Fatalf("base0D swatch = %v, want %v", got, want)
+	}
+	// The label is drawn in a contrasting color inside the cell.
+	label := labelColor(want)
+	found := false
+	for dy := range cellH {
+		for dx := range cellW {
+			if img.RGBAAt(pad+5*cellW+dx, pad+titleH+cellH+dy) == label {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no label pixels in base0D swatch")
+	}
+}
+
+func TestCompareAlignsSlots(t *testing.T) {
+	t.Parallel()
+	a := loadPalette(t, "materialyou_dark16_bliss.json")
+	b := loadPalette(t, "kmeans_light24_bliss.json")
+	img := Compare([]Entry{{"a", a}, {"b", b}})
+
+	if got, want := img.Bounds().Dx(), 2*pad+nameW+24*stripCellW; got != want {
+		t.Fatalf("width = %d, want %d (widest palette)", got, want)
+	}
+	for row, p := range []*api.Palette{a, b} {
+		want, _ := api.ParseHex(p.Base0D)
+		x := pad + nameW + 0x0D*stripCellW + 1
+		y := pad + headerH + row*stripCellH + 1
+		if got := img.RGBAAt(x, y); got != want {
+			t.Errorf("row %d base0D = %v, want %v", row, got, want)
+		}
+	}
+}
+
+func TestANSI(t *testing.T) {
+	t.Parallel()
+	p := loadPalette(t, "materialyou_dark16_bliss.json")
+	var buf bytes.Buffer
+	if err := ANSI(&buf, Entry{Name: "bliss", Palette: p}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	c, _ := api.ParseHex(p.Base0D)
+	for _, want := range []string{"bliss (dark)", bgSeq(c), "#" + p.Base0D, "base0D"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	// Title plus two lines for each of two rows, each row ending in a reset.
+	if lines := strings.Count(out, "\n"); lines != 5 {
+		t.Errorf("lines = %d, want 5", lines)
+	}
+	if strings.Count(out, reset+"\n") != 4 {
+		t.Error("rows do not end with a reset")
+	}
+
+	buf.Reset()
+	if err := CompareANSI(&buf, []Entry{{"kmeans", p}, {"mediancut", p}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buf.String(), bgSeq(c)); got != 2 {
+		t.Errorf("base0D blocks = %d, want 2", got)
+	}
+}
+
+func TestLabelColor(t *testing.T) {
+	t.Parallel()
+	if got := labelColor(color.RGBA{0xf0, 0xf0, 0xf0, 255}); got.R != 0 {
+		t.Errorf("label on light gray = %v, want black", got)
+	}
+	if got := labelColor(color.RGBA{0x20, 0x20, 0x40, 255}); got.R != 255 {
+		t.Errorf("label on navy = %v, want white", got)
+	}
+}
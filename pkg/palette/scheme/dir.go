@@ -0,0 +1,115 @@
+package scheme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+)
+
+// List loads every scheme under dirs, sorted by slug. Files may sit in a
+// directory or one level below it (the tinted-theming/schemes layout keeps
+// base16/ and base24/ apart), in which case the slug keeps the subdirectory.
+// When dirs share a slug the earlier dir wins; unparsable files are logged
+// and skipped. Missing dirs are ignored.
+func List(ctx context.Context, dirs ...string) ([]*Scheme, error) {
+	logger := logging.GetLogger(ctx)
+	seen := map[string]bool{}
+	var out []*Scheme
+	for _, dir := range dirs {
+		paths, err := schemeFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			s, err := Load(path)
+			if err != nil {
+				logger.Warn("skipping palette scheme", "path", path, "error", err)
+				continue
+			}
+			s.Slug = relSlug(dir, path)
+			if seen[s.Slug] {
+				continue
+			}
+			seen[s.Slug] = true
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slug < out[j].Slug })
+	return out, nil
+}
+
+// Find resolves name to a scheme. name may be a path to a scheme file, a
+// full slug ("base24/dracula"), or a bare file name ("dracula"), which
+// matches the first scheme by List order (base16 before base24).
+func Find(ctx context.Context, name string, dirs ...string) (*Scheme, error) {
+	if strings.ContainsRune(name, os.PathSeparator) || isSchemeFile(name) {
+		if _, err := os.Stat(name); err == nil {
+			return Load(name)
+		}
+	}
+	schemes, err := List(ctx, dirs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schemes {
+		if s.Slug == name {
+			return s, nil
+		}
+	}
+	for _, s := range schemes {
+		if filepath.Base(s.Slug) == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s (searched %s)", ErrSchemeNotFound, name, strings.Join(dirs, ", "))
+}
+
+func schemeFiles(dir string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(d.Name(), ".") || filepath.Dir(path) != dir) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if isSchemeFile(path) {
+			out = append(out, path)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func isSchemeFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func slugOf(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func relSlug(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return slugOf(path)
+	}
+	return filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+}
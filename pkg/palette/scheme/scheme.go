@@ -0,0 +1,130 @@
+// Package scheme loads tinted-theming (base16/base24) scheme files.
+//
+// Both the current format (system/name/author/variant plus a palette map)
+// and the legacy flat base16 format (scheme/author plus top-level baseXX
+// keys) are accepted.
+package scheme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/lucasew/workspaced/pkg/palette/api"
+)
+
+// ErrSchemeNotFound is returned by Find for unknown scheme names.
+var ErrSchemeNotFound = errors.New("palette scheme not found")
+
+// ErrInvalidScheme is returned for files that are not a usable scheme.
+var ErrInvalidScheme = errors.New("invalid palette scheme")
+
+// Scheme is one parsed scheme file.
+type Scheme struct {
+	// Slug is the path below the scheme directory without extension
+	// (e.g. "gruvbox-dark" or "base24/dracula").
+	Slug    string       `json:"slug"`
+	Name    string       `json:"name"`
+	Author  string       `json:"author,omitempty"`
+	System  string       `json:"system"`  // "base16" or "base24"
+	Variant string       `json:"variant"` // "dark" or "light"
+	Path    string       `json:"path,omitempty"`
+	Palette *api.Palette `json:"palette"`
+}
+
+type schemeFile struct {
+	System  string            `yaml:"system"`
+	Name    string            `yaml:"name"`
+	Scheme  string            `yaml:"scheme"` // legacy name field
+	Author  string            `yaml:"author"`
+	Variant string            `yaml:"variant"`
+	Palette map[string]string `yaml:"palette"`
+}
+
+// Parse decodes a scheme from YAML.
+func Parse(data []byte) (*Scheme, error) {
+	var f schemeFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScheme, err)
+	}
+	colors := f.Palette
+	if len(colors) == 0 {
+		// Legacy format: baseXX keys at the top level.
+		var flat map[string]any
+		if err := yaml.Unmarshal(data, &flat); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidScheme, err)
+		}
+		colors = map[string]string{}
+		for k, v := range flat {
+			if s, ok := v.(string); ok && strings.HasPrefix(strings.ToLower(k), "base") {
+				colors[k] = s
+			}
+		}
+	}
+	lower := make(map[string]string, len(colors))
+	for k, v := range colors {
+		lower[strings.ToLower(k)] = v
+	}
+
+	s := &Scheme{
+		Name:    strings.TrimSpace(f.Name),
+		Author:  strings.TrimSpace(f.Author),
+		System:  strings.ToLower(strings.TrimSpace(f.System)),
+		Variant: strings.ToLower(strings.TrimSpace(f.Variant)),
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSpace(f.Scheme)
+	}
+	if s.System == "" {
+		s.System = "base16"
+		if _, ok := lower["base10"]; ok {
+			s.System = "base24"
+		}
+	}
+	n := 16
+	switch s.System {
+	case "base16":
+	case "base24":
+		n = 24
+	default:
+		return nil, fmt.Errorf("%w: unsupported system %q", ErrInvalidScheme, s.System)
+	}
+
+	hexes := make([]string, n)
+	for i := range hexes {
+		key := fmt.Sprintf("base%02x", i)
+		c, err := api.ParseHex(lower[key])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidScheme, key, err)
+		}
+		hexes[i] = api.ToHex(c)
+	}
+	s.Palette = api.PaletteFromHexes(hexes)
+
+	if s.Variant != "dark" && s.Variant != "light" {
+		s.Variant = api.Variant(s.Palette)
+	}
+	return s, nil
+}
+
+// Load parses the scheme file at path. Slug is the file name without
+// extension.
+func Load(path string) (*Scheme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.Path = path
+	s.Slug = slugOf(path)
+	if s.Name == "" {
+		s.Name = s.Slug
+	}
+	return s, nil
+}
@@ -0,0 +1,113 @@
+package scheme
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+)
+
+func TestParseLegacyFormat(t *testing.T) {
+	t.Parallel()
+	s, err := Load(palettetest.Path(t, "schemes/solarized-light.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Slug != "solarized-light" || s.Name != "Solarized Light" || s.System != "base16" {
+		t.Fatalf("scheme = %+v", s)
+	}
+	// No variant in the file: derived from base00 vs base05.
+	if s.Variant != "light" {
+		t.Fatalf("variant = %q, want light", s.Variant)
+	}
+	if s.Palette.Base00 != "fdf6e3" || s.Palette.Base0A != "b58900" || s.Palette.Base10 != "" {
+		t.Fatalf("palette = %+v", s.Palette)
+	}
+}
+
+func TestParseBase24(t *testing.T) {
+	t.Parallel()
+	s, err := Load(palettetest.Path(t, "schemes/base24/dracula.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.System != "base24" || s.Variant != "dark" {
+		t.Fatalf("scheme = %+v", s)
+	}
+	if s.Palette.Base0F != "bd93f9" || s.Palette.Base17 != "f5a3d2" {
+		t.Fatalf("palette = %+v", s.Palette)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"missing slots": "system: base16\npalette:\n  base00: '#000000'\n",
+		"bad hex":       strings.Replace(legacy, "dc322f", "nothex", 1),
+		"bad system":    "system: base32\n" + legacy,
+		"not yaml":      "palette: [",
+	}
+	for name, data := range cases {
+		if _, err := Parse([]byte(data)); !errors.Is(err, ErrInvalidScheme) {
+			t.Errorf("%s: error = %v, want ErrInvalidScheme", name, err)
+		}
+	}
+}
+
+func TestListAndFind(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	dir := palettetest.Path(t, "schemes")
+
+	schemes, err := List(ctx, dir, t.TempDir()+"/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slugs []string
+	for _, s := range schemes {
+		slugs = append(slugs, s.Slug)
+	}
+	// broken.yaml is skipped with a warning.
+	want := "base16/dracula,base16/gruvbox-dark,base24/dracula,solarized-light"
+	if got := strings.Join(slugs, ","); got != want {
+		t.Fatalf("slugs = %s, want %s", got, want)
+	}
+
+	s, err := Find(ctx, "dracula", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Slug != "base16/dracula" {
+		t.Fatalf("bare name resolved to %s, want base16/dracula", s.Slug)
+	}
+	if s, err = Find(ctx, "base24/dracula", dir); err != nil || s.System != "base24" {
+		t.Fatalf("Find(base24/dracula) = %+v, %v", s, err)
+	}
+	if s, err = Find(ctx, palettetest.Path(t, "schemes/solarized-light.yaml")); err != nil || s.Name != "Solarized Light" {
+		t.Fatalf("Find(path) = %+v, %v", s, err)
+	}
+	if _, err := Find(ctx, "nope", dir); !errors.Is(err, ErrSchemeNotFound) {
+		t.Fatalf("Find(nope) error = %v, want ErrSchemeNotFound", err)
+	}
+}
+
+const legacy = `scheme: "Solarized Light"
+base00: "fdf6e3"
+base01: "eee8d5"
+base02: "93a1a1"
+base03: "839496"
+base04: "657b83"
+base05: "586e75"
+base06: "073642"
+base07: "002b36"
+base08: "dc322f"
+base09: "cb4b16"
+base0A: "b58900"
+base0B: "859900"
+base0C: "2aa198"
+base0D: "268bd2"
+base0E: "6c71c4"
+base0F: "d33682"
+`
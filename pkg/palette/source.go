@@ -0,0 +1,309 @@
+package palette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasew/workspaced/internal/atomicfile"
+	"github.com/lucasew/workspaced/internal/configcue"
+	envdriver "github.com/lucasew/workspaced/pkg/driver/env"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette/api"
+	"github.com/lucasew/workspaced/pkg/palette/contrast"
+	"github.com/lucasew/workspaced/pkg/palette/scheme"
+)
+
+// ErrInvalidSource is returned when a palette source names neither a
+// scheme nor an image (or both).
+var ErrInvalidSource = errors.New("palette source needs exactly one of scheme or image")
+
+// ErrNoPalette is returned by LoadInput when no argument is given and no
+// desktop palette is configured.
+var ErrNoPalette = errors.New("no active palette: pass a palette, image or scheme")
+
+// Source selects the desktop palette (CUE workspaced.desktop.palette):
+// a named scheme file, or an image run through an extraction driver.
+type Source struct {
+	Scheme   string `json:"scheme,omitempty"`
+	Image    string `json:"image,omitempty"`
+	Driver   string `json:"driver,omitempty"`   // default "genetic"
+	Polarity string `json:"polarity,omitempty"` // default from desktop.dark_mode, else "any"
+	Colors   int    `json:"colors,omitempty"`   // default 16
+	// Contrast, when "aa" or "aaa", runs contrast.Fix on the result.
+	Contrast string `json:"contrast,omitempty"`
+}
+
+// Resolved is a palette plus where it came from.
+type Resolved struct {
+	Source  Source       `json:"source"`
+	Name    string       `json:"name"`
+	Variant string       `json:"variant"`
+	Palette *api.Palette `json:"palette"`
+}
+
+// TemplateData is the `.palette` value templates see: the baseXX keys of
+// the palette plus name and variant, so `paletteExport "kitty" .palette`
+// and `{{ .palette.base0D }}` both work.
+func (r *Resolved) TemplateData() map[string]any {
+	out := map[string]any{}
+	if data, err := json.Marshal(r.Palette); err == nil {
+		if err := json.Unmarshal(data, &out); err != nil {
+			out = map[string]any{}
+		}
+	}
+	out["name"] = r.Name
+	out["variant"] = r.Variant
+	return out
+}
+
+// Resolve loads src. Scheme names are looked up in dirs (see SchemeDirs).
+func Resolve(ctx context.Context, src Source, dirs []string) (*Resolved, error) {
+	r, err := resolve(ctx, src, dirs)
+	if err != nil || src.Contrast == "" {
+		return r, err
+	}
+	level, err := contrast.ParseLevel(src.Contrast)
+	if err != nil {
+		return nil, err
+	}
+	fixed, changes, err := contrast.Fix(r.Palette, level)
+	if err != nil {
+		return nil, fmt.Errorf("contrast: %w", err)
+	}
+	logger := logging.GetLogger(ctx)
+	for _, c := range changes {
+		logger.Debug("adjusted palette contrast", "slot", c.Slot, "from", c.From, "to", c.To, "delta_e", c.DeltaE, "met", c.Met)
+	}
+	r.Palette = fixed
+	return r, nil
+}
+
+func resolve(ctx context.Context, src Source, dirs []string) (*Resolved, error) {
+	switch {
+	case (src.Scheme == "") == (src.Image == ""):
+		return nil, ErrInvalidSource
+	case src.Scheme != "":
+		s, err := scheme.Find(ctx, src.Scheme, dirs...)
+		if err != nil {
+			return nil, err
+		}
+		return &Resolved{Source: src, Name: s.Name, Variant: s.Variant, Palette: s.Palette}, nil
+	}
+
+	image, err := envdriver.ExpandPathContext(ctx, src.Image)
+	if err != nil {
+		return nil, err
+	}
+	driver := src.Driver
+	if driver == "" {
+		driver = "genetic"
+	}
+	polarity := src.Polarity
+	if polarity == "" {
+		polarity = "any"
+	}
+	pol, err := ParsePolarity(polarity)
+	if err != nil {
+		return nil, err
+	}
+	colors := src.Colors
+	if colors == 0 {
+		colors = 16
+	}
+	p, err := ExtractCached(ctx, image, driver, api.Options{Polarity: pol, ColorCount: colors, MaxSamples: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("palette from %s: %w", src.Image, err)
+	}
+	name := strings.TrimSuffix(filepath.Base(image), filepath.Ext(image))
+	return &Resolved{Source: src, Name: name, Variant: api.Variant(p), Palette: p}, nil
+}
+
+// Input is a palette named on the command line.
+type Input struct {
+	// Arg is a palette .json file, an image, a scheme name, "-" for palette
+	// JSON on Stdin, or empty for the active desktop palette.
+	Arg   string
+	Stdin io.Reader
+	// Dirs are searched for scheme names; empty means SchemeDirs.
+	Dirs []string
+	// Driver and Options apply when Arg is an image.
+	Driver  string
+	Options api.Options
+	// Raw skips the contrast adjustment of the active palette's source.
+	Raw bool
+}
+
+// LoadInput resolves in the way the palette commands read their argument.
+func LoadInput(ctx context.Context, in Input) (*Resolved, error) {
+	if in.Arg == "-" {
+		p, err := DecodeJSON(in.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return &Resolved{Name: "stdin", Variant: api.Variant(p), Palette: p}, nil
+	}
+	if info, err := os.Stat(in.Arg); in.Arg != "" && err == nil && !info.IsDir() {
+		p, err := LoadFile(ctx, in.Arg, in.Driver, in.Options)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(in.Arg), filepath.Ext(in.Arg))
+		return &Resolved{Name: name, Variant: api.Variant(p), Palette: p}, nil
+	}
+
+	cfg, err := configcue.LoadHome(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	dirs := in.Dirs
+	if len(dirs) == 0 {
+		if dirs, err = SchemeDirs(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	src, ok := Source{Scheme: in.Arg}, in.Arg != ""
+	if !ok {
+		if src, ok, err = ConfigSource(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		return nil, ErrNoPalette
+	}
+	if in.Raw {
+		src.Contrast = ""
+	}
+	return Resolve(ctx, src, dirs)
+}
+
+// ConfigSource returns the active palette source: the one recorded by
+// `palette apply`, else workspaced.desktop.palette. ok is false when
+// neither is set. An unset image polarity follows desktop.dark_mode.
+func ConfigSource(ctx context.Context, cfg *configcue.Config) (src Source, ok bool, err error) {
+	if applied, found, err := AppliedSource(ctx); err != nil {
+		return Source{}, false, err
+	} else if found {
+		src, ok = applied, true
+	} else if cfg != nil {
+		if _, lookupErr := cfg.Lookup("desktop.palette"); lookupErr == nil {
+			if err := cfg.Decode("desktop.palette", &src); err != nil {
+				return Source{}, false, fmt.Errorf("decode desktop.palette: %w", err)
+			}
+			ok = true
+		}
+	}
+	if ok && src.Image != "" && src.Polarity == "" && cfg != nil {
+		var dark bool
+		if err := cfg.Decode("desktop.dark_mode", &dark); err == nil {
+			src.Polarity = "light"
+			if dark {
+				src.Polarity = "dark"
+			}
+		}
+	}
+	return src, ok, nil
+}
+
+// FromConfig resolves the active palette (see ConfigSource). It returns
+// nil without error when no palette is configured.
+func FromConfig(ctx context.Context, cfg *configcue.Config) (*Resolved, error) {
+	src, ok, err := ConfigSource(ctx, cfg)
+	if err != nil || !ok {
+		return nil, err
+	}
+	dirs, err := SchemeDirs(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Resolve(ctx, src, dirs)
+}
+
+// SchemeDirs returns the directories searched for scheme files:
+// workspaced.desktop.palette_dirs when set, else <dotfiles>/palettes and
+// ~/.local/share/workspaced/palettes.
+func SchemeDirs(ctx context.Context, cfg *configcue.Config) ([]string, error) {
+	var dirs []string
+	if cfg != nil {
+		if _, err := cfg.Lookup("desktop.palette_dirs"); err == nil {
+			if err := cfg.Decode("desktop.palette_dirs", &dirs); err != nil {
+				return nil, fmt.Errorf("decode desktop.palette_dirs: %w", err)
+			}
+			for i, dir := range dirs {
+				expanded, err := envdriver.ExpandPathContext(ctx, dir)
+				if err != nil {
+					return nil, err
+				}
+				dirs[i] = expanded
+			}
+			return dirs, nil
+		}
+	}
+	if root, err := envdriver.GetDotfilesRoot(ctx); err == nil && root != "" {
+		dirs = append(dirs, filepath.Join(root, "palettes"))
+	}
+	dataDir, err := envdriver.GetUserDataDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(dirs, filepath.Join(dataDir, "palettes")), nil
+}
+
+// AppliedSource reads the source recorded by SetAppliedSource.
+func AppliedSource(ctx context.Context) (Source, bool, error) {
+	path, err := appliedSourcePath(ctx)
+	if err != nil {
+		return Source{}, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Source{}, false, nil
+	}
+	if err != nil {
+		return Source{}, false, err
+	}
+	var src Source
+	if err := json.Unmarshal(data, &src); err != nil {
+		return Source{}, false, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return src, true, nil
+}
+
+// SetAppliedSource records src as the active palette, overriding
+// workspaced.desktop.palette until ClearAppliedSource.
+func SetAppliedSource(ctx context.Context, src Source) error {
+	path, err := appliedSourcePath(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(src, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteBytes(path, append(data, '\n'), 0o644)
+}
+
+// ClearAppliedSource drops the `palette apply` override.
+func ClearAppliedSource(ctx context.Context) error {
+	path, err := appliedSourcePath(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func appliedSourcePath(ctx context.Context) (string, error) {
+	dataDir, err := envdriver.GetUserDataDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "palette", "applied.json"), nil
+}
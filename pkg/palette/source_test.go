@@ -0,0 +1,104 @@
+package palette_test
+
+import (
+	"errors"
+	"testing"
+
+	_ "github.com/lucasew/workspaced/pkg/driver/env/native"
+	"github.com/lucasew/workspaced/pkg/logging"
+	"github.com/lucasew/workspaced/pkg/palette"
+	"github.com/lucasew/workspaced/pkg/palette/contrast"
+	"github.com/lucasew/workspaced/pkg/palette/palettetest"
+	_ "github.com/lucasew/workspaced/pkg/palette/prelude"
+	"github.com/lucasew/workspaced/pkg/palette/scheme"
+)
+
+func TestResolveScheme(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	dirs := []string{palettetest.Path(t, "schemes")}
+
+	r, err := palette.Resolve(ctx, palette.Source{Scheme: "gruvbox-dark"}, dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "Gruvbox dark, medium" || r.Variant != "dark" || r.Palette.Base08 != "fb4934" {
+		t.Fatalf("resolved = %+v", r)
+	}
+	data := r.TemplateData()
+	if data["base0D"] != "83a598" || data["variant"] != "dark" || data["name"] != r.Name {
+		t.Fatalf("template data = %v", data)
+	}
+	if _, ok := data["base10"]; ok {
+		t.Fatalf("base16 scheme leaked empty base24 keys: %v", data)
+	}
+
+	if _, err := palette.Resolve(ctx, palette.Source{Scheme: "nope"}, dirs); !errors.Is(err, scheme.ErrSchemeNotFound) {
+		t.Fatalf("unknown scheme error = %v", err)
+	}
+	if _, err := palette.Resolve(ctx, palette.Source{Scheme: "a", Image: "b"}, dirs); !errors.Is(err, palette.ErrInvalidSource) {
+		t.Fatalf("scheme+image error = %v", err)
+	}
+}
+
+func TestResolveContrast(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	dirs := []string{palettetest.Path(t, "schemes")}
+	plain, err := palette.Resolve(ctx, palette.Source{Scheme: "solarized-light"}, dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := palette.Resolve(ctx, palette.Source{Scheme: "solarized-light", Contrast: "aaa"}, dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *fixed.Palette == *plain.Palette {
+		t.Fatal("contrast: aaa left solarized-light unchanged")
+	}
+	if fixed.Palette.Base00 != plain.Palette.Base00 {
+		t.Fatalf("background moved: %s -> %s", plain.Palette.Base00, fixed.Palette.Base00)
+	}
+	if _, err := palette.Resolve(ctx, palette.Source{Scheme: "solarized-light", Contrast: "max"}, dirs); !errors.Is(err, contrast.ErrUnknownLevel) {
+		t.Fatalf("bad level error = %v", err)
+	}
+}
+
+func TestResolveImage(t *testing.T) {
+	t.Parallel()
+	ctx := logging.NewWriterContext(t.Output())
+	r, err := palette.Resolve(ctx, palette.Source{
+		Image:    palettetest.Path(t, "solid_4285f4.png"),
+		Driver:   "materialyou",
+		Polarity: "light",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "solid_4285f4" || r.Variant != "light" || r.Palette.Base00 == "" {
+		t.Fatalf("resolved = %+v", r)
+	}
+}
+
+func TestAppliedSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := logging.NewWriterContext(t.Output())
+
+	if _, ok, err := palette.AppliedSource(ctx); err != nil || ok {
+		t.Fatalf("AppliedSource before apply = %v, %v", ok, err)
+	}
+	if err := palette.SetAppliedSource(ctx, palette.Source{Scheme: "dracula"}); err != nil {
+		t.Fatal(err)
+	}
+	// The applied source wins over (here: absent) config.
+	src, ok, err := palette.ConfigSource(ctx, nil)
+	if err != nil || !ok || src.Scheme != "dracula" {
+		t.Fatalf("ConfigSource = %+v, %v, %v", src, ok, err)
+	}
+	if err := palette.ClearAppliedSource(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := palette.ConfigSource(ctx, nil); err != nil || ok {
+		t.Fatalf("ConfigSource after clear = %v, %v", ok, err)
+	}
+}
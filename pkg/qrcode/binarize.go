@@ -0,0 +1,127 @@
+package qrcode
+
+import (
+	"image"
+	"image/color"
+)
+
+// bitmap is a thresholded image; true is dark.
+type bitmap struct {
+	w, h int
+	bits []bool
+}
+
+func (b *bitmap) at(x, y int) bool {
+	if x < 0 || y < 0 || x >= b.w || y >= b.h {
+		return false
+	}
+	return b.bits[y*b.w+x]
+}
+
+func (b *bitmap) inverted() *bitmap {
+	inv := &bitmap{w: b.w, h: b.h, bits: make([]bool, len(b.bits))}
+	for i, v := range b.bits {
+		inv.bits[i] = !v
+	}
+	return inv
+}
+
+// luminance is an 8-bit gray copy of img.
+type luminance struct {
+	w, h int
+	pix  []uint8
+}
+
+func toLuminance(img image.Image) *luminance {
+	bounds := img.Bounds()
+	l := &luminance{w: bounds.Dx(), h: bounds.Dy()}
+	l.pix = make([]uint8, l.w*l.h)
+	switch src := img.(type) {
+	case *image.Gray:
+		for y := range l.h {
+			off := src.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			copy(l.pix[y*l.w:(y+1)*l.w], src.Pix[off:off+l.w])
+		}
+	case *image.YCbCr:
+		for y := range l.h {
+			off := src.YOffset(bounds.Min.X, bounds.Min.Y+y)
+			copy(l.pix[y*l.w:(y+1)*l.w], src.Y[off:off+l.w])
+		}
+	default:
+		for y := range l.h {
+			for x := range l.w {
+				g := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+				l.pix[y*l.w+x] = g.Y
+			}
+		}
+	}
+	return l
+}
+
+// adaptiveThreshold marks a pixel dark when it is clearly darker than the
+// mean of the window around it, which copes with uneven lighting. The
+// window is wide compared to a module so the solid centers of finder
+// patterns are not mistaken for background.
+func adaptiveThreshold(l *luminance) *bitmap {
+	w, h := l.w, l.h
+	integral := make([]int, (w+1)*(h+1))
+	for y := range h {
+		row := 0
+		for x := range w {
+			row += int(l.pix[y*w+x])
+			integral[(y+1)*(w+1)+x+1] = integral[y*(w+1)+x+1] + row
+		}
+	}
+	radius := max(8, max(w, h)/8)
+	b := &bitmap{w: w, h: h, bits: make([]bool, w*h)}
+	for y := range h {
+		y0, y1 := max(0, y-radius), min(h, y+radius+1)
+		for x := range w {
+			x0, x1 := max(0, x-radius), min(w, x+radius+1)
+			sum := integral[y1*(w+1)+x1] - integral[y0*(w+1)+x1] - integral[y1*(w+1)+x0] + integral[y0*(w+1)+x0]
+			count := (x1 - x0) * (y1 - y0)
+			// Dark when 15% below the local mean.
+			b.bits[y*w+x] = int(l.pix[y*w+x])*count*100 < sum*85
+		}
+	}
+	return b
+}
+
+// globalThreshold splits the histogram with Otsu's method, for clean
+// images where the code fills most of the frame.
+func globalThreshold(l *luminance) *bitmap {
+	var hist [256]int
+	for _, p := range l.pix {
+		hist[p]++
+	}
+	total := len(l.pix)
+	sum := 0
+	for i, c := range hist {
+		sum += i * c
+	}
+	var sumB, weightB int
+	var best float64
+	threshold := 128
+	for t, c := range hist {
+		weightB += c
+		if weightB == 0 {
+			continue
+		}
+		weightF := total - weightB
+		if weightF == 0 {
+			break
+		}
+		sumB += t * c
+		meanB := float64(sumB) / float64(weightB)
+		meanF := float64(sum-sumB) / float64(weightF)
+		between := float64(weightB) * float64(weightF) * (meanB - meanF) * (meanB - meanF)
+		if between > best {
+			best, threshold = between, t
+		}
+	}
+	b := &bitmap{w: l.w, h: l.h, bits: make([]bool, len(l.pix))}
+	for i, p := range l.pix {
+		b.bits[i] = int(p) <= threshold
+	}
+	return b
+}
@@ -0,0 +1,212 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+var errTruncated = errors.New("data ends inside a segment")
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (b *bitReader) left() int { return len(b.data)*8 - b.pos }
+
+func (b *bitReader) read(n int) (int, error) {
+	if n > b.left() {
+		return 0, errTruncated
+	}
+	v := 0
+	for range n {
+		bit := b.data[b.pos/8] >> (7 - b.pos%8) & 1
+		v = v<<1 | int(bit)
+		b.pos++
+	}
+	return v, nil
+}
+
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// ECI assignment numbers with a known text encoding.
+const (
+	eciLatin1   = 3
+	eciShiftJIS = 20
+	eciUTF8     = 26
+)
+
+// parseData decodes the segments of the corrected data codewords.
+func parseData(data []byte, version int) (string, error) {
+	r := &bitReader{data: data}
+	var out strings.Builder
+	eci := -1
+
+	countBits := func(small, medium, large int) int {
+		switch {
+		case version <= 9:
+			return small
+		case version <= 26:
+			return medium
+		}
+		return large
+	}
+
+	for r.left() >= 4 {
+		mode, _ := r.read(4)
+		switch mode {
+		case 0x0:
+			return out.String(), nil
+		case 0x1: // numeric
+			count, err := r.read(countBits(10, 12, 14))
+			if err != nil {
+				return "", err
+			}
+			for ; count >= 3; count -= 3 {
+				v, err := r.read(10)
+				if err != nil || v > 999 {
+					return "", fmt.Errorf("invalid numeric segment")
+				}
+				fmt.Fprintf(&out, "%03d", v)
+			}
+			if count == 2 {
+				v, err := r.read(7)
+				if err != nil || v > 99 {
+					return "", fmt.Errorf("invalid numeric segment")
+				}
+				fmt.Fprintf(&out, "%02d", v)
+			} else if count == 1 {
+				v, err := r.read(4)
+				if err != nil || v > 9 {
+					return "", fmt.Errorf("invalid numeric segment")
+				}
+				fmt.Fprintf(&out, "%d", v)
+			}
+		case 0x2: // alphanumeric
+			count, err := r.read(countBits(9, 11, 13))
+			if err != nil {
+				return "", err
+			}
+			for ; count >= 2; count -= 2 {
+				v, err := r.read(11)
+				if err != nil || v >= 45*45 {
+					return "", fmt.Errorf("invalid alphanumeric segment")
+				}
+				out.WriteByte(alphanumeric[v/45])
+				out.WriteByte(alphanumeric[v%45])
+			}
+			if count == 1 {
+				v, err := r.read(6)
+				if err != nil || v >= 45 {
+					return "", fmt.Errorf("invalid alphanumeric segment")
+				}
+				out.WriteByte(alphanumeric[v])
+			}
+		case 0x4: // byte
+			count, err := r.read(countBits(8, 16, 16))
+			if err != nil {
+				return "", err
+			}
+			raw := make([]byte, count)
+			for i := range raw {
+				v, err := r.read(8)
+				if err != nil {
+					return "", err
+				}
+				raw[i] = byte(v)
+			}
+			text, err := decodeBytes(raw, eci)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(text)
+		case 0x8: // kanji, 13 bits per Shift JIS character
+			count, err := r.read(countBits(8, 10, 12))
+			if err != nil {
+				return "", err
+			}
+			raw := make([]byte, 0, 2*count)
+			for range count {
+				v, err := r.read(13)
+				if err != nil {
+					return "", err
+				}
+				sjis := v/0xc0<<8 | v%0xc0
+				if sjis < 0x1f00 {
+					sjis += 0x8140
+				} else {
+					sjis += 0xc140
+				}
+				raw = append(raw, byte(sjis>>8), byte(sjis))
+			}
+			text, err := japanese.ShiftJIS.NewDecoder().Bytes(raw)
+			if err != nil {
+				return "", fmt.Errorf("invalid kanji segment: %w", err)
+			}
+			out.Write(text)
+		case 0x7: // ECI designator, 1 to 3 bytes by its leading bits
+			first, err := r.read(8)
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case first&0x80 == 0:
+				eci = first
+			case first&0xc0 == 0x80:
+				next, err := r.read(8)
+				if err != nil {
+					return "", err
+				}
+				eci = (first&0x3f)<<8 | next
+			default:
+				next, err := r.read(16)
+				if err != nil {
+					return "", err
+				}
+				eci = (first&0x1f)<<16 | next
+			}
+		case 0x3: // structured append: symbol index, count and parity
+			if _, err := r.read(16); err != nil {
+				return "", err
+			}
+		case 0x5: // FNC1 in first position
+		case 0x9: // FNC1 in second position, with an application indicator
+			if _, err := r.read(8); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("unknown segment mode %#x", mode)
+		}
+	}
+	return out.String(), nil
+}
+
+// decodeBytes interprets a byte segment. Without an ECI the standard says
+// ISO-8859-1, but nearly every encoder writes UTF-8, so valid UTF-8 wins.
+func decodeBytes(raw []byte, eci int) (string, error) {
+	switch eci {
+	case eciShiftJIS:
+		text, err := japanese.ShiftJIS.NewDecoder().Bytes(raw)
+		return string(text), err
+	case eciLatin1:
+		return latin1(raw), nil
+	case eciUTF8:
+		return string(raw), nil
+	}
+	if utf8.Valid(raw) {
+		return string(raw), nil
+	}
+	return latin1(raw), nil
+}
+
+func latin1(raw []byte) string {
+	var b strings.Builder
+	for _, c := range raw {
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package qrcode
+
+import "testing"
+
+// bitWriter packs segments for parseData.
+type bitWriter struct {
+	data []byte
+	n    int
+}
+
+func (w *bitWriter) write(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if v>>i&1 == 1 {
+			w.data[w.n/8] |= 0x80 >> (w.n % 8)
+		}
+		w.n++
+	}
+}
+
+func TestParseData(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		build   func(w *bitWriter)
+		want    string
+	}{
+		{"numeric", 1, func(w *bitWriter) {
+			// The standard's "01234567".
+			w.write(0x1, 4)
+			w.write(8, 10)
+			w.write(12, 10)
+			w.write(345, 10)
+			w.write(67, 7)
+		}, "01234567"},
+		{"alphanumeric", 1, func(w *bitWriter) {
+			w.write(0x2, 4)
+			w.write(5, 9)
+			w.write(10*45+11, 11) // AB
+			w.write(12*45+36, 11) // "C "
+			w.write(44, 6)        // :
+		}, "ABC :"},
+		{"byte with wide count", 10, func(w *bitWriter) {
+			w.write(0x4, 4)
+			w.write(3, 16)
+			for _, c := range []byte("ok!") {
+				w.write(int(c), 8)
+			}
+		}, "ok!"},
+		{"utf-8 bytes", 1, func(w *bitWriter) {
+			w.write(0x4, 4)
+			w.write(2, 8)
+			w.write(0xc3, 8)
+			w.write(0xa9, 8)
+		}, "é"},
+		{"latin1 eci", 1, func(w *bitWriter) {
+			w.write(0x7, 4)
+			w.write(eciLatin1, 8)
+			w.write(0x4, 4)
+			w.write(1, 8)
+			w.write(0xe9, 8)
+		}, "é"},
+		{"kanji", 1, func(w *bitWriter) {
+			// 点 is 0x935f in Shift JIS: 0x935f-0x8140 = 0x121f,
+			// packed as 0x12*0xc0+0x1f.
+			w.write(0x8, 4)
+			w.write(1, 8)
+			w.write(0xd9f, 13)
+		}, "点"},
+		{"mixed with terminator and padding", 1, func(w *bitWriter) {
+			w.write(0x1, 4)
+			w.write(2, 10)
+			w.write(42, 7)
+			w.write(0x2, 4)
+			w.write(1, 9)
+			w.write(36, 6)
+			w.write(0, 4)
+			w.write(0xec11, 16)
+		}, "42 "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bitWriter{}
+			tt.build(w)
+			got, err := parseData(w.data, tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDataTruncated(t *testing.T) {
+	w := &bitWriter{}
+	w.write(0x4, 4)
+	w.write(10, 8)
+	w.write('a', 8)
+	if _, err := parseData(w.data, 1); err == nil {
+		t.Fatal("expected an error for a short byte segment")
+	}
+}
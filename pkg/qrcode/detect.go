@@ -0,0 +1,437 @@
+package qrcode
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// finder is a candidate finder pattern: the 7x7 squares in three corners.
+type finder struct {
+	point
+	module float64
+	count  int
+}
+
+// ratioOK checks runs against the 1:1:3:1:1 profile of a finder.
+func ratioOK(c [5]int) bool {
+	total := c[0] + c[1] + c[2] + c[3] + c[4]
+	if total < 7 {
+		return false
+	}
+	m := float64(total) / 7
+	tol := m / 2
+	return math.Abs(float64(c[0])-m) < tol &&
+		math.Abs(float64(c[1])-m) < tol &&
+		math.Abs(float64(c[2])-3*m) < 3*tol &&
+		math.Abs(float64(c[3])-m) < tol &&
+		math.Abs(float64(c[4])-m) < tol
+}
+
+// crossCheck measures the finder profile through (x, y) along (dx, dy),
+// returning the runs and the center offset along that direction.
+func crossCheck(b *bitmap, x, y, dx, dy, limit int) (c [5]int, center float64, ok bool) {
+	at := func(i int) bool { return b.at(x+i*dx, y+i*dy) }
+	if !at(0) {
+		return c, 0, false
+	}
+	i := 0
+	for ; at(i) && -i <= limit; i-- {
+		c[2]++
+	}
+	lo := i + 1
+	for ; !at(i) && -i <= limit; i-- {
+		c[1]++
+	}
+	for ; at(i) && -i <= limit; i-- {
+		c[0]++
+	}
+	i = 1
+	for ; at(i) && i <= limit; i++ {
+		c[2]++
+	}
+	hi := i
+	for ; !at(i) && i <= limit; i++ {
+		c[3]++
+	}
+	for ; at(i) && i <= limit; i++ {
+		c[4]++
+	}
+	return c, float64(lo+hi) / 2, ratioOK(c)
+}
+
+// findFinders scans every row for the finder profile, confirms each hit
+// vertically and horizontally, and merges hits on the same pattern.
+func findFinders(b *bitmap) []finder {
+	var found []finder
+	runs := make([]int, 0, 64)
+	for y := range b.h {
+		// Runs of the row, starting with a light one (possibly empty).
+		runs = runs[:0]
+		dark := false
+		n := 0
+		for x := range b.w {
+			if b.at(x, y) != dark {
+				runs = append(runs, n)
+				dark = !dark
+				n = 0
+			}
+			n++
+		}
+		runs = append(runs, n)
+
+		start := 0
+		for i := 0; i+5 <= len(runs); i++ {
+			if i > 0 {
+				start += runs[i-1]
+			}
+			if i%2 == 0 {
+				// Light run; finders start dark.
+				continue
+			}
+			h := [5]int{runs[i], runs[i+1], runs[i+2], runs[i+3], runs[i+4]}
+			if !ratioOK(h) {
+				continue
+			}
+			hTotal := h[0] + h[1] + h[2] + h[3] + h[4]
+			cx := float64(start+h[0]+h[1]) + float64(h[2])/2
+			v, dy, ok := crossCheck(b, int(cx), y, 0, 1, 2*hTotal)
+			if !ok {
+				continue
+			}
+			vTotal := v[0] + v[1] + v[2] + v[3] + v[4]
+			if 5*abs(vTotal-hTotal) >= 2*hTotal {
+				continue
+			}
+			cy := float64(y) + dy
+			h2, dx, ok := crossCheck(b, int(cx), int(cy), 1, 0, 2*hTotal)
+			if !ok {
+				continue
+			}
+			cx = float64(int(cx)) + dx
+			total := h2[0] + h2[1] + h2[2] + h2[3] + h2[4]
+			found = addFinder(found, finder{point{cx, cy}, float64(total+vTotal) / 14, 1})
+		}
+	}
+	return found
+}
+
+func addFinder(found []finder, f finder) []finder {
+	for i, g := range found {
+		if math.Abs(g.x-f.x) <= g.module && math.Abs(g.y-f.y) <= g.module &&
+			math.Abs(g.module-f.module) <= math.Max(1, g.module/2) {
+			n := float64(g.count)
+			found[i] = finder{
+				point{(g.x*n + f.x) / (n + 1), (g.y*n + f.y) / (n + 1)},
+				(g.module*n + f.module) / (n + 1),
+				g.count + 1,
+			}
+			return found
+		}
+	}
+	return append(found, f)
+}
+
+// corners is three finders ordered top-left, top-right, bottom-left in
+// symbol coordinates.
+type corners struct {
+	tl, tr, bl finder
+}
+
+// triples picks plausible sets of three finders, best first: similar
+// module sizes, at the corners of a near isosceles right triangle.
+func triples(found []finder) []corners {
+	// A real finder is crossed by several rows; single hits are noise.
+	found = slices.DeleteFunc(found, func(f finder) bool { return f.count < 2 })
+	slices.SortFunc(found, func(a, b finder) int { return cmp.Compare(b.count, a.count) })
+	found = found[:min(len(found), 12)]
+
+	type scored struct {
+		corners
+		score float64
+	}
+	var ret []scored
+	for i := range found {
+		for j := i + 1; j < len(found); j++ {
+			for k := j + 1; k < len(found); k++ {
+				a, b, c := found[i], found[j], found[k]
+				minModule := min(a.module, b.module, c.module)
+				if max(a.module, b.module, c.module) > 2*minModule {
+					continue
+				}
+				// The right angle is opposite the longest side.
+				ab, ac, bc := a.dist(b.point), a.dist(c.point), b.dist(c.point)
+				switch {
+				case bc >= ab && bc >= ac:
+				case ac >= ab:
+					a, b = b, a
+					ac, bc = bc, ac
+				default:
+					a, c = c, a
+					ab, bc = bc, ab
+				}
+				legMin, legMax := min(ab, ac), max(ab, ac)
+				if legMin < 10*minModule || legMax > 1.6*legMin {
+					continue
+				}
+				angle := math.Abs(bc*bc-ab*ab-ac*ac) / (bc * bc)
+				if angle > 0.5 {
+					continue
+				}
+				// Image y grows down, so top-right is counterclockwise.
+				if (b.x-a.x)*(c.y-a.y)-(b.y-a.y)*(c.x-a.x) < 0 {
+					b, c = c, b
+				}
+				ret = append(ret, scored{corners{a, b, c}, angle + (legMax-legMin)/legMax})
+			}
+		}
+	}
+	slices.SortFunc(ret, func(a, b scored) int { return cmp.Compare(a.score, b.score) })
+	out := make([]corners, len(ret))
+	for i, s := range ret {
+		out[i] = s.corners
+	}
+	return out
+}
+
+// runModules measures a finder along the line from one finder center
+// towards another: center, light ring and dark ring span 3.5 modules each
+// way. Measuring along the line keeps rotation from inflating the size.
+func runModules(b *bitmap, from, to point) float64 {
+	d := from.dist(to)
+	ux, uy := (to.x-from.x)/d, (to.y-from.y)/d
+	edge := func(sign float64) float64 {
+		dark := true
+		changes := 0
+		for t := 0.0; t < d; t += 0.5 {
+			x, y := from.x+sign*t*ux, from.y+sign*t*uy
+			if b.at(int(math.Floor(x)), int(math.Floor(y))) != dark {
+				dark = !dark
+				changes++
+				if changes == 3 {
+					return t
+				}
+			}
+		}
+		return math.NaN()
+	}
+	return (edge(1) + edge(-1)) / 7
+}
+
+// dimensions estimates the symbol size from the finder distances, nearest
+// first. Sizes are 4*version+17.
+func (c corners) dimensions(b *bitmap) []int {
+	modTR := (runModules(b, c.tl.point, c.tr.point) + runModules(b, c.tr.point, c.tl.point)) / 2
+	modBL := (runModules(b, c.tl.point, c.bl.point) + runModules(b, c.bl.point, c.tl.point)) / 2
+	if math.IsNaN(modTR) || math.IsNaN(modBL) {
+		modTR = (c.tl.module + c.tr.module) / 2
+		modBL = (c.tl.module + c.bl.module) / 2
+	}
+	est := (c.tl.dist(c.tr.point)/modTR+c.tl.dist(c.bl.point)/modBL)/2 + 7
+	version := int(math.Round((est - 17) / 4))
+	var ret []int
+	for _, v := range []int{version, version - 1, version + 1} {
+		if v >= 1 && v <= 40 {
+			ret = append(ret, 17+4*v)
+		}
+	}
+	return ret
+}
+
+// grid is a sampled symbol; true is dark.
+type grid struct {
+	dim  int
+	bits []bool
+}
+
+func (g *grid) at(r, c int) bool {
+	return g.bits[r*g.dim+c]
+}
+
+// axes are the image steps of one module along the symbol rows and
+// columns, as the finders see them.
+func (c corners) axes(dim int) (u, v point) {
+	span := float64(dim - 7)
+	u = point{(c.tr.x - c.tl.x) / span, (c.tr.y - c.tl.y) / span}
+	v = point{(c.bl.x - c.tl.x) / span, (c.bl.y - c.tl.y) / span}
+	return u, v
+}
+
+// fourth picks the point that, with the finders, fixes the perspective:
+// the bottom-right alignment pattern when there is one, otherwise the
+// corner that completes a parallelogram. It returns the symbol and image
+// coordinates and whether an alignment pattern was found.
+func fourth(b *bitmap, c corners, dim int) (from, to point, aligned bool) {
+	if dim > 21 {
+		if p, ok := findAlignment(b, c, dim); ok {
+			return point{float64(dim) - 6.5, float64(dim) - 6.5}, p, true
+		}
+	}
+	far := float64(dim) - 3.5
+	return point{far, far}, point{c.tr.x + c.bl.x - c.tl.x, c.tr.y + c.bl.y - c.tl.y}, false
+}
+
+// sample maps the symbol of size dim onto the image through the finders
+// and the fourth point, reading each module at its center.
+func sample(b *bitmap, c corners, dim int, from4, to4 point) *grid {
+	far := float64(dim) - 3.5
+	t := quadToQuad(
+		[4]point{{3.5, 3.5}, {far, 3.5}, from4, {3.5, far}},
+		[4]point{c.tl.point, c.tr.point, to4, c.bl.point},
+	)
+	g := &grid{dim: dim, bits: make([]bool, dim*dim)}
+	for r := range dim {
+		for col := range dim {
+			p := t.apply(point{float64(col) + 0.5, float64(r) + 0.5})
+			g.bits[r*dim+col] = b.at(int(math.Floor(p.x)), int(math.Floor(p.y)))
+		}
+	}
+	return g
+}
+
+// decodeAt samples and decodes a symbol of size dim.
+func decodeAt(b *bitmap, c corners, dim int) (*Code, error) {
+	from4, to4, aligned := fourth(b, c, dim)
+	g := sample(b, c, dim, from4, to4)
+	if dim >= 45 {
+		// Version 7 and up carry their version; trust it over the
+		// estimate from the finder spacing.
+		if v := readVersion(g); v != 0 && 17+4*v != dim {
+			dim = 17 + 4*v
+			from4, to4, aligned = fourth(b, c, dim)
+			g = sample(b, c, dim, from4, to4)
+		}
+	}
+	if timing(g) < 0.7 {
+		return nil, errNoTiming
+	}
+	code, err := decodeGrid(g)
+	if err == nil || aligned {
+		return code, err
+	}
+	// The parallelogram ignores perspective, which matters most for
+	// version 1 where nothing else pins the far corner. Nudge it around
+	// and let error correction tell which guess is right.
+	u, v := c.axes(dim)
+	for _, du := range nudges {
+		for _, dv := range nudges {
+			if du == 0 && dv == 0 {
+				continue
+			}
+			p := point{to4.x + du*u.x + dv*v.x, to4.y + du*u.y + dv*v.y}
+			if code, err := decodeGrid(sample(b, c, dim, from4, p)); err == nil {
+				return code, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+var errNoTiming = errors.New("timing patterns do not line up")
+
+// timing is the fraction of the timing pattern modules that read as
+// expected, a cheap sign that the grid sits on a symbol at all.
+func timing(g *grid) float64 {
+	good, n := 0, 0
+	for i := 8; i < g.dim-8; i++ {
+		want := i%2 == 0
+		if g.at(6, i) == want {
+			good++
+		}
+		if g.at(i, 6) == want {
+			good++
+		}
+		n += 2
+	}
+	return float64(good) / float64(n)
+}
+
+// nudges are the offsets, in modules, tried for the far corner.
+var nudges = []float64{0, -0.5, 0.5, -1, 1, -1.5, 1.5, -2, 2}
+
+// findAlignment looks for the bottom-right alignment pattern near where
+// the finders put it, matching the 5x5 pattern module by module. The search
+// widens, and the pattern is rescaled, to follow strong perspective.
+func findAlignment(b *bitmap, c corners, dim int) (point, bool) {
+	u, v := c.axes(dim)
+	steps := float64(dim) - 10
+	est := point{c.tl.x + steps*(u.x+v.x), c.tl.y + steps*(u.y+v.y)}
+	module := math.Hypot(u.x, u.y)
+
+	for _, reach := range []float64{4, 8} {
+		radius := int(math.Ceil(reach * module))
+		for _, scale := range []float64{1, 0.8, 1.25, 0.65} {
+			best := 0
+			var hits []point
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					p := point{est.x + float64(dx), est.y + float64(dy)}
+					score := 0
+					for i := -2; i <= 2; i++ {
+						for j := -2; j <= 2; j++ {
+							want := max(abs(i), abs(j)) != 1
+							x := p.x + scale*(float64(i)*u.x+float64(j)*v.x)
+							y := p.y + scale*(float64(i)*u.y+float64(j)*v.y)
+							if b.at(int(math.Floor(x)), int(math.Floor(y))) == want {
+								score++
+							}
+						}
+					}
+					switch {
+					case score > best:
+						best, hits = score, append(hits[:0], p)
+					case score == best:
+						hits = append(hits, p)
+					}
+				}
+			}
+			if best < 23 {
+				continue
+			}
+			// Several spots can match equally; keep the ones around the
+			// match nearest to the estimate and use their center.
+			near := hits[0]
+			for _, p := range hits {
+				if p.dist(est) < near.dist(est) {
+					near = p
+				}
+			}
+			var sum point
+			n := 0.0
+			for _, p := range hits {
+				if p.dist(near) <= module {
+					sum.x, sum.y, n = sum.x+p.x, sum.y+p.y, n+1
+				}
+			}
+			return point{sum.x / n, sum.y / n}, true
+		}
+	}
+	return point{}, false
+}
+
+// decodeGrid reads a sampled symbol.
+func decodeGrid(g *grid) (*Code, error) {
+	version := (g.dim - 17) / 4
+	info, err := readFormat(g)
+	if err != nil {
+		return nil, err
+	}
+	data, err := correct(readCodewords(g, version, info.mask), version, info.level)
+	if err != nil {
+		return nil, fmt.Errorf("version %d-%s: %w", version, info.level, err)
+	}
+	text, err := parseData(data, version)
+	if err != nil {
+		return nil, err
+	}
+	return &Code{Text: text, Version: version, Level: info.level}, nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
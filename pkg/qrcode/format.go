@@ -0,0 +1,275 @@
+package qrcode
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Level is the error correction level of a symbol.
+type Level int
+
+const (
+	LevelL Level = iota // recovers 7% of the codewords
+	LevelM              // 15%
+	LevelQ              // 25%
+	LevelH              // 30%
+)
+
+func (l Level) String() string {
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+// levelFromBits maps the two level bits of the format information.
+var levelFromBits = [4]Level{LevelM, LevelL, LevelH, LevelQ}
+
+// bch computes the BCH remainder of value<<shift over generator.
+func bch(value, generator, shift int) int {
+	genLen := bits.Len(uint(generator))
+	v := value << shift
+	for bits.Len(uint(v)) >= genLen {
+		v ^= generator << (bits.Len(uint(v)) - genLen)
+	}
+	return value<<shift | v
+}
+
+// formatCodes are the 32 masked format information words.
+var formatCodes = func() (codes [32]int) {
+	for data := range codes {
+		codes[data] = bch(data, 0x537, 10) ^ 0x5412
+	}
+	return codes
+}()
+
+// versionCodes are the version information words of versions 7 to 40.
+var versionCodes = func() (codes [41]int) {
+	for v := 7; v <= 40; v++ {
+		codes[v] = bch(v, 0x1f25, 12)
+	}
+	return codes
+}()
+
+// formatInfo is the level and data mask of a symbol.
+type formatInfo struct {
+	level Level
+	mask  int
+}
+
+// readFormat decodes the format information from either of its copies,
+// tolerating up to 3 bit errors.
+func readFormat(g *grid) (formatInfo, error) {
+	dim := g.dim
+	var a, b int
+	for i := range 15 {
+		// Copy around the top-left finder.
+		var r, c int
+		switch {
+		case i < 6:
+			r, c = i, 8
+		case i == 6:
+			r, c = 7, 8
+		case i == 7:
+			r, c = 8, 8
+		case i == 8:
+			r, c = 8, 7
+		default:
+			r, c = 8, 14-i
+		}
+		if g.at(r, c) {
+			a |= 1 << i
+		}
+		// Copy split between the top-right and bottom-left finders.
+		if i < 8 {
+			r, c = 8, dim-1-i
+		} else {
+			r, c = dim-15+i, 8
+		}
+		if g.at(r, c) {
+			b |= 1 << i
+		}
+	}
+
+	best, bestDist := -1, 4
+	for data, code := range formatCodes {
+		for _, read := range []int{a, b} {
+			if d := bits.OnesCount(uint(read ^ code)); d < bestDist {
+				best, bestDist = data, d
+			}
+		}
+	}
+	if best < 0 {
+		return formatInfo{}, fmt.Errorf("unreadable format information")
+	}
+	return formatInfo{level: levelFromBits[best>>3], mask: best & 7}, nil
+}
+
+// readVersion decodes the version information of symbols of version 7 and
+// up, or returns 0 when neither copy is readable.
+func readVersion(g *grid) int {
+	dim := g.dim
+	var a, b int
+	for i := range 18 {
+		if g.at(i/3, dim-11+i%3) {
+			a |= 1 << i
+		}
+		if g.at(dim-11+i%3, i/3) {
+			b |= 1 << i
+		}
+	}
+	best, bestDist := 0, 4
+	for v := 7; v <= 40; v++ {
+		for _, read := range []int{a, b} {
+			if d := bits.OnesCount(uint(read ^ versionCodes[v])); d < bestDist {
+				best, bestDist = v, d
+			}
+		}
+	}
+	return best
+}
+
+// masked reports whether data mask m flips the module at row r, column c.
+func masked(m, r, c int) bool {
+	switch m {
+	case 0:
+		return (r+c)%2 == 0
+	case 1:
+		return r%2 == 0
+	case 2:
+		return c%3 == 0
+	case 3:
+		return (r+c)%3 == 0
+	case 4:
+		return (r/2+c/3)%2 == 0
+	case 5:
+		return r*c%2+r*c%3 == 0
+	case 6:
+		return (r*c%2+r*c%3)%2 == 0
+	default:
+		return (r*c%3+(r+c)%2)%2 == 0
+	}
+}
+
+// functionModules marks the modules that do not carry data: finders with
+// their separators and format information, timing patterns, alignment
+// patterns and version information.
+func functionModules(version int) [][]bool {
+	dim := 17 + 4*version
+	f := make([][]bool, dim)
+	for r := range f {
+		f[r] = make([]bool, dim)
+	}
+	fill := func(r0, c0, h, w int) {
+		for r := r0; r < r0+h; r++ {
+			for c := c0; c < c0+w; c++ {
+				f[r][c] = true
+			}
+		}
+	}
+	fill(0, 0, 9, 9)
+	fill(0, dim-8, 9, 8)
+	fill(dim-8, 0, 8, 9)
+	pos := alignmentPositions[version-1]
+	for _, r := range pos {
+		for _, c := range pos {
+			if f[r][c] {
+				// Overlaps a finder.
+				continue
+			}
+			fill(r-2, c-2, 5, 5)
+		}
+	}
+	fill(6, 0, 1, dim)
+	fill(0, 6, dim, 1)
+	if version >= 7 {
+		fill(0, dim-11, 6, 3)
+		fill(dim-11, 0, 3, 6)
+	}
+	return f
+}
+
+// readCodewords reads the data region in the zigzag order of the
+// standard, removing the data mask.
+func readCodewords(g *grid, version, mask int) []byte {
+	dim := g.dim
+	function := functionModules(version)
+	var out []byte
+	var cur byte
+	n := 0
+	up := true
+	for col := dim - 1; col > 0; col -= 2 {
+		if col == 6 {
+			col--
+		}
+		for i := range dim {
+			r := i
+			if up {
+				r = dim - 1 - i
+			}
+			for k := range 2 {
+				c := col - k
+				if function[r][c] {
+					continue
+				}
+				bit := g.at(r, c) != masked(mask, r, c)
+				cur <<= 1
+				if bit {
+					cur |= 1
+				}
+				n++
+				if n == 8 {
+					out = append(out, cur)
+					cur, n = 0, 0
+				}
+			}
+		}
+		up = !up
+	}
+	return out
+}
+
+// correct splits the interleaved codewords into blocks, repairs each and
+// returns the data codewords in order.
+func correct(codewords []byte, version int, level Level) ([]byte, error) {
+	groups := rsBlocks[version-1][level]
+	type block struct {
+		data  int
+		words []byte
+	}
+	var blocks []block
+	total := 0
+	for _, g := range groups {
+		for range g.count {
+			blocks = append(blocks, block{data: g.data, words: make([]byte, 0, g.total)})
+			total += g.total
+		}
+	}
+	if len(codewords) < total {
+		return nil, fmt.Errorf("read %d codewords, version %d needs %d", len(codewords), version, total)
+	}
+	ecLen := groups[0].total - groups[0].data
+
+	i := 0
+	maxData := groups[len(groups)-1].data
+	for k := range maxData {
+		for b := range blocks {
+			if k < blocks[b].data {
+				blocks[b].words = append(blocks[b].words, codewords[i])
+				i++
+			}
+		}
+	}
+	for range ecLen {
+		for b := range blocks {
+			blocks[b].words = append(blocks[b].words, codewords[i])
+			i++
+		}
+	}
+
+	var data []byte
+	for _, b := range blocks {
+		if _, err := rsCorrect(b.words, ecLen); err != nil {
+			return nil, err
+		}
+		data = append(data, b.words[:b.data]...)
+	}
+	return data, nil
+}
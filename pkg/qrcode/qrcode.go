@@ -0,0 +1,67 @@
+// Package qrcode decodes QR codes from images.
+//
+// The decoder is deliberately small: it finds the three finder patterns,
+// uses the bottom-right alignment pattern to correct perspective, samples
+// the module grid and runs Reed-Solomon error correction. Model 2 symbols
+// of every version are supported; micro QR and structured append
+// reassembly are not.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// ErrNotFound is returned when no readable symbol is in the image.
+var ErrNotFound = errors.New("no QR code found")
+
+// Code is a decoded symbol.
+type Code struct {
+	Text    string
+	Version int
+	Level   Level
+}
+
+// Decode reads the first QR code it can find in img. Light-on-dark
+// symbols are decoded as well.
+func Decode(img image.Image) (*Code, error) {
+	l := toLuminance(img)
+	if l.w == 0 || l.h == 0 {
+		return nil, ErrNotFound
+	}
+	var lastErr error
+	for _, b := range []*bitmap{adaptiveThreshold(l), globalThreshold(l)} {
+		for _, candidate := range []*bitmap{b, b.inverted()} {
+			code, err := decodeBitmap(candidate)
+			if err == nil {
+				return code, nil
+			}
+			if lastErr == nil || !errors.Is(err, ErrNotFound) {
+				lastErr = err
+			}
+		}
+	}
+	if errors.Is(lastErr, ErrNotFound) {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNotFound, lastErr)
+}
+
+func decodeBitmap(b *bitmap) (*Code, error) {
+	candidates := triples(findFinders(b))
+	if len(candidates) == 0 {
+		return nil, ErrNotFound
+	}
+	var lastErr error
+	for _, c := range candidates[:min(len(candidates), 8)] {
+		for _, dim := range c.dimensions(b) {
+			code, err := decodeAt(b, c, dim)
+			if err == nil {
+				return code, nil
+			}
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
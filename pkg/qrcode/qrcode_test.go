@@ -0,0 +1,93 @@
+package qrcode
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadPNG(t *testing.T, name string) image.Image {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// The fixtures were produced by an independent encoder and rendered with
+// the distortions named below.
+func TestDecodeFixtures(t *testing.T) {
+	tests := []struct {
+		file    string
+		text    string
+		version int
+		level   Level
+	}{
+		// Clean and axis aligned.
+		{"wifi.png", "WIFI:T:WPA;S:home-net;P:correct horse battery staple;;", 4, LevelM},
+		// Rotated with perspective and noise on a grey background.
+		{"otpauth.png", "otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example", 7, LevelQ},
+		// Light modules on a dark background.
+		{"inverted.png", "hello, world", 1, LevelL},
+		// Version information and several alignment patterns.
+		{"version10.png", "https://example.com/docs/setup?step=1&token=" + strings.Repeat("abcdefghij", 6) + "&lang=en", 10, LevelH},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			code, err := Decode(loadPNG(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code.Text != tt.text {
+				t.Errorf("text = %q, want %q", code.Text, tt.text)
+			}
+			if code.Version != tt.version || code.Level != tt.level {
+				t.Errorf("got %d-%s, want %d-%s", code.Version, code.Level, tt.version, tt.level)
+			}
+		})
+	}
+}
+
+func TestDecodeSubImage(t *testing.T) {
+	src := loadPNG(t, "wifi.png")
+	gray := image.NewGray(image.Rect(0, 0, src.Bounds().Dx()+30, src.Bounds().Dy()+30))
+	for i := range gray.Pix {
+		gray.Pix[i] = 255
+	}
+	for y := range src.Bounds().Dy() {
+		for x := range src.Bounds().Dx() {
+			gray.Set(x+30, y+30, src.At(x, y))
+		}
+	}
+	sub := gray.SubImage(image.Rect(20, 20, gray.Bounds().Dx(), gray.Bounds().Dy()))
+	code, err := Decode(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(code.Text, "WIFI:") {
+		t.Errorf("text = %q", code.Text)
+	}
+}
+
+func TestDecodeNotFound(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 120, 80))
+	for x := range 120 {
+		for y := range 80 {
+			img.SetGray(x, y, color.Gray{uint8(x + y)})
+		}
+	}
+	if _, err := Decode(img); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
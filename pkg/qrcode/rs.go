@@ -0,0 +1,150 @@
+package qrcode
+
+import "errors"
+
+// errTooManyErrors is returned when a block has more errors than its error
+// correction codewords can repair.
+var errTooManyErrors = errors.New("too many errors to correct")
+
+// GF(256) with the QR primitive polynomial x^8 + x^4 + x^3 + x^2 + 1.
+var gfExp, gfLog = func() (exp [512]byte, log [256]byte) {
+	x := 1
+	for i := range 255 {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// gfPow is alpha^e.
+func gfPow(e int) byte {
+	e %= 255
+	if e < 0 {
+		e += 255
+	}
+	return gfExp[e]
+}
+
+// polyEval evaluates p, lowest degree first, at x.
+func polyEval(p []byte, x byte) byte {
+	var y byte
+	for i := len(p) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// rsCorrect repairs block in place. The last ecLen bytes are the error
+// correction codewords, and block[0] is the highest degree coefficient.
+// It returns how many codewords were corrected.
+func rsCorrect(block []byte, ecLen int) (int, error) {
+	n := len(block)
+	// Syndromes S_j = R(alpha^j).
+	syndromes := make([]byte, ecLen)
+	clean := true
+	for j := range syndromes {
+		var s byte
+		x := gfPow(j)
+		for _, c := range block {
+			s = gfMul(s, x) ^ c
+		}
+		syndromes[j] = s
+		clean = clean && s == 0
+	}
+	if clean {
+		return 0, nil
+	}
+
+	// Berlekamp-Massey for the error locator, lowest degree first.
+	locator := []byte{1}
+	prev := []byte{1}
+	l, m := 0, 1
+	b := byte(1)
+	for i := range ecLen {
+		d := syndromes[i]
+		for k := 1; k <= l && k < len(locator); k++ {
+			d ^= gfMul(locator[k], syndromes[i-k])
+		}
+		if d == 0 {
+			m++
+			continue
+		}
+		coef := gfDiv(d, b)
+		next := make([]byte, max(len(locator), len(prev)+m))
+		copy(next, locator)
+		for k, p := range prev {
+			next[k+m] ^= gfMul(coef, p)
+		}
+		if 2*l <= i {
+			prev = locator
+			l = i + 1 - l
+			b = d
+			m = 1
+		} else {
+			m++
+		}
+		locator = next
+	}
+	for len(locator) > 1 && locator[len(locator)-1] == 0 {
+		locator = locator[:len(locator)-1]
+	}
+	if l != len(locator)-1 || 2*l > ecLen {
+		return 0, errTooManyErrors
+	}
+
+	// Error evaluator Omega = S * Lambda mod x^ecLen.
+	omega := make([]byte, ecLen)
+	for i, s := range syndromes {
+		for k, c := range locator {
+			if i+k < ecLen {
+				omega[i+k] ^= gfMul(s, c)
+			}
+		}
+	}
+	// Formal derivative: only odd powers survive in characteristic 2.
+	deriv := make([]byte, len(locator))
+	for k := 1; k < len(locator); k += 2 {
+		deriv[k-1] = locator[k]
+	}
+
+	// Chien search over every position, then Forney for the magnitude.
+	found := 0
+	for power := range n {
+		xInv := gfPow(-power)
+		if polyEval(locator, xInv) != 0 {
+			continue
+		}
+		den := polyEval(deriv, xInv)
+		if den == 0 {
+			return 0, errTooManyErrors
+		}
+		magnitude := gfMul(gfPow(power), gfDiv(polyEval(omega, xInv), den))
+		block[n-1-power] ^= magnitude
+		found++
+	}
+	if found != l {
+		return 0, errTooManyErrors
+	}
+	return found, nil
+}
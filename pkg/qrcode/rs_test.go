@@ -0,0 +1,72 @@
+package qrcode
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// rsEncode appends ecLen error correction codewords to data.
+func rsEncode(data []byte, ecLen int) []byte {
+	gen := []byte{1}
+	for i := range ecLen {
+		next := make([]byte, len(gen)+1)
+		for k, c := range gen {
+			next[k] ^= c
+			next[k+1] ^= gfMul(c, gfPow(i))
+		}
+		gen = next
+	}
+	rem := make([]byte, len(data)+ecLen)
+	copy(rem, data)
+	for i := range data {
+		coef := rem[i]
+		for k, c := range gen {
+			rem[i+k] ^= gfMul(coef, c)
+		}
+	}
+	return append(bytes.Clone(data), rem[len(data):]...)
+}
+
+func TestRSEncodeMatchesStandard(t *testing.T) {
+	// The 1-M symbol for "01234567" from the standard's worked example.
+	data := []byte{0x10, 0x20, 0x0c, 0x56, 0x61, 0x80, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11}
+	want := []byte{0xa5, 0x24, 0xd4, 0xc1, 0xed, 0x36, 0xc7, 0x87, 0x2c, 0x55}
+	if got := rsEncode(data, 10)[len(data):]; !bytes.Equal(got, want) {
+		t.Fatalf("ec = % x, want % x", got, want)
+	}
+}
+
+func TestRSCorrect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 200 {
+		ecLen := 2 * (1 + r.Intn(15))
+		data := make([]byte, 1+r.Intn(100))
+		r.Read(data)
+		clean := rsEncode(data, ecLen)
+
+		block := bytes.Clone(clean)
+		errs := r.Intn(ecLen/2 + 1)
+		for _, i := range r.Perm(len(block))[:errs] {
+			block[i] ^= byte(1 + r.Intn(255))
+		}
+		n, err := rsCorrect(block, ecLen)
+		if err != nil {
+			t.Fatalf("%d errors with %d ec codewords: %v", errs, ecLen, err)
+		}
+		if n != errs || !bytes.Equal(block, clean) {
+			t.Fatalf("corrected %d of %d errors, block differs: %v", n, errs, !bytes.Equal(block, clean))
+		}
+	}
+}
+
+func TestRSCorrectTooMany(t *testing.T) {
+	clean := rsEncode([]byte("otpauth://totp/x"), 10)
+	block := bytes.Clone(clean)
+	for i := range 8 {
+		block[i] ^= 0x5a
+	}
+	if _, err := rsCorrect(block, 10); err == nil && bytes.Equal(block, clean) {
+		t.Fatal("repaired more errors than the code allows")
+	}
+}
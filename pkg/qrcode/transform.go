@@ -0,0 +1,72 @@
+package qrcode
+
+import "math"
+
+type point struct {
+	x, y float64
+}
+
+func (p point) dist(q point) float64 {
+	return math.Hypot(p.x-q.x, p.y-q.y)
+}
+
+// transform is a projective mapping in homogeneous coordinates: a point
+// (x, y) maps to ((a*x + b*y + c) / w, (d*x + e*y + f) / w) with
+// w = g*x + h*y + i.
+type transform [9]float64
+
+func (t transform) apply(p point) point {
+	w := t[6]*p.x + t[7]*p.y + t[8]
+	return point{(t[0]*p.x + t[1]*p.y + t[2]) / w, (t[3]*p.x + t[4]*p.y + t[5]) / w}
+}
+
+func (t transform) mul(o transform) transform {
+	var r transform
+	for row := range 3 {
+		for col := range 3 {
+			for k := range 3 {
+				r[row*3+col] += t[row*3+k] * o[k*3+col]
+			}
+		}
+	}
+	return r
+}
+
+// adjugate inverts t up to a scale factor, which is all a projective
+// mapping needs.
+func (t transform) adjugate() transform {
+	return transform{
+		t[4]*t[8] - t[5]*t[7], t[2]*t[7] - t[1]*t[8], t[1]*t[5] - t[2]*t[4],
+		t[5]*t[6] - t[3]*t[8], t[0]*t[8] - t[2]*t[6], t[2]*t[3] - t[0]*t[5],
+		t[3]*t[7] - t[4]*t[6], t[1]*t[6] - t[0]*t[7], t[0]*t[4] - t[1]*t[3],
+	}
+}
+
+// squareToQuad maps the unit square corners (0,0), (1,0), (1,1), (0,1) to
+// q in the same order.
+func squareToQuad(q [4]point) transform {
+	dx3 := q[0].x - q[1].x + q[2].x - q[3].x
+	dy3 := q[0].y - q[1].y + q[2].y - q[3].y
+	if dx3 == 0 && dy3 == 0 {
+		return transform{
+			q[1].x - q[0].x, q[3].x - q[0].x, q[0].x,
+			q[1].y - q[0].y, q[3].y - q[0].y, q[0].y,
+			0, 0, 1,
+		}
+	}
+	dx1, dx2 := q[1].x-q[2].x, q[3].x-q[2].x
+	dy1, dy2 := q[1].y-q[2].y, q[3].y-q[2].y
+	den := dx1*dy2 - dx2*dy1
+	g := (dx3*dy2 - dx2*dy3) / den
+	h := (dx1*dy3 - dx3*dy1) / den
+	return transform{
+		q[1].x - q[0].x + g*q[1].x, q[3].x - q[0].x + h*q[3].x, q[0].x,
+		q[1].y - q[0].y + g*q[1].y, q[3].y - q[0].y + h*q[3].y, q[0].y,
+		g, h, 1,
+	}
+}
+
+// quadToQuad maps the corners of from onto the corners of to.
+func quadToQuad(from, to [4]point) transform {
+	return squareToQuad(to).mul(squareToQuad(from).adjugate())
+}